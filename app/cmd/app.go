@@ -17,6 +17,7 @@ import (
 	"github.com/DimKa163/keeper/internal/cli/crypto"
 	"github.com/DimKa163/keeper/internal/cli/persistence"
 	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/beevik/guid"
 	"github.com/spf13/cobra"
 	_ "modernc.org/sqlite"
 )
@@ -25,7 +26,15 @@ type ServiceContainer struct {
 	DB          *sql.DB
 	UserService *app.UserService
 	SyncService app.Syncer
+	RemoteSync  *app.SyncService
+	Devices     *app.DeviceService
+	Sessions    *app.SessionService
+	Accounts    *app.AccountService
+	Shares      *app.ShareService
+	Vaults      *app.VaultService
+	Audit       *app.AuditService
 	DataService *app.DataManager
+	Recovery    *app.RecoveryService
 	Decoder     core.Decoder
 	Encoder     core.Encoder
 }
@@ -52,22 +61,62 @@ func New(version, commit, date string) (*CMD, error) {
 		return nil, err
 	}
 	fileProvider := datatool.NewFileProvider(fmt.Sprintf("%s\\", dir))
-	syncService, err := createSyncService(db, fileProvider)
-	if err != nil && errors.Is(err, app.ErrServerUnavailable) {
-		fmt.Println("remote server unavailable")
-		err = nil
-	}
+	encoder := crypto.NewGzipEncoder(crypto.NewAesEncoder())
+	decoder := crypto.NewGzipDecoder(crypto.NewAesDecoder())
+	client, serv, err := createRemoteClient(db)
 	if err != nil {
 		return nil, err
 	}
-	encoder := crypto.NewGzipEncoder(crypto.NewAesEncoder())
-	decoder := crypto.NewGzipDecoder(crypto.NewAesDecoder())
+	var syncService app.Syncer = app.NewEmptySyncer()
+	var remote *app.SyncService
+	var devices *app.DeviceService
+	var sessions *app.SessionService
+	var accounts *app.AccountService
+	var shares *app.ShareService
+	var vaults *app.VaultService
+	var audit *app.AuditService
+	recovery := app.NewRecoveryService(db, encoder, decoder, fileProvider)
+	if client != nil {
+		remote = app.NewSyncService(client, db, fileProvider, encoder, decoder)
+		devices = app.NewDeviceService(client, db, serv.ID)
+		sessions = app.NewSessionService(client, db, serv.ID)
+		accounts = app.NewAccountService(client, db, serv.ID)
+		shares = app.NewShareService(client, db, serv.ID, encoder, decoder)
+		remote.SetShares(shares)
+		vaults = app.NewVaultService(client, db, serv.ID, shares, fileProvider, encoder, decoder)
+		remote.SetVaults(vaults)
+		audit = app.NewAuditService(client)
+		if err = devices.Load(context.Background()); err != nil {
+			return nil, err
+		}
+		if err = sessions.Load(context.Background()); err != nil {
+			return nil, err
+		}
+		err = remote.IsHealthy(context.Background())
+		if err != nil && !errors.Is(err, app.ErrServerUnavailable) {
+			return nil, err
+		}
+		if err != nil {
+			reportUnavailable(serv)
+		} else {
+			syncService = remote
+			recovery.SetRemote(app.NewRemoteRecoveryStore(client), remote)
+		}
+	}
 	cmd := &CMD{
 		ServiceContainer: &ServiceContainer{
 			DB:          db,
 			UserService: app.NewUserService(db),
 			DataService: app.NewDataService(db, encoder, decoder, syncService, fileProvider),
 			SyncService: syncService,
+			RemoteSync:  remote,
+			Devices:     devices,
+			Sessions:    sessions,
+			Accounts:    accounts,
+			Shares:      shares,
+			Vaults:      vaults,
+			Audit:       audit,
+			Recovery:    recovery,
 			Encoder:     encoder,
 			Decoder:     decoder,
 		},
@@ -130,6 +179,54 @@ func (cmd *CMD) RegisterCommands() error {
 	if err := commands.BindConflictSolveCommand(cmd.root, cmd.DataService, cmd.UserService, cmd.SyncService); err != nil {
 		return err
 	}
+	if err := commands.BindConflictsCommand(cmd.root, cmd.DataService, cmd.UserService, cmd.SyncService); err != nil {
+		return err
+	}
+	if err := commands.BindWatchCommand(cmd.root, cmd.UserService, cmd.SyncService); err != nil {
+		return err
+	}
+	if err := commands.BindDaemonCommand(cmd.root, cmd.UserService, cmd.DB, cmd.RemoteSync); err != nil {
+		return err
+	}
+	if err := commands.BindStatusCommand(cmd.root, cmd.DB); err != nil {
+		return err
+	}
+	if err := commands.BindRemoteCommand(cmd.root, cmd.DB); err != nil {
+		return err
+	}
+	if err := commands.BindRemoteLoginCommand(cmd.root, cmd.UserService, cmd.DB); err != nil {
+		return err
+	}
+	if err := commands.BindSessionsCommand(cmd.root, cmd.UserService, cmd.Sessions); err != nil {
+		return err
+	}
+	if err := commands.BindLogoutCommand(cmd.root, cmd.Sessions); err != nil {
+		return err
+	}
+	if err := commands.BindDevicesCommand(cmd.root, cmd.UserService, cmd.Devices); err != nil {
+		return err
+	}
+	if err := commands.BindAccountCommand(cmd.root, cmd.UserService, cmd.Accounts); err != nil {
+		return err
+	}
+	if err := commands.BindShareCommand(cmd.root, cmd.UserService, cmd.Shares); err != nil {
+		return err
+	}
+	if err := commands.BindSharesCommand(cmd.root, cmd.UserService, cmd.Shares); err != nil {
+		return err
+	}
+	if err := commands.BindVaultCommand(cmd.root, cmd.UserService, cmd.Vaults, cmd.DataService); err != nil {
+		return err
+	}
+	if err := commands.BindAuditLogCommand(cmd.root, cmd.Audit); err != nil {
+		return err
+	}
+	if err := commands.BindRecoveryCommand(cmd.root, cmd.UserService, cmd.Recovery); err != nil {
+		return err
+	}
+	if err := commands.BindResealCommand(cmd.root, cmd.UserService, cmd.DataService); err != nil {
+		return err
+	}
 	if err := commands.BindGetVersionCommand(cmd.root, cmd.version, cmd.commit, cmd.date); err != nil {
 		return err
 	}
@@ -170,25 +267,59 @@ func (cmd *CMD) Execute() error {
 		return err
 	}
 	ctx = common.SetVersion(ctx, version.Value)
+	clientID, err := getClientID(ctx, cmd.DB)
+	if err != nil {
+		return err
+	}
+	ctx = common.SetClientID(ctx, clientID)
 	return cmd.root.ExecuteContext(ctx)
 }
 
-func createSyncService(db *sql.DB, fp *datatool.FileProvider) (app.Syncer, error) {
+// getClientID read id of this installation, new one is generated on first start
+func getClientID(ctx context.Context, db *sql.DB) (string, error) {
+	id, err := persistence.GetSetting(ctx, db, persistence.ClientIDSetting)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	id = guid.NewString()
+	if err = persistence.SaveSetting(ctx, db, persistence.ClientIDSetting, id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// reportUnavailable tell why remote server is unavailable, changed server key must not look like network issue
+func reportUnavailable(serv *core.Server) {
+	if serv.TLS {
+		if _, err := app.VerifyFingerprint(context.Background(), serv); errors.Is(err, app.ErrFingerprintMismatch) {
+			fmt.Printf("⚠️ %v\nsync is disabled, run 'keeper remote trust' if you expect the key change\n", err)
+			return
+		}
+	}
+	fmt.Println("remote server unavailable")
+}
+
+// createRemoteClient create client of active remote server, nil if no server registered
+func createRemoteClient(db *sql.DB) (*app.RemoteClient, *core.Server, error) {
 	serv, err := persistence.GetServer(context.Background(), db, true)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
-			return nil, err
+			return nil, nil, err
 		}
-		return app.NewEmptySyncer(), nil
+		return nil, nil, nil
 	}
-	client, err := app.NewRemoteClient(serv.Address, serv.Login, serv.Password)
+	creds, err := app.NewTransportCredentials(serv)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if err = client.IsHealthy(context.Background()); err != nil {
-		return nil, err
+	client, err := app.NewRemoteClient(serv.Address, serv.Login, serv.Password, creds)
+	if err != nil {
+		return nil, nil, err
 	}
-	return app.NewSyncService(client, db, fp), nil
+	return client, serv, nil
 }
 
 func createDirIfNotExist() (string, string, error) {
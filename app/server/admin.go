@@ -0,0 +1,336 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/infrastructure/security"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/spf13/cobra"
+)
+
+// BindAdminCommands maintenance commands run from the server console, they work on database
+// and blob storage of the config directly and don't need the server to be running
+func BindAdminCommands(root *cobra.Command, server *Server) error {
+	for _, bind := range []func(*cobra.Command, *Server) error{
+		bindUsersCommand,
+		bindMigrateCommand,
+		bindGCCommand,
+		bindStatsCommand,
+		bindVerifyBlobsCommand,
+		bindCopyBlobsCommand,
+		bindRotateJWTKeyCommand,
+	} {
+		if err := bind(root, server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindUsersCommand(root *cobra.Command, server *Server) error {
+	cmd := &cobra.Command{
+		Use:   "users",
+		Short: "manage user accounts",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return server.AddStorage()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "list users",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := server.AdminService.Users(cmd.Context())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "LOGIN\tID\tCREATED\tLOGIN BY\tDISABLED")
+			for _, user := range users {
+				loginBy := "password"
+				if user.Verifier != nil {
+					loginBy = "srp"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					user.Login, user.ID, formatTimePtr(user.CreatedAt), loginBy, formatTimePtr(user.DisabledAt))
+			}
+			return w.Flush()
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "disable <login>",
+		Short: "forbid login of the user and revoke all sessions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.AdminService.DisableUser(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("user %s disabled\n", args[0])
+			return nil
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "enable <login>",
+		Short: "allow login of disabled user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.AdminService.EnableUser(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("user %s enabled\n", args[0])
+			return nil
+		},
+	})
+	var confirm string
+	deleteCmd := &cobra.Command{
+		Use:   "delete <login>",
+		Short: "delete user with own records and files, records pushed to vaults stay",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if confirm != args[0] {
+				return errors.New("repeat the login with --confirm to delete the user")
+			}
+			if err := server.AdminService.DeleteUser(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("user %s deleted\n", args[0])
+			return nil
+		},
+	}
+	deleteCmd.Flags().StringVar(&confirm, "confirm", "", "login of the user once more")
+	cmd.AddCommand(deleteCmd)
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindMigrateCommand(root *cobra.Command, server *Server) error {
+	var path string
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "manage database schema",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return server.AddStorage()
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&path, "path", "p", "", "directory with migrations, migrations shipped with the server by default")
+	cmd.AddCommand(&cobra.Command{
+		Use:   "up",
+		Short: "apply all new migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.MigrateFrom(path); err != nil {
+				return err
+			}
+			return printMigrationStatus(server, path)
+		},
+	})
+	var steps int
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "roll back the last migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps < 1 {
+				return errors.New("--steps must be positive")
+			}
+			if err := server.MigrateDown(path, steps); err != nil {
+				return err
+			}
+			return printMigrationStatus(server, path)
+		},
+	}
+	down.Flags().IntVarP(&steps, "steps", "n", 1, "number of migrations to roll back")
+	cmd.AddCommand(down)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "show schema version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printMigrationStatus(server, path)
+		},
+	})
+	root.AddCommand(cmd)
+	return nil
+}
+
+func printMigrationStatus(server *Server, path string) error {
+	status, err := server.MigrationStatus(path)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		fmt.Printf("schema version %d is dirty, the migration failed halfway and needs manual repair\n", status.Version)
+		return nil
+	}
+	fmt.Printf("schema version %d\n", status.Version)
+	return nil
+}
+
+func bindGCCommand(root *cobra.Command, server *Server) error {
+	var config usecase.GCConfig
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "remove deleted records every device has pulled after retention and files no record refers to",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.AddStorage(); err != nil {
+				return err
+			}
+			result, err := usecase.NewGarbageCollector(server.UnitOfWork, server.Filer, &config).Collect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if result.DryRun {
+				fmt.Printf("would remove %d deleted records, %d files (%s)\n",
+					result.Tombstones, result.Blobs, formatBytes(result.BlobBytes))
+				return nil
+			}
+			fmt.Printf("removed %d deleted records, %d files (%s)\n",
+				result.Tombstones, result.Blobs, formatBytes(result.BlobBytes))
+			return nil
+		},
+	}
+	cmd.Flags().DurationVarP(&config.Retention, "retention", "r", 90*24*time.Hour, "keep deleted records for the duration")
+	cmd.Flags().DurationVarP(&config.Grace, "grace", "g", time.Hour, "keep files changed within the duration")
+	cmd.Flags().BoolVarP(&config.DryRun, "dry-run", "n", false, "show what would be removed")
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindStatsCommand(root *cobra.Command, server *Server) error {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "show records and files of every user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.AddStorage(); err != nil {
+				return err
+			}
+			stats, err := server.AdminService.Stats(cmd.Context())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "LOGIN\tRECORDS\tDELETED\tRECORDS SIZE\tFILES\tFILES SIZE")
+			for _, item := range stats {
+				_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%d\t%s\n",
+					item.User.Login, item.Records, item.Tombstones, formatBytes(item.PayloadBytes),
+					item.Blobs, formatBytes(item.BlobBytes))
+			}
+			return w.Flush()
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVerifyBlobsCommand(root *cobra.Command, server *Server) error {
+	var grace time.Duration
+	cmd := &cobra.Command{
+		Use:   "verify-blobs",
+		Short: "find records without file and files without record",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := server.AddStorage(); err != nil {
+				return err
+			}
+			report, err := server.AdminService.VerifyBlobs(cmd.Context(), grace)
+			if err != nil {
+				return err
+			}
+			for _, secret := range report.Missing {
+				fmt.Printf("missing file: record %s version %d of user %s\n", secret.ID, secret.Version, secret.UserID)
+			}
+			for _, blob := range report.Orphans {
+				fmt.Printf("orphan file: %s version %d, %s\n", blob.Name, blob.Version, formatBytes(blob.Size))
+			}
+			fmt.Printf("checked %d records: %d missing files, %d orphan files\n",
+				report.Checked, len(report.Missing), len(report.Orphans))
+			if len(report.Missing) > 0 {
+				return errors.New("some records have no file")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().DurationVarP(&grace, "grace", "g", time.Hour, "files changed within the duration aren't reported")
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindCopyBlobsCommand(root *cobra.Command, server *Server) error {
+	var from, to string
+	cmd := &cobra.Command{
+		Use:   "copy-blobs",
+		Short: "copy files from one blob storage to another, e.g. before switching BLOB_STORAGE",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				from = server.BlobStorage
+			}
+			if from == to {
+				return fmt.Errorf("source and destination are the same storage %q", from)
+			}
+			src, err := addFiler(server.Config, from)
+			if err != nil {
+				return err
+			}
+			dst, err := addFiler(server.Config, to)
+			if err != nil {
+				return err
+			}
+			result, err := usecase.CopyBlobs(cmd.Context(), src, dst)
+			if result != nil {
+				fmt.Printf("copied %d files (%s), %d already copied\n",
+					result.Copied, formatBytes(result.Bytes), result.Skipped)
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "source storage, file or s3, BLOB_STORAGE by default")
+	cmd.Flags().StringVar(&to, "to", "", "destination storage, file or s3")
+	if err := cmd.MarkFlagRequired("to"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindRotateJWTKeyCommand(root *cobra.Command, server *Server) error {
+	cmd := &cobra.Command{
+		Use:   "rotate-jwt-key",
+		Short: "add new signing key to JWT_KEYS_DIR",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if server.JWTKeysDir == "" {
+				return errors.New("JWT_KEYS_DIR is not set, tokens are signed with SECRET")
+			}
+			id, err := security.GenerateKey(server.JWTKeysDir, time.Now())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("key %s created, restart the server to sign tokens with it\n", id)
+			if server.JWTSigningKeyID != "" {
+				fmt.Printf("JWT_SIGNING_KEY_ID pins key %s, set it to %s or unset it\n", server.JWTSigningKeyID, id)
+			}
+			fmt.Println("remove previous key once TOKEN_EXPIRATION has passed after the restart")
+			return nil
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
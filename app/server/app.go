@@ -3,8 +3,15 @@ package server
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"net"
+	"net/http"
+	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,30 +22,54 @@ import (
 
 	"github.com/DimKa163/keeper/internal/server/domain"
 	"github.com/DimKa163/keeper/internal/server/domain/auth"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/audit"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/memory"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/metrics"
 	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
 	"github.com/DimKa163/keeper/internal/server/infrastructure/security"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/sqlite"
 	"github.com/DimKa163/keeper/internal/server/shared/logging"
 	"github.com/DimKa163/keeper/internal/server/usecase"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 type ServiceContainer struct {
-	DBPool        *pgxpool.Pool
-	UnitOfWork    domain.UnitOfWork
-	AuthService   auth.AuthService
-	AuthEngine    auth.Engine
-	UserService   domain.UserService
-	SyncService   *usecase.SyncService
-	UserRPCServer *interfaces.UsersServer
-	SyncRPCServer *interfaces.SyncServer
-	HealthServer  *interfaces.HealthService
+	DBPool         *pgxpool.Pool
+	SQLite         *sqlite.DB
+	MemoryStore    *memory.Store
+	UnitOfWork     domain.UnitOfWork
+	Filer          domain.Filer
+	AuthService    auth.AuthService
+	AuthEngine     auth.Engine
+	UserService    domain.UserService
+	SyncService    *usecase.SyncService
+	DeviceService  *usecase.DeviceService
+	SessionService *usecase.SessionService
+	ShareService   *usecase.ShareService
+	VaultService   *usecase.VaultService
+	AuditService   *usecase.AuditService
+	AuditLog       domain.AuditLog
+	AdminService   *usecase.AdminService
+	Collector      *usecase.GarbageCollector
+	Metrics        *metrics.Metrics
+	UserRPCServer  *interfaces.UsersServer
+	SyncRPCServer  *interfaces.SyncServer
+	DeviceServer   *interfaces.DeviceServer
+	SessionServer  *interfaces.SessionServer
+	ShareServer    *interfaces.ShareServer
+	VaultServer    *interfaces.VaultServer
+	AuditServer    *interfaces.AuditServer
+	HealthServer   *interfaces.HealthService
 }
 
 type Server struct {
 	listener net.Listener
+	certs    *security.CertReloader
 	Version  string
 	Commit   string
 	Date     string
@@ -48,35 +79,115 @@ type Server struct {
 }
 
 func NewServer(config *Config, version, commit, date string) (*Server, error) {
-	listener, err := net.Listen("tcp", config.Addr)
-	if err != nil {
-		return nil, err
-	}
 	return &Server{
 		Config:           config,
 		ServiceContainer: &ServiceContainer{},
-		listener:         listener,
 		Version:          version,
 		Commit:           commit,
 		Date:             date,
 	}, nil
 }
 
+// AddStorage connect to database and blob storage, admin commands need nothing else.
+// DATABASE of sqlite:// scheme is a SQLite file, of memory:// scheme is kept in memory, Postgres url otherwise
+func (server *Server) AddStorage() error {
+	if server.UnitOfWork != nil {
+		return nil
+	}
+	var err error
+	switch {
+	case memory.IsMemory(server.Database):
+		server.MemoryStore = memory.NewStore()
+		server.UnitOfWork = memory.NewUnitOfWork(server.MemoryStore)
+	case sqlite.IsSQLite(server.Database):
+		server.SQLite, err = sqlite.Open(server.Database)
+		if err != nil {
+			return err
+		}
+		server.UnitOfWork = sqlite.NewUnitOfWork(server.SQLite)
+	default:
+		server.DBPool, err = addPgPool(server.Database)
+		if err != nil {
+			return err
+		}
+		server.UnitOfWork = addUnitOfWork(server.DBPool)
+	}
+	server.AuditLog = audit.NewStoreAuditLog(server.UnitOfWork)
+	server.Filer, err = addFiler(server.Config, server.BlobStorage)
+	if err != nil {
+		return err
+	}
+	server.AdminService = usecase.NewAdminService(server.UnitOfWork, server.Filer, server.AuditLog)
+	return nil
+}
+
+// ListenBuffer serve on in-process listener of the buffer size instead of ADDR, clients dial it
+// with the returned listener. Must be called before AddServices
+func (server *Server) ListenBuffer(size int) *bufconn.Listener {
+	listener := bufconn.Listen(size)
+	server.listener = listener
+	return listener
+}
+
 func (server *Server) AddServices() error {
 	var err error
-	server.DBPool, err = addPgPool(server.Database)
+	if server.listener == nil {
+		server.listener, err = net.Listen("tcp", server.Addr)
+		if err != nil {
+			return err
+		}
+	}
+	if err = server.AddStorage(); err != nil {
+		return err
+	}
+	if server.MetricsAddr != "" {
+		server.Metrics = metrics.New()
+		server.AuditLog = metrics.NewAuditLog(server.AuditLog, server.Metrics)
+	}
+	server.HealthServer = interfaces.NewHealthService(server.database())
+	server.AuthEngine, err = addAuthEngine(server.Config)
 	if err != nil {
 		return err
 	}
-	server.HealthServer = interfaces.NewHealthService(server.DBPool)
-	server.AuthEngine = addAuthEngine(server.Config)
-	server.ServerImpl = NewGRPCServer(server.listener, addGrpcServer(server.ServiceContainer), server.ServiceContainer)
-	server.UnitOfWork = addUnitOfWork(server.DBPool)
 	server.AuthService = addAuthService(server.Config)
-	server.UserService = addUserService(server.UnitOfWork, server.AuthService, server.AuthEngine)
-	server.UserRPCServer = interfaces.NewUserServer(server.UserService)
-	server.SyncService = usecase.NewSyncService(server.UnitOfWork, data.NewFileProvider(datatool.NewFileProvider(server.FilePath)))
+	server.SessionService = addSessionService(server.UnitOfWork, server.AuthEngine, server.AuditLog, server.Config)
+	filer := server.Filer
+	server.UserService, err = addUserService(server.UnitOfWork, server.AuthService, server.SessionService, filer, server.AuditLog, server.Config)
+	if err != nil {
+		return err
+	}
+	server.DeviceService = usecase.NewDeviceService(server.UnitOfWork, server.UserService, server.AuditLog)
+	server.certs, err = addCertReloader(server.Config)
+	if err != nil {
+		return err
+	}
+	server.ServerImpl = NewGRPCServer(server.listener, addGrpcServer(server.ServiceContainer, server.certs, server.Config.MaxMessageSize), server.ServiceContainer)
+	server.UserRPCServer = interfaces.NewUserServer(server.UserService, server.SessionService)
+	broker := usecase.NewChangeBroker()
+	server.SyncService = usecase.NewSyncService(server.UnitOfWork, filer, broker, &usecase.SyncConfig{
+		Audit:          server.AuditLog,
+		MaxSecrets:     int64(server.Config.MaxSecrets),
+		MaxBlobBytes:   int64(server.Config.MaxBlobBytes),
+		MaxBlobSize:    int64(server.Config.MaxBlobSize),
+		MaxMessageSize: int64(server.Config.MaxMessageSize),
+	})
 	server.SyncRPCServer = interfaces.NewSyncServer(server.SyncService)
+	server.DeviceServer = interfaces.NewDeviceServer(server.DeviceService)
+	server.SessionServer = interfaces.NewSessionServer(server.SessionService)
+	server.ShareService = usecase.NewShareService(server.UnitOfWork, broker)
+	server.ShareServer = interfaces.NewShareServer(server.ShareService)
+	server.VaultService = usecase.NewVaultService(server.UnitOfWork)
+	server.VaultServer = interfaces.NewVaultServer(server.VaultService)
+	server.AuditService = usecase.NewAuditService(server.UnitOfWork, server.Admins)
+	server.AuditServer = interfaces.NewAuditServer(server.AuditService)
+	server.Collector = usecase.NewGarbageCollector(server.UnitOfWork, filer, &usecase.GCConfig{
+		Retention: time.Duration(server.Config.GCRetention) * time.Second,
+		Grace:     time.Duration(server.Config.GCGrace) * time.Second,
+		DryRun:    server.Config.GCDryRun,
+	})
+	if server.Metrics != nil {
+		return addMetricsCollectors(server.ServiceContainer)
+	}
 	return nil
 }
 
@@ -92,12 +203,64 @@ func (server *Server) Map() {
 	server.ServerImpl.Map()
 }
 
+// migrationsPath Postgres migrations shipped with the server, relative to working directory.
+// SQLite migrations are built into the server
+const migrationsPath = "./internal/server/migrations"
+
+// Migrate apply migrations shipped with the server
 func (server *Server) Migrate() error {
-	return persistence.Migrate(server.DBPool, "./internal/server/migrations")
+	return server.MigrateFrom("")
 }
 
+// MigrateFrom apply migrations of the directory, migrations shipped with the server when path is empty.
+// In-memory storage has no schema, nothing is applied
 func (server *Server) MigrateFrom(path string) error {
-	return persistence.Migrate(server.DBPool, path)
+	if server.MemoryStore != nil {
+		return nil
+	}
+	if server.SQLite != nil {
+		return sqlite.Migrate(server.SQLite, path)
+	}
+	return persistence.Migrate(server.DBPool, postgresMigrations(path))
+}
+
+// MigrateDown roll back the given number of the last migrations of the directory
+func (server *Server) MigrateDown(path string, steps int) error {
+	if server.MemoryStore != nil {
+		return nil
+	}
+	if server.SQLite != nil {
+		return sqlite.MigrateDown(server.SQLite, path, steps)
+	}
+	return persistence.MigrateDown(server.DBPool, postgresMigrations(path), steps)
+}
+
+func (server *Server) MigrationStatus(path string) (*persistence.MigrationStatus, error) {
+	if server.MemoryStore != nil {
+		return &persistence.MigrationStatus{}, nil
+	}
+	if server.SQLite != nil {
+		return sqlite.GetMigrationStatus(server.SQLite, path)
+	}
+	return persistence.GetMigrationStatus(server.DBPool, postgresMigrations(path))
+}
+
+// database connection of the storage in use
+func (server *Server) database() interfaces.Pinger {
+	if server.MemoryStore != nil {
+		return server.MemoryStore
+	}
+	if server.SQLite != nil {
+		return server.SQLite
+	}
+	return server.DBPool
+}
+
+func postgresMigrations(path string) string {
+	if path == "" {
+		return migrationsPath
+	}
+	return path
 }
 
 func (server *Server) Run() error {
@@ -111,9 +274,104 @@ func (server *Server) Run() error {
 		defer cancel()
 		_ = server.ServerImpl.Shutdown(timeoutCtx)
 	}()
+	if server.certs != nil {
+		go server.reloadCertsOnHangup(ctx)
+	}
+	if server.GCInterval > 0 {
+		go server.collectGarbage(ctx, time.Duration(server.GCInterval)*time.Second)
+	}
+	if server.Metrics != nil {
+		listener, err := net.Listen("tcp", server.MetricsAddr)
+		if err != nil {
+			return err
+		}
+		go server.serveMetrics(ctx, listener)
+	}
 	return server.ListenAndServe()
 }
 
+// serveMetrics serve Prometheus metrics over HTTP until context is done
+func (server *Server) serveMetrics(ctx context.Context, listener net.Listener) {
+	logger := logging.Logger(ctx).Sugar()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", server.Metrics.Handler())
+	httpServer := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(timeoutCtx)
+	}()
+	logger.Infof("Serving metrics on %s", listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("metrics server failed: %v", err)
+	}
+}
+
+// collectGarbage run garbage collector every interval until context is done
+func (server *Server) collectGarbage(ctx context.Context, interval time.Duration) {
+	logger := logging.Logger(ctx).Sugar()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := server.Collector.Collect(ctx)
+			if err != nil {
+				logger.Errorf("garbage collection failed: %v", err)
+				continue
+			}
+			logger.Infof("garbage collected: %d deleted records, %d files of %d bytes, dry run: %t, took %s",
+				result.Tombstones, result.Blobs, result.BlobBytes, result.DryRun, server.Collector.Metrics().LastDuration)
+		}
+	}
+}
+
+// reloadCertsOnHangup reload TLS certificate and client CA on SIGHUP, connections are not dropped
+func (server *Server) reloadCertsOnHangup(ctx context.Context) {
+	logger := logging.Logger(ctx).Sugar()
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			if err := server.certs.Reload(); err != nil {
+				logger.Errorf("failed to reload certificates: %v", err)
+				continue
+			}
+			logger.Info("certificates reloaded")
+		}
+	}
+}
+
+// addFiler blob storage of the kind, file, s3 or memory
+func addFiler(config *Config, storage string) (domain.Filer, error) {
+	switch storage {
+	case "", "file":
+		return data.NewFileProvider(datatool.NewFileProvider(config.FilePath)), nil
+	case "memory":
+		return data.NewMemoryFileProvider(), nil
+	case "s3":
+		return data.NewS3FileProvider(context.Background(), &data.S3Config{
+			Endpoint:  config.S3Endpoint,
+			AccessKey: config.S3AccessKey,
+			SecretKey: config.S3SecretKey,
+			Region:    config.S3Region,
+			Bucket:    config.S3Bucket,
+			Prefix:    config.S3Prefix,
+			Secure:    config.S3Secure,
+			PartSize:  int64(config.S3PartSize),
+		})
+	default:
+		return nil, fmt.Errorf("unknown blob storage %q, file, s3 or memory expected", storage)
+	}
+}
+
 func addPgPool(database string) (*pgxpool.Pool, error) {
 	pg, err := pgxpool.New(context.Background(), database)
 	if err != nil {
@@ -125,17 +383,67 @@ func addPgPool(database string) (*pgxpool.Pool, error) {
 func addUnitOfWork(pool *pgxpool.Pool) domain.UnitOfWork {
 	return persistence.NewUnitOfWork(pool)
 }
-func addGrpcServer(container *ServiceContainer) *grpc.Server {
+
+// addCertReloader load TLS certificate, nil when TLS isn't configured
+func addCertReloader(config *Config) (*security.CertReloader, error) {
+	if config.TLSCert == "" && config.TLSKey == "" {
+		if config.TLSClientCA != "" {
+			return nil, errors.New("TLS_CLIENT_CA requires TLS_CERT and TLS_KEY")
+		}
+		return nil, nil
+	}
+	return security.NewCertReloader(config.TLSCert, config.TLSKey, config.TLSClientCA)
+}
+
+// addMetricsCollectors report pool stats of Postgres and garbage collector results on scrape
+func addMetricsCollectors(container *ServiceContainer) error {
+	if container.DBPool != nil {
+		if err := container.Metrics.Register(metrics.NewPoolCollector(container.DBPool)); err != nil {
+			return err
+		}
+	}
+	return container.Metrics.Register(metrics.NewGCCollector(container.Collector))
+}
+
+// messageOverhead room for ids, shares and framing of push message over its limit
+const messageOverhead = 64 * 1024
+
+// addGrpcServer metrics interceptors go first, so RPCs rejected by authentication are counted too.
+// Messages larger than push message limit are refused before they are read into memory
+func addGrpcServer(container *ServiceContainer, certs *security.CertReloader, maxMessageSize uint) *grpc.Server {
 	chain := make([]grpc.UnaryServerInterceptor, 0)
+	streamChain := make([]grpc.StreamServerInterceptor, 0)
+	if container.Metrics != nil {
+		chain = append(chain, interfaces.UnaryMetricsInterceptor(container.Metrics))
+		streamChain = append(streamChain, interfaces.StreamMetricsInterceptor(container.Metrics))
+	}
 	chain = append(chain, interfaces.UnaryLoggingInterceptor())
+	chain = append(chain, interfaces.UnaryPeerInterceptor())
 	skip := make(map[string]bool)
 	skip["/go.Users/Login"] = true
 	skip["/go.Users/Register"] = true
+	skip["/go.Users/RegisterVerifier"] = true
+	skip["/go.Users/BeginLogin"] = true
+	skip["/go.Users/FinishLogin"] = true
+	skip["/go.Users/Refresh"] = true
 	skip["/go.HealthService/Check"] = true
-	chain = append(chain, interfaces.UnaryIdentifyInterceptor(container.AuthEngine, skip))
-	streamChain := make([]grpc.StreamServerInterceptor, 0)
-	streamChain = append(streamChain, interfaces.StreamIdentifyInterceptor(container.AuthEngine))
-	return grpc.NewServer(grpc.ChainUnaryInterceptor(chain...), grpc.ChainStreamInterceptor(streamChain...))
+	skip["/go.Devices/Register"] = true
+	verifiers := &interfaces.Verifiers{
+		Devices:  container.DeviceService,
+		Sessions: container.SessionService,
+	}
+	chain = append(chain, interfaces.UnaryIdentifyInterceptor(container.AuthEngine, verifiers, skip))
+	streamChain = append(streamChain, interfaces.StreamPeerInterceptor())
+	streamChain = append(streamChain, interfaces.StreamIdentifyInterceptor(container.AuthEngine, verifiers))
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(chain...), grpc.ChainStreamInterceptor(streamChain...)}
+	if maxMessageSize > 0 {
+		// dek, data and a file block are counted, other fields fit into the reserve
+		opts = append(opts, grpc.MaxRecvMsgSize(int(maxMessageSize)+messageOverhead))
+	}
+	if certs != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+	}
+	return grpc.NewServer(opts...)
 }
 
 func addAuthService(config *Config) auth.AuthService {
@@ -148,15 +456,94 @@ func addAuthService(config *Config) auth.AuthService {
 	})
 }
 
-func addAuthEngine(config *Config) auth.Engine {
-	return security.NewJWTEngine(&security.JWTConfig{
-		SecretKey:       []byte(config.Secret),
+// addAuthEngine sign tokens with keys from JWT_KEYS_DIR, shared SECRET is used when the directory isn't set.
+// Key is rotated by adding a new key to the directory, the old one is removed after token expiration
+func addAuthEngine(config *Config) (auth.Engine, error) {
+	jwtConfig := &security.JWTConfig{
 		TokenExpiration: time.Duration(config.TokenExpiration) * time.Second,
+	}
+	if config.JWTKeysDir == "" {
+		if config.Secret == "" {
+			return nil, errors.New("either SECRET or JWT_KEYS_DIR is required")
+		}
+		jwtConfig.SecretKey = []byte(config.Secret)
+		return security.NewJWTEngine(jwtConfig), nil
+	}
+	keys, err := security.LoadKeys(config.JWTKeysDir)
+	if err != nil {
+		return nil, err
+	}
+	jwtConfig.SigningKeyID, err = security.SigningKeyID(keys, config.JWTSigningKeyID)
+	if err != nil {
+		return nil, err
+	}
+	jwtConfig.Keys = keys
+	return security.NewJWTEngine(jwtConfig), nil
+}
+
+func addSessionService(unitOfWork domain.UnitOfWork, engine auth.Engine, auditLog domain.AuditLog, config *Config) *usecase.SessionService {
+	return usecase.NewSessionService(unitOfWork, engine, &usecase.SessionConfig{
+		AccessTokenExpiration:  time.Duration(config.TokenExpiration) * time.Second,
+		RefreshTokenExpiration: time.Duration(config.RefreshTokenExpiration) * time.Second,
+		Audit:                  auditLog,
 	})
 }
 
-func addUserService(unitOfWork domain.UnitOfWork, authService auth.AuthService, engine auth.Engine) domain.UserService {
-	return usecase.NewUserService(unitOfWork, authService, engine)
+// addUserService lockout is counted per login and per client address. LEGACY_LOGIN allows password logins
+// while accounts registered before SRP migrate
+func addUserService(
+	unitOfWork domain.UnitOfWork,
+	authService auth.AuthService,
+	sessions *usecase.SessionService,
+	filer domain.Filer,
+	auditLog domain.AuditLog,
+	config *Config,
+) (domain.UserService, error) {
+	var blocklist []string
+	if config.PasswordBlocklist != "" {
+		data, err := os.ReadFile(config.PasswordBlocklist)
+		if err != nil {
+			return nil, err
+		}
+		blocklist = strings.Split(string(data), "\n")
+	}
+	fakeVerifierKey, err := loginSecret(config)
+	if err != nil {
+		return nil, err
+	}
+	lockout := time.Duration(config.LoginLockout) * time.Second
+	maxLockout := time.Duration(config.LoginMaxLockout) * time.Second
+	return usecase.NewUserService(unitOfWork, authService, sessions, filer, &usecase.UserConfig{
+		Logins: usecase.NewLoginLimiter(&usecase.LimiterConfig{
+			MaxAttempts: int(config.LoginMaxAttempts),
+			Lockout:     lockout,
+			MaxLockout:  maxLockout,
+		}),
+		Peers: usecase.NewLoginLimiter(&usecase.LimiterConfig{
+			MaxAttempts: int(config.PeerMaxAttempts),
+			Lockout:     lockout,
+			MaxLockout:  maxLockout,
+		}),
+		Policy:          usecase.NewPasswordPolicy(int(config.PasswordMinLength), blocklist),
+		Audit:           auditLog,
+		LegacyLogin:     config.LegacyLogin,
+		Handshakes:      usecase.NewHandshakeStore(),
+		FakeVerifierKey: fakeVerifierKey,
+	}), nil
+}
+
+// loginSecret key of fake verifiers, it's derived from the secret so the JWT key isn't used as is
+func loginSecret(config *Config) ([]byte, error) {
+	secret := config.LoginSecret
+	if secret == "" {
+		secret = config.Secret
+	}
+	if secret == "" {
+		return nil, errors.New("either LOGIN_SECRET or SECRET is required")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("keeper fake verifier"))
+	return mac.Sum(nil), nil
 }
 
 type ServerImpl interface {
@@ -189,6 +576,11 @@ func (gs *GRPCServer) Map() {
 	gs.services.HealthServer.Bind(gs.Server)
 	gs.services.UserRPCServer.Bind(gs.Server)
 	gs.services.SyncRPCServer.Bind(gs.Server)
+	gs.services.DeviceServer.Bind(gs.Server)
+	gs.services.SessionServer.Bind(gs.Server)
+	gs.services.ShareServer.Bind(gs.Server)
+	gs.services.VaultServer.Bind(gs.Server)
+	gs.services.AuditServer.Bind(gs.Server)
 }
 
 func (gs *GRPCServer) Shutdown(ctx context.Context) error {
@@ -2,14 +2,49 @@
 package server
 
 type Config struct {
-	Addr            string `env:"ADDR" envDefault:":3300"`
-	Database        string `env:"DATABASE,required"`
-	FilePath        string `env:"FilePath" envDefault:""`
-	Secret          string `env:"SECRET,required"`
-	TokenExpiration uint   `env:"TOKEN_EXPIRATION" envDefault:"3600"`
-	Memory          uint   `env:"ARGON_MEMORY" envDefault:"64"`
-	Iterations      uint   `env:"ARGON_ITERATIONS" envDefault:"3"`
-	Parallelism     uint   `env:"ARGON_PARALLELISM" envDefault:"2"`
-	SaltLength      uint   `env:"ARGON_SALT_LENGTH" envDefault:"16"`
-	KeyLength       uint   `env:"ARGON_KEY_LENGTH" envDefault:"32"`
+	Addr                   string `env:"ADDR" envDefault:":3300"`
+	Database               string `env:"DATABASE,required"`
+	FilePath               string `env:"FilePath" envDefault:""`
+	BlobStorage            string `env:"BLOB_STORAGE" envDefault:"file"`
+	S3Endpoint             string `env:"S3_ENDPOINT"`
+	S3AccessKey            string `env:"S3_ACCESS_KEY"`
+	S3SecretKey            string `env:"S3_SECRET_KEY"`
+	S3Region               string `env:"S3_REGION"`
+	S3Bucket               string `env:"S3_BUCKET" envDefault:"keeper"`
+	S3Prefix               string `env:"S3_PREFIX"`
+	S3Secure               bool   `env:"S3_SECURE" envDefault:"true"`
+	S3PartSize             uint   `env:"S3_PART_SIZE" envDefault:"8388608"`
+	Secret                 string `env:"SECRET"`
+	JWTKeysDir             string `env:"JWT_KEYS_DIR"`
+	JWTSigningKeyID        string `env:"JWT_SIGNING_KEY_ID"`
+	TLSCert                string `env:"TLS_CERT"`
+	TLSKey                 string `env:"TLS_KEY"`
+	TLSClientCA            string `env:"TLS_CLIENT_CA"`
+	TokenExpiration        uint   `env:"TOKEN_EXPIRATION" envDefault:"900"`
+	RefreshTokenExpiration uint   `env:"REFRESH_TOKEN_EXPIRATION" envDefault:"2592000"`
+	Memory                 uint   `env:"ARGON_MEMORY" envDefault:"64"`
+	Iterations             uint   `env:"ARGON_ITERATIONS" envDefault:"3"`
+	Parallelism            uint   `env:"ARGON_PARALLELISM" envDefault:"2"`
+	SaltLength             uint   `env:"ARGON_SALT_LENGTH" envDefault:"16"`
+	KeyLength              uint   `env:"ARGON_KEY_LENGTH" envDefault:"32"`
+	LoginMaxAttempts       uint   `env:"LOGIN_MAX_ATTEMPTS" envDefault:"5"`
+	PeerMaxAttempts        uint   `env:"LOGIN_PEER_MAX_ATTEMPTS" envDefault:"50"`
+	LoginLockout           uint   `env:"LOGIN_LOCKOUT" envDefault:"30"`
+	LoginMaxLockout        uint   `env:"LOGIN_MAX_LOCKOUT" envDefault:"3600"`
+	PasswordMinLength      uint   `env:"PASSWORD_MIN_LENGTH" envDefault:"8"`
+	PasswordBlocklist      string `env:"PASSWORD_BLOCKLIST"`
+	LegacyLogin            bool   `env:"LEGACY_LOGIN" envDefault:"false"`
+	LoginSecret            string `env:"LOGIN_SECRET"`
+	MaxSecrets             uint   `env:"QUOTA_MAX_SECRETS" envDefault:"10000"`
+	MaxBlobBytes           uint   `env:"QUOTA_MAX_BLOB_BYTES" envDefault:"1073741824"`
+	MaxBlobSize            uint   `env:"QUOTA_MAX_BLOB_SIZE" envDefault:"67108864"`
+	MaxMessageSize         uint   `env:"PUSH_MAX_MESSAGE_SIZE" envDefault:"2097152"`
+	GCInterval             uint   `env:"GC_INTERVAL" envDefault:"3600"`
+	GCRetention            uint   `env:"GC_RETENTION" envDefault:"7776000"`
+	GCGrace                uint   `env:"GC_GRACE" envDefault:"3600"`
+	GCDryRun               bool   `env:"GC_DRY_RUN" envDefault:"false"`
+	// MetricsAddr address of HTTP server with Prometheus metrics on /metrics, metrics aren't collected when empty
+	MetricsAddr string `env:"METRICS_ADDR"`
+	// Admins logins allowed to read audit of every user
+	Admins []string `env:"ADMINS" envSeparator:","`
 }
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"os"
+
 	"github.com/DimKa163/keeper/app/server"
 	"github.com/DimKa163/keeper/internal/server/shared/logging"
 	"github.com/caarlos0/env"
+	"github.com/spf13/cobra"
 )
 
 var (
@@ -25,15 +28,33 @@ func main() {
 	if err = srv.AddLogging(); err != nil {
 		panic(err)
 	}
+	root := &cobra.Command{
+		Use:          "server",
+		Short:        "keeper sync server, without command the server is started",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(srv)
+		},
+	}
+	if err = server.BindAdminCommands(root, srv); err != nil {
+		panic(err)
+	}
+	if err = root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func serve(srv *server.Server) error {
 	logger := logging.GetLogger()
-	if err = srv.AddServices(); err != nil {
+	if err := srv.AddServices(); err != nil {
 		logger.Fatal(err.Error())
 	}
 	srv.Map()
-	if err = srv.Migrate(); err != nil {
+	if err := srv.Migrate(); err != nil {
 		logger.Fatal(err.Error())
 	}
-	if err = srv.Run(); err != nil {
+	if err := srv.Run(); err != nil {
 		logger.Fatal(err.Error())
 	}
+	return nil
 }
@@ -0,0 +1,173 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	server2 "github.com/DimKa163/keeper/app/server"
+	"github.com/DimKa163/keeper/internal/common"
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMemoryServer_ShouldPushAndPullSecrets(t *testing.T) {
+	ctx := context.Background()
+	serv := runMemory(t)
+	text := guid.NewString()
+	file := guid.NewString()
+	content := make([]byte, 3*1024*1024+100)
+	_, _ = rand.Read(content)
+
+	pushCtx := common.WriteForce(common.WriteClientVersion(ctx, 0), false)
+	stream, err := serv.DataClient.PushStream(pushCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, stream.Send(pushOperation(pb.OperationType_Default, text, []byte("payload"), nil)))
+	assert.NoError(t, stream.Send(pushOperation(pb.OperationType_Begin, file, nil, nil)))
+	for i := 0; i < len(content); i += 1024 * 1024 {
+		assert.NoError(t, stream.Send(pushOperation(pb.OperationType_BinaryPart, file, nil, content[i:min(i+1024*1024, len(content))])))
+	}
+	assert.NoError(t, stream.Send(pushOperation(pb.OperationType_End, file, nil, nil)))
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, response.GetSuccess())
+
+	var req pb.PullRequest
+	pulled, err := serv.DataClient.Pull(ctx, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int32(1), pulled.GetVersion())
+	assert.Len(t, pulled.GetSecrets(), 2)
+	versions := make(map[string]int32)
+	for _, secret := range pulled.GetSecrets() {
+		versions[secret.GetId()] = secret.GetVersion()
+		if secret.GetId() == text {
+			assert.Equal(t, []byte("payload"), secret.GetData())
+		}
+	}
+
+	var fileReq pb.PullStreamRequest
+	fileReq.SetId(file)
+	fileReq.SetVersion(versions[file])
+	chunks, err := serv.DataClient.PullStream(ctx, &fileReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received bytes.Buffer
+	for {
+		chunk, err := chunks.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		received.Write(chunk.GetBuffer())
+	}
+	assert.Equal(t, content, received.Bytes())
+}
+
+// runMemory start server with in-memory storage on in-process listener, root user is registered and logged in by the client
+func runMemory(t *testing.T) *services {
+	const password = "root password"
+	serv := &services{}
+	srv, err := server2.NewServer(&server2.Config{
+		Database:               "memory://",
+		BlobStorage:            "memory",
+		Secret:                 "secret",
+		TokenExpiration:        5000,
+		RefreshTokenExpiration: 5000,
+		SaltLength:             16,
+		Iterations:             1,
+		Parallelism:            1,
+		Memory:                 1024,
+		KeyLength:              32,
+		LoginMaxAttempts:       5,
+		PeerMaxAttempts:        50,
+		LoginLockout:           30,
+		LegacyLogin:            true,
+		MaxSecrets:             100,
+		MaxBlobBytes:           64 * 1024 * 1024,
+		MaxBlobSize:            16 * 1024 * 1024,
+		MaxMessageSize:         2 * 1024 * 1024,
+	}, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener := srv.ListenBuffer(1024 * 1024)
+	if err = srv.AddServices(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Map()
+	if err = srv.AddLogging(); err != nil {
+		t.Fatal(err)
+	}
+	if err = srv.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = srv.ListenAndServe()
+	}()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+	serv.Server = srv
+	dialer := grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	})
+	conn, err := grpc.NewClient("passthrough:///bufconn", grpc.WithTransportCredentials(insecure.NewCredentials()), dialer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	serv.UsersClient = pb.NewUsersClient(conn)
+	var root pb.User
+	root.SetLogin("root")
+	root.SetPassword(password)
+	if _, err = serv.UsersClient.Register(context.Background(), &root); err != nil {
+		t.Fatal(err)
+	}
+	serv.interceptor = newInterceptor(serv.UsersClient, "root", password)
+	serv.streamInterceptor = newStreamInterceptor(serv.UsersClient, "root", password)
+	protectedConn, err := grpc.NewClient("passthrough:///bufconn", grpc.WithTransportCredentials(insecure.NewCredentials()), dialer,
+		grpc.WithChainUnaryInterceptor(serv.interceptor.Handle()),
+		grpc.WithChainStreamInterceptor(serv.streamInterceptor.Handle()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = protectedConn.Close()
+	})
+	serv.DataClient = pb.NewSyncClient(protectedConn)
+	return serv
+}
+
+func pushOperation(operation pb.OperationType, id string, data, buffer []byte) *pb.PushOperation {
+	var secret pb.Secret
+	secret.SetId(id)
+	secret.SetModifiedAt(timestamppb.Now())
+	secret.SetDek([]byte("dek"))
+	secret.SetData(data)
+	var op pb.PushOperation
+	op.SetType(operation)
+	op.SetSecret(&secret)
+	op.SetBuffer(buffer)
+	return &op
+}
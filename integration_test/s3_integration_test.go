@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/data"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/minio"
+)
+
+func TestS3FileProvider_ShouldWriteInPartsAndReadByRanges(t *testing.T) {
+	ctx := context.Background()
+	container, filer := runMinio(ctx, t)
+	defer container.Terminate(ctx)
+
+	name := guid.NewString()
+	content := make([]byte, 2*data.MinPartSize+datatool.MB/2)
+	_, _ = rand.Read(content)
+	writer, err := filer.OpenWrite(name, 3)
+	assert.NoError(t, err)
+	for chunk := range slices.Chunk(content, int(datatool.MB)) {
+		_, err = writer.Write(chunk)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+
+	reader, err := filer.OpenRead(name, 3)
+	assert.NoError(t, err)
+	read, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, content, read)
+
+	blob, err := filer.Stat(name, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), blob.Size)
+	var walked []*domain.Blob
+	assert.NoError(t, filer.Walk(func(blob *domain.Blob) error {
+		walked = append(walked, blob)
+		return nil
+	}))
+	assert.Len(t, walked, 1)
+	assert.Equal(t, name, walked[0].Name)
+	assert.Equal(t, int32(3), walked[0].Version)
+
+	assert.NoError(t, filer.Remove(name, 3))
+	_, err = filer.Stat(name, 3)
+	assert.True(t, os.IsNotExist(err))
+	_, err = filer.OpenRead(name, 3)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestS3FileProvider_AbortedUploadShouldLeaveNoFile(t *testing.T) {
+	ctx := context.Background()
+	container, filer := runMinio(ctx, t)
+	defer container.Terminate(ctx)
+
+	name := guid.NewString()
+	writer, err := filer.OpenWrite(name, 1)
+	assert.NoError(t, err)
+	_, err = writer.Write(make([]byte, data.MinPartSize+1))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Abort())
+
+	_, err = filer.Stat(name, 1)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCopyBlobs_ShouldCopyFilesFromDiskToS3(t *testing.T) {
+	ctx := context.Background()
+	container, filer := runMinio(ctx, t)
+	defer container.Terminate(ctx)
+
+	disk := data.NewFileProvider(datatool.NewFileProvider(t.TempDir()))
+	name := guid.NewString()
+	writer, err := disk.OpenWrite(name, 2)
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte("content"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	result, err := usecase.CopyBlobs(ctx, disk, filer)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Copied)
+	result, err = usecase.CopyBlobs(ctx, disk, filer)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Skipped)
+
+	reader, err := filer.OpenRead(name, 2)
+	assert.NoError(t, err)
+	defer reader.Close()
+	read, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(read))
+}
+
+func runMinio(ctx context.Context, t *testing.T) (testcontainers.Container, *data.S3FileProvider) {
+	container, err := minio.Run(ctx, "minio/minio:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint, err := container.ConnectionString(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("minio started at: %s", endpoint)
+	filer, err := data.NewS3FileProvider(ctx, &data.S3Config{
+		Endpoint:  endpoint,
+		AccessKey: container.Username,
+		SecretKey: container.Password,
+		Bucket:    "keeper",
+		Prefix:    "blobs/",
+		PartSize:  data.MinPartSize,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return container, filer
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/DimKa163/keeper/internal/pb"
 	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -84,6 +85,67 @@ func TestUserService_Login(t *testing.T) {
 	assert.True(t, resp.HasToken())
 }
 
+func TestSyncService_PullPages(t *testing.T) {
+	ctx := context.Background()
+	ids := make([]guid.Guid, 0, 5)
+	container, serv, err := run(ctx, t, func(s *services) error {
+		user, err := s.UnitOfWork.UserRepository().Get(ctx, "root")
+		if err != nil {
+			return err
+		}
+		for i := 1; i <= 5; i++ {
+			id := *guid.New()
+			ids = append(ids, id)
+			if err = s.UnitOfWork.SecretRepository().Insert(ctx, &domain.Secret{
+				ID:         id,
+				ModifiedAt: time.Now(),
+				UserID:     user.ID,
+				Type:       domain.TextType,
+				Payload:    []byte("payload"),
+				Dek:        []byte("dek"),
+				Version:    int32(i),
+			}); err != nil {
+				return err
+			}
+		}
+		return s.UnitOfWork.SyncStateRepository().Insert(ctx, &domain.SyncState{
+			ID:     "Secret",
+			UserID: user.ID,
+			Value:  5,
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer container.Terminate(ctx)
+	defer serv.DBPool.Close()
+	defer serv.Shutdown(ctx)
+
+	var req pb.PullRequest
+	req.SetSince(1)
+	req.SetPageSize(2)
+	stream, err := serv.DataClient.PullPages(ctx, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make([]string, 0, 4)
+	for {
+		page, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, int32(5), page.GetVersion())
+		for _, secret := range page.GetSecrets() {
+			received = append(received, secret.GetId())
+		}
+		if page.GetLast() {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{ids[1].String(), ids[2].String(), ids[3].String(), ids[4].String()}, received)
+}
+
 //func TestDataService_Push(t *testing.T) {
 //	ctx := context.Background()
 //	container, serv, err := run(ctx, t, func(s *services) error {
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/pb"
+)
+
+// AccountService manage account on remote server
+type AccountService struct {
+	client   *RemoteClient
+	db       *sql.DB
+	serverID int32
+}
+
+func NewAccountService(client *RemoteClient, db *sql.DB, serverID int32) *AccountService {
+	return &AccountService{client: client, db: db, serverID: serverID}
+}
+
+// ChangePassword other sessions of the account are terminated by server
+func (as *AccountService) ChangePassword(ctx context.Context, password, newPassword string) error {
+	salt, verifier, err := newVerifier(as.client.creds.login, newPassword)
+	if err != nil {
+		return err
+	}
+	proof, err := as.prove(ctx, password)
+	if err != nil {
+		return err
+	}
+	var req pb.ChangePasswordRequest
+	req.SetHandshakeId(proof.handshakeID)
+	req.SetProof(proof.proof)
+	req.SetSalt(salt)
+	req.SetVerifier(verifier)
+	_, err = as.client.UsersClient.ChangePassword(ctx, &req)
+	return err
+}
+
+// ChangeLogin rename account, login of registered server is updated too
+func (as *AccountService) ChangeLogin(ctx context.Context, password, newLogin string) error {
+	proof, err := as.prove(ctx, password)
+	if err != nil {
+		return err
+	}
+	var req pb.ChangeLoginRequest
+	req.SetHandshakeId(proof.handshakeID)
+	req.SetProof(proof.proof)
+	req.SetNewLogin(newLogin)
+	if _, err := as.client.UsersClient.ChangeLogin(ctx, &req); err != nil {
+		return err
+	}
+	return persistence.UpdateServerLogin(ctx, as.db, as.serverID, newLogin)
+}
+
+// DeleteAccount remove account on server and forget the server, local records are kept
+func (as *AccountService) DeleteAccount(ctx context.Context, password string) (err error) {
+	proof, err := as.prove(ctx, password)
+	if err != nil {
+		return err
+	}
+	var req pb.DeleteAccountRequest
+	req.SetHandshakeId(proof.handshakeID)
+	req.SetProof(proof.proof)
+	if _, err = as.client.UsersClient.DeleteAccount(ctx, &req); err != nil {
+		return err
+	}
+	tx, err := as.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return persistence.TxDeleteServer(ctx, tx, as.serverID)
+}
+
+// prove password of the account with SRP handshake
+func (as *AccountService) prove(ctx context.Context, password string) (*passwordProof, error) {
+	return prove(ctx, as.client.UsersClient, as.client.creds.login, password)
+}
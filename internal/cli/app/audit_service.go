@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuditService read audit trail of remote server
+type AuditService struct {
+	client *RemoteClient
+}
+
+func NewAuditService(client *RemoteClient) *AuditService {
+	return &AuditService{client: client}
+}
+
+// Query events newest first, reading other users is allowed to server administrators only
+func (as *AuditService) Query(ctx context.Context, query *core.AuditQuery) ([]*core.AuditEvent, error) {
+	var req pb.AuditRequest
+	req.SetLogin(query.Login)
+	req.SetAll(query.All)
+	if !query.Since.IsZero() {
+		req.SetSince(timestamppb.New(query.Since))
+	}
+	req.SetBeforeId(query.BeforeID)
+	req.SetLimit(query.Limit)
+	res, err := as.client.Audit(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*core.AuditEvent, len(res.GetEvents()))
+	for i, event := range res.GetEvents() {
+		events[i] = &core.AuditEvent{
+			ID:       event.GetId(),
+			Type:     event.GetType(),
+			Login:    event.GetLogin(),
+			DeviceID: event.GetDeviceId(),
+			ClientID: event.GetClientId(),
+			Peer:     event.GetPeer(),
+			SecretID: event.GetSecretId(),
+			Version:  event.GetVersion(),
+			Bytes:    event.GetBytes(),
+			Reason:   event.GetReason(),
+			At:       event.GetAt().AsTime(),
+		}
+	}
+	return events, nil
+}
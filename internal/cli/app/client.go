@@ -3,53 +3,105 @@ package app
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/common"
 
 	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/srp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 var (
 	ErrServerUnavailable = errors.New("server is unavailable")
+	ErrSessionExpired    = errors.New("remote session expired, run remote-login")
 )
 
+// tokenExpirationGap access token is refreshed the gap before it expires
+const tokenExpirationGap = 30 * time.Second
+
 type RemoteClient struct {
-	addr  string
-	login string
-	pass  string
+	addr     string
+	creds    *identity
+	registry pb.DevicesClient
 	pb.HealthServiceClient
 	pb.UsersClient
 	pb.SyncClient
+	pb.DevicesClient
+	pb.SessionsClient
+	pb.SharesClient
+	pb.VaultsClient
+	pb.AuditsClient
 }
 
-func NewRemoteClient(addr string, login string, pass string) (*RemoteClient, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewRemoteClient(addr string, login string, pass string, transport credentials.TransportCredentials) (*RemoteClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(transport))
 	if err != nil {
 		return nil, err
 	}
 	usersClient := pb.NewUsersClient(conn)
+	creds := &identity{users: usersClient, login: login, pass: pass}
 	protectedConn, err := grpc.NewClient(addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithChainUnaryInterceptor(newInterceptor(usersClient, login, pass).Handle()),
-		grpc.WithChainStreamInterceptor(newStreamInterceptor(usersClient, login, pass).Handle()))
+		grpc.WithTransportCredentials(transport),
+		grpc.WithChainUnaryInterceptor(newInterceptor(creds).Handle()),
+		grpc.WithChainStreamInterceptor(newStreamInterceptor(creds).Handle()))
 	if err != nil {
 		return nil, err
 	}
 	return &RemoteClient{
-		login:               login,
-		pass:                pass,
+		creds:               creds,
 		addr:                addr,
+		registry:            pb.NewDevicesClient(conn),
 		HealthServiceClient: pb.NewHealthServiceClient(conn),
 		UsersClient:         usersClient,
 		SyncClient:          pb.NewSyncClient(protectedConn),
+		DevicesClient:       pb.NewDevicesClient(protectedConn),
+		SessionsClient:      pb.NewSessionsClient(protectedConn),
+		SharesClient:        pb.NewSharesClient(protectedConn),
+		VaultsClient:        pb.NewVaultsClient(protectedConn),
+		AuditsClient:        pb.NewAuditsClient(protectedConn),
 	}, nil
 }
 
+// SetDevice sign next logins with the device key
+func (rm *RemoteClient) SetDevice(device *core.Device) {
+	rm.creds.setDevice(device)
+}
+
+// RegisterDevice register a new key pair of the installation on the server.
+// Pending device can't login until another device of the user approves it
+func (rm *RemoteClient) RegisterDevice(ctx context.Context, serverID int32, name string) (*core.Device, bool, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, false, err
+	}
+	proof, err := prove(ctx, rm.UsersClient, rm.creds.login, rm.creds.pass)
+	if err != nil {
+		return nil, false, err
+	}
+	var req pb.RegisterDeviceRequest
+	req.SetLogin(rm.creds.login)
+	req.SetHandshakeId(proof.handshakeID)
+	req.SetProof(proof.proof)
+	req.SetName(name)
+	req.SetPublicKey(public)
+	res, err := rm.registry.Register(ctx, &req)
+	if err != nil {
+		return nil, false, err
+	}
+	return &core.Device{ServerID: serverID, ID: res.GetId(), PrivateKey: private}, res.GetPending(), nil
+}
+
 func (rm *RemoteClient) IsHealthy(ctx context.Context) error {
 	res, err := rm.Check(ctx, &pb.HealthCheckRequest{})
 	if err != nil {
@@ -68,10 +120,7 @@ func (rm *RemoteClient) IsHealthy(ctx context.Context) error {
 }
 
 func (rm *RemoteClient) TryToAuthenticate(ctx context.Context) error {
-	var us pb.User
-	us.SetLogin(rm.login)
-	us.SetPassword(rm.pass)
-	_, err := rm.Login(ctx, &us)
+	res, err := rm.creds.authenticate(ctx)
 	if err != nil {
 		code, ok := status.FromError(err)
 		if !ok {
@@ -81,50 +130,256 @@ func (rm *RemoteClient) TryToAuthenticate(ctx context.Context) error {
 			return err
 		}
 		fmt.Println("authentification failed. trying create a new user")
-		if _, err = rm.Register(ctx, &us); err != nil {
+		salt, verifier, err := newVerifier(rm.creds.login, rm.creds.pass)
+		if err != nil {
+			return err
+		}
+		var req pb.RegisterVerifierRequest
+		req.SetLogin(rm.creds.login)
+		req.SetSalt(salt)
+		req.SetVerifier(verifier)
+		if res, err = rm.UsersClient.RegisterVerifier(ctx, &req); err != nil {
 			return err
 		}
 		fmt.Println("✅ new user was created successfully")
-		return nil
+		return rm.creds.accept(ctx, res)
 	}
 	fmt.Println("✅ authentification succeeded.")
+	return rm.creds.accept(ctx, res)
+}
+
+// MigrateLogin upgrade account registered before SRP with a one-time password login.
+// It's made only when the user asks for it
+func (rm *RemoteClient) MigrateLogin(ctx context.Context) error {
+	res, err := rm.creds.legacyLogin(ctx)
+	if err != nil {
+		return err
+	}
+	return rm.creds.accept(ctx, res)
+}
+
+// SetRefreshToken continue session saved before, the password isn't needed while session is alive
+func (rm *RemoteClient) SetRefreshToken(token string) {
+	rm.creds.mu.Lock()
+	defer rm.creds.mu.Unlock()
+	rm.creds.refreshToken = token
+}
+
+// SetTokenStore save refresh token on every rotation
+func (rm *RemoteClient) SetTokenStore(store TokenStore) {
+	rm.creds.mu.Lock()
+	defer rm.creds.mu.Unlock()
+	rm.creds.store = store
+}
+
+// StartSession login with the password, current session is closed
+func (rm *RemoteClient) StartSession(ctx context.Context) error {
+	if rm.creds.hasSession() {
+		// the old session may be gone already
+		_, _ = rm.SessionsClient.Logout(ctx, &pb.LogoutRequest{})
+		rm.creds.reset()
+	}
+	res, err := rm.creds.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+	return rm.creds.accept(ctx, res)
+}
+
+// EndSession logout on server and forget the tokens
+func (rm *RemoteClient) EndSession(ctx context.Context) error {
+	if _, err := rm.SessionsClient.Logout(ctx, &pb.LogoutRequest{}); err != nil {
+		return err
+	}
+	rm.creds.reset()
 	return nil
 }
 
-type unaryIdentifyInterceptor struct {
-	users    pb.UsersClient
-	token    string
-	username string
-	userpass string
+// TokenStore keeper of the refresh token between runs
+type TokenStore interface {
+	SaveRefreshToken(ctx context.Context, token string) error
+}
+
+// identity credentials and tokens of the remote user shared by interceptors
+type identity struct {
+	mu           sync.Mutex
+	refreshMu    sync.Mutex
+	users        pb.UsersClient
+	login        string
+	pass         string
+	device       *core.Device
+	token        string
+	expiresAt    time.Time
+	refreshToken string
+	store        TokenStore
+}
+
+func (c *identity) setDevice(device *core.Device) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.device = device
 }
 
-func newInterceptor(users pb.UsersClient, username, userpass string) *unaryIdentifyInterceptor {
-	return &unaryIdentifyInterceptor{
-		users:    users,
-		username: username,
-		userpass: userpass,
+func (c *identity) hasSession() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token != "" || c.refreshToken != ""
+}
+
+func (c *identity) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+	c.refreshToken = ""
+	c.expiresAt = time.Time{}
+}
+
+// getToken return cached token, refresh it when there is no one or it is about to expire
+func (c *identity) getToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token := c.token
+	valid := time.Now().Add(tokenExpirationGap).Before(c.expiresAt)
+	c.mu.Unlock()
+	if token != "" && valid {
+		return token, nil
 	}
+	return c.refresh(ctx, token)
+}
+
+// refresh exchange refresh token for new tokens, login with the password when there is no session.
+// Refresh token can be used once, so refreshes are serialized and stale token is compared with the current one
+func (c *identity) refresh(ctx context.Context, stale string) (string, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	c.mu.Lock()
+	token, refreshToken := c.token, c.refreshToken
+	c.mu.Unlock()
+	if token != stale {
+		return token, nil
+	}
+	if refreshToken != "" {
+		var req pb.RefreshRequest
+		req.SetRefreshToken(refreshToken)
+		res, err := c.users.Refresh(ctx, &req)
+		if err == nil {
+			if err = c.accept(ctx, res); err != nil {
+				return "", err
+			}
+			return res.GetToken(), nil
+		}
+		if status.Code(err) != codes.Unauthenticated {
+			return "", err
+		}
+	}
+	if c.pass == "" {
+		return "", ErrSessionExpired
+	}
+	res, err := c.authenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err = c.accept(ctx, res); err != nil {
+		return "", err
+	}
+	return res.GetToken(), nil
+}
+
+// accept keep issued tokens, refresh token is saved to the store
+func (c *identity) accept(ctx context.Context, res *pb.UserResponse) error {
+	c.mu.Lock()
+	c.token = res.GetToken()
+	c.expiresAt = time.Unix(res.GetExpiresAt(), 0)
+	c.refreshToken = res.GetRefreshToken()
+	store := c.store
+	c.mu.Unlock()
+	if store == nil || res.GetRefreshToken() == "" {
+		return nil
+	}
+	return store.SaveRefreshToken(ctx, res.GetRefreshToken())
+}
+
+// authenticate login with SRP handshake, request is signed when the device is registered
+func (c *identity) authenticate(ctx context.Context) (*pb.UserResponse, error) {
+	proof, err := prove(ctx, c.users, c.login, c.pass)
+	if err != nil {
+		return nil, err
+	}
+	var req pb.FinishLoginRequest
+	req.SetLogin(c.login)
+	req.SetHandshakeId(proof.handshakeID)
+	req.SetProof(proof.proof)
+	if deviceID, ts, signature, ok := c.sign(); ok {
+		req.SetDeviceId(deviceID)
+		req.SetTimestamp(ts)
+		req.SetSignature(signature)
+	}
+	res, err := c.users.FinishLogin(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if err = proof.verifyServer(res.GetServerProof()); err != nil {
+		return nil, err
+	}
+	return res.GetTokens(), nil
+}
+
+// legacyLogin login with the password, the verifier is required so the account is upgraded by the same request
+func (c *identity) legacyLogin(ctx context.Context) (*pb.UserResponse, error) {
+	salt, err := srp.NewSalt()
+	if err != nil {
+		return nil, err
+	}
+	var us pb.User
+	us.SetLogin(c.login)
+	us.SetPassword(c.pass)
+	// the server has accepted this password already
+	us.SetSalt(salt)
+	us.SetVerifier(srp.Verifier(c.pass, salt))
+	if deviceID, ts, signature, ok := c.sign(); ok {
+		us.SetDeviceId(deviceID)
+		us.SetTimestamp(ts)
+		us.SetSignature(signature)
+	}
+	return c.users.Login(ctx, &us)
+}
+
+// sign login challenge with the device key, false when the device isn't registered
+func (c *identity) sign() (string, int64, []byte, bool) {
+	c.mu.Lock()
+	device := c.device
+	c.mu.Unlock()
+	if device == nil {
+		return "", 0, nil, false
+	}
+	ts := time.Now().Unix()
+	return device.ID, ts, ed25519.Sign(device.PrivateKey, common.DeviceChallenge(c.login, device.ID, ts)), true
+}
+
+type unaryIdentifyInterceptor struct {
+	creds *identity
+}
+
+func newInterceptor(creds *identity) *unaryIdentifyInterceptor {
+	return &unaryIdentifyInterceptor{creds: creds}
 }
 
 func (h *unaryIdentifyInterceptor) Handle() grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		var err error
-		if h.token == "" {
-			h.token, err = h.login(ctx)
-			if err != nil {
-				return err
-			}
+		token, err := h.creds.getToken(ctx)
+		if err != nil {
+			return err
 		}
-		md := metadata.New(map[string]string{"authorization": fmt.Sprintf("Bearer %s", h.token)})
+		md := metadata.New(map[string]string{"authorization": fmt.Sprintf("Bearer %s", token)})
 		err = invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
 		if err != nil {
 			if e, ok := status.FromError(err); ok {
 				if e.Code() == codes.Unauthenticated {
-					h.token, err = h.login(ctx)
+					token, err = h.creds.refresh(ctx, token)
 					if err != nil {
 						return err
 					}
-					err = invoker(ctx, method, req, reply, cc, opts...)
+					md = metadata.New(map[string]string{"authorization": fmt.Sprintf("Bearer %s", token)})
+					err = invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
 				}
 			}
 		}
@@ -132,42 +387,21 @@ func (h *unaryIdentifyInterceptor) Handle() grpc.UnaryClientInterceptor {
 	}
 }
 
-func (h *unaryIdentifyInterceptor) login(ctx context.Context) (string, error) {
-	var us pb.User
-	us.SetLogin(h.username)
-	us.SetPassword(h.userpass)
-	t, err := h.users.Login(ctx, &us)
-	if err != nil {
-		return "", err
-	}
-	return t.GetToken(), nil
-}
-
 type streamIdentifyInterceptor struct {
-	users    pb.UsersClient
-	token    string
-	username string
-	userpass string
+	creds *identity
 }
 
-func newStreamInterceptor(users pb.UsersClient, username, userpass string) *streamIdentifyInterceptor {
-	return &streamIdentifyInterceptor{
-		users:    users,
-		username: username,
-		userpass: userpass,
-	}
+func newStreamInterceptor(creds *identity) *streamIdentifyInterceptor {
+	return &streamIdentifyInterceptor{creds: creds}
 }
 
 func (h *streamIdentifyInterceptor) Handle() grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-		var err error
-		if h.token == "" {
-			h.token, err = h.login(ctx)
-			if err != nil {
-				return nil, err
-			}
+		token, err := h.creds.getToken(ctx)
+		if err != nil {
+			return nil, err
 		}
-		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", h.token)
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
 
 		stream, err := streamer(ctx, desc, cc, method, opts...)
 		if err != nil {
@@ -177,14 +411,3 @@ func (h *streamIdentifyInterceptor) Handle() grpc.StreamClientInterceptor {
 		return stream, nil
 	}
 }
-
-func (h *streamIdentifyInterceptor) login(ctx context.Context) (string, error) {
-	var us pb.User
-	us.SetLogin(h.username)
-	us.SetPassword(h.userpass)
-	t, err := h.users.Login(ctx, &us)
-	if err != nil {
-		return "", err
-	}
-	return t.GetToken(), nil
-}
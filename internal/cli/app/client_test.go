@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIdentity_Authenticate_ShouldNotSendPasswordWhenHandshakeRefused(t *testing.T) {
+	users := &refusingUsersClient{}
+	creds := &identity{users: users, login: "dima", pass: "qwerty12"}
+
+	_, err := creds.authenticate(context.Background())
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.False(t, users.passwordSent)
+}
+
+func TestIdentity_LegacyLogin_ShouldUpgradeAccount(t *testing.T) {
+	users := &refusingUsersClient{}
+	creds := &identity{users: users, login: "dima", pass: "short"}
+
+	_, err := creds.legacyLogin(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, users.passwordSent)
+	assert.NotEmpty(t, users.verifier)
+}
+
+// refusingUsersClient server refusing every handshake, as an impostor downgrading the client would
+func TestNewVerifier_ShouldRefuseWeakPassword(t *testing.T) {
+	_, _, short := newVerifier("dima", "qwerty")
+	_, _, asLogin := newVerifier("Dima1234", "dIMA1234")
+	_, verifier, err := newVerifier("dima", "qwerty12")
+
+	assert.ErrorIs(t, short, ErrWeakPassword)
+	assert.ErrorIs(t, asLogin, ErrPasswordEqualsLogin)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, verifier)
+}
+
+type refusingUsersClient struct {
+	pb.UsersClient
+	passwordSent bool
+	verifier     []byte
+}
+
+func (c *refusingUsersClient) BeginLogin(context.Context, *pb.BeginLoginRequest, ...grpc.CallOption) (*pb.BeginLoginResponse, error) {
+	return nil, status.Error(codes.FailedPrecondition, "account has no verifier yet")
+}
+
+func (c *refusingUsersClient) Login(_ context.Context, in *pb.User, _ ...grpc.CallOption) (*pb.UserResponse, error) {
+	c.passwordSent = in.GetPassword() != ""
+	c.verifier = in.GetVerifier()
+	return &pb.UserResponse{}, nil
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/datatool"
+)
+
+var ErrUnknownVersion = errors.New("unknown conflict strategy, use local, remote, newest or both")
+
+// ParseVersion parse name of conflict strategy
+func ParseVersion(name string) (Version, error) {
+	switch name {
+	case "local":
+		return Local, nil
+	case "remote":
+		return Remote, nil
+	case "newest":
+		return Newest, nil
+	case "both":
+		return Both, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnknownVersion, name)
+}
+
+func (v Version) String() string {
+	switch v {
+	case Local:
+		return "local"
+	case Remote:
+		return "remote"
+	case Newest:
+		return "newest"
+	case Both:
+		return "both"
+	}
+	return ""
+}
+
+// conflictResolver apply chosen version of conflicting secret
+type conflictResolver struct {
+	encoder core.Encoder
+	decoder core.Decoder
+	fp      *datatool.FileProvider
+}
+
+func newConflictResolver(encoder core.Encoder, decoder core.Decoder, fp *datatool.FileProvider) *conflictResolver {
+	return &conflictResolver{encoder: encoder, decoder: decoder, fp: fp}
+}
+
+// solve apply version and delete conflict. Chosen records stay newer than sync state and are pushed on next sync
+func (cr *conflictResolver) solve(ctx context.Context, tx *sql.Tx, version Version, conflict *core.Conflict) error {
+	var err error
+	if version == Newest {
+		version = newest(conflict)
+	}
+	switch version {
+	case Local:
+		err = cr.applyLocal(ctx, tx, conflict)
+	case Remote:
+		err = cr.applyRemote(ctx, tx, conflict)
+	case Both:
+		err = cr.applyBoth(ctx, tx, conflict)
+	default:
+		err = ErrUnknownVersion
+	}
+	if err != nil {
+		return err
+	}
+	return persistence.TxDeleteConflict(ctx, tx, conflict.ID)
+}
+
+// solveAll apply version to every conflict, returns count of solved conflicts
+func (cr *conflictResolver) solveAll(ctx context.Context, tx *sql.Tx, version Version) (int, error) {
+	conflicts, err := persistence.TxGetAllConflict(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	for _, conflict := range conflicts {
+		if err = cr.solve(ctx, tx, version, conflict); err != nil {
+			return 0, fmt.Errorf("failed to solve conflict of secret %s: %w", conflict.RecordID, err)
+		}
+	}
+	return len(conflicts), nil
+}
+
+func (cr *conflictResolver) applyLocal(ctx context.Context, tx *sql.Tx, conflict *core.Conflict) error {
+	local := conflict.Local.Record
+	remote := conflict.Remote.Record
+	if remote.BigData && !conflict.Remote.Deleted {
+		if err := cr.fp.Remove(remote.ID, remote.Version, "remote"); err != nil {
+			return err
+		}
+	}
+	if err := persistence.TxUpdateRecord(ctx, tx, local); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cr *conflictResolver) applyRemote(ctx context.Context, tx *sql.Tx, conflict *core.Conflict) error {
+	local := conflict.Local.Record
+	remote := conflict.Remote.Record
+	remote.Deleted = conflict.Remote.Deleted
+	if local.BigData {
+		if err := cr.fp.Remove(local.ID, local.Version); err != nil {
+			return err
+		}
+	}
+	if remote.BigData && !remote.Deleted {
+		if err := cr.copyFile(remote); err != nil {
+			return err
+		}
+	}
+	if err := persistence.TxUpdateRecord(ctx, tx, remote); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyBoth keep local version and save remote version as a new secret
+func (cr *conflictResolver) applyBoth(ctx context.Context, tx *sql.Tx, conflict *core.Conflict) error {
+	if conflict.Remote.Deleted {
+		return cr.applyLocal(ctx, tx, conflict)
+	}
+	if conflict.Local.Deleted {
+		return cr.applyRemote(ctx, tx, conflict)
+	}
+	if err := cr.copyRecord(ctx, tx, conflict.Remote.Record); err != nil {
+		return err
+	}
+	return cr.applyLocal(ctx, tx, conflict)
+}
+
+// copyRecord re-encrypt record under a new identity
+func (cr *conflictResolver) copyRecord(ctx context.Context, tx *sql.Tx, source *core.Record) error {
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		return err
+	}
+	dek, err := source.DecodeDek(cr.decoder, masterKey)
+	if err != nil {
+		return err
+	}
+	data, err := source.DecodeData(cr.decoder, dek)
+	if err != nil {
+		return err
+	}
+	record := core.CreateRecord(source.Type)
+	record.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	record.ModifiedAt = source.ModifiedAt
+	record.BigData = source.BigData
+	record.Version = source.Version
+	record.Vault = source.Vault
+	newDek, err := datatool.GenerateDek(32)
+	if err != nil {
+		return err
+	}
+	if err = record.Encode(cr.encoder, data, newDek, masterKey); err != nil {
+		return err
+	}
+	if record.BigData {
+		if err = reencryptFile(cr.fp, cr.encoder, cr.decoder, source, dek, record, newDek, "remote"); err != nil {
+			return err
+		}
+	}
+	return persistence.TxInsertRecord(ctx, tx, record)
+}
+
+func (cr *conflictResolver) copyFile(record *core.Record) error {
+	var reader io.ReadCloser
+	var writer io.WriteCloser
+	var err error
+	reader, err = cr.fp.OpenRead(record.ID, record.Version, "remote")
+	if err != nil {
+		return err
+	}
+	writer, err = cr.fp.OpenWrite(record.ID, record.Version)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(writer, reader); err != nil {
+		return err
+	}
+
+	if err = reader.Close(); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	if err = cr.fp.Remove(record.ID, record.Version, "remote"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// newest version modified last, remote wins when both are modified at the same time
+func newest(conflict *core.Conflict) Version {
+	if conflict.Local.Record.ModifiedAt.After(conflict.Remote.Record.ModifiedAt) {
+		return Local
+	}
+	return Remote
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, version := range []Version{Local, Remote, Newest, Both} {
+		parsed, err := ParseVersion(version.String())
+		assert.NoError(t, err)
+		assert.Equal(t, version, parsed)
+	}
+	_, err := ParseVersion("mine")
+	assert.ErrorIs(t, err, ErrUnknownVersion)
+}
+
+func TestSolveConflictNewestShouldTakeRemote(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	conflict := prepareConflict(ctx, t, manager, time.Hour)
+
+	err := manager.SolveConflict(ctx, Newest, conflict)
+
+	assert.NoError(t, err)
+	record, err := persistence.GetRecordByID(ctx, manager.db, conflict.RecordID)
+	assert.NoError(t, err)
+	masterKey, _ := common.GetMasterKey(ctx)
+	lp, err := record.DecodeLoginPass(manager.decoder, masterKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "remote", lp.Pass)
+	conflicts, err := manager.GetAllConflicts(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
+
+func TestSolveConflictBothShouldKeepRemoteAsNewSecret(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	conflict := prepareConflict(ctx, t, manager, -time.Hour)
+
+	err := manager.SolveConflict(ctx, Both, conflict)
+
+	assert.NoError(t, err)
+	records, err := manager.GetAll(ctx, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	masterKey, _ := common.GetMasterKey(ctx)
+	passes := make([]string, 0, len(records))
+	for _, record := range records {
+		lp, err := record.DecodeLoginPass(manager.decoder, masterKey)
+		assert.NoError(t, err)
+		passes = append(passes, lp.Pass)
+	}
+	assert.ElementsMatch(t, []string{"Pass", "remote"}, passes)
+}
+
+// prepareConflict create secret and conflict with remote version modified with offset
+func prepareConflict(ctx context.Context, t *testing.T, manager *DataManager, offset time.Duration) *core.Conflict {
+	id, err := createLoginPass(ctx, manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := persistence.GetRecordByID(ctx, manager.db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&core.LoginPass{Name: "Test", Login: "Login", Pass: "remote", URL: "http:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dek, err := datatool.GenerateDek(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := *local
+	remote.Version = local.Version + 1
+	remote.ModifiedAt = local.ModifiedAt.Add(offset)
+	if err = remote.Encode(manager.encoder, data, dek, masterKey); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := manager.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = persistence.TxInsertConflict(ctx, tx, &core.Conflict{
+		RecordID: id,
+		Local:    &core.ConflictItem{Record: local},
+		Remote:   &core.ConflictItem{Record: &remote},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := manager.GetAllConflicts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conflicts[0]
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+)
+
+type DaemonState string
+
+const (
+	DaemonIdle    DaemonState = "idle"
+	DaemonBackoff DaemonState = "backoff"
+	DaemonPaused  DaemonState = "paused"
+	DaemonStopped DaemonState = "stopped"
+)
+
+type (
+	// DaemonOption auto-sync settings
+	DaemonOption struct {
+		// Interval between regular syncs
+		Interval time.Duration
+		// CheckInterval between checks of local changes and conflicts
+		CheckInterval time.Duration
+		MinBackoff    time.Duration
+		MaxBackoff    time.Duration
+	}
+	// DaemonStatus state of auto-sync shared with keeper status
+	DaemonStatus struct {
+		PID         int         `json:"pid"`
+		State       DaemonState `json:"state"`
+		LastSync    time.Time   `json:"last_sync"`
+		LastError   string      `json:"last_error,omitempty"`
+		Failures    int         `json:"failures"`
+		NextAttempt time.Time   `json:"next_attempt"`
+		UpdatedAt   time.Time   `json:"updated_at"`
+	}
+	// LocalStatus sync state of local storage
+	LocalStatus struct {
+		Version   int32
+		Pending   int64
+		Conflicts int64
+	}
+)
+
+// RemoteSyncer syncer which can tell remote server availability
+type RemoteSyncer interface {
+	Syncer
+	IsHealthy(ctx context.Context) error
+}
+
+// Daemon run sync regularly and after local writes
+type Daemon struct {
+	db     *sql.DB
+	syncer RemoteSyncer
+	option *DaemonOption
+	status *DaemonStatus
+}
+
+func NewDaemon(db *sql.DB, syncer RemoteSyncer, option *DaemonOption) *Daemon {
+	return &Daemon{
+		db:     db,
+		syncer: syncer,
+		option: option,
+		status: &DaemonStatus{
+			PID:   os.Getpid(),
+			State: DaemonIdle,
+		},
+	}
+}
+
+// Run sync until context is done
+func (d *Daemon) Run(ctx context.Context) error {
+	d.status.NextAttempt = time.Now()
+	defer func() {
+		d.status.State = DaemonStopped
+		if err := d.save(context.WithoutCancel(ctx)); err != nil {
+			fmt.Printf("failed to save daemon status: %s\n", err)
+		}
+	}()
+	for {
+		if err := d.step(ctx, time.Now()); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(d.option.CheckInterval):
+		}
+	}
+}
+
+// step sync when it is time or local changes appeared
+func (d *Daemon) step(ctx context.Context, now time.Time) error {
+	conflict, err := persistence.ConflictExist(ctx, d.db)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		// sync resumes once conflicts are resolved
+		d.status.State = DaemonPaused
+		return d.save(ctx)
+	}
+	if d.status.State == DaemonPaused {
+		d.status.State = DaemonIdle
+		d.status.NextAttempt = now
+	}
+	if d.status.State == DaemonIdle && now.Before(d.status.NextAttempt) {
+		pending, err := d.pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			d.status.NextAttempt = now
+		}
+	}
+	if now.Before(d.status.NextAttempt) {
+		return d.save(ctx)
+	}
+	if err = d.sync(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, ErrConflictData) {
+			d.status.State = DaemonPaused
+			d.status.LastError = err.Error()
+			return d.save(ctx)
+		}
+		d.status.Failures++
+		d.status.State = DaemonBackoff
+		d.status.LastError = err.Error()
+		d.status.NextAttempt = now.Add(backoff(d.option.MinBackoff, d.option.MaxBackoff, d.status.Failures))
+		fmt.Printf("sync failed: %s. next attempt at %s\n", err, d.status.NextAttempt.Format(time.RFC3339))
+		return d.save(ctx)
+	}
+	d.status.Failures = 0
+	d.status.State = DaemonIdle
+	d.status.LastError = ""
+	d.status.LastSync = now
+	d.status.NextAttempt = now.Add(d.option.Interval)
+	return d.save(ctx)
+}
+
+func (d *Daemon) sync(ctx context.Context) error {
+	if err := d.syncer.IsHealthy(ctx); err != nil {
+		return err
+	}
+	return d.syncer.Sync(ctx, &SyncOption{})
+}
+
+func (d *Daemon) pending(ctx context.Context) (int64, error) {
+	syncState, err := getState(ctx, d.db)
+	if err != nil {
+		return -1, err
+	}
+	return persistence.CountRecordGreater(ctx, d.db, syncState.Value)
+}
+
+// GetLocalStatus read synced version, count of local changes and conflicts
+func GetLocalStatus(ctx context.Context, db *sql.DB) (*LocalStatus, error) {
+	syncState, err := getState(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := persistence.CountRecordGreater(ctx, db, syncState.Value)
+	if err != nil {
+		return nil, err
+	}
+	conflicts, err := persistence.GetConflictCount(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalStatus{Version: syncState.Value, Pending: pending, Conflicts: conflicts}, nil
+}
+
+func (d *Daemon) save(ctx context.Context) error {
+	d.status.UpdatedAt = time.Now()
+	js, err := json.Marshal(d.status)
+	if err != nil {
+		return err
+	}
+	return persistence.SaveSetting(ctx, d.db, persistence.DaemonStatusSetting, string(js))
+}
+
+// GetDaemonStatus read status saved by daemon, nil if daemon never ran
+func GetDaemonStatus(ctx context.Context, db *sql.DB) (*DaemonStatus, error) {
+	js, err := persistence.GetSetting(ctx, db, persistence.DaemonStatusSetting)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var status DaemonStatus
+	if err = json.Unmarshal([]byte(js), &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// backoff exponential delay with jitter, in range of [delay/2, delay]
+func backoff(minDelay, maxDelay time.Duration, failures int) time.Duration {
+	delay := minDelay
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
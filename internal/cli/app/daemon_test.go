@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffShouldStayInBounds(t *testing.T) {
+	minDelay, maxDelay := time.Second, time.Minute
+	for failures := 1; failures < 20; failures++ {
+		delay := backoff(minDelay, maxDelay, failures)
+		expected := min(minDelay<<(failures-1), maxDelay)
+		assert.GreaterOrEqual(t, delay, expected/2)
+		assert.LessOrEqual(t, delay, expected)
+	}
+}
+
+func TestDaemonStepShouldBackoffWhileServerUnavailable(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	syncer := &fakeRemoteSyncer{healthErr: ErrServerUnavailable}
+	daemon := NewDaemon(manager.db, syncer, &DaemonOption{
+		Interval:   time.Minute,
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+	})
+	now := time.Now()
+
+	err := daemon.step(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, DaemonBackoff, daemon.status.State)
+	assert.Equal(t, 1, daemon.status.Failures)
+	assert.True(t, daemon.status.NextAttempt.After(now))
+	assert.Equal(t, 0, syncer.syncs)
+
+	// no request until the next attempt
+	assert.NoError(t, daemon.step(ctx, now))
+	assert.Equal(t, 0, syncer.syncs)
+
+	syncer.healthErr = nil
+	err = daemon.step(ctx, daemon.status.NextAttempt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, DaemonIdle, daemon.status.State)
+	assert.Equal(t, 0, daemon.status.Failures)
+	assert.Equal(t, 1, syncer.syncs)
+	status, err := GetDaemonStatus(ctx, manager.db)
+	assert.NoError(t, err)
+	assert.Equal(t, DaemonIdle, status.State)
+}
+
+func TestDaemonStepShouldPauseOnConflict(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	syncer := &fakeRemoteSyncer{}
+	daemon := NewDaemon(manager.db, syncer, &DaemonOption{Interval: time.Minute})
+	tx, err := manager.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = persistence.TxInsertConflict(ctx, tx, &core.Conflict{
+		RecordID: "record",
+		Local:    &core.ConflictItem{},
+		Remote:   &core.ConflictItem{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		conflicts, _ := persistence.GetAllConflict(ctx, manager.db)
+		for _, conflict := range conflicts {
+			_ = persistence.DeleteConflict(ctx, manager.db, conflict.ID)
+		}
+	}()
+
+	err = daemon.step(ctx, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, DaemonPaused, daemon.status.State)
+	assert.Equal(t, 0, syncer.syncs)
+}
+
+type fakeRemoteSyncer struct {
+	healthErr error
+	syncs     int
+}
+
+func (s *fakeRemoteSyncer) Sync(ctx context.Context, option *SyncOption) error {
+	s.syncs++
+	return nil
+}
+
+func (s *fakeRemoteSyncer) IsHealthy(ctx context.Context) error {
+	return s.healthErr
+}
@@ -21,6 +21,7 @@ import (
 var (
 	ErrFileToBig      = errors.New("file is too big")
 	ErrConflictExists = errors.New("conflict exists! solve first")
+	ErrLegacyRecord   = errors.New("record is sealed by an old version of keeper, run keeper reseal first")
 )
 
 type Version int
@@ -29,6 +30,10 @@ const (
 	_ Version = iota
 	Local
 	Remote
+	// Newest version modified last
+	Newest
+	// Both keep local version and save remote version as a new secret
+	Both
 )
 
 type (
@@ -64,6 +69,7 @@ type DataManager struct {
 	decoder     core.Decoder
 	syncManager Syncer
 	fp          *datatool.FileProvider
+	resolver    *conflictResolver
 }
 
 func NewDataService(
@@ -79,6 +85,7 @@ func NewDataService(
 		decoder,
 		syncManager,
 		fileProvider,
+		newConflictResolver(encoder, decoder, fileProvider),
 	}
 }
 
@@ -106,7 +113,7 @@ func (dm *DataManager) ExtractFile(ctx context.Context, record *core.Record) (*c
 	if err != nil {
 		return nil, nil, err
 	}
-	dek, err := dm.decoder.Decode(record.Dek, masterKey)
+	dek, err := record.DecodeDek(dm.decoder, masterKey)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -115,7 +122,7 @@ func (dm *DataManager) ExtractFile(ctx context.Context, record *core.Record) (*c
 	if err != nil {
 		return nil, nil, err
 	}
-	return md, crypto.NewFileDecoder(dm.decoder, fs, dek), nil
+	return md, fileDecoder(dm.decoder, record, fs, dek), nil
 }
 
 // GetAllConflicts get all conflicts
@@ -132,6 +139,11 @@ func (dm *DataManager) Decode(ctx context.Context, record *core.Record) ([]byte,
 	return record.Decode(dm.decoder, masterKey)
 }
 
+// GetConflict get conflict by id
+func (dm *DataManager) GetConflict(ctx context.Context, id int32) (*core.Conflict, error) {
+	return persistence.GetConflictByID(ctx, dm.db, id)
+}
+
 // SolveConflict solve conflict between client version and server version
 func (dm *DataManager) SolveConflict(ctx context.Context, version Version, conflict *core.Conflict) error {
 	tx, err := dm.db.BeginTx(ctx, nil)
@@ -145,17 +157,8 @@ func (dm *DataManager) SolveConflict(ctx context.Context, version Version, confl
 		}
 		_ = tx.Commit()
 	}()
-	switch version {
-	case Local:
-		if err = dm.applyLocal(ctx, tx, conflict); err != nil {
-			return err
-		}
-	case Remote:
-		if err = dm.applyRemote(ctx, tx, conflict); err != nil {
-			return err
-		}
-	}
-	return nil
+	err = dm.resolver.solve(ctx, tx, version, conflict)
+	return err
 }
 
 // DeleteConflict delete conflict
@@ -173,6 +176,9 @@ func (dm *DataManager) CreateLoginPass(ctx context.Context, request *LoginPassRe
 			request,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -190,6 +196,9 @@ func (dm *DataManager) UpdateLoginPass(ctx context.Context, id string, request *
 			request,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -208,6 +217,9 @@ func (dm *DataManager) CreateText(ctx context.Context, request *TextRequest, syn
 			request,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -225,6 +237,9 @@ func (dm *DataManager) UpdateText(ctx context.Context, id string, request *TextR
 			request,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -243,6 +258,9 @@ func (dm *DataManager) CreateBankCard(ctx context.Context, req *BankCardRequest,
 			req,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -260,6 +278,9 @@ func (dm *DataManager) UpdateBankCard(ctx context.Context, id string, req *BankC
 			req,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -278,6 +299,9 @@ func (dm *DataManager) CreateBinary(ctx context.Context, req *BinaryRequest, syn
 			req,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -295,6 +319,9 @@ func (dm *DataManager) UpdateBinary(ctx context.Context, id string, req *BinaryR
 			req,
 		)
 	})
+	if err != nil {
+		return "", err
+	}
 	if sync {
 		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
 			return "", err
@@ -356,11 +383,18 @@ func (dm *DataManager) execUpdate(ctx context.Context, id string, op func(ctx co
 	if ex {
 		return "", ErrConflictExists
 	}
-	version := common.GetVersion(ctx)
 	record, err = persistence.TxGetRecordByID(ctx, tx, id)
 	if err != nil {
 		return "", err
 	}
+	if record.IsShared() {
+		err = ErrSharedRecord
+		return "", err
+	}
+	if ctx, err = dm.scope(ctx, tx, record.Vault); err != nil {
+		return "", err
+	}
+	version := common.GetVersion(ctx)
 	record, err = op(ctx, tx, record)
 	if err != nil {
 		return "", err
@@ -406,25 +440,250 @@ func (dm *DataManager) deleteRecord(ctx context.Context, id string) error {
 	if ex {
 		return ErrConflictExists
 	}
-	version := common.GetVersion(ctx)
-	newVersion := version + 1
 	record, err := persistence.GetRecordByID(ctx, dm.db, id)
 	if err != nil {
 		return err
 	}
+	if record.IsShared() {
+		err = ErrSharedRecord
+		return err
+	}
+	if ctx, err = dm.scope(ctx, tx, record.Vault); err != nil {
+		return err
+	}
+	newVersion := common.GetVersion(ctx) + 1
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		return err
+	}
+	source := *record
+	if err = record.Rebind(dm.encoder, dm.decoder, masterKey, newVersion); err != nil {
+		return err
+	}
 	if record.BigData {
-		if err = dm.fp.Rename(record.ID, record.Version, newVersion); err != nil {
+		if err = rebindFile(dm.fp, dm.encoder, dm.decoder, &source, masterKey, record); err != nil {
 			return err
 		}
 	}
 	record.Deleted = true
-	record.Version = newVersion
 	if _, err = dm.update(ctx, tx, record); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Reseal re-encrypt records and their files sealed before ciphertexts were bound to the record identity,
+// returns number of resealed records. Record keeps its version, server copy is bound on the next change
+func (dm *DataManager) Reseal(ctx context.Context) (int, error) {
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := dm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		_ = tx.Commit()
+	}()
+	records, err := persistence.TxGetOwnRecords(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	resealed := 0
+	for _, record := range records {
+		if !record.Legacy {
+			continue
+		}
+		var dek []byte
+		if dek, err = record.DecodeDek(dm.decoder, masterKey); err != nil {
+			return resealed, err
+		}
+		if record.BigData {
+			if err = dm.resealFile(record, dek); err != nil {
+				return resealed, err
+			}
+		}
+		if err = record.Reseal(dm.encoder, dm.decoder, masterKey); err != nil {
+			return resealed, err
+		}
+		if err = persistence.TxUpdateRecord(ctx, tx, record); err != nil {
+			return resealed, err
+		}
+		resealed++
+	}
+	// merge bases are copies of the same records
+	bases, err := persistence.TxGetAllBaseRecord(ctx, tx)
+	if err != nil {
+		return resealed, err
+	}
+	for _, base := range bases {
+		if !base.Legacy {
+			continue
+		}
+		if _, decodeErr := base.DecodeDek(dm.decoder, masterKey); decodeErr != nil {
+			continue
+		}
+		if err = base.Reseal(dm.encoder, dm.decoder, masterKey); err != nil {
+			return resealed, err
+		}
+		if err = persistence.TxSaveBaseRecord(ctx, tx, base); err != nil {
+			return resealed, err
+		}
+	}
+	return resealed, nil
+}
+
+// resealFile rewrite file of the old format in segments bound to the record identity
+func (dm *DataManager) resealFile(record *core.Record, dek []byte) error {
+	reader, err := dm.fp.OpenRead(record.ID, record.Version)
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(fileDecoder(dm.decoder, record, reader, dek))
+	_ = reader.Close()
+	if err != nil {
+		return err
+	}
+	if err = dm.fp.Remove(record.ID, record.Version); err != nil {
+		return err
+	}
+	return dm.writeFile(content, dek, record)
+}
+
+// MoveToVault move own record to the team vault. Record is copied under a new identity and version
+// of the vault, the original one is deleted
+func (dm *DataManager) MoveToVault(ctx context.Context, id, vaultID string, sync bool) (string, error) {
+	moved, err := dm.moveToVault(ctx, id, vaultID)
+	if err != nil {
+		return "", err
+	}
+	if sync {
+		if err = dm.syncManager.Sync(ctx, &SyncOption{}); err != nil {
+			return "", err
+		}
+	}
+	return moved, nil
+}
+
+func (dm *DataManager) moveToVault(ctx context.Context, id, vaultID string) (string, error) {
+	tx, err := dm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		_ = tx.Commit()
+	}()
+	ex, err := persistence.TxConflictExist(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+	if ex {
+		err = ErrConflictExists
+		return "", err
+	}
+	source, err := persistence.TxGetRecordByID(ctx, tx, id)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case source.Deleted:
+		err = sql.ErrNoRows
+	case source.IsShared():
+		err = ErrSharedRecord
+	case source.InVault():
+		err = ErrVaultRecord
+	}
+	if err != nil {
+		return "", err
+	}
+	vaultCtx, err := dm.scope(ctx, tx, vaultID)
+	if err != nil {
+		return "", err
+	}
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	dek, err := source.DecodeDek(dm.decoder, masterKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := source.DecodeData(dm.decoder, dek)
+	if err != nil {
+		return "", err
+	}
+	record := core.CreateRecord(source.Type)
+	record.BigData = source.BigData
+	record.Vault = vaultID
+	record.Version = common.GetVersion(vaultCtx) + 1
+	newDek, err := datatool.GenerateDek(32)
+	if err != nil {
+		return "", err
+	}
+	if err = record.Encode(dm.encoder, data, newDek, masterKey); err != nil {
+		return "", err
+	}
+	if record.BigData {
+		if err = reencryptFile(dm.fp, dm.encoder, dm.decoder, source, dek, record, newDek); err != nil {
+			return "", err
+		}
+	}
+	if _, err = dm.insert(ctx, tx, record); err != nil {
+		return "", err
+	}
+	// the source record is deleted as usual
+	old := *source
+	newVersion := common.GetVersion(ctx) + 1
+	if err = source.Rebind(dm.encoder, dm.decoder, masterKey, newVersion); err != nil {
+		return "", err
+	}
+	if source.BigData {
+		if err = rebindFile(dm.fp, dm.encoder, dm.decoder, &old, masterKey, source); err != nil {
+			return "", err
+		}
+	}
+	source.Deleted = true
+	if _, err = dm.update(ctx, tx, source); err != nil {
+		return "", err
+	}
+	return record.ID, nil
+}
+
+// scope set version of the sync state the record is changed under. Records of a vault
+// are changed under the state of the vault, readers can't change them
+func (dm *DataManager) scope(ctx context.Context, tx *sql.Tx, vaultID string) (context.Context, error) {
+	if vaultID == "" {
+		return ctx, nil
+	}
+	vault, err := persistence.TxGetVault(ctx, tx, vaultID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVaultNotFound
+		}
+		return nil, err
+	}
+	if !vault.CanWrite() {
+		return nil, ErrVaultReadOnly
+	}
+	state, err := persistence.TxGetState(ctx, tx, vaultStateName(vault.ID))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		state = &core.SyncState{ID: vaultStateName(vault.ID)}
+	}
+	return common.SetVersion(ctx, state.Value), nil
+}
+
 func (dm *DataManager) processLoginPass(ctx context.Context, record *core.Record, data *LoginPassRequest) (*core.Record, error) {
 	masterKey, err := common.GetMasterKey(ctx)
 	if err != nil {
@@ -460,17 +719,10 @@ func (dm *DataManager) processLoginPass(ctx context.Context, record *core.Record
 	if err != nil {
 		return nil, err
 	}
-	cipher, err := dm.encoder.Encode(js, dek)
-	if err != nil {
-		return nil, err
-	}
-	record.Data = cipher
-	dekCipher, err := dm.encoder.Encode(dek, masterKey)
-	if err != nil {
+	record.Version = version + 1
+	if err = record.Encode(dm.encoder, js, dek, masterKey); err != nil {
 		return nil, err
 	}
-	record.Dek = dekCipher
-	record.Version = version + 1
 	return record, nil
 }
 
@@ -503,17 +755,10 @@ func (dm *DataManager) processText(ctx context.Context, record *core.Record, dat
 	if err != nil {
 		return nil, err
 	}
-	cipher, err := dm.encoder.Encode(js, dek)
-	if err != nil {
-		return nil, err
-	}
-	record.Data = cipher
-	dekCipher, err := dm.encoder.Encode(dek, masterKey)
-	if err != nil {
+	record.Version = version + 1
+	if err = record.Encode(dm.encoder, js, dek, masterKey); err != nil {
 		return nil, err
 	}
-	record.Dek = dekCipher
-	record.Version = version + 1
 	return record, nil
 }
 
@@ -565,17 +810,10 @@ func (dm *DataManager) processBankCard(ctx context.Context, record *core.Record,
 	if err != nil {
 		return nil, err
 	}
-	cipher, err := dm.encoder.Encode(js, dek)
-	if err != nil {
-		return nil, err
-	}
-	record.Data = cipher
-	dekCipher, err := dm.encoder.Encode(dek, masterKey)
-	if err != nil {
+	record.Version = version + 1
+	if err = record.Encode(dm.encoder, js, dek, masterKey); err != nil {
 		return nil, err
 	}
-	record.Dek = dekCipher
-	record.Version = version + 1
 	return record, nil
 }
 
@@ -619,8 +857,9 @@ func (dm *DataManager) processBinary(ctx context.Context, record *core.Record, d
 	if err != nil {
 		return nil, err
 	}
+	record.Version = version + 1
 	if record.BigData {
-		if err = dm.writeFile(content, dek, record, version+1); err != nil {
+		if err = dm.writeFile(content, dek, record); err != nil {
 			return nil, err
 		}
 	} else {
@@ -630,114 +869,100 @@ func (dm *DataManager) processBinary(ctx context.Context, record *core.Record, d
 	if err != nil {
 		return nil, err
 	}
-	mdCipher, err := dm.encoder.Encode(js, dek)
-	if err != nil {
+	if err = record.Encode(dm.encoder, js, dek, masterKey); err != nil {
 		return nil, err
 	}
-	record.Data = mdCipher
-	cipherDek, err := dm.encoder.Encode(dek, masterKey)
-	if err != nil {
-		return nil, err
-	}
-	record.Dek = cipherDek
-	record.Version = version + 1
 	return record, nil
 }
 
-func (dm *DataManager) writeFile(content, dek []byte, record *core.Record, version int32) error {
-	f, err := dm.fp.OpenWrite(record.ID, version)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	var cipherData []byte
-	cipherData, err = dm.encoder.Encode(content, dek)
+func (dm *DataManager) writeFile(content, dek []byte, record *core.Record) error {
+	f, err := dm.fp.OpenWrite(record.ID, record.Version)
 	if err != nil {
 		return err
 	}
-	if _, err = f.Write(cipherData); err != nil {
+	w := crypto.NewFileEncoder(dm.encoder, f, dek, record.AdditionalData(core.FileRole))
+	if _, err = w.Write(content); err != nil {
+		_ = w.Close()
 		return err
 	}
-	return nil
-}
-
-func (dm *DataManager) insert(ctx context.Context, tx *sql.Tx, record *core.Record) (string, error) {
-	date := time.Now().UTC().Truncate(time.Second)
-	record.CreatedAt = date
-	record.ModifiedAt = date
-	if err := persistence.TxInsertRecord(ctx, tx, record); err != nil {
-		return "", err
-	}
-	return record.ID, nil
+	return w.Close()
 }
 
-func (dm *DataManager) update(ctx context.Context, tx *sql.Tx, record *core.Record) (*core.Record, error) {
-	record.ModifiedAt = time.Now().UTC().Truncate(time.Second)
-	if err := persistence.TxUpdateRecord(ctx, tx, record); err != nil {
-		return nil, err
+// fileDecoder only a local legacy record may have a file of the old format
+func fileDecoder(decoder core.Decoder, record *core.Record, fs io.ReadCloser, dek []byte) io.ReadCloser {
+	if record.Legacy {
+		return crypto.NewLegacyFileDecoder(decoder, fs, dek, record.AdditionalData(core.FileRole))
 	}
-	return record, nil
+	return crypto.NewFileDecoder(decoder, fs, dek, record.AdditionalData(core.FileRole))
 }
 
-func (dm *DataManager) applyLocal(ctx context.Context, tx *sql.Tx, conflict *core.Conflict) error {
-	local := conflict.Local.Record
-	remote := conflict.Remote.Record
-	if remote.BigData {
-		if err := dm.fp.Remove(remote.ID, remote.Version, "remote"); err != nil {
-			return err
-		}
-	}
-	if err := persistence.TxUpdateRecord(ctx, tx, local); err != nil {
+// reencryptFile re-encrypt file of the source record, read from dir, as a file of the target record
+func reencryptFile(
+	fp *datatool.FileProvider,
+	encoder core.Encoder,
+	decoder core.Decoder,
+	source *core.Record,
+	dek []byte,
+	target *core.Record,
+	targetDek []byte,
+	dir ...string,
+) error {
+	reader, err := fp.OpenRead(source.ID, source.Version, dir...)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func (dm *DataManager) applyRemote(ctx context.Context, tx *sql.Tx, conflict *core.Conflict) error {
-	local := conflict.Local.Record
-	remote := conflict.Remote.Record
-	if local.BigData {
-		if err := dm.fp.Remove(local.ID, local.Version); err != nil {
-			return err
-		}
+	defer func(reader io.ReadCloser) {
+		_ = reader.Close()
+	}(reader)
+	if err = fp.Remove(target.ID, target.Version); err != nil && !os.IsNotExist(err) {
+		return err
 	}
-	if remote.BigData {
-		if err := dm.copyFile(remote); err != nil {
-			return err
-		}
+	writer, err := fp.OpenWrite(target.ID, target.Version)
+	if err != nil {
+		return err
 	}
-	if err := persistence.TxUpdateRecord(ctx, tx, remote); err != nil {
+	dst := crypto.NewFileEncoder(encoder, writer, targetDek, target.AdditionalData(core.FileRole))
+	if _, err = io.Copy(dst, fileDecoder(decoder, source, reader, dek)); err != nil {
+		_ = dst.Close()
 		return err
 	}
-	return nil
+	return dst.Close()
 }
 
-func (dm *DataManager) copyFile(record *core.Record) error {
-	var reader io.ReadCloser
-	var writer io.WriteCloser
-	var err error
-	// копирую два файла
-	reader, err = dm.fp.OpenRead(record.ID, record.Version, "remote")
-	if err != nil {
-		return err
-	}
-	writer, err = dm.fp.OpenWrite(record.ID, record.Version)
+// rebindFile move file of the record to its new version. Dek is kept, so the file is sealed again
+// under the new version, source is the record before rebind with dek wrapped by key
+func rebindFile(
+	fp *datatool.FileProvider,
+	encoder core.Encoder,
+	decoder core.Decoder,
+	source *core.Record,
+	key []byte,
+	target *core.Record,
+) error {
+	dek, err := source.DecodeDek(decoder, key)
 	if err != nil {
 		return err
 	}
-	if _, err = io.Copy(writer, reader); err != nil {
+	if err = reencryptFile(fp, encoder, decoder, source, dek, target, dek); err != nil {
 		return err
 	}
+	return fp.Remove(source.ID, source.Version)
+}
 
-	if err = reader.Close(); err != nil {
-		return err
-	}
-	if err = writer.Close(); err != nil {
-		return err
+func (dm *DataManager) insert(ctx context.Context, tx *sql.Tx, record *core.Record) (string, error) {
+	date := time.Now().UTC().Truncate(time.Second)
+	record.CreatedAt = date
+	record.ModifiedAt = date
+	if err := persistence.TxInsertRecord(ctx, tx, record); err != nil {
+		return "", err
 	}
+	return record.ID, nil
+}
 
-	if err = dm.fp.Remove(record.ID, record.Version, "remote"); err != nil {
-		return err
+func (dm *DataManager) update(ctx context.Context, tx *sql.Tx, record *core.Record) (*core.Record, error) {
+	record.ModifiedAt = time.Now().UTC().Truncate(time.Second)
+	if err := persistence.TxUpdateRecord(ctx, tx, record); err != nil {
+		return nil, err
 	}
-	return nil
+	return record, nil
 }
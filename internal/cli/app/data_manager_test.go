@@ -4,11 +4,15 @@ import (
 	"context"
 	"crypto/rand"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
 	"github.com/DimKa163/keeper/internal/cli/crypto"
 	"github.com/DimKa163/keeper/internal/cli/persistence"
 	"github.com/DimKa163/keeper/internal/datatool"
@@ -275,7 +279,7 @@ func TestUpdateBankCardShouldBeSuccess(t *testing.T) {
 
 func TestCreateBigBinaryShouldBeSuccess(t *testing.T) {
 	ctx, manager, cleanUp := configure(t)
-	filePath := fmt.Sprintf("%s\\%s", manager.fp.Path, "binary.bin")
+	filePath := filepath.Join(manager.fp.Path, "binary.bin")
 	file, err := os.Create(filePath)
 	if err != nil {
 		t.Fatal(err)
@@ -316,7 +320,7 @@ func TestCreateBigBinaryShouldBeSuccess(t *testing.T) {
 
 func TestCreateNotBigBinaryShouldBeSuccess(t *testing.T) {
 	ctx, manager, cleanUp := configure(t)
-	filePath := fmt.Sprintf("%s\\%s", manager.fp.Path, "binary.bin")
+	filePath := filepath.Join(manager.fp.Path, "binary.bin")
 	file, err := os.Create(filePath)
 	if err != nil {
 		t.Fatal(err)
@@ -357,13 +361,13 @@ func TestCreateNotBigBinaryShouldBeSuccess(t *testing.T) {
 
 func TestUpdateBinaryShouldBeSuccess(t *testing.T) {
 	ctx, manager, cleanUp := configure(t)
-	filePath := fmt.Sprintf("%s\\%s", manager.fp.Path, "TestUpdateBinaryShouldBeSuccess_old.bin")
+	filePath := filepath.Join(manager.fp.Path, "TestUpdateBinaryShouldBeSuccess_old.bin")
 
 	id, err := createBinaryFile(ctx, filePath, manager)
 	if err != nil {
 		t.Fatal(err)
 	}
-	filePath = fmt.Sprintf("%s\\%s", manager.fp.Path, "TestUpdateBinaryShouldBeSuccess_new.bin")
+	filePath = filepath.Join(manager.fp.Path, "TestUpdateBinaryShouldBeSuccess_new.bin")
 	file, err := os.Create(filePath)
 	if err != nil {
 		t.Fatal(err)
@@ -403,6 +407,132 @@ func TestUpdateBinaryShouldBeSuccess(t *testing.T) {
 	}
 }
 
+func TestResealShouldBindLegacyRecordAndFile(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// legacy format: no associated data, file in one block
+	content := make([]byte, datatool.MB*2)
+	if _, err = rand.Read(content); err != nil {
+		t.Fatal(err)
+	}
+	dek, err := datatool.GenerateDek(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := core.CreateRecord(core.OtherType)
+	record.BigData = true
+	record.Version = 1
+	record.Legacy = true
+	js, err := json.Marshal(core.Binary{Name: "binary.bin", SizeBytes: int64(len(content))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Data, err = manager.encoder.Encode(js, dek, nil); err != nil {
+		t.Fatal(err)
+	}
+	if record.Dek, err = manager.encoder.Encode(dek, masterKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := manager.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = persistence.TxInsertRecord(ctx, tx, record); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	cipherFile, err := manager.encoder.Encode(content, dek, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := manager.fp.OpenWrite(record.ID, record.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = file.Write(cipherFile)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	stored, err := manager.Get(ctx, record.ID)
+	assert.NoError(t, err)
+	readFile := func(stored *core.Record) []byte {
+		md, reader, err := manager.ExtractFile(ctx, stored)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		assert.Equal(t, "binary.bin", md.Name)
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		return data
+	}
+	assert.Equal(t, content, readFile(stored))
+	assert.True(t, stored.Legacy)
+
+	resealed, err := manager.Reseal(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resealed)
+
+	stored, err = manager.Get(ctx, record.ID)
+	assert.NoError(t, err)
+	_, err = manager.decoder.Decode(stored.Dek, masterKey, stored.AdditionalData(core.DekRole))
+	assert.NoError(t, err)
+	assert.Equal(t, content, readFile(stored))
+	assert.False(t, stored.Legacy)
+	resealed, err = manager.Reseal(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, resealed)
+
+	if err := manager.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanUp(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteBigBinaryShouldSealFileUnderNewVersion(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	id, err := createBinaryFile(ctx, filepath.Join(manager.fp.Path, "binary.bin"), manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := manager.Get(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, reader, err := manager.ExtractFile(ctx, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+
+	assert.NoError(t, manager.Delete(common.SetVersion(ctx, created.Version), id, false))
+
+	deleted, err := manager.Get(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Greater(t, deleted.Version, created.Version)
+	assert.ErrorIs(t, manager.fp.IsExist(id, created.Version), os.ErrNotExist)
+	_, reader, err = manager.ExtractFile(ctx, deleted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+}
+
 func configure(t *testing.T) (context.Context, *DataManager, func() error) {
 	masterKey := make([]byte, 32)
 
@@ -415,14 +545,11 @@ func configure(t *testing.T) (context.Context, *DataManager, func() error) {
 	encoder := crypto.NewAesEncoder()
 
 	decoder := crypto.NewAesDecoder()
-	if err := os.Mkdir("test", os.ModePerm); err != nil {
-		t.Fatal(err)
-	}
-	path := "./test"
+	path := t.TempDir()
 
 	fileProvider := datatool.NewFileProvider(path)
 
-	db, err := sql.Open("sqlite", "file:memdb1?mode=memory&cache=shared")
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
 	if err != nil {
 		t.Fatal(err)
 	}
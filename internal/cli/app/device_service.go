@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/pb"
+)
+
+// DeviceService manage devices of the user registered on remote server
+type DeviceService struct {
+	client   *RemoteClient
+	db       *sql.DB
+	serverID int32
+}
+
+func NewDeviceService(client *RemoteClient, db *sql.DB, serverID int32) *DeviceService {
+	return &DeviceService{client: client, db: db, serverID: serverID}
+}
+
+// Load sign logins with the device key registered before
+func (ds *DeviceService) Load(ctx context.Context) error {
+	device, err := persistence.GetDevice(ctx, ds.db, ds.serverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	ds.client.SetDevice(device)
+	return nil
+}
+
+// Ensure register the installation as a device on first use, logins are signed with the device key after
+func (ds *DeviceService) Ensure(ctx context.Context) error {
+	device, err := persistence.GetDevice(ctx, ds.db, ds.serverID)
+	if err == nil {
+		ds.client.SetDevice(device)
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	name, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	device, pending, err := ds.client.RegisterDevice(ctx, ds.serverID, name)
+	if err != nil {
+		return err
+	}
+	if err = persistence.SaveDevice(ctx, ds.db, device); err != nil {
+		return err
+	}
+	if pending {
+		fmt.Printf("device %s waits for approval, run 'keeper devices approve %s' on another device\n", device.ID, device.ID)
+	}
+	ds.client.SetDevice(device)
+	return nil
+}
+
+func (ds *DeviceService) List(ctx context.Context) ([]*core.DeviceInfo, error) {
+	res, err := ds.client.DevicesClient.List(ctx, &pb.ListDevicesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*core.DeviceInfo, len(res.GetDevices()))
+	for i, device := range res.GetDevices() {
+		devices[i] = toDeviceInfo(device)
+	}
+	return devices, nil
+}
+
+func (ds *DeviceService) Rename(ctx context.Context, id, name string) (*core.DeviceInfo, error) {
+	var req pb.RenameDeviceRequest
+	req.SetId(id)
+	req.SetName(name)
+	device, err := ds.client.Rename(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	return toDeviceInfo(device), nil
+}
+
+// Revoke revoked device can neither login nor use tokens issued before
+func (ds *DeviceService) Revoke(ctx context.Context, id string) error {
+	var req pb.RevokeDeviceRequest
+	req.SetId(id)
+	_, err := ds.client.DevicesClient.Revoke(ctx, &req)
+	return err
+}
+
+// Approve let pending device login, the installation must be an approved device itself
+func (ds *DeviceService) Approve(ctx context.Context, id string) error {
+	var req pb.ApproveDeviceRequest
+	req.SetId(id)
+	_, err := ds.client.DevicesClient.Approve(ctx, &req)
+	return err
+}
+
+func toDeviceInfo(device *pb.Device) *core.DeviceInfo {
+	return &core.DeviceInfo{
+		ID:          device.GetId(),
+		Name:        device.GetName(),
+		CreatedAt:   device.GetCreatedAt().AsTime(),
+		LastSeenAt:  device.GetLastSeenAt().AsTime(),
+		SyncVersion: device.GetSyncVersion(),
+		Revoked:     device.GetRevoked(),
+		Pending:     device.GetPending(),
+		Current:     device.GetCurrent(),
+	}
+}
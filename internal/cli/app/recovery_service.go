@@ -0,0 +1,319 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrRecoveryNotConfigured = errors.New("recovery is not configured, run recovery split first")
+	ErrInvalidRecoveryShares = errors.New("shares don't restore the recovery key")
+)
+
+// RecoveryStore keeper of the wrapped master key on the server, so shares restore access on a new device
+type RecoveryStore interface {
+	SaveRecovery(ctx context.Context, recovery []byte) error
+	LoadRecovery(ctx context.Context) ([]byte, error)
+}
+
+type RecoveryService struct {
+	db      *sql.DB
+	encoder core.Encoder
+	decoder core.Decoder
+	fp      *datatool.FileProvider
+	store   RecoveryStore
+	syncer  Syncer
+}
+
+func NewRecoveryService(
+	db *sql.DB,
+	encoder core.Encoder,
+	decoder core.Decoder,
+	fileProvider *datatool.FileProvider,
+) *RecoveryService {
+	return &RecoveryService{
+		db:      db,
+		encoder: encoder,
+		decoder: decoder,
+		fp:      fileProvider,
+	}
+}
+
+// SetRemote keep the wrapped master key on the server too. Syncer pulls secrets when restoring on a new device
+func (rs *RecoveryService) SetRemote(store RecoveryStore, syncer Syncer) {
+	rs.store = store
+	rs.syncer = syncer
+}
+
+// Split generate a new recovery key, wrap master key with it and split the recovery key into shares.
+// Shares of a previous split stop working
+func (rs *RecoveryService) Split(ctx context.Context, shares, threshold int) ([][]byte, error) {
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := rs.user(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recoveryKey, err := datatool.GenerateDek(32)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := crypto.Split(recoveryKey, shares, threshold)
+	if err != nil {
+		return nil, err
+	}
+	key, err := rs.encoder.Encode(masterKey, recoveryKey, recoveryAdditionalData(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	recovery := &core.Recovery{
+		UserID:    user.ID,
+		Key:       key,
+		Shares:    shares,
+		Threshold: threshold,
+	}
+	if err = persistence.SaveRecovery(ctx, rs.db, recovery); err != nil {
+		return nil, err
+	}
+	if err = rs.push(ctx, recovery); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// Restore rebuild recovery key from shares and re-encrypt every secret with a new master key.
+// On a device without local recovery the wrapped key is fetched from the server and secrets are pulled first
+func (rs *RecoveryService) Restore(ctx context.Context, shares [][]byte, pass string) error {
+	recoveryKey, err := crypto.Combine(shares)
+	if err != nil {
+		return err
+	}
+	user, err := rs.user(ctx)
+	if err != nil {
+		return err
+	}
+	recovery, err := rs.load(ctx, user)
+	if err != nil {
+		return err
+	}
+	oldKey, err := rs.decoder.Decode(recovery.Key, recoveryKey, recoveryAdditionalData(recovery.UserID))
+	if err != nil {
+		return ErrInvalidRecoveryShares
+	}
+	if recovery.UserID != user.ID && rs.syncer != nil {
+		if err = rs.syncer.Sync(common.SetMasterKey(ctx, oldKey), &SyncOption{PullOnly: true}); err != nil {
+			return err
+		}
+	}
+	newKey := deriveMasterKey(pass)
+	// shares keep working with the new key
+	recovery.UserID = user.ID
+	recovery.Key, err = rs.encoder.Encode(newKey, recoveryKey, recoveryAdditionalData(user.ID))
+	if err != nil {
+		return err
+	}
+	if err = rs.reset(ctx, user, pass, recovery, oldKey, newKey); err != nil {
+		return err
+	}
+	return rs.push(ctx, recovery)
+}
+
+// reset re-encrypt secrets with the new master key, change the local password and save the new wrap in one transaction
+func (rs *RecoveryService) reset(ctx context.Context, user *core.User, pass string, recovery *core.Recovery, oldKey, newKey []byte) error {
+	tx, err := rs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		_ = tx.Commit()
+	}()
+	ex, err := persistence.TxConflictExist(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if ex {
+		return ErrConflictExists
+	}
+	if err = rs.rekey(ctx, tx, oldKey, newKey); err != nil {
+		return err
+	}
+	salt, err := datatool.GenerateSalt()
+	if err != nil {
+		return err
+	}
+	user.Salt = salt
+	user.Password = datatool.Hash([]byte(pass), salt, 2, 64, 32, 2)
+	if err = persistence.TxUpdateUser(ctx, tx, user); err != nil {
+		return err
+	}
+	err = persistence.TxSaveRecovery(ctx, tx, recovery)
+	return err
+}
+
+// load local recovery, the one kept on the server when the device has none
+func (rs *RecoveryService) load(ctx context.Context, user *core.User) (*core.Recovery, error) {
+	recovery, err := persistence.GetRecovery(ctx, rs.db, user.ID)
+	if err == nil {
+		return recovery, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if rs.store == nil {
+		return nil, ErrRecoveryNotConfigured
+	}
+	data, err := rs.store.LoadRecovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recovery = &core.Recovery{}
+	if err = json.Unmarshal(data, recovery); err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+func (rs *RecoveryService) push(ctx context.Context, recovery *core.Recovery) error {
+	if rs.store == nil {
+		return nil
+	}
+	data, err := json.Marshal(recovery)
+	if err != nil {
+		return err
+	}
+	return rs.store.SaveRecovery(ctx, data)
+}
+
+// rekey wrap every dek with a new master key. Records get a new version to be pushed on next sync
+func (rs *RecoveryService) rekey(ctx context.Context, tx *sql.Tx, oldKey, newKey []byte) error {
+	records, err := persistence.TxGetAllRecordGreater(ctx, tx, -1)
+	if err != nil {
+		return err
+	}
+	version := common.GetVersion(ctx) + 1
+	for _, record := range records {
+		source := *record
+		if err = record.Rekey(rs.encoder, rs.decoder, oldKey, newKey, version); err != nil {
+			return fmt.Errorf("failed to re-encrypt secret %s: %w", record.ID, err)
+		}
+		if record.BigData && source.Version != version {
+			if err = rebindFile(rs.fp, rs.encoder, rs.decoder, &source, oldKey, record); err != nil {
+				return err
+			}
+		}
+		if err = persistence.TxUpdateRecord(ctx, tx, record); err != nil {
+			return err
+		}
+	}
+	// shared records aren't pushed, so they keep their version
+	shared, err := persistence.TxGetSharedRecords(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, record := range shared {
+		if err = record.Rekey(rs.encoder, rs.decoder, oldKey, newKey, record.Version); err != nil {
+			return fmt.Errorf("failed to re-encrypt shared secret %s: %w", record.ID, err)
+		}
+		if err = persistence.TxUpdateRecord(ctx, tx, record); err != nil {
+			return err
+		}
+	}
+	if err = rekeyShareKeys(ctx, tx, rs.encoder, rs.decoder, oldKey, newKey); err != nil {
+		return err
+	}
+	// vault records are stored under the vault key on the server
+	inVaults, err := persistence.TxGetVaultRecords(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, record := range inVaults {
+		if err = record.Rekey(rs.encoder, rs.decoder, oldKey, newKey, record.Version); err != nil {
+			return fmt.Errorf("failed to re-encrypt vault secret %s: %w", record.ID, err)
+		}
+		if err = persistence.TxUpdateRecord(ctx, tx, record); err != nil {
+			return err
+		}
+	}
+	if err = rekeyVaultKeys(ctx, tx, rs.encoder, rs.decoder, oldKey, newKey); err != nil {
+		return err
+	}
+	bases, err := persistence.TxGetAllBaseRecord(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, base := range bases {
+		if err = base.Rekey(rs.encoder, rs.decoder, oldKey, newKey, base.Version); err != nil {
+			return fmt.Errorf("failed to re-encrypt base of secret %s: %w", base.ID, err)
+		}
+		if err = persistence.TxSaveBaseRecord(ctx, tx, base); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *RecoveryService) user(ctx context.Context) (*core.User, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return persistence.GetUser(ctx, rs.db, hostname)
+}
+
+func recoveryAdditionalData(userID string) []byte {
+	return []byte(fmt.Sprintf("keeper|%s|recovery", userID))
+}
+
+// RemoteRecoveryStore recovery kept on the remote server
+type RemoteRecoveryStore struct {
+	client *RemoteClient
+}
+
+func NewRemoteRecoveryStore(client *RemoteClient) *RemoteRecoveryStore {
+	return &RemoteRecoveryStore{client: client}
+}
+
+// SaveRecovery implements RecoveryStore, servers without recovery support are skipped
+func (rr *RemoteRecoveryStore) SaveRecovery(ctx context.Context, recovery []byte) error {
+	var req pb.SetRecoveryRequest
+	req.SetRecovery(recovery)
+	if _, err := rr.client.SetRecovery(ctx, &req); err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// LoadRecovery implements RecoveryStore
+func (rr *RemoteRecoveryStore) LoadRecovery(ctx context.Context) ([]byte, error) {
+	res, err := rr.client.GetRecovery(ctx, &pb.GetRecoveryRequest{})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.Unimplemented:
+			return nil, ErrRecoveryNotConfigured
+		}
+		return nil, err
+	}
+	return res.GetRecovery(), nil
+}
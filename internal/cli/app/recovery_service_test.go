@@ -0,0 +1,193 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreShouldBeSuccess(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	userService := NewUserService(manager.db)
+	recoveryService := NewRecoveryService(manager.db, manager.encoder, manager.decoder, manager.fp)
+	if err := userService.Register(ctx, "old"); err != nil {
+		t.Fatal(err)
+	}
+	masterKey, err := userService.Auth(ctx, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = common.SetMasterKey(ctx, masterKey)
+	id, err := createLoginPass(ctx, manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := recoveryService.Split(ctx, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = recoveryService.Restore(ctx, [][]byte{shares[1], shares[3], shares[4]}, "new")
+
+	assert.NoError(t, err)
+	_, err = userService.Auth(ctx, "old")
+	assert.Error(t, err)
+	newKey, err := userService.Auth(ctx, "new")
+	assert.NoError(t, err)
+	r, err := persistence.GetRecordByID(ctx, manager.db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lp, err := r.DecodeLoginPass(manager.decoder, newKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test", lp.Name)
+
+	// shares keep working after the key is changed
+	err = recoveryService.Restore(ctx, [][]byte{shares[0], shares[1], shares[2]}, "newest")
+	assert.NoError(t, err)
+
+	if err := manager.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanUp(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreWithWrongSharesShouldFail(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	userService := NewUserService(manager.db)
+	recoveryService := NewRecoveryService(manager.db, manager.encoder, manager.decoder, manager.fp)
+	if err := userService.Register(ctx, "old"); err != nil {
+		t.Fatal(err)
+	}
+	shares, err := recoveryService.Split(ctx, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = recoveryService.Restore(ctx, shares[:2], "new")
+
+	assert.ErrorIs(t, err, ErrInvalidRecoveryShares)
+	if err := manager.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanUp(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreOnFreshDatabaseShouldFetchRecoveryFromServer(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	store := &fakeRecoveryStore{}
+	userService := NewUserService(manager.db)
+	recoveryService := NewRecoveryService(manager.db, manager.encoder, manager.decoder, manager.fp)
+	recoveryService.SetRemote(store, &mockSyncer{})
+	if err := userService.Register(ctx, "old"); err != nil {
+		t.Fatal(err)
+	}
+	masterKey, err := userService.Auth(ctx, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = common.SetMasterKey(ctx, masterKey)
+	id, err := createLoginPass(ctx, manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := recoveryService.Split(ctx, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := persistence.GetRecordByID(ctx, manager.db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := sql.Open("sqlite", "file:"+t.Name()+"-fresh?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = persistence.Migrate(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if err = NewUserService(fresh).Register(ctx, "temporary"); err != nil {
+		t.Fatal(err)
+	}
+	restored := NewRecoveryService(fresh, manager.encoder, manager.decoder, manager.fp)
+	restored.SetRemote(store, &pullSyncer{db: fresh, records: []*core.Record{record}})
+
+	err = restored.Restore(ctx, [][]byte{shares[0], shares[2]}, "new")
+
+	assert.NoError(t, err)
+	newKey, err := NewUserService(fresh).Auth(ctx, "new")
+	assert.NoError(t, err)
+	r, err := persistence.GetRecordByID(ctx, fresh, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lp, err := r.DecodeLoginPass(manager.decoder, newKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test", lp.Name)
+	// the new wrap is pushed back, so the next device restores with the same shares
+	recoveryKey, err := crypto.Combine([][]byte{shares[1], shares[2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromServer, err := restored.load(ctx, &core.User{ID: "unknown"})
+	assert.NoError(t, err)
+	key, err := manager.decoder.Decode(fromServer.Key, recoveryKey, recoveryAdditionalData(fromServer.UserID))
+	assert.NoError(t, err)
+	assert.Equal(t, newKey, key)
+
+	if err := fresh.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanUp(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type fakeRecoveryStore struct {
+	recovery []byte
+}
+
+func (s *fakeRecoveryStore) SaveRecovery(_ context.Context, recovery []byte) error {
+	s.recovery = recovery
+	return nil
+}
+
+func (s *fakeRecoveryStore) LoadRecovery(_ context.Context) ([]byte, error) {
+	if s.recovery == nil {
+		return nil, ErrRecoveryNotConfigured
+	}
+	return s.recovery, nil
+}
+
+// pullSyncer stores records as a pull from the server would
+type pullSyncer struct {
+	db      *sql.DB
+	records []*core.Record
+}
+
+func (s *pullSyncer) Sync(ctx context.Context, _ *SyncOption) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, record := range s.records {
+		if err = persistence.TxInsertRecord(ctx, tx, record); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
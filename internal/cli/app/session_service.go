@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/pb"
+)
+
+// SessionService keep session on remote server instead of the password
+type SessionService struct {
+	client   *RemoteClient
+	db       *sql.DB
+	serverID int32
+}
+
+func NewSessionService(client *RemoteClient, db *sql.DB, serverID int32) *SessionService {
+	return &SessionService{client: client, db: db, serverID: serverID}
+}
+
+// Load continue saved session, rotated refresh tokens are saved back
+func (ss *SessionService) Load(ctx context.Context) error {
+	ss.client.SetTokenStore(ss)
+	token, err := persistence.GetServerSession(ctx, ss.db, ss.serverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	ss.client.SetRefreshToken(token)
+	return nil
+}
+
+// Login start a new session with the password, it is not stored after
+func (ss *SessionService) Login(ctx context.Context) error {
+	ss.client.SetTokenStore(ss)
+	return ss.client.StartSession(ctx)
+}
+
+// Logout close the session, login with the password is required after
+func (ss *SessionService) Logout(ctx context.Context) error {
+	if err := ss.client.EndSession(ctx); err != nil {
+		return err
+	}
+	return persistence.DeleteServerSession(ctx, ss.db, ss.serverID)
+}
+
+func (ss *SessionService) List(ctx context.Context) ([]*core.SessionInfo, error) {
+	res, err := ss.client.SessionsClient.List(ctx, &pb.ListSessionsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*core.SessionInfo, len(res.GetSessions()))
+	for i, session := range res.GetSessions() {
+		sessions[i] = &core.SessionInfo{
+			ID:         session.GetId(),
+			CreatedAt:  session.GetCreatedAt().AsTime(),
+			LastUsedAt: session.GetLastUsedAt().AsTime(),
+			ExpiresAt:  session.GetExpiresAt().AsTime(),
+			DeviceID:   session.GetDeviceId(),
+			Current:    session.GetCurrent(),
+		}
+	}
+	return sessions, nil
+}
+
+func (ss *SessionService) Terminate(ctx context.Context, id string) error {
+	var req pb.TerminateSessionRequest
+	req.SetId(id)
+	_, err := ss.client.Terminate(ctx, &req)
+	return err
+}
+
+// SaveRefreshToken implements TokenStore
+func (ss *SessionService) SaveRefreshToken(ctx context.Context, token string) (err error) {
+	tx, err := ss.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	if err = persistence.TxSaveServerSession(ctx, tx, ss.serverID, token); err != nil {
+		return err
+	}
+	return persistence.TxClearServerPassword(ctx, tx, ss.serverID)
+}
@@ -0,0 +1,311 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	clicommon "github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrSharedRecord    = errors.New("record is shared with you, only its owner can change it")
+	ErrRecordNotSynced = errors.New("record has local changes, sync it first")
+	ErrNoShareKey      = errors.New("no share key on this device, shared secret can't be opened")
+)
+
+// ShareService share records with other users of the remote server. Dek of the record is sealed
+// to X25519 public key of the recipient, server never sees it
+type ShareService struct {
+	client   *RemoteClient
+	db       *sql.DB
+	serverID int32
+	encoder  core.Encoder
+	decoder  core.Decoder
+}
+
+func NewShareService(client *RemoteClient, db *sql.DB, serverID int32, encoder core.Encoder, decoder core.Decoder) *ShareService {
+	return &ShareService{client: client, db: db, serverID: serverID, encoder: encoder, decoder: decoder}
+}
+
+// Ensure generate key pair on first use and publish its public key. Private key is wrapped with the master key
+func (ss *ShareService) Ensure(ctx context.Context) error {
+	_, err := persistence.GetShareKey(ctx, ss.db, ss.serverID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return err
+	}
+	public, private, err := crypto.GenerateShareKey()
+	if err != nil {
+		return err
+	}
+	wrapped, err := ss.encoder.Encode(private, masterKey, shareKeyAdditionalData(ss.serverID))
+	if err != nil {
+		return err
+	}
+	var req pb.SetPublicKeyRequest
+	req.SetPublicKey(public)
+	if _, err = ss.client.SetPublicKey(ctx, &req); err != nil {
+		// server without sharing
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		return err
+	}
+	return persistence.SaveShareKey(ctx, ss.db, &core.ShareKey{ServerID: ss.serverID, PublicKey: public, PrivateKey: wrapped})
+}
+
+// Fingerprint fingerprint of own public key, the owner compares it before sharing
+func (ss *ShareService) Fingerprint(ctx context.Context) (string, error) {
+	key, err := persistence.GetShareKey(ctx, ss.db, ss.serverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoShareKey
+		}
+		return "", err
+	}
+	return ShareKeyFingerprint(key.PublicKey), nil
+}
+
+// PublicKey public key of the user, compare its fingerprint with the one the user sees before sharing
+func (ss *ShareService) PublicKey(ctx context.Context, login string) ([]byte, error) {
+	var req pb.GetPublicKeyRequest
+	req.SetLogin(login)
+	res, err := ss.client.GetPublicKey(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	return res.GetPublicKey(), nil
+}
+
+// Share seal dek of the record to the user, record shows up in the next pull of the user.
+// Returns fingerprint of the key the dek was sealed to
+func (ss *ShareService) Share(ctx context.Context, id, login string) (string, error) {
+	record, err := ss.syncedRecord(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	publicKey, err := ss.PublicKey(ctx, login)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := ss.sealDek(ctx, record, publicKey)
+	if err != nil {
+		return "", err
+	}
+	var req pb.ShareRequest
+	req.SetSecretId(record.ID)
+	req.SetLogin(login)
+	req.SetDek(sealed)
+	if _, err = ss.client.Share(ctx, &req); err != nil {
+		return "", err
+	}
+	return ShareKeyFingerprint(publicKey), nil
+}
+
+// Revoke stop sharing the record, it is removed from devices of the user with the next pull.
+// Whatever the user has seen stays known, change the secret itself after
+func (ss *ShareService) Revoke(ctx context.Context, id, login string) error {
+	var req pb.RevokeShareRequest
+	req.SetSecretId(id)
+	req.SetLogin(login)
+	_, err := ss.client.SharesClient.Revoke(ctx, &req)
+	return err
+}
+
+// List records the user shares with others
+func (ss *ShareService) List(ctx context.Context) ([]*core.ShareInfo, error) {
+	res, err := ss.client.SharesClient.List(ctx, &pb.ListSharesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]*core.ShareInfo, len(res.GetShares()))
+	for i, share := range res.GetShares() {
+		shares[i] = &core.ShareInfo{
+			RecordID:  share.GetSecretId(),
+			Login:     share.GetLogin(),
+			PublicKey: share.GetPublicKey(),
+			CreatedAt: share.GetCreatedAt().AsTime(),
+		}
+	}
+	return shares, nil
+}
+
+// syncedRecord own record known to the server under its current version
+func (ss *ShareService) syncedRecord(ctx context.Context, id string) (*core.Record, error) {
+	record, err := persistence.GetRecordByID(ctx, ss.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.IsShared() {
+		return nil, ErrSharedRecord
+	}
+	// vault records are shared through the vault
+	if record.InVault() {
+		return nil, ErrVaultRecord
+	}
+	if record.Deleted {
+		return nil, sql.ErrNoRows
+	}
+	if record.IsChanged(&core.SyncState{Value: clicommon.GetVersion(ctx)}) {
+		return nil, ErrRecordNotSynced
+	}
+	return record, nil
+}
+
+func (ss *ShareService) sealDek(ctx context.Context, record *core.Record, publicKey []byte) ([]byte, error) {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := record.DecodeDek(ss.decoder, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	// the recipient only accepts data bound to the record
+	if record.Legacy {
+		return nil, ErrLegacyRecord
+	}
+	return crypto.SealKey(dek, publicKey, record.AdditionalData(core.ShareRole))
+}
+
+// seal dek of every pushed record sealed to everyone the record is shared with, server refuses
+// to change shared secret without them
+func (ss *ShareService) seal(ctx context.Context, records []*core.Record) (map[string][]*pb.ShareKey, error) {
+	res, err := ss.client.SharesClient.List(ctx, &pb.ListSharesRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+		return nil, err
+	}
+	recipients := make(map[string][]*pb.ShareInfo)
+	for _, share := range res.GetShares() {
+		recipients[share.GetSecretId()] = append(recipients[share.GetSecretId()], share)
+	}
+	keys := make(map[string][]*pb.ShareKey)
+	for _, record := range records {
+		if record.Deleted {
+			continue
+		}
+		for _, share := range recipients[record.ID] {
+			sealed, err := ss.sealDek(ctx, record, share.GetPublicKey())
+			if err != nil {
+				return nil, err
+			}
+			var key pb.ShareKey
+			key.SetLogin(share.GetLogin())
+			key.SetDek(sealed)
+			keys[record.ID] = append(keys[record.ID], &key)
+		}
+	}
+	return keys, nil
+}
+
+// open re-encrypt secrets shared with the user under their own master key. Payload of shared secret
+// is bound to the version of the owner, the user knows it under their own one
+func (ss *ShareService) open(ctx context.Context, secrets []*pb.Secret) error {
+	var privateKey []byte
+	for _, secret := range secrets {
+		if secret.GetOwner() == "" || secret.GetDeleted() {
+			continue
+		}
+		if privateKey == nil {
+			var err error
+			if privateKey, err = ss.privateKey(ctx); err != nil {
+				return err
+			}
+		}
+		if err := ss.adopt(ctx, secret, privateKey); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrTamperedSecret, secret.GetId(), err)
+		}
+	}
+	return nil
+}
+
+func (ss *ShareService) adopt(ctx context.Context, secret *pb.Secret, privateKey []byte) error {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return err
+	}
+	record := toRecord(secret)
+	dek, err := crypto.OpenKey(record.Dek, privateKey, record.AdditionalData(core.ShareRole))
+	if err != nil {
+		return err
+	}
+	record.Version = secret.GetDataVersion()
+	data, err := ss.decoder.Decode(record.Data, dek, record.AdditionalData(core.DataRole))
+	if err != nil {
+		return err
+	}
+	// file is sealed again under the local version when it's downloaded
+	record.Version = secret.GetVersion()
+	if err = record.Encode(ss.encoder, data, dek, masterKey); err != nil {
+		return err
+	}
+	secret.SetDek(record.Dek)
+	secret.SetData(record.Data)
+	return nil
+}
+
+func (ss *ShareService) privateKey(ctx context.Context) ([]byte, error) {
+	key, err := persistence.GetShareKey(ctx, ss.db, ss.serverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoShareKey
+		}
+		return nil, err
+	}
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ss.decoder.Decode(key.PrivateKey, masterKey, shareKeyAdditionalData(ss.serverID))
+}
+
+// ShareKeyFingerprint sha256 of share public key, users compare it out of band
+func ShareKeyFingerprint(publicKey []byte) string {
+	sum := sha256.Sum256(publicKey)
+	return fingerprintPrefix + hex.EncodeToString(sum[:])
+}
+
+// rekeyShareKeys wrap private share keys with a new master key
+func rekeyShareKeys(ctx context.Context, tx *sql.Tx, encoder core.Encoder, decoder core.Decoder, oldKey, newKey []byte) error {
+	keys, err := persistence.TxGetAllShareKeys(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		ad := shareKeyAdditionalData(key.ServerID)
+		private, err := decoder.Decode(key.PrivateKey, oldKey, ad)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt share key: %w", err)
+		}
+		if key.PrivateKey, err = encoder.Encode(private, newKey, ad); err != nil {
+			return err
+		}
+		if err = persistence.TxSaveShareKey(ctx, tx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func shareKeyAdditionalData(serverID int32) []byte {
+	return []byte(fmt.Sprintf("keeper|%d|share-key", serverID))
+}
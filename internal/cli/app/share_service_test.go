@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestShareService_AdoptShouldReencryptUnderRecipientKey(t *testing.T) {
+	encoder := crypto.NewGzipEncoder(crypto.NewAesEncoder())
+	decoder := crypto.NewGzipDecoder(crypto.NewAesDecoder())
+	ownerKey, _ := datatool.GenerateDek(32)
+	recipientKey, _ := datatool.GenerateDek(32)
+	public, private, err := crypto.GenerateShareKey()
+	assert.NoError(t, err)
+	owned := core.CreateRecord(core.TextType)
+	owned.Version = 5
+	dek, _ := datatool.GenerateDek(32)
+	assert.NoError(t, owned.Encode(encoder, []byte(`{"content":"shared"}`), dek, ownerKey))
+	sealed, err := crypto.SealKey(dek, public, owned.AdditionalData(core.ShareRole))
+	assert.NoError(t, err)
+	var secret pb.Secret
+	secret.SetId(owned.ID)
+	secret.SetType(pb.SecretType_Text)
+	secret.SetModifiedAt(timestamppb.New(time.Now()))
+	secret.SetData(owned.Data)
+	secret.SetDek(sealed)
+	secret.SetVersion(9)
+	secret.SetDataVersion(owned.Version)
+	secret.SetOwner("dima")
+	ctx := common.SetMasterKey(context.Background(), recipientKey)
+	ss := NewShareService(nil, nil, 1, encoder, decoder)
+
+	err = ss.adopt(ctx, &secret, private)
+
+	assert.NoError(t, err)
+	record := toRecord(&secret)
+	assert.Equal(t, "dima", record.Owner)
+	data, err := record.Decode(decoder, recipientKey)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"content":"shared"}`, string(data))
+}
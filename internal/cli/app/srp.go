@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/srp"
+)
+
+// minPasswordLength server can't check the password it never sees, so the client does
+const minPasswordLength = 8
+
+var (
+	ErrWeakPassword        = fmt.Errorf("password must be at least %d characters long", minPasswordLength)
+	ErrPasswordEqualsLogin = errors.New("password must differ from the login")
+)
+
+// passwordProof proof of the password for the server made by SRP handshake
+type passwordProof struct {
+	handshakeID string
+	proof       []byte
+	client      *srp.Client
+}
+
+// prove start SRP handshake and make the proof of the password. Password is never sent instead,
+// account registered before SRP is upgraded explicitly by RemoteClient.MigrateLogin
+func prove(ctx context.Context, users pb.UsersClient, login, password string) (*passwordProof, error) {
+	client, err := srp.NewClient(password)
+	if err != nil {
+		return nil, err
+	}
+	var req pb.BeginLoginRequest
+	req.SetLogin(login)
+	req.SetPublicKey(client.PublicKey())
+	res, err := users.BeginLogin(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := client.Proof(res.GetSalt(), res.GetPublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return &passwordProof{handshakeID: res.GetHandshakeId(), proof: proof, client: client}, nil
+}
+
+// verifyServer server must prove it knows the verifier, otherwise it is an impostor
+func (p *passwordProof) verifyServer(serverProof []byte) error {
+	if err := p.client.VerifyServer(serverProof); err != nil {
+		return errors.Join(errors.New("server failed to prove the password verifier"), err)
+	}
+	return nil
+}
+
+// newVerifier salt and SRP verifier of a new password. Server finds only the password equal to the login
+// as typed, the other letter cases are refused here
+func newVerifier(login, password string) ([]byte, []byte, error) {
+	if len([]rune(password)) < minPasswordLength {
+		return nil, nil, ErrWeakPassword
+	}
+	if strings.EqualFold(login, password) {
+		return nil, nil, ErrPasswordEqualsLogin
+	}
+	salt, err := srp.NewSalt()
+	if err != nil {
+		return nil, nil, err
+	}
+	return salt, srp.Verifier(password, salt), nil
+}
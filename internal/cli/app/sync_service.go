@@ -6,27 +6,46 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"reflect"
 	"time"
 
+	clicommon "github.com/DimKa163/keeper/internal/cli/common"
 	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
 	"github.com/DimKa163/keeper/internal/cli/persistence"
 	"github.com/DimKa163/keeper/internal/common"
 	"github.com/DimKa163/keeper/internal/datatool"
 	"github.com/DimKa163/keeper/internal/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var ErrConflictData = errors.New("conflict detected! pull first")
+var ErrTamperedSecret = errors.New("secret doesn't match its identity")
 var syncTypeName = reflect.TypeOf(core.Record{}).Name()
 
+const (
+	// maxBindAttempts forced push attempts while other clients keep changing version of the server
+	maxBindAttempts = 3
+	// pullPageSize secrets count requested per page
+	pullPageSize = 100
+	// watchRetryDelay delay before subscription is restored
+	watchRetryDelay = 5 * time.Second
+	// sharedDir prefix of a shared file received under the version of its owner
+	sharedDir = "shared"
+)
+
 type (
 	SyncOption struct {
 		PushOnly bool
 		PullOnly bool
 		Force    bool
+		// OnConflict solve conflicts with the strategy, conflicts are left for the user when not set
+		OnConflict Version
 	}
 )
 
@@ -34,27 +53,128 @@ type Syncer interface {
 	Sync(ctx context.Context, option *SyncOption) error
 }
 
+// Watcher pull changes as soon as other clients push them
+type Watcher interface {
+	Watch(ctx context.Context) error
+}
+
 type PushSecretStream grpc.ClientStreamingClient[pb.PushOperation, pb.PushResponse]
 
 type SyncService struct {
 	client       *RemoteClient
 	db           *sql.DB
 	fileProvider *datatool.FileProvider
+	encoder      core.Encoder
+	decoder      core.Decoder
+	resolver     *conflictResolver
+	shares       *ShareService
+	vaults       *VaultService
 }
 
 func NewSyncService(
 	client *RemoteClient,
 	db *sql.DB,
 	fileProvider *datatool.FileProvider,
+	encoder core.Encoder,
+	decoder core.Decoder,
 ) *SyncService {
 	return &SyncService{
 		client:       client,
 		db:           db,
 		fileProvider: fileProvider,
+		encoder:      encoder,
+		decoder:      decoder,
+		resolver:     newConflictResolver(encoder, decoder, fileProvider),
 	}
 }
 
+// SetShares open records shared with the user and seal deks of shared records on push
+func (ss *SyncService) SetShares(shares *ShareService) {
+	ss.shares = shares
+}
+
+// SetVaults sync records of team vaults the user is a member of, each under its own state
+func (ss *SyncService) SetVaults(vaults *VaultService) {
+	ss.vaults = vaults
+}
+
 func (ss *SyncService) Sync(ctx context.Context, option *SyncOption) error {
+	fmt.Println("starting sync process")
+	if ss.shares != nil {
+		// publish the share key so others can share with the user
+		if err := ss.shares.Ensure(ctx); err != nil {
+			return err
+		}
+	}
+	if err := ss.syncScope(ctx, nil, option); err != nil {
+		return err
+	}
+	if ss.vaults == nil {
+		return nil
+	}
+	vaults, err := ss.vaults.refresh(ctx)
+	if err != nil {
+		return err
+	}
+	for _, vault := range vaults {
+		fmt.Printf("syncing vault %s\n", vault.Name)
+		if err = ss.syncScope(ctx, vault, option); err != nil {
+			return fmt.Errorf("vault %s: %w", vault.Name, err)
+		}
+	}
+	return nil
+}
+
+// syncScope sync own records when vault is nil, otherwise records of the vault
+func (ss *SyncService) syncScope(ctx context.Context, vault *core.Vault, option *SyncOption) error {
+	syncState, err := getScopeState(ctx, ss.db, vault)
+	if err != nil {
+		return err
+	}
+	var rejected bool
+	// readers only pull
+	if (!option.PullOnly || option.PushOnly) && (vault == nil || vault.CanWrite()) {
+		if err = ss.inTx(ctx, func(tx *sql.Tx) error {
+			return ss.push(ctx, tx, vault, syncState, option.Force)
+		}); err != nil {
+			if !errors.Is(err, ErrConflictData) {
+				return err
+			}
+			fmt.Println("pull first, conflict detected")
+			if option.OnConflict == 0 || option.PushOnly {
+				return err
+			}
+			rejected = true
+		}
+	}
+	if option.PushOnly {
+		return nil
+	}
+	if err = ss.pull(ctx, vault, syncState, option.Force); err != nil {
+		return err
+	}
+	if option.OnConflict == 0 {
+		return nil
+	}
+	var solved int
+	if err = ss.inTx(ctx, func(tx *sql.Tx) error {
+		solved, err = ss.resolver.solveAll(ctx, tx, option.OnConflict)
+		return err
+	}); err != nil {
+		return err
+	}
+	if solved > 0 {
+		fmt.Printf("solved %d conflicts, taken %s\n", solved, option.OnConflict)
+		return ss.syncScope(ctx, vault, &SyncOption{Force: true})
+	}
+	// pulled without conflicts, push again
+	if rejected {
+		return ss.syncScope(ctx, vault, &SyncOption{})
+	}
+	return nil
+}
+
+func (ss *SyncService) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
 	var err error
 	tx, err := ss.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -67,57 +187,90 @@ func (ss *SyncService) Sync(ctx context.Context, option *SyncOption) error {
 		}
 		_ = tx.Commit()
 	}()
-	fmt.Println("starting sync process")
-	syncState, err := getState(ctx, tx)
+	err = fn(tx)
+	return err
+}
+
+func (ss *SyncService) push(ctx context.Context, tx *sql.Tx, vault *core.Vault, syncState *core.SyncState, force bool) error {
+	var err error
+	fmt.Printf("current version: %d\n", syncState.Value)
+	var records []*core.Record
+	if vault == nil {
+		records, err = persistence.TxGetAllRecordGreater(ctx, tx, syncState.Value)
+	} else {
+		records, err = persistence.TxGetVaultRecordGreater(ctx, tx, vault.ID, syncState.Value)
+	}
 	if err != nil {
 		return err
 	}
-	if !option.PullOnly && !option.PushOnly {
-		if err = ss.push(ctx, tx, syncState, option.Force); err != nil {
-			if errors.Is(err, ErrConflictData) {
-				fmt.Println("pull first, conflict detected")
-			}
+
+	if len(records) == 0 {
+		return nil
+	}
+	// records are bound to the server version they will be stored under,
+	// forced push rebinds them when that version moves
+	for attempt := 1; ; attempt++ {
+		var base int32
+		if base, err = ss.bind(ctx, tx, vault, records, syncState, force); err != nil {
 			return err
 		}
-		if err = ss.pull(ctx, tx, syncState, option.Force); err != nil {
-			return err
+		err = ss.send(ctx, vault, records, base, force)
+		if !force || status.Code(err) != codes.Aborted || attempt == maxBindAttempts {
+			break
 		}
-
-	} else if option.PushOnly {
-		if err = ss.push(ctx, tx, syncState, option.Force); err != nil {
-			if errors.Is(err, ErrConflictData) {
-				fmt.Println("pull first, conflict detected")
-			}
-			return err
+	}
+	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return ErrConflictData
 		}
-	} else {
-		if err = ss.pull(ctx, tx, syncState, option.Force); err != nil {
+		return err
+	}
+	// pushed versions become merge bases
+	for _, record := range records {
+		if record.Deleted {
+			err = persistence.TxDeleteBaseRecord(ctx, tx, record.ID)
+		} else {
+			err = persistence.TxSaveBaseRecord(ctx, tx, record)
+		}
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (ss *SyncService) push(ctx context.Context, tx *sql.Tx, syncState *core.SyncState, force bool) error {
+// send push records bound to the version next to base
+func (ss *SyncService) send(ctx context.Context, vault *core.Vault, records []*core.Record, base int32, force bool) error {
 	var err error
-	fmt.Printf("current version: %d\n", syncState.Value)
-	records, err := persistence.TxGetAllRecordGreater(ctx, tx, syncState.Value)
-	if err != nil {
-		return err
+	var shareKeys map[string][]*pb.ShareKey
+	if ss.shares != nil && vault == nil {
+		if shareKeys, err = ss.shares.seal(ctx, records); err != nil {
+			return err
+		}
 	}
-
-	if len(records) == 0 {
-		return nil
+	sent := records
+	if vault != nil {
+		// vault records go out under the vault key
+		sent = make([]*core.Record, len(records))
+		for i, record := range records {
+			if sent[i], err = ss.vaults.seal(ctx, vault, record); err != nil {
+				return err
+			}
+		}
+		ctx = common.WriteVault(ctx, vault.ID)
 	}
-	ctx = common.WriteClientVersion(ctx, syncState.Value)
+	ctx = common.WriteClientVersion(ctx, base)
 	ctx = common.WriteForce(ctx, force)
+	if clientID := clicommon.GetClientID(ctx); clientID != "" {
+		ctx = common.WriteClientID(ctx, clientID)
+	}
 	stream, err := ss.client.SyncClient.PushStream(ctx)
 	if err != nil {
 		return err
 	}
-	for _, record := range records {
+	for _, record := range sent {
 		if record.BigData {
-			if err = ss.pushFile(stream, record); err != nil {
+			if err = ss.pushFile(stream, record, shareKeys[record.ID]); err != nil {
 				if errors.Is(err, io.EOF) {
 					break
 				}
@@ -125,17 +278,63 @@ func (ss *SyncService) push(ctx context.Context, tx *sql.Tx, syncState *core.Syn
 			continue
 		}
 		op := toDefault(record)
+		op.GetSecret().SetShares(shareKeys[record.ID])
 		if err = stream.Send(op); err != nil {
+			// the server closed the stream, CloseAndRecv returns why
+			if errors.Is(err, io.EOF) {
+				break
+			}
 			return err
 		}
 	}
-	if _, err = stream.CloseAndRecv(); err != nil {
-		return err
+	_, err = stream.CloseAndRecv()
+	return err
+}
+
+// bind re-encrypt records under the version server will assign to them and return the server
+// version they are bound to, the local one or the current one of the server when force
+func (ss *SyncService) bind(
+	ctx context.Context,
+	tx *sql.Tx,
+	vault *core.Vault,
+	records []*core.Record,
+	syncState *core.SyncState,
+	force bool,
+) (int32, error) {
+	base := syncState.Value
+	if force {
+		serverVersion, err := ss.serverVersion(ctx, vault)
+		if err != nil {
+			return 0, err
+		}
+		base = serverVersion
 	}
-	return nil
+	version := base + 1
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		if record.Version == version {
+			continue
+		}
+		source := *record
+		if err = record.Rebind(ss.encoder, ss.decoder, masterKey, version); err != nil {
+			return 0, err
+		}
+		if record.BigData {
+			if err = rebindFile(ss.fileProvider, ss.encoder, ss.decoder, &source, masterKey, record); err != nil {
+				return 0, err
+			}
+		}
+		if err = persistence.TxUpdateRecord(ctx, tx, record); err != nil {
+			return 0, err
+		}
+	}
+	return base, nil
 }
 
-func (ss *SyncService) pushFile(stream PushSecretStream, record *core.Record) error {
+func (ss *SyncService) pushFile(stream PushSecretStream, record *core.Record, shareKeys []*pb.ShareKey) error {
 	var n int
 	begin := toBegin(record)
 	if err := stream.Send(begin); err != nil {
@@ -166,34 +365,186 @@ func (ss *SyncService) pushFile(stream PushSecretStream, record *core.Record) er
 		}
 	}
 	end := toEndFile(record)
+	end.GetSecret().SetShares(shareKeys)
 	if err = stream.Send(end); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (ss *SyncService) pull(ctx context.Context, tx *sql.Tx, syncState *core.SyncState, force bool) error {
+// IsHealthy check remote server is available
+func (ss *SyncService) IsHealthy(ctx context.Context) error {
+	return ss.client.IsHealthy(ctx)
+}
+
+// Watch keep subscription open and pull on every change made by other clients.
+// Subscription is restored after connection loss
+func (ss *SyncService) Watch(ctx context.Context) error {
+	if clientID := clicommon.GetClientID(ctx); clientID != "" {
+		ctx = common.WriteClientID(ctx, clientID)
+	}
+	for {
+		err := ss.watch(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		fmt.Printf("subscription lost: %s\n", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+func (ss *SyncService) watch(ctx context.Context) error {
+	stream, err := ss.client.Subscribe(ctx, &pb.SubscribeRequest{})
+	if err != nil {
+		return err
+	}
+	fmt.Println("watching for changes")
+	// catch up on changes made while unsubscribed
+	if err = ss.Sync(ctx, &SyncOption{PullOnly: true}); err != nil {
+		return err
+	}
+	for {
+		var event *pb.ChangeEvent
+		event, err = stream.Recv()
+		if err != nil {
+			return err
+		}
+		var syncState *core.SyncState
+		if event.GetVaultId() != "" {
+			syncState, err = getNamedState(ctx, ss.db, vaultStateName(event.GetVaultId()))
+		} else {
+			syncState, err = getState(ctx, ss.db)
+		}
+		if err != nil {
+			return err
+		}
+		if event.GetVersion() <= syncState.Value {
+			continue
+		}
+		fmt.Printf("remote version %d available\n", event.GetVersion())
+		if err = ss.Sync(ctx, &SyncOption{PullOnly: true}); err != nil {
+			return err
+		}
+	}
+}
+
+// serverVersion read sync state version of the server, of the vault when it is set
+func (ss *SyncService) serverVersion(ctx context.Context, vault *core.Vault) (int32, error) {
+	var request pb.PullRequest
+	request.SetSince(math.MaxInt32)
+	if vault != nil {
+		request.SetVaultId(vault.ID)
+	}
+	stream, err := ss.client.PullPages(ctx, &request)
+	if err != nil {
+		return 0, err
+	}
+	page, err := stream.Recv()
+	if err != nil {
+		return 0, err
+	}
+	return page.GetVersion(), nil
+}
+
+// pull receive changes page by page. Every page is applied in its own transaction,
+// interrupted pull continues from the last applied secret
+func (ss *SyncService) pull(ctx context.Context, vault *core.Vault, syncState *core.SyncState, force bool) error {
 	fmt.Println("starting receiving secrets from server")
-	var err error
+	progress, err := getPullProgress(ctx, ss.db, syncState)
+	if err != nil {
+		return err
+	}
 	var request pb.PullRequest
 	request.SetSince(syncState.Value)
-	resp, err := ss.client.Pull(ctx, &request)
+	request.SetAfterVersion(progress.Version)
+	request.SetAfterId(progress.SecretID)
+	request.SetPageSize(pullPageSize)
+	if vault != nil {
+		request.SetVaultId(vault.ID)
+	}
+	stream, err := ss.client.PullPages(ctx, &request)
 	if err != nil {
 		return err
 	}
+	var last bool
+	for !last {
+		var page *pb.PullPage
+		page, err = stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if vault != nil {
+			if err = ss.vaults.open(ctx, vault, page.GetSecrets()); err != nil {
+				return err
+			}
+		} else if ss.shares != nil {
+			if err = ss.shares.open(ctx, page.GetSecrets()); err != nil {
+				return err
+			}
+		}
+		if err = ss.verify(ctx, page.GetSecrets()); err != nil {
+			return err
+		}
+		last = page.GetLast()
+		if err = ss.inTx(ctx, func(tx *sql.Tx) error {
+			return ss.applyPage(ctx, tx, syncState, progress, page, force)
+		}); err != nil {
+			return err
+		}
+	}
+	if progress.Conflict {
+		var count int64
+		count, err = persistence.GetConflictCount(ctx, ss.db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("❗😠detected conflict. count %d\n", count)
+		return nil
+	}
+	fmt.Println("✅ secrets received successfully")
+	return nil
+}
 
-	var hasConflict bool
-	for _, item := range resp.GetSecrets() {
+func (ss *SyncService) applyPage(
+	ctx context.Context,
+	tx *sql.Tx,
+	syncState *core.SyncState,
+	progress *core.PullProgress,
+	page *pb.PullPage,
+	force bool,
+) error {
+	var err error
+	for _, item := range page.GetSecrets() {
 		var record *core.Record
 		record, err = persistence.TxGetRecordByID(ctx, tx, item.GetId())
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return err
 		}
+		if err = checkReplay(record, item, syncState); err != nil {
+			return err
+		}
 		if record != nil {
 			if isConflictDetected(record, item, syncState) && !force {
-				hasConflict = true
+				var conflict *core.Conflict
+				// the merged record is pushed on the next sync
+				conflict, err = ss.merge(ctx, tx, record, item, page.GetVersion()+1)
+				if err != nil {
+					return err
+				}
+				if conflict == nil {
+					fmt.Printf("merged changes of secret %s\n", item.GetId())
+					continue
+				}
+				progress.Conflict = true
 				fmt.Printf("detected conflict for secret %s\n", item.GetId())
-				if err = ss.createConflict(ctx, tx, record, item); err != nil {
+				if err = ss.createConflict(ctx, tx, conflict, item); err != nil {
 					return err
 				}
 				continue
@@ -216,83 +567,125 @@ func (ss *SyncService) pull(ctx context.Context, tx *sql.Tx, syncState *core.Syn
 			}
 		}
 	}
-	if hasConflict {
-		var count int64
-		count, err = persistence.TxGetConflictCount(ctx, tx)
-		if err != nil {
-			return err
+	if !page.GetLast() {
+		if secrets := page.GetSecrets(); len(secrets) > 0 {
+			tail := secrets[len(secrets)-1]
+			progress.Version = tail.GetVersion()
+			progress.SecretID = tail.GetId()
 		}
-		fmt.Printf("❗😠detected conflict. count %d\n", count)
-		return nil
+		return persistence.TxSavePullProgress(ctx, tx, progress)
 	}
-	syncState.Value = resp.GetVersion()
-	if err = persistence.SaveState(ctx, tx, syncState); err != nil {
+	if err = persistence.TxDeletePullProgress(ctx, tx, progress.ID); err != nil {
 		return err
 	}
-	fmt.Println("✅ secrets received successfully")
-	return nil
+	// keep the version so the change is pulled again after resolution
+	if progress.Conflict {
+		return nil
+	}
+	syncState.Value = page.GetVersion()
+	return persistence.SaveState(ctx, tx, syncState)
 }
 
 func (ss *SyncService) createConflict(
 	ctx context.Context,
 	tx *sql.Tx,
-	target *core.Record,
+	conflict *core.Conflict,
 	secret *pb.Secret,
 ) error {
 	if secret.GetIsBig() && !secret.GetDeleted() {
-		var pollStream pb.PullStreamRequest
-		pollStream.SetId(secret.GetId())
-		pollStream.SetVersion(secret.GetVersion())
-		stream, err := ss.client.PullStream(ctx, &pollStream)
-		if err != nil {
+		if err := ss.download(ctx, secret, "remote"); err != nil {
 			return err
 		}
-		file, err := ss.fileProvider.OpenWrite(secret.GetId(), secret.GetVersion(), "remote")
-		if err != nil {
-			return err
-		}
-		defer func(file io.WriteCloser) {
-			err := file.Close()
-			if err != nil {
-				fmt.Printf("failed to close file: %s\n", err)
-			}
-		}(file)
-		for {
-			chunk, err := stream.Recv()
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					break
-				}
-			}
-			switch chunk.GetType() {
-			case pb.ChunkType_FilePart:
-				buffer := chunk.GetBuffer()
-				written := 0
-				for written < len(buffer) {
-					n, err := file.Write(buffer[written:])
-					if err != nil {
-						return err
-					}
-					written += n
-				}
-			case pb.ChunkType_ErrData:
-				continue
-			}
-		}
 	}
+	return persistence.TxInsertConflict(ctx, tx, conflict)
+}
+
+// merge three-way merge of local and remote changes against the revision of last sync.
+// Returns nil when all changes are merged into local record, otherwise conflict left for the user
+func (ss *SyncService) merge(
+	ctx context.Context,
+	tx *sql.Tx,
+	local *core.Record,
+	secret *pb.Secret,
+	version int32,
+) (*core.Conflict, error) {
+	remote := toRecord(secret)
 	conflict := &core.Conflict{
-		RecordID: target.ID,
+		RecordID: local.ID,
 		Local: &core.ConflictItem{
-			Record:  target,
-			Deleted: target.Deleted,
+			Record:  local,
+			Deleted: local.Deleted,
 		},
 		Remote: &core.ConflictItem{
-			Record:  toRecord(secret),
+			Record:  remote,
 			Deleted: secret.GetDeleted(),
 		},
 	}
-	return persistence.TxInsertConflict(ctx, tx, conflict)
+	// files and deletions are resolved by the user
+	if local.BigData || remote.BigData || local.Deleted || secret.GetDeleted() || local.Type != remote.Type {
+		return conflict, nil
+	}
+	base, err := persistence.TxGetBaseRecord(ctx, tx, local.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return conflict, nil
+		}
+		return nil, err
+	}
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	baseData, err := base.Decode(ss.decoder, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	localData, err := local.Decode(ss.decoder, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	remoteData, err := remote.Decode(ss.decoder, masterKey)
+	if err != nil {
+		return nil, err
+	}
+	result, err := core.Merge(baseData, localData, remoteData)
+	if err != nil {
+		if errors.Is(err, core.ErrNotMergeable) {
+			return conflict, nil
+		}
+		return nil, err
+	}
+	if len(result.Fields) > 0 {
+		if err = ss.reencode(local, result.Local, masterKey, local.Version); err != nil {
+			return nil, err
+		}
+		if err = ss.reencode(remote, result.Remote, masterKey, remote.Version); err != nil {
+			return nil, err
+		}
+		conflict.Local.Fields = result.Fields
+		conflict.Remote.Fields = result.Fields
+		return conflict, nil
+	}
+	if err = ss.reencode(local, result.Local, masterKey, version); err != nil {
+		return nil, err
+	}
+	local.ModifiedAt = time.Now().UTC().Truncate(time.Second)
+	if err = persistence.TxUpdateRecord(ctx, tx, local); err != nil {
+		return nil, err
+	}
+	return nil, persistence.TxSaveBaseRecord(ctx, tx, remote)
+}
+
+// reencode encrypt new data of record with a fresh dek
+func (ss *SyncService) reencode(record *core.Record, data, masterKey []byte, version int32) error {
+	dek, err := datatool.GenerateDek(32)
+	if err != nil {
+		return err
+	}
+	record.Version = version
+	return record.Encode(ss.encoder, data, dek, masterKey)
 }
+
 func (ss *SyncService) update(ctx context.Context, tx *sql.Tx, target *core.Record, secret *pb.Secret) error {
 	if secret.GetIsBig() {
 		if err := ss.updateFile(ctx, target, secret); err != nil {
@@ -304,61 +697,27 @@ func (ss *SyncService) update(ctx context.Context, tx *sql.Tx, target *core.Reco
 	target.Dek = secret.GetDek()
 	target.Data = secret.GetData()
 	target.Version = secret.GetVersion()
-	return persistence.TxUpdateRecord(ctx, tx, target)
+	target.Legacy = false
+	if err := persistence.TxUpdateRecord(ctx, tx, target); err != nil {
+		return err
+	}
+	return persistence.TxSaveBaseRecord(ctx, tx, target)
 }
 
 func (ss *SyncService) updateFile(ctx context.Context, target *core.Record, secret *pb.Secret) error {
 	if target.Version < secret.GetVersion() {
-		var pollStream pb.PullStreamRequest
-		pollStream.SetId(target.ID)
-		pollStream.SetVersion(secret.GetVersion())
-		stream, err := ss.client.PullStream(ctx, &pollStream)
-		if err != nil {
-			return err
-		}
-		file, err := ss.fileProvider.OpenWrite(target.ID, secret.GetVersion())
-		if err != nil {
+		if err := ss.download(ctx, secret); err != nil {
 			return err
 		}
-		defer func(file io.WriteCloser) {
-			err = file.Close()
-			if err != nil {
-				fmt.Printf("failed to close file: %s\n", err)
-			}
-		}(file)
-		for {
-			var chunk *pb.Chunk
-			chunk, err = stream.Recv()
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					break
-				}
-				return err
-			}
-			switch chunk.GetType() {
-			case pb.ChunkType_FilePart:
-				buffer := chunk.GetBuffer()
-				written := 0
-				for written < len(buffer) {
-					var n int
-					n, err = file.Write(buffer[written:])
-					if err != nil {
-						return err
-					}
-					written += n
-				}
-			case pb.ChunkType_ErrData:
-				return nil
-			}
-		}
 		if target.BigData {
-			if err = ss.fileProvider.Remove(target.ID, target.Version); err != nil {
+			if err := ss.fileProvider.Remove(target.ID, target.Version); err != nil {
 				return err
 			}
 		}
 	}
 	return nil
 }
+
 func (ss *SyncService) create(ctx context.Context, tx *sql.Tx, secret *pb.Secret) error {
 	record := toRecord(secret)
 	if record.BigData {
@@ -366,54 +725,123 @@ func (ss *SyncService) create(ctx context.Context, tx *sql.Tx, secret *pb.Secret
 			return err
 		}
 	}
-	return persistence.TxInsertRecord(ctx, tx, record)
+	if err := persistence.TxInsertRecord(ctx, tx, record); err != nil {
+		return err
+	}
+	return persistence.TxSaveBaseRecord(ctx, tx, record)
 }
 
 func (ss *SyncService) createFile(ctx context.Context, secret *pb.Secret) error {
+	if err := ss.fileProvider.Remove(secret.GetId(), secret.GetVersion()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return ss.download(ctx, secret)
+}
+
+// download receive file from server and check it belongs to the record. File shared by another user is
+// sealed under the version of the owner, it's received aside and sealed again under the local version
+func (ss *SyncService) download(ctx context.Context, secret *pb.Secret, dst ...string) error {
+	record := toRecord(secret)
+	sealed, dir := record, dst
+	if record.IsShared() {
+		owner := *record
+		owner.Version = secret.GetDataVersion()
+		sealed, dir = &owner, []string{sharedDir}
+		if err := ss.fileProvider.Remove(sealed.ID, sealed.Version, dir...); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
 	var pollStream pb.PullStreamRequest
-	pollStream.SetId(secret.GetId())
-	pollStream.SetVersion(secret.GetVersion())
+	pollStream.SetId(record.ID)
+	pollStream.SetVersion(record.Version)
 	stream, err := ss.client.PullStream(ctx, &pollStream)
 	if err != nil {
 		return err
 	}
-	if err = ss.fileProvider.Remove(secret.GetId(), secret.GetVersion()); err != nil && !os.IsNotExist(err) {
+	file, err := ss.fileProvider.OpenWrite(sealed.ID, sealed.Version, dir...)
+	if err != nil {
+		return err
+	}
+	if err = receive(stream, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	err = ss.verifyFile(ctx, record, sealed, dir...)
+	if err != nil || sealed != record {
+		if rmErr := ss.fileProvider.Remove(sealed.ID, sealed.Version, dir...); rmErr != nil {
+			fmt.Printf("failed to remove file: %s\n", rmErr)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrTamperedSecret, record.ID, err)
+	}
+	return nil
+}
+
+// verifyFile check file of the sealed record, read from dir, belongs to the record. File sealed under
+// another version is sealed again as the file of the record
+func (ss *SyncService) verifyFile(ctx context.Context, record, sealed *core.Record, dir ...string) error {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
 		return err
 	}
-	file, err := ss.fileProvider.OpenWrite(secret.GetId(), secret.GetVersion())
+	dek, err := record.DecodeDek(ss.decoder, masterKey)
 	if err != nil {
 		return err
 	}
-	defer func(file io.WriteCloser) {
-		err := file.Close()
-		if err != nil {
-			fmt.Printf("failed to close file: %s\n", err)
+	if sealed != record {
+		return reencryptFile(ss.fileProvider, ss.encoder, ss.decoder, sealed, dek, record, dek, dir...)
+	}
+	file, err := ss.fileProvider.OpenRead(record.ID, record.Version, dir...)
+	if err != nil {
+		return err
+	}
+	reader := crypto.NewFileDecoder(ss.decoder, file, dek, record.AdditionalData(core.FileRole))
+	defer func(reader io.ReadCloser) {
+		_ = reader.Close()
+	}(reader)
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+// verify check every received secret is encrypted for the record it claims to be
+func (ss *SyncService) verify(ctx context.Context, secrets []*pb.Secret) error {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if secret.GetDeleted() {
+			continue
 		}
-	}(file)
+		if _, err = toRecord(secret).Decode(ss.decoder, masterKey); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrTamperedSecret, secret.GetId(), err)
+		}
+	}
+	return nil
+}
+
+func receive(stream grpc.ServerStreamingClient[pb.Chunk], file io.Writer) error {
 	for {
 		chunk, err := stream.Recv()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				break
+				return nil
 			}
 			return err
 		}
 		switch chunk.GetType() {
 		case pb.ChunkType_FilePart:
-			buffer := chunk.GetBuffer()
-			written := 0
-			for written < len(buffer) {
-				n, err := file.Write(buffer[written:])
-				if err != nil {
-					return err
-				}
-				written += n
+			if _, err = file.Write(chunk.GetBuffer()); err != nil {
+				return err
 			}
 		case pb.ChunkType_ErrData:
 			continue
 		}
 	}
-	return nil
 }
 
 func (ss *SyncService) delete(ctx context.Context, tx *sql.Tx, target *core.Record) error {
@@ -422,9 +850,26 @@ func (ss *SyncService) delete(ctx context.Context, tx *sql.Tx, target *core.Reco
 			return err
 		}
 	}
+	if err := persistence.TxDeleteBaseRecord(ctx, tx, target.ID); err != nil {
+		return err
+	}
 	return persistence.TxDeleteRecord(ctx, tx, target.ID)
 }
 
+// checkReplay received secret must be newer than the synced state and not older than the local copy.
+// Tombstones carry no authenticated data, so an old version or deletion replayed by the server is refused
+func checkReplay(record *core.Record, secret *pb.Secret, syncState *core.SyncState) error {
+	if secret.GetVersion() <= syncState.Value {
+		return fmt.Errorf("%w: %s: version %d is not after synced %d",
+			ErrTamperedSecret, secret.GetId(), secret.GetVersion(), syncState.Value)
+	}
+	if record != nil && secret.GetVersion() < record.Version {
+		return fmt.Errorf("%w: %s: version %d is older than local %d",
+			ErrTamperedSecret, secret.GetId(), secret.GetVersion(), record.Version)
+	}
+	return nil
+}
+
 func isConflictDetected(record *core.Record, secret *pb.Secret, syncState *core.SyncState) bool {
 	return record.IsChanged(syncState) && !record.ModifiedAt.Equal(secret.GetModifiedAt().AsTime())
 }
@@ -479,6 +924,8 @@ func toRecord(secret *pb.Secret) *core.Record {
 	record.Dek = secret.GetDek()
 	record.Data = secret.GetData()
 	record.Version = secret.GetVersion()
+	record.Owner = secret.GetOwner()
+	record.Vault = secret.GetVaultId()
 	switch secret.GetType() {
 	case pb.SecretType_LoginPass:
 		record.Type = core.LoginPassType
@@ -492,19 +939,47 @@ func toRecord(secret *pb.Secret) *core.Record {
 	return &record
 }
 
-func getState(ctx context.Context, tx *sql.Tx) (*core.SyncState, error) {
-	syncState, err := persistence.TxGetState(ctx, tx, syncTypeName)
+func getState(ctx context.Context, db *sql.DB) (*core.SyncState, error) {
+	return getNamedState(ctx, db, syncTypeName)
+}
+
+// getScopeState state of own records when vault is nil, otherwise state of the vault
+func getScopeState(ctx context.Context, db *sql.DB, vault *core.Vault) (*core.SyncState, error) {
+	if vault == nil {
+		return getState(ctx, db)
+	}
+	return getNamedState(ctx, db, vaultStateName(vault.ID))
+}
+
+func getNamedState(ctx context.Context, db *sql.DB, name string) (*core.SyncState, error) {
+	syncState, err := persistence.GetState(ctx, db, name)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return nil, err
 		}
 		syncState = &core.SyncState{
-			ID:    syncTypeName,
+			ID:    name,
 			Value: 0,
 		}
 	}
 	return syncState, nil
 }
+
+// getPullProgress read progress of an interrupted pull. Progress of a pull started from another version is dropped
+func getPullProgress(ctx context.Context, db *sql.DB, syncState *core.SyncState) (*core.PullProgress, error) {
+	progress, err := persistence.GetPullProgress(ctx, db, syncState.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if progress == nil || progress.Since != syncState.Value {
+		progress = &core.PullProgress{
+			ID:    syncState.ID,
+			Since: syncState.Value,
+		}
+	}
+	return progress, nil
+}
+
 func toSecret(record *core.Record) *pb.Secret {
 	var secret pb.Secret
 	secret.SetId(record.ID)
@@ -535,3 +1010,7 @@ func NewEmptySyncer() *EmptySyncer {
 func (e EmptySyncer) Sync(ctx context.Context, option *SyncOption) error {
 	return nil
 }
+
+func (e EmptySyncer) Watch(ctx context.Context) error {
+	return ErrServerUnavailable
+}
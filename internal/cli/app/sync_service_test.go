@@ -1 +1,203 @@
 package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeShouldTakeNotOverlappingFields(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	ss := NewSyncService(nil, manager.db, manager.fp, manager.encoder, manager.decoder)
+	local, secret := prepareMerge(ctx, t, manager, "local", &core.LoginPass{
+		Name: "Test", Login: "Login", Pass: "Pass", URL: "https://remote",
+	})
+	tx, err := manager.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	conflict, err := ss.merge(ctx, tx, local, secret, 3)
+
+	assert.NoError(t, err)
+	assert.Nil(t, conflict)
+	record, err := persistence.TxGetRecordByID(ctx, tx, local.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), record.Version)
+	masterKey, _ := common.GetMasterKey(ctx)
+	lp, err := record.DecodeLoginPass(manager.decoder, masterKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "local", lp.Pass)
+	assert.Equal(t, "https://remote", lp.URL)
+	base, err := persistence.TxGetBaseRecord(ctx, tx, local.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, secret.GetVersion(), base.Version)
+}
+
+func TestMergeShouldLeaveOnlyConflictingFields(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	ss := NewSyncService(nil, manager.db, manager.fp, manager.encoder, manager.decoder)
+	local, secret := prepareMerge(ctx, t, manager, "local", &core.LoginPass{
+		Name: "Test", Login: "Login", Pass: "remote", URL: "https://remote",
+	})
+	tx, err := manager.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	conflict, err := ss.merge(ctx, tx, local, secret, 3)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, conflict)
+	assert.Equal(t, []string{"pass"}, conflict.Local.Fields)
+	masterKey, _ := common.GetMasterKey(ctx)
+	lp, err := conflict.Local.Record.DecodeLoginPass(manager.decoder, masterKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "local", lp.Pass)
+	assert.Equal(t, "https://remote", lp.URL)
+	rp, err := conflict.Remote.Record.DecodeLoginPass(manager.decoder, masterKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "remote", rp.Pass)
+	assert.Equal(t, "https://remote", rp.URL)
+}
+
+// prepareMerge create synced record, change password locally and build remote revision
+func prepareMerge(
+	ctx context.Context,
+	t *testing.T,
+	manager *DataManager,
+	pass string,
+	remote *core.LoginPass,
+) (*core.Record, *pb.Secret) {
+	id, err := createLoginPass(ctx, manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := persistence.GetRecordByID(ctx, manager.db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := manager.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = persistence.TxSaveBaseRecord(ctx, tx, base); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = manager.UpdateLoginPass(common.SetVersion(ctx, base.Version), id, &LoginPassRequest{Pass: pass}, false); err != nil {
+		t.Fatal(err)
+	}
+	local, err := persistence.GetRecordByID(ctx, manager.db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dek, err := datatool.GenerateDek(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := *base
+	other.Version = base.Version + 1
+	other.ModifiedAt = time.Now().Add(time.Minute).UTC()
+	if err = other.Encode(manager.encoder, data, dek, masterKey); err != nil {
+		t.Fatal(err)
+	}
+	return local, toSecret(&other)
+}
+
+func TestVerifyShouldRejectSecretSealedWithoutAdditionalData(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	ss := NewSyncService(nil, manager.db, manager.fp, manager.encoder, manager.decoder)
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dek, err := datatool.GenerateDek(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := core.CreateRecord(core.TextType)
+	record.Version = 1
+	// a legacy marker set by the server side is not trusted
+	record.Legacy = true
+	if record.Data, err = manager.encoder.Encode([]byte(`{}`), dek, nil); err != nil {
+		t.Fatal(err)
+	}
+	if record.Dek, err = manager.encoder.Encode(dek, masterKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	err = ss.verify(ctx, []*pb.Secret{toSecret(record)})
+	assert.ErrorIs(t, err, ErrTamperedSecret)
+}
+
+func TestApplyPageShouldRejectReplayedVersions(t *testing.T) {
+	ctx, manager, cleanUp := configure(t)
+	defer cleanUp()
+	ss := NewSyncService(nil, manager.db, manager.fp, manager.encoder, manager.decoder)
+	id, err := createTextContent(ctx, manager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := persistence.GetRecordByID(ctx, manager.db, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local.Version = 5
+	if err = persistence.UpdateRecord(ctx, manager.db, local); err != nil {
+		t.Fatal(err)
+	}
+	older := *local
+	older.Version = 4
+	older.Deleted = true
+	cases := []struct {
+		name   string
+		state  int32
+		secret *pb.Secret
+	}{
+		{"tombstone older than local", 3, toSecret(&older)},
+		{"version already synced", 5, toSecret(local)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var page pb.PullPage
+			page.SetVersion(6)
+			page.SetLast(true)
+			page.SetSecrets([]*pb.Secret{c.secret})
+			state := &core.SyncState{ID: "state", Value: c.state}
+			tx, err := manager.db.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+			err = ss.applyPage(ctx, tx, state, &core.PullProgress{ID: "state"}, &page, false)
+			assert.ErrorIs(t, err, ErrTamperedSecret)
+		})
+	}
+	stored, err := persistence.GetRecordByID(ctx, manager.db, id)
+	assert.NoError(t, err)
+	assert.False(t, stored.Deleted)
+	assert.Equal(t, int32(5), stored.Version)
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	ErrInvalidCA           = errors.New("no certificates found in CA bundle")
+	ErrFingerprintMismatch = errors.New("server key doesn't match pinned fingerprint")
+	ErrNoServerCertificate = errors.New("server presented no certificate")
+	ErrCertificateExpired  = errors.New("server certificate is expired or not yet valid")
+)
+
+const fingerprintPrefix = "sha256:"
+
+// NewTransportCredentials credentials of connection to the server, plaintext when TLS is off.
+// Pinned key is trusted without CA, system roots are used when there is neither
+func NewTransportCredentials(server *core.Server) (credentials.TransportCredentials, error) {
+	if !server.TLS {
+		return insecure.NewCredentials(), nil
+	}
+	roots, err := trustedRoots(server, server.Fingerprint != "")
+	if err != nil {
+		return nil, err
+	}
+	config, err := newTLSConfig(server, roots)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// Fingerprint sha256 of certificate public key, stays the same when certificate is renewed with the same key
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return fingerprintPrefix + hex.EncodeToString(sum[:])
+}
+
+// ProbeFingerprint read fingerprint of the key presented by the server to be pinned, the pin isn't checked.
+// Certificate chain is verified against CA when it's set, the user confirms the key otherwise
+func ProbeFingerprint(ctx context.Context, server *core.Server) (string, error) {
+	unpinned := *server
+	unpinned.Fingerprint = ""
+	roots, err := trustedRoots(server, true)
+	if err != nil {
+		return "", err
+	}
+	config, err := newTLSConfig(&unpinned, roots)
+	if err != nil {
+		return "", err
+	}
+	config.NextProtos = []string{"h2"}
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", server.Address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", ErrNoServerCertificate
+	}
+	return Fingerprint(certs[0]), nil
+}
+
+// VerifyFingerprint probe the server and compare its key with the pinned one
+func VerifyFingerprint(ctx context.Context, server *core.Server) (string, error) {
+	actual, err := ProbeFingerprint(ctx, server)
+	if err != nil {
+		return "", err
+	}
+	return actual, checkPin(server.Fingerprint, actual)
+}
+
+func checkPin(pin, actual string) error {
+	if pin != "" && pin != actual {
+		return fmt.Errorf("%w: pinned %s, presented %s", ErrFingerprintMismatch, pin, actual)
+	}
+	return nil
+}
+
+// trustedRoots roots the certificate chain is verified against: CA when it's set, none when the key is pinned,
+// system roots otherwise
+func trustedRoots(server *core.Server, pinned bool) (*x509.CertPool, error) {
+	if server.CA == "" {
+		if pinned {
+			return nil, nil
+		}
+		return x509.SystemCertPool()
+	}
+	data, err := os.ReadFile(server.CA)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, ErrInvalidCA
+	}
+	return roots, nil
+}
+
+func newTLSConfig(server *core.Server, roots *x509.CertPool) (*tls.Config, error) {
+	// chain is verified in verifyConnection
+	config := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}
+	if server.Cert != "" || server.Key != "" {
+		cert, err := tls.LoadX509KeyPair(server.Cert, server.Key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	host, _, err := net.SplitHostPort(server.Address)
+	if err != nil {
+		return nil, err
+	}
+	config.ServerName = host
+	pin := server.Fingerprint
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		return verifyConnection(state, roots, host, pin)
+	}
+	return config, nil
+}
+
+// verifyConnection check server key against the pin and certificate chain against roots and host name.
+// Without roots only host name and validity period of the certificate are checked
+func verifyConnection(state tls.ConnectionState, roots *x509.CertPool, host, pin string) error {
+	if len(state.PeerCertificates) == 0 {
+		return ErrNoServerCertificate
+	}
+	leaf := state.PeerCertificates[0]
+	if err := checkPin(pin, Fingerprint(leaf)); err != nil {
+		return err
+	}
+	if roots == nil {
+		now := time.Now()
+		if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+			return ErrCertificateExpired
+		}
+		return leaf.VerifyHostname(host)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
@@ -0,0 +1,233 @@
+package app
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func TestRemoteClient_TLS_ShouldVerifyServerWithCA(t *testing.T) {
+	pki := createTestPKI(t)
+	addr := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{pki.server}})
+
+	trusted := checkHealth(t, &core.Server{Address: addr, TLS: true, CA: pki.caFile})
+	untrusted := checkHealth(t, &core.Server{Address: addr, TLS: true, CA: createTestPKI(t).caFile})
+
+	assert.NoError(t, trusted)
+	assert.Error(t, untrusted)
+}
+
+func TestRemoteClient_TLS_ShouldTrustPinnedKey(t *testing.T) {
+	pki := createTestPKI(t)
+	addr := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{pki.server}})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fingerprint, err := ProbeFingerprint(ctx, &core.Server{Address: addr, TLS: true, CA: pki.caFile})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Fingerprint(pki.server.Leaf), fingerprint)
+	assert.NoError(t, checkHealth(t, &core.Server{Address: addr, TLS: true, CA: pki.caFile, Fingerprint: fingerprint}))
+}
+
+func TestRemoteClient_TLS_ShouldTrustPinnedKeyWithoutCA(t *testing.T) {
+	pki := createTestPKI(t)
+	addr := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{pki.server}})
+	_, port, _ := net.SplitHostPort(addr)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the certificate isn't issued by system roots, the confirmed key is trusted on its own
+	fingerprint, err := ProbeFingerprint(ctx, &core.Server{Address: addr, TLS: true})
+	pinned := checkHealth(t, &core.Server{Address: addr, TLS: true, Fingerprint: fingerprint})
+	unpinned := checkHealth(t, &core.Server{Address: addr, TLS: true})
+	otherKey := checkHealth(t, &core.Server{Address: addr, TLS: true, Fingerprint: Fingerprint(createTestPKI(t).server.Leaf)})
+	otherHost := checkHealth(t, &core.Server{Address: "127.0.0.1:" + port, TLS: true, Fingerprint: fingerprint})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Fingerprint(pki.server.Leaf), fingerprint)
+	assert.NoError(t, pinned)
+	assert.Error(t, unpinned)
+	assert.Error(t, otherKey)
+	assert.Error(t, otherHost)
+}
+
+func TestRemoteClient_TLS_ShouldVerifyHostName(t *testing.T) {
+	pki := createTestPKI(t)
+	addr := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{pki.server}})
+
+	_, port, _ := net.SplitHostPort(addr)
+
+	// the certificate is issued for localhost
+	err := checkHealth(t, &core.Server{Address: "127.0.0.1:" + port, TLS: true, CA: pki.caFile})
+
+	assert.Error(t, err)
+}
+
+func TestRemoteClient_TLS_ShouldRefuseChangedKey(t *testing.T) {
+	pinned := createTestPKI(t)
+	pki := createTestPKI(t)
+	addr := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{pki.server}})
+	serv := &core.Server{Address: addr, TLS: true, CA: pki.caFile, Fingerprint: Fingerprint(pinned.server.Leaf)}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	healthErr := checkHealth(t, serv)
+	_, verifyErr := VerifyFingerprint(ctx, serv)
+
+	assert.Error(t, healthErr)
+	assert.ErrorIs(t, verifyErr, ErrFingerprintMismatch)
+}
+
+func TestRemoteClient_MutualTLS_ShouldPresentClientCertificate(t *testing.T) {
+	pki := createTestPKI(t)
+	addr := startTLSServer(t, &tls.Config{
+		Certificates: []tls.Certificate{pki.server},
+		ClientCAs:    pki.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+
+	withCert := checkHealth(t, &core.Server{Address: addr, TLS: true, CA: pki.caFile, Cert: pki.certFile, Key: pki.keyFile})
+	withoutCert := checkHealth(t, &core.Server{Address: addr, TLS: true, CA: pki.caFile})
+
+	assert.NoError(t, withCert)
+	assert.Error(t, withoutCert)
+}
+
+func checkHealth(t *testing.T, server *core.Server) error {
+	creds, err := NewTransportCredentials(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewRemoteClient(server.Address, "", "", creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.IsHealthy(ctx)
+}
+
+type healthyServer struct {
+	pb.UnimplementedHealthServiceServer
+}
+
+func (healthyServer) Check(context.Context, *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	var res pb.HealthCheckResponse
+	res.SetState(pb.ServerState_Healthy)
+	return &res, nil
+}
+
+func startTLSServer(t *testing.T, config *tls.Config) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(config)))
+	pb.RegisterHealthServiceServer(server, healthyServer{})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return "localhost:" + portOf(listener.Addr())
+}
+
+func portOf(addr net.Addr) string {
+	_, port, _ := net.SplitHostPort(addr.String())
+	return port
+}
+
+type testPKI struct {
+	pool     *x509.CertPool
+	server   tls.Certificate
+	caFile   string
+	certFile string
+	keyFile  string
+}
+
+// createTestPKI CA with server and client certificates for localhost
+func createTestPKI(t *testing.T) *testPKI {
+	dir := t.TempDir()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "keeper test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pki := &testPKI{pool: x509.NewCertPool(), caFile: filepath.Join(dir, "ca.crt")}
+	pki.pool.AddCert(caCert)
+	writePEM(t, pki.caFile, "CERTIFICATE", caDer)
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			DNSNames:     []string{"localhost"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, keyDer
+	}
+	serverDer, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	pki.server, err = tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverDer}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: serverKey}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientDer, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+	pki.certFile = filepath.Join(dir, "client.crt")
+	pki.keyFile = filepath.Join(dir, "client.key")
+	writePEM(t, pki.certFile, "CERTIFICATE", clientDer)
+	writePEM(t, pki.keyFile, "PRIVATE KEY", clientKey)
+	return pki
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
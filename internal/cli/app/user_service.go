@@ -56,6 +56,10 @@ func (us *UserService) Auth(ctx context.Context, pass string) ([]byte, error) {
 	if !shared.Compare(hash, user.Password) {
 		return nil, errors.New("invalid password")
 	}
+	return deriveMasterKey(pass), nil
+}
+
+func deriveMasterKey(pass string) []byte {
 	key := sha256.Sum256([]byte(pass))
-	return key[:], nil
+	return key[:]
 }
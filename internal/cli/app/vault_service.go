@@ -0,0 +1,393 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	clicommon "github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/beevik/guid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrVaultNotFound = errors.New("vault not found, sync first")
+	ErrVaultReadOnly = errors.New("vault is read only for you")
+	ErrVaultRecord   = errors.New("record belongs to a vault")
+)
+
+// VaultService vaults shared by a team. Vault key is sealed to every member, records of the vault
+// are re-encrypted with it on push and pull
+type VaultService struct {
+	client   *RemoteClient
+	db       *sql.DB
+	serverID int32
+	shares   *ShareService
+	fp       *datatool.FileProvider
+	encoder  core.Encoder
+	decoder  core.Decoder
+}
+
+func NewVaultService(
+	client *RemoteClient,
+	db *sql.DB,
+	serverID int32,
+	shares *ShareService,
+	fileProvider *datatool.FileProvider,
+	encoder core.Encoder,
+	decoder core.Decoder,
+) *VaultService {
+	return &VaultService{
+		client:   client,
+		db:       db,
+		serverID: serverID,
+		shares:   shares,
+		fp:       fileProvider,
+		encoder:  encoder,
+		decoder:  decoder,
+	}
+}
+
+// Create create vault, the user becomes its owner
+func (vs *VaultService) Create(ctx context.Context, name string) (*core.Vault, error) {
+	if err := vs.shares.Ensure(ctx); err != nil {
+		return nil, err
+	}
+	shareKey, err := persistence.GetShareKey(ctx, vs.db, vs.serverID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoShareKey
+		}
+		return nil, err
+	}
+	key, err := datatool.GenerateDek(32)
+	if err != nil {
+		return nil, err
+	}
+	id := guid.NewString()
+	sealed, err := crypto.SealKey(key, shareKey.PublicKey, vaultKeyAdditionalData(id))
+	if err != nil {
+		return nil, err
+	}
+	var req pb.CreateVaultRequest
+	req.SetId(id)
+	req.SetName(name)
+	req.SetVaultKey(sealed)
+	res, err := vs.client.VaultsClient.Create(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	vault, err := vs.wrap(ctx, res.GetVault(), key)
+	if err != nil {
+		return nil, err
+	}
+	if err = vs.inTx(ctx, func(tx *sql.Tx) error {
+		return persistence.TxSaveVault(ctx, tx, vault)
+	}); err != nil {
+		return nil, err
+	}
+	return vault, nil
+}
+
+// Invite add the user to the vault or change their role. Returns fingerprint of the key
+// the vault key was sealed to
+func (vs *VaultService) Invite(ctx context.Context, id, login string, role core.VaultRole) (string, error) {
+	vault, err := vs.Find(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	key, err := vs.key(ctx, vault)
+	if err != nil {
+		return "", err
+	}
+	publicKey, err := vs.shares.PublicKey(ctx, login)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := crypto.SealKey(key, publicKey, vaultKeyAdditionalData(vault.ID))
+	if err != nil {
+		return "", err
+	}
+	var req pb.InviteRequest
+	req.SetVaultId(vault.ID)
+	req.SetLogin(login)
+	req.SetRole(pb.VaultRole(role))
+	req.SetVaultKey(sealed)
+	if _, err = vs.client.Invite(ctx, &req); err != nil {
+		return "", err
+	}
+	return ShareKeyFingerprint(publicKey), nil
+}
+
+// Members members of the vault
+func (vs *VaultService) Members(ctx context.Context, id string) ([]*core.VaultMember, error) {
+	vault, err := vs.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var req pb.MembersRequest
+	req.SetVaultId(vault.ID)
+	res, err := vs.client.Members(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*core.VaultMember, len(res.GetMembers()))
+	for i, member := range res.GetMembers() {
+		members[i] = &core.VaultMember{
+			Login:     member.GetLogin(),
+			Role:      core.VaultRole(member.GetRole()),
+			PublicKey: member.GetPublicKey(),
+			CreatedAt: member.GetCreatedAt().AsTime(),
+		}
+	}
+	return members, nil
+}
+
+// Leave stop being a member of the vault, its records are removed from this device
+func (vs *VaultService) Leave(ctx context.Context, id string) error {
+	vault, err := vs.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+	var req pb.LeaveVaultRequest
+	req.SetVaultId(vault.ID)
+	if _, err = vs.client.Leave(ctx, &req); err != nil {
+		return err
+	}
+	return vs.inTx(ctx, func(tx *sql.Tx) error {
+		return vs.forget(ctx, tx, vault)
+	})
+}
+
+// List vaults the user is a member of
+func (vs *VaultService) List(ctx context.Context) ([]*core.Vault, error) {
+	return vs.refresh(ctx)
+}
+
+// refresh save vaults the user is a member of with their keys, vaults the user was removed from are forgotten
+func (vs *VaultService) refresh(ctx context.Context) ([]*core.Vault, error) {
+	res, err := vs.client.VaultsClient.List(ctx, &pb.ListVaultsRequest{})
+	if err != nil {
+		// server without vaults
+		if status.Code(err) == codes.Unimplemented {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var privateKey []byte
+	vaults := make([]*core.Vault, 0, len(res.GetVaults()))
+	listed := make(map[string]struct{}, len(res.GetVaults()))
+	for _, item := range res.GetVaults() {
+		if privateKey == nil {
+			if privateKey, err = vs.shares.privateKey(ctx); err != nil {
+				return nil, err
+			}
+		}
+		key, err := crypto.OpenKey(item.GetVaultKey(), privateKey, vaultKeyAdditionalData(item.GetId()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open key of vault %s: %w", item.GetName(), err)
+		}
+		vault, err := vs.wrap(ctx, item, key)
+		if err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, vault)
+		listed[vault.ID] = struct{}{}
+	}
+	if err = vs.inTx(ctx, func(tx *sql.Tx) error {
+		known, err := persistence.TxGetAllVaults(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, vault := range known {
+			if _, ok := listed[vault.ID]; ok || vault.ServerID != vs.serverID {
+				continue
+			}
+			fmt.Printf("you are not a member of vault %s anymore\n", vault.Name)
+			if err = vs.forget(ctx, tx, vault); err != nil {
+				return err
+			}
+		}
+		for _, vault := range vaults {
+			if err = persistence.TxSaveVault(ctx, tx, vault); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return vaults, nil
+}
+
+// forget remove the vault and its records from this device
+func (vs *VaultService) forget(ctx context.Context, tx *sql.Tx, vault *core.Vault) error {
+	records, err := persistence.TxGetVaultRecordGreater(ctx, tx, vault.ID, -1)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.BigData {
+			if err = vs.fp.Remove(record.ID, record.Version); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err = persistence.TxDeleteBaseRecord(ctx, tx, record.ID); err != nil {
+			return err
+		}
+	}
+	if err = persistence.TxDeleteVaultRecords(ctx, tx, vault.ID); err != nil {
+		return err
+	}
+	if err = persistence.TxDeleteState(ctx, tx, vaultStateName(vault.ID)); err != nil {
+		return err
+	}
+	if err = persistence.TxDeletePullProgress(ctx, tx, vaultStateName(vault.ID)); err != nil {
+		return err
+	}
+	return persistence.TxDeleteVault(ctx, tx, vault.ID)
+}
+
+// open re-encrypt pulled secrets of the vault under the master key
+func (vs *VaultService) open(ctx context.Context, vault *core.Vault, secrets []*pb.Secret) error {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return err
+	}
+	key, err := vs.key(ctx, vault)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		if secret.GetDeleted() {
+			continue
+		}
+		record := toRecord(secret)
+		if err = record.Rekey(vs.encoder, vs.decoder, key, masterKey, record.Version); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrTamperedSecret, secret.GetId(), err)
+		}
+		secret.SetDek(record.Dek)
+		secret.SetData(record.Data)
+	}
+	return nil
+}
+
+// seal copy of the record encrypted with the vault key to be pushed. Local record stays under the master key
+func (vs *VaultService) seal(ctx context.Context, vault *core.Vault, record *core.Record) (*core.Record, error) {
+	if record.Deleted {
+		return record, nil
+	}
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := vs.key(ctx, vault)
+	if err != nil {
+		return nil, err
+	}
+	sealed := *record
+	if err = sealed.Rekey(vs.encoder, vs.decoder, masterKey, key, record.Version); err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// key unwrap the vault key with the master key
+func (vs *VaultService) key(ctx context.Context, vault *core.Vault) ([]byte, error) {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vs.decoder.Decode(vault.Key, masterKey, vaultKeyAdditionalData(vault.ID))
+}
+
+// wrap vault as the server lists it with the key wrapped with the master key
+func (vs *VaultService) wrap(ctx context.Context, item *pb.Vault, key []byte) (*core.Vault, error) {
+	masterKey, err := clicommon.GetMasterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := vs.encoder.Encode(key, masterKey, vaultKeyAdditionalData(item.GetId()))
+	if err != nil {
+		return nil, err
+	}
+	return &core.Vault{
+		ID:       item.GetId(),
+		ServerID: vs.serverID,
+		Name:     item.GetName(),
+		Role:     core.VaultRole(item.GetRole()),
+		Key:      wrapped,
+	}, nil
+}
+
+// Find vault known on this device by id or name
+func (vs *VaultService) Find(ctx context.Context, id string) (*core.Vault, error) {
+	vault, err := persistence.GetVault(ctx, vs.db, id)
+	if err == nil {
+		return vault, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	vaults, err := persistence.GetVaults(ctx, vs.db, vs.serverID)
+	if err != nil {
+		return nil, err
+	}
+	for _, vault = range vaults {
+		if vault.Name == id {
+			return vault, nil
+		}
+	}
+	return nil, ErrVaultNotFound
+}
+
+func (vs *VaultService) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := vs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rekeyVaultKeys wrap vault keys with a new master key
+func rekeyVaultKeys(ctx context.Context, tx *sql.Tx, encoder core.Encoder, decoder core.Decoder, oldKey, newKey []byte) error {
+	vaults, err := persistence.TxGetAllVaults(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, vault := range vaults {
+		ad := vaultKeyAdditionalData(vault.ID)
+		key, err := decoder.Decode(vault.Key, oldKey, ad)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt key of vault %s: %w", vault.Name, err)
+		}
+		if vault.Key, err = encoder.Encode(key, newKey, ad); err != nil {
+			return err
+		}
+		if err = persistence.TxSaveVault(ctx, tx, vault); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vaultStateName name of sync state and pull progress of the vault
+func vaultStateName(id string) string {
+	return syncTypeName + ":" + id
+}
+
+func vaultKeyAdditionalData(id string) []byte {
+	return []byte(fmt.Sprintf("keeper|%s|vault-key", id))
+}
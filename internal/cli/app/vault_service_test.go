@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/DimKa163/keeper/internal/cli/persistence"
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultService_SealShouldOpenUnderAnotherMemberKey(t *testing.T) {
+	encoder := crypto.NewGzipEncoder(crypto.NewAesEncoder())
+	decoder := crypto.NewGzipDecoder(crypto.NewAesDecoder())
+	writerKey, _ := datatool.GenerateDek(32)
+	readerKey, _ := datatool.GenerateDek(32)
+	vaultKey, _ := datatool.GenerateDek(32)
+	vs := NewVaultService(nil, nil, 1, nil, nil, encoder, decoder)
+	vaultID := "infra"
+	writerVault := wrappedVault(t, encoder, vaultID, vaultKey, writerKey)
+	readerVault := wrappedVault(t, encoder, vaultID, vaultKey, readerKey)
+	record := core.CreateRecord(core.TextType)
+	record.Vault = vaultID
+	record.Version = 3
+	dek, _ := datatool.GenerateDek(32)
+	assert.NoError(t, record.Encode(encoder, []byte(`{"content":"vault"}`), dek, writerKey))
+
+	sealed, err := vs.seal(common.SetMasterKey(context.Background(), writerKey), writerVault, record)
+	assert.NoError(t, err)
+	secret := toSecret(sealed)
+	secret.SetVaultId(vaultID)
+	err = vs.open(common.SetMasterKey(context.Background(), readerKey), readerVault, []*pb.Secret{secret})
+
+	assert.NoError(t, err)
+	opened := toRecord(secret)
+	assert.Equal(t, vaultID, opened.Vault)
+	assert.Equal(t, int32(3), opened.Version)
+	data, err := opened.Decode(decoder, readerKey)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"content":"vault"}`, string(data))
+	// the local copy stays under the writer's master key
+	_, err = record.Decode(decoder, writerKey)
+	assert.NoError(t, err)
+}
+
+func TestMoveToVaultShouldCopyUnderVaultVersion(t *testing.T) {
+	ctx, dataService, cleanUp := configure(t)
+	defer func() {
+		if err := cleanUp(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	id, err := createTextContent(ctx, dataService)
+	assert.NoError(t, err)
+	saveVault(ctx, t, dataService.db, &core.Vault{ID: "infra", ServerID: 1, Name: "infra", Role: core.VaultWriter}, 7)
+
+	moved, err := dataService.MoveToVault(ctx, id, "infra", false)
+
+	assert.NoError(t, err)
+	record, err := dataService.Get(ctx, moved)
+	assert.NoError(t, err)
+	assert.Equal(t, "infra", record.Vault)
+	assert.Equal(t, int32(8), record.Version)
+	text, err := record.DecodeText(dataService.decoder, mustMasterKey(ctx, t))
+	assert.NoError(t, err)
+	assert.Equal(t, "yep, its content", text.Content)
+	source, err := dataService.Get(ctx, id)
+	assert.NoError(t, err)
+	assert.True(t, source.Deleted)
+	assert.Equal(t, int32(1), source.Version)
+}
+
+func TestUpdateVaultRecordShouldFailForReader(t *testing.T) {
+	ctx, dataService, cleanUp := configure(t)
+	defer func() {
+		if err := cleanUp(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	vault := &core.Vault{ID: "infra", ServerID: 1, Name: "infra", Role: core.VaultWriter}
+	saveVault(ctx, t, dataService.db, vault, 0)
+	id, err := createTextContent(ctx, dataService)
+	assert.NoError(t, err)
+	moved, err := dataService.MoveToVault(ctx, id, vault.ID, false)
+	assert.NoError(t, err)
+	vault.Role = core.VaultReader
+	saveVault(ctx, t, dataService.db, vault, 0)
+
+	_, err = dataService.UpdateText(ctx, moved, &TextRequest{Content: "changed"}, false)
+
+	assert.ErrorIs(t, err, ErrVaultReadOnly)
+}
+
+func wrappedVault(t *testing.T, encoder core.Encoder, id string, vaultKey, masterKey []byte) *core.Vault {
+	wrapped, err := encoder.Encode(vaultKey, masterKey, vaultKeyAdditionalData(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &core.Vault{ID: id, ServerID: 1, Name: id, Role: core.VaultWriter, Key: wrapped}
+}
+
+func saveVault(ctx context.Context, t *testing.T, db *sql.DB, vault *core.Vault, version int32) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault.Key = []byte("wrapped")
+	if err = persistence.TxSaveVault(ctx, tx, vault); err != nil {
+		t.Fatal(err)
+	}
+	if err = persistence.SaveState(ctx, tx, &core.SyncState{ID: vaultStateName(vault.ID), Value: version}); err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustMasterKey(ctx context.Context, t *testing.T) []byte {
+	masterKey, err := common.GetMasterKey(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return masterKey
+}
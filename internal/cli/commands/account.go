@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/spf13/cobra"
+)
+
+var errNotConfirmed = errors.New("account deletion is not confirmed, pass --yes")
+
+func BindAccountCommand(root *cobra.Command, userService *app.UserService, accountService *app.AccountService) error {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "manage account on remote server",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if accountService == nil {
+				return errNoRemoteServer
+			}
+			return nil
+		},
+	}
+	if err := bindAccountPasswordCommand(cmd, userService, accountService); err != nil {
+		return err
+	}
+	if err := bindAccountLoginCommand(cmd, userService, accountService); err != nil {
+		return err
+	}
+	if err := bindAccountDeleteCommand(cmd, userService, accountService); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindAccountPasswordCommand(root *cobra.Command, userService *app.UserService, accountService *app.AccountService) error {
+	var key string
+	var pass string
+	var newPass string
+	cmd := &cobra.Command{
+		Use:   "password",
+		Short: "change password, other sessions are terminated",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if err := accountService.ChangePassword(ctx, pass, newPass); err != nil {
+				return err
+			}
+			fmt.Println("✅ password changed.")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&pass, "pass", "p", "", "current remote server password")
+	cmd.Flags().StringVarP(&newPass, "new", "n", "", "new remote server password")
+	for _, name := range []string{"key", "pass", "new"} {
+		if err := cobra.MarkFlagRequired(cmd.Flags(), name); err != nil {
+			return err
+		}
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindAccountLoginCommand(root *cobra.Command, userService *app.UserService, accountService *app.AccountService) error {
+	var key string
+	var pass string
+	var newLogin string
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "change login",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if err := accountService.ChangeLogin(ctx, pass, newLogin); err != nil {
+				return err
+			}
+			fmt.Printf("✅ login changed to %s.\n", newLogin)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&pass, "pass", "p", "", "remote server password")
+	cmd.Flags().StringVarP(&newLogin, "new", "n", "", "new remote server login")
+	for _, name := range []string{"key", "pass", "new"} {
+		if err := cobra.MarkFlagRequired(cmd.Flags(), name); err != nil {
+			return err
+		}
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindAccountDeleteCommand(root *cobra.Command, userService *app.UserService, accountService *app.AccountService) error {
+	var key string
+	var pass string
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "delete account with all secrets on remote server, local records are kept",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if !yes {
+				return errNotConfirmed
+			}
+			if err := accountService.DeleteAccount(ctx, pass); err != nil {
+				return err
+			}
+			fmt.Println("✅ account deleted, remote server is unregistered.")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&pass, "pass", "p", "", "remote server password")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "confirm deletion")
+	for _, name := range []string{"key", "pass"} {
+		if err := cobra.MarkFlagRequired(cmd.Flags(), name); err != nil {
+			return err
+		}
+	}
+	root.AddCommand(cmd)
+	return nil
+}
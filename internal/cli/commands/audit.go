@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/spf13/cobra"
+)
+
+// BindAuditLogCommand show audit trail of remote server
+func BindAuditLogCommand(root *cobra.Command, auditService *app.AuditService) error {
+	var query core.AuditQuery
+	var since string
+	cmd := &cobra.Command{
+		Use:   "audit-log",
+		Short: "show logins, syncs and downloads recorded by remote server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if auditService == nil {
+				return errNoRemoteServer
+			}
+			var err error
+			if query.Since, err = parseSince(since, time.Now()); err != nil {
+				return err
+			}
+			events, err := auditService.Query(cmd.Context(), &query)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tTIME\tEVENT\tLOGIN\tDEVICE\tPEER\tSECRET\tVERSION\tBYTES\tREASON")
+			for _, event := range events {
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+					event.ID, formatTime(event.At), event.Type, orDash(event.Login), orDash(event.DeviceID),
+					orDash(event.Peer), orDash(event.SecretID), event.Version, event.Bytes, orDash(event.Reason))
+			}
+			if err = w.Flush(); err != nil {
+				return err
+			}
+			if len(events) > 0 && int32(len(events)) == query.Limit {
+				fmt.Printf("older events: --before %d\n", events[len(events)-1].ID)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&query.All, "all", "a", false, "events of every user, server administrators only")
+	cmd.Flags().StringVarP(&query.Login, "login", "l", "", "events of the login, server administrators only")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "events since date (2006-01-02) or for duration (24h)")
+	cmd.Flags().Int64VarP(&query.BeforeID, "before", "b", 0, "events older than the id")
+	cmd.Flags().Int32VarP(&query.Limit, "limit", "n", 100, "max number of events")
+	root.AddCommand(cmd)
+	return nil
+}
+
+// parseSince date in local time, RFC 3339 time or duration back from now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q, use date, RFC 3339 time or duration", value)
+	}
+	return t, nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -135,13 +135,7 @@ func BindExportBinaryCommand(root *cobra.Command, userService *app.UserService,
 					return err
 				}
 				defer file.Close()
-				content := make([]byte, d.SizeBytes)
-				_, err = f.Read(content)
-				if err != nil {
-					return err
-				}
-				_, err = file.Write(content)
-				if err != nil {
+				if _, err = io.Copy(file, f); err != nil {
 					return err
 				}
 			}
@@ -0,0 +1,287 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/spf13/cobra"
+)
+
+type (
+	conflictView struct {
+		ID            int32     `json:"id"`
+		RecordID      string    `json:"record_id"`
+		CreatedAt     time.Time `json:"created_at"`
+		Fields        []string  `json:"fields,omitempty"`
+		LocalDeleted  bool      `json:"local_deleted"`
+		RemoteDeleted bool      `json:"remote_deleted"`
+	}
+	conflictDiffView struct {
+		conflictView
+		Local  map[string]any `json:"local"`
+		Remote map[string]any `json:"remote"`
+	}
+)
+
+func BindConflictsCommand(
+	root *cobra.Command,
+	dataManager *app.DataManager,
+	userService *app.UserService,
+	syncService app.Syncer,
+) error {
+	cmd := &cobra.Command{
+		Use:   "conflicts",
+		Short: "manage sync conflicts",
+	}
+	if err := bindConflictsListCommand(cmd, dataManager); err != nil {
+		return err
+	}
+	if err := bindConflictsShowCommand(cmd, dataManager, userService); err != nil {
+		return err
+	}
+	if err := bindConflictsResolveCommand(cmd, dataManager, userService, syncService); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindConflictsListCommand(root *cobra.Command, dataManager *app.DataManager) error {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list not solved conflicts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conflicts, err := dataManager.GetAllConflicts(cmd.Context())
+			if err != nil {
+				return err
+			}
+			views := make([]conflictView, 0, len(conflicts))
+			for _, conflict := range conflicts {
+				views = append(views, toConflictView(conflict))
+			}
+			if asJSON {
+				return printJSON(views)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tSECRET\tCREATED\tFIELDS")
+			for _, view := range views {
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+					view.ID, view.RecordID, view.CreatedAt.Local().Format(time.DateTime), describeFields(view))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print as json")
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindConflictsShowCommand(root *cobra.Command, dataManager *app.DataManager, userService *app.UserService) error {
+	var key string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "show local and remote versions side by side",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			conflict, err := getConflict(ctx, dataManager, args[0])
+			if err != nil {
+				return err
+			}
+			view := conflictDiffView{conflictView: toConflictView(conflict)}
+			if view.Local, err = decodeFields(ctx, dataManager, conflict.Local); err != nil {
+				return err
+			}
+			if view.Remote, err = decodeFields(ctx, dataManager, conflict.Remote); err != nil {
+				return err
+			}
+			if asJSON {
+				return printJSON(view)
+			}
+			printDiff(&view)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print as json")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindConflictsResolveCommand(
+	root *cobra.Command,
+	dataManager *app.DataManager,
+	userService *app.UserService,
+	syncService app.Syncer,
+) error {
+	var key string
+	var take string
+	var noSync bool
+	cmd := &cobra.Command{
+		Use:   "resolve <id>",
+		Short: "solve conflict with local, remote, newest or both versions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			version, err := app.ParseVersion(take)
+			if err != nil {
+				return err
+			}
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			conflict, err := getConflict(ctx, dataManager, args[0])
+			if err != nil {
+				return err
+			}
+			if err = dataManager.SolveConflict(ctx, version, conflict); err != nil {
+				return err
+			}
+			fmt.Printf("conflict %d solved, taken %s\n", conflict.ID, version)
+			conflicts, err := dataManager.GetAllConflicts(ctx)
+			if err != nil {
+				return err
+			}
+			if len(conflicts) > 0 {
+				fmt.Printf("%d conflicts left\n", len(conflicts))
+				return nil
+			}
+			if noSync {
+				return nil
+			}
+			return syncService.Sync(ctx, &app.SyncOption{
+				Force: true,
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&take, "take", "t", "", "version to keep: local, remote, newest or both")
+	cmd.Flags().BoolVar(&noSync, "no-sync", false, "don't sync after the last conflict is solved")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "take"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func getConflict(ctx context.Context, dataManager *app.DataManager, arg string) (*core.Conflict, error) {
+	id, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%s not a conflict id", arg)
+	}
+	return dataManager.GetConflict(ctx, int32(id))
+}
+
+func toConflictView(conflict *core.Conflict) conflictView {
+	return conflictView{
+		ID:            conflict.ID,
+		RecordID:      conflict.RecordID,
+		CreatedAt:     conflict.CreatedAt,
+		Fields:        conflict.Local.Fields,
+		LocalDeleted:  conflict.Local.Deleted,
+		RemoteDeleted: conflict.Remote.Deleted,
+	}
+}
+
+func describeFields(view conflictView) string {
+	switch {
+	case view.LocalDeleted:
+		return "deleted locally"
+	case view.RemoteDeleted:
+		return "deleted on server"
+	case len(view.Fields) > 0:
+		return strings.Join(view.Fields, ", ")
+	}
+	return "whole secret"
+}
+
+// decodeFields decrypt version of secret into fields, nil for deleted version
+func decodeFields(ctx context.Context, dataManager *app.DataManager, item *core.ConflictItem) (map[string]any, error) {
+	if item.Deleted {
+		return nil, nil
+	}
+	data, err := dataManager.Decode(ctx, item.Record)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func printDiff(view *conflictDiffView) {
+	fmt.Printf("conflict %d of secret %s\n", view.ID, view.RecordID)
+	keys := make([]string, 0, len(view.Local)+len(view.Remote))
+	for key := range view.Local {
+		keys = append(keys, key)
+	}
+	for key := range view.Remote {
+		if _, ok := view.Local[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "\tFIELD\tLOCAL\tREMOTE")
+	if view.LocalDeleted || view.RemoteDeleted {
+		_, _ = fmt.Fprintf(w, "!\tdeleted\t%t\t%t\n", view.LocalDeleted, view.RemoteDeleted)
+	}
+	for _, key := range keys {
+		local, remote := formatField(view.Local, key), formatField(view.Remote, key)
+		mark := ""
+		if local != remote {
+			mark = "!"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mark, key, local, remote)
+	}
+	_ = w.Flush()
+}
+
+func formatField(fields map[string]any, key string) string {
+	value, ok := fields[key]
+	if !ok {
+		return "-"
+	}
+	text := fmt.Sprint(value)
+	// don't print file content
+	if len(text) > 64 {
+		return text[:61] + "..."
+	}
+	return text
+}
+
+func printJSON(v any) error {
+	js, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(js))
+	return nil
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/spf13/cobra"
+)
+
+func BindDaemonCommand(root *cobra.Command, userService *app.UserService, db *sql.DB, syncService *app.SyncService) error {
+	var key string
+	option := &app.DaemonOption{}
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "sync in background regularly and after local changes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// runs until interrupted, the global timeout doesn't apply
+			ctx, cancel := signal.NotifyContext(context.WithoutCancel(cmd.Context()), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			if syncService == nil {
+				fmt.Println("remote server not configured")
+				return nil
+			}
+			return app.NewDaemon(db, syncService, option).Run(ctx)
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().DurationVarP(&option.Interval, "interval", "i", 5*time.Minute, "interval between syncs")
+	cmd.Flags().DurationVar(&option.CheckInterval, "check-interval", 5*time.Second, "interval between checks of local changes")
+	cmd.Flags().DurationVar(&option.MinBackoff, "min-backoff", 5*time.Second, "first retry delay when server is unavailable")
+	cmd.Flags().DurationVar(&option.MaxBackoff, "max-backoff", 10*time.Minute, "maximum retry delay when server is unavailable")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func BindStatusCommand(root *cobra.Command, db *sql.DB) error {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show sync status",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			local, err := app.GetLocalStatus(ctx, db)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("synced version: %d\n", local.Version)
+			fmt.Printf("local changes: %d\n", local.Pending)
+			fmt.Printf("conflicts: %d\n", local.Conflicts)
+			status, err := app.GetDaemonStatus(ctx, db)
+			if err != nil {
+				return err
+			}
+			if status == nil {
+				fmt.Println("daemon: never started")
+				return nil
+			}
+			fmt.Printf("daemon: %s (pid %d, updated %s)\n", status.State, status.PID, formatTime(status.UpdatedAt))
+			fmt.Printf("last sync: %s\n", formatTime(status.LastSync))
+			if status.State == app.DaemonStopped {
+				return nil
+			}
+			fmt.Printf("next attempt: %s\n", formatTime(status.NextAttempt))
+			if status.LastError != "" {
+				fmt.Printf("last error: %s (failures %d)\n", status.LastError, status.Failures)
+			}
+			return nil
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format(time.DateTime)
+}
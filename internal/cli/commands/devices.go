@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/spf13/cobra"
+)
+
+var errNoRemoteServer = errors.New("no remote server registered")
+
+func BindDevicesCommand(root *cobra.Command, userService *app.UserService, deviceService *app.DeviceService) error {
+	cmd := &cobra.Command{
+		Use:   "devices",
+		Short: "manage devices registered on remote server",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if deviceService == nil {
+				return errNoRemoteServer
+			}
+			return nil
+		},
+	}
+	if err := bindDevicesListCommand(cmd, deviceService); err != nil {
+		return err
+	}
+	if err := bindDevicesRenameCommand(cmd, userService, deviceService); err != nil {
+		return err
+	}
+	if err := bindDevicesRevokeCommand(cmd, userService, deviceService); err != nil {
+		return err
+	}
+	if err := bindDevicesApproveCommand(cmd, userService, deviceService); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindDevicesListCommand(root *cobra.Command, deviceService *app.DeviceService) error {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list devices",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			devices, err := deviceService.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tNAME\tLAST SEEN\tSYNCED\tSTATE")
+			for _, device := range devices {
+				state := "active"
+				switch {
+				case device.Revoked:
+					state = "revoked"
+				case device.Pending:
+					state = "pending"
+				}
+				if device.Current {
+					state += " (this device)"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
+					device.ID, device.Name, formatTime(device.LastSeenAt), device.SyncVersion, state)
+			}
+			return w.Flush()
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindDevicesRenameCommand(root *cobra.Command, userService *app.UserService, deviceService *app.DeviceService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "rename <id> <name>",
+		Short: "rename device",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			device, err := deviceService.Rename(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("device %s renamed to %s\n", device.ID, device.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindDevicesRevokeCommand(root *cobra.Command, userService *app.UserService, deviceService *app.DeviceService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "revoke device, it can't sync anymore",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if err := deviceService.Revoke(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("device %s revoked\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindDevicesApproveCommand(root *cobra.Command, userService *app.UserService, deviceService *app.DeviceService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "approve <id>",
+		Short: "approve pending device, it can login after",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if err := deviceService.Approve(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("device %s approved\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
@@ -47,6 +47,12 @@ func BindListCommand(root *cobra.Command, userService *app.UserService, dataMana
 				if err != nil {
 					return err
 				}
+				if record.IsShared() {
+					fmt.Printf("shared by %s (read only)\n", record.Owner)
+				}
+				if record.InVault() {
+					fmt.Printf("in vault %s\n", record.Vault)
+				}
 				fmt.Println(js)
 			}
 			return nil
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/crypto"
+	"github.com/spf13/cobra"
+)
+
+var ErrNoShares = errors.New("no shares provided")
+
+func BindRecoveryCommand(root *cobra.Command, userService *app.UserService, recoveryService *app.RecoveryService) error {
+	cmd := &cobra.Command{
+		Use:   "recovery",
+		Short: "recover vault access with shares of a recovery key",
+	}
+	if err := bindRecoverySplitCommand(cmd, userService, recoveryService); err != nil {
+		return err
+	}
+	if err := bindRecoveryRestoreCommand(cmd, recoveryService); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindRecoverySplitCommand(root *cobra.Command, userService *app.UserService, recoveryService *app.RecoveryService) error {
+	var key string
+	var shares int
+	var threshold int
+	var out string
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "split a recovery key into shares",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			parts, err := recoveryService.Split(ctx, shares, threshold)
+			if err != nil {
+				return err
+			}
+			for i, part := range parts {
+				mnemonic := crypto.EncodeMnemonic(part)
+				if out == "" {
+					fmt.Printf("share %d: %s\n", i+1, mnemonic)
+					continue
+				}
+				path := filepath.Join(out, fmt.Sprintf("share-%d.txt", i+1))
+				if err = os.WriteFile(path, []byte(mnemonic+"\n"), 0o600); err != nil {
+					return err
+				}
+				fmt.Printf("share %d written to %s\n", i+1, path)
+			}
+			fmt.Printf("any %d of %d shares restore access\n", threshold, shares)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().IntVarP(&shares, "shares", "n", 5, "number of shares")
+	cmd.Flags().IntVarP(&threshold, "threshold", "t", 3, "number of shares required to restore")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "directory to write share files")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindRecoveryRestoreCommand(root *cobra.Command, recoveryService *app.RecoveryService) error {
+	var key string
+	var mnemonics []string
+	var files []string
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "restore access with shares and set a new master key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				mnemonics = append(mnemonics, string(content))
+			}
+			if len(mnemonics) == 0 {
+				return ErrNoShares
+			}
+			parts := make([][]byte, len(mnemonics))
+			for i, mnemonic := range mnemonics {
+				part, err := crypto.DecodeMnemonic(mnemonic)
+				if err != nil {
+					return err
+				}
+				parts[i] = part
+			}
+			if err := recoveryService.Restore(ctx, parts, key); err != nil {
+				return err
+			}
+			fmt.Println("master key changed, run sync to push re-encrypted secrets")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "new key")
+	cmd.Flags().StringArrayVarP(&mnemonics, "share", "s", nil, "share words")
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "share file")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
@@ -1,20 +1,36 @@
 package commands
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/DimKa163/keeper/internal/cli/app"
 	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
 	"github.com/DimKa163/keeper/internal/cli/persistence"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var errPlaintextServer = errors.New("remote server is connected without TLS, there is no key to pin")
+var errKeyNotTrusted = errors.New("server key is not trusted, compare the fingerprint out of band and pass --fingerprint")
+
+// legacyLoginUsage flag of one-time login with the password, the account is upgraded to SRP by it
+const legacyLoginUsage = "send the password once to upgrade account registered before SRP"
+
 func BindRegisterRemoteServer(root *cobra.Command, userService *app.UserService, db *sql.DB) error {
 	var key string
 	var addr string
 	var login string
 	var pass string
 	var active bool
+	var transport tlsOptions
+	var expected string
+	var legacy bool
 	cmd := &cobra.Command{
 		Use:   "register-remote-server",
 		Short: "register remote server",
@@ -25,20 +41,47 @@ func BindRegisterRemoteServer(root *cobra.Command, userService *app.UserService,
 				return err
 			}
 			ctx = common.SetMasterKey(ctx, masterKey)
-			if err = persistence.InsertServer(ctx, db, addr, login, pass, active); err != nil {
+			serv := &core.Server{
+				Address: addr,
+				Login:   login,
+				Active:  active,
+			}
+			transport.apply(serv)
+			if serv.TLS {
+				// pin the key before any password is sent
+				if serv.Fingerprint, err = app.ProbeFingerprint(ctx, serv); err != nil {
+					return err
+				}
+				if err = confirmKey(serv.Address, expected, serv.Fingerprint); err != nil {
+					return err
+				}
+			}
+			creds, err := app.NewTransportCredentials(serv)
+			if err != nil {
 				return err
 			}
-			client, err := app.NewRemoteClient(addr, login, pass)
+			// only the session is kept, not the password
+			id, err := persistence.InsertServer(ctx, db, serv)
+			if err != nil {
+				return err
+			}
+			client, err := app.NewRemoteClient(addr, login, pass, creds)
 			if err != nil {
 				return err
 			}
 			if err = client.IsHealthy(ctx); err != nil {
 				return err
 			}
+			if legacy {
+				if err = client.MigrateLogin(ctx); err != nil {
+					return err
+				}
+				fmt.Println("✅ account upgraded to SRP, the password won't be sent again")
+			}
 			if err = client.TryToAuthenticate(ctx); err != nil {
-				return err
+				return withLegacyLoginHint(err)
 			}
-			return nil
+			return startSession(ctx, client, db, id)
 		},
 	}
 	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
@@ -46,6 +89,13 @@ func BindRegisterRemoteServer(root *cobra.Command, userService *app.UserService,
 	cmd.Flags().StringVarP(&login, "login", "l", "", "remote server login")
 	cmd.Flags().StringVarP(&pass, "pass", "p", "", "remote server password")
 	cmd.Flags().BoolVarP(&active, "active", "i", true, "active remote server")
+	cmd.Flags().BoolVar(&transport.tls, "tls", false, "connect over TLS")
+	cmd.Flags().StringVar(&transport.ca, "ca", "", "CA bundle to verify server certificate, implies --tls")
+	cmd.Flags().StringVar(&transport.cert, "cert", "", "client certificate for mTLS, implies --tls")
+	cmd.Flags().StringVar(&transport.key, "cert-key", "", "client certificate key for mTLS")
+	cmd.Flags().StringVar(&expected, "fingerprint", "", "fingerprint the server must present, asked to confirm when not set")
+	cmd.Flags().BoolVar(&legacy, "legacy-login", false, legacyLoginUsage)
+	cmd.MarkFlagsRequiredTogether("cert", "cert-key")
 	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
 		return err
 	}
@@ -61,3 +111,271 @@ func BindRegisterRemoteServer(root *cobra.Command, userService *app.UserService,
 	root.AddCommand(cmd)
 	return nil
 }
+
+// BindRemoteLoginCommand start a new session on active remote server when the old one expired or was terminated
+func BindRemoteLoginCommand(root *cobra.Command, userService *app.UserService, db *sql.DB) error {
+	var key string
+	var pass string
+	var legacy bool
+	cmd := &cobra.Command{
+		Use:   "remote-login",
+		Short: "login to active remote server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			serv, err := persistence.GetServer(ctx, db, true)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errNoRemoteServer
+				}
+				return err
+			}
+			creds, err := app.NewTransportCredentials(serv)
+			if err != nil {
+				return err
+			}
+			client, err := app.NewRemoteClient(serv.Address, serv.Login, pass, creds)
+			if err != nil {
+				return err
+			}
+			if err = client.IsHealthy(ctx); err != nil {
+				return err
+			}
+			if legacy {
+				if err = client.MigrateLogin(ctx); err != nil {
+					return err
+				}
+				fmt.Println("✅ account upgraded to SRP, the password won't be sent again")
+			}
+			if err = startSession(ctx, client, db, serv.ID); err != nil {
+				return withLegacyLoginHint(err)
+			}
+			fmt.Println("✅ authentification succeeded.")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&pass, "pass", "p", "", "remote server password")
+	cmd.Flags().BoolVar(&legacy, "legacy-login", false, legacyLoginUsage)
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "pass"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+// withLegacyLoginHint failed login of an account registered before SRP looks the same as a wrong password,
+// the user is told how to upgrade it
+func withLegacyLoginHint(err error) error {
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.AlreadyExists:
+		return fmt.Errorf("%w\naccount registered before SRP must log in once with --legacy-login", err)
+	default:
+		return err
+	}
+}
+
+// startSession register the device if needed and start a session bound to it
+func startSession(ctx context.Context, client *app.RemoteClient, db *sql.DB, serverID int32) error {
+	if err := app.NewDeviceService(client, db, serverID).Ensure(ctx); err != nil {
+		return err
+	}
+	sessions := app.NewSessionService(client, db, serverID)
+	if err := sessions.Load(ctx); err != nil {
+		return err
+	}
+	return sessions.Login(ctx)
+}
+
+// BindRemoteCommand settings of active remote server
+func BindRemoteCommand(root *cobra.Command, db *sql.DB) error {
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: "manage active remote server",
+	}
+	if err := bindRemoteTLSCommand(cmd, db); err != nil {
+		return err
+	}
+	if err := bindRemoteFingerprintCommand(cmd, db); err != nil {
+		return err
+	}
+	if err := bindRemoteTrustCommand(cmd, db); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindRemoteTLSCommand(root *cobra.Command, db *sql.DB) error {
+	var transport tlsOptions
+	var disable bool
+	cmd := &cobra.Command{
+		Use:   "tls",
+		Short: "set TLS settings of active remote server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			serv, err := persistence.GetServer(ctx, db, true)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errNoRemoteServer
+				}
+				return err
+			}
+			if disable {
+				transport = tlsOptions{}
+				serv.TLS, serv.CA, serv.Cert, serv.Key = false, "", "", ""
+			}
+			transport.apply(serv)
+			if _, err = app.NewTransportCredentials(serv); err != nil {
+				return err
+			}
+			if err = persistence.UpdateServerTLS(ctx, db, serv); err != nil {
+				return err
+			}
+			if !serv.TLS {
+				fmt.Printf("%s: plaintext\n", serv.Address)
+				return nil
+			}
+			roots := "system roots"
+			if serv.Fingerprint != "" {
+				roots = "pinned key"
+			}
+			fmt.Printf("%s: tls (ca: %s, client certificate: %s)\n", serv.Address, orDefault(serv.CA, roots), orDefault(serv.Cert, "none"))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&transport.tls, "tls", false, "connect over TLS")
+	cmd.Flags().StringVar(&transport.ca, "ca", "", "CA bundle to verify server certificate, implies --tls")
+	cmd.Flags().StringVar(&transport.cert, "cert", "", "client certificate for mTLS, implies --tls")
+	cmd.Flags().StringVar(&transport.key, "key", "", "client certificate key for mTLS")
+	cmd.Flags().BoolVar(&disable, "disable", false, "connect without TLS")
+	cmd.MarkFlagsRequiredTogether("cert", "key")
+	cmd.MarkFlagsMutuallyExclusive("disable", "tls")
+	cmd.MarkFlagsMutuallyExclusive("disable", "ca")
+	cmd.MarkFlagsMutuallyExclusive("disable", "cert")
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindRemoteFingerprintCommand(root *cobra.Command, db *sql.DB) error {
+	cmd := &cobra.Command{
+		Use:   "fingerprint",
+		Short: "show pinned and presented key fingerprint of active remote server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			serv, err := getTLSServer(ctx, db)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("pinned:    %s\n", orDefault(serv.Fingerprint, "none"))
+			actual, err := app.VerifyFingerprint(ctx, serv)
+			if actual == "" {
+				return err
+			}
+			fmt.Printf("presented: %s\n", actual)
+			if err != nil {
+				fmt.Println("⚠️ server key changed, run 'keeper remote trust' if you expect it")
+				return nil
+			}
+			fmt.Println("✅ server key matches.")
+			return nil
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindRemoteTrustCommand(root *cobra.Command, db *sql.DB) error {
+	var expected string
+	cmd := &cobra.Command{
+		Use:   "trust",
+		Short: "pin the key active remote server presents now",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			serv, err := getTLSServer(ctx, db)
+			if err != nil {
+				return err
+			}
+			actual, err := app.ProbeFingerprint(ctx, serv)
+			if err != nil {
+				return err
+			}
+			if err = confirmKey(serv.Address, expected, actual); err != nil {
+				return err
+			}
+			if err = persistence.UpdateServerFingerprint(ctx, db, serv.ID, actual); err != nil {
+				return err
+			}
+			fmt.Printf("✅ %s trusted with key %s\n", serv.Address, actual)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&expected, "fingerprint", "", "fingerprint the server must present, asked to confirm when not set")
+	root.AddCommand(cmd)
+	return nil
+}
+
+// confirmKey check presented key against the expected fingerprint, without it the user compares
+// the fingerprint out of band and confirms it
+func confirmKey(address, expected, actual string) error {
+	if expected != "" {
+		if !strings.EqualFold(expected, actual) {
+			return fmt.Errorf("%w: expected %s, presented %s", app.ErrFingerprintMismatch, expected, actual)
+		}
+		return nil
+	}
+	fmt.Printf("%s presents key %s\ntrust it? [y/N] ", address, actual)
+	var input string
+	// empty answer means no
+	_, _ = fmt.Scanln(&input)
+	if !strings.EqualFold(input, "y") && !strings.EqualFold(input, "yes") {
+		return errKeyNotTrusted
+	}
+	return nil
+}
+
+// getTLSServer active remote server, it must be connected over TLS
+func getTLSServer(ctx context.Context, db *sql.DB) (*core.Server, error) {
+	serv, err := persistence.GetServer(ctx, db, true)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errNoRemoteServer
+		}
+		return nil, err
+	}
+	if !serv.TLS {
+		return nil, errPlaintextServer
+	}
+	return serv, nil
+}
+
+// tlsOptions transport flags of remote server
+type tlsOptions struct {
+	tls  bool
+	ca   string
+	cert string
+	key  string
+}
+
+// apply set given options to server, any of certificate options turns TLS on
+func (o tlsOptions) apply(serv *core.Server) {
+	if o.ca != "" {
+		serv.CA = o.ca
+	}
+	if o.cert != "" {
+		serv.Cert, serv.Key = o.cert, o.key
+	}
+	serv.TLS = serv.TLS || o.tls || o.ca != "" || o.cert != ""
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// BindResealCommand re-encrypt records saved by versions of keeper before records were bound to their identity
+func BindResealCommand(root *cobra.Command, userService *app.UserService, dataManager *app.DataManager) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "reseal",
+		Short: "re-encrypt records saved by an old version of keeper",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			resealed, err := dataManager.Reseal(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("records resealed: %d\n", resealed)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
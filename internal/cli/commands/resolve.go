@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/DimKa163/keeper/internal/cli/app"
 	"github.com/DimKa163/keeper/internal/cli/common"
@@ -55,10 +56,6 @@ func BindConflictSolveCommand(
 				if err = dataManager.SolveConflict(ctx, app.Version(i), conflict); err != nil {
 					return err
 				}
-
-				if err = dataManager.DeleteConflict(ctx, conflict); err != nil {
-					return err
-				}
 			}
 			if err = syncService.Sync(ctx, &app.SyncOption{
 				Force: true,
@@ -79,6 +76,9 @@ func BindConflictSolveCommand(
 func printMenu(ctx context.Context, manager *app.DataManager, conflict *core.Conflict) error {
 	local := conflict.Local
 	remote := conflict.Remote
+	if len(local.Fields) > 0 {
+		fmt.Printf("conflicting fields: %s\n", strings.Join(local.Fields, ", "))
+	}
 	fmt.Println("1. local:")
 	if err := printSecret(ctx, manager, local.Record); err != nil {
 		return err
@@ -91,6 +91,8 @@ func printMenu(ctx context.Context, manager *app.DataManager, conflict *core.Con
 	fmt.Println("Select:")
 	fmt.Println("1 - local")
 	fmt.Println("2 - remote")
+	fmt.Println("3 - newest")
+	fmt.Println("4 - both")
 	return nil
 }
 func printSecret(ctx context.Context, manager *app.DataManager, record *core.Record) error {
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/spf13/cobra"
+)
+
+func BindSessionsCommand(root *cobra.Command, userService *app.UserService, sessionService *app.SessionService) error {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "manage sessions on remote server",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if sessionService == nil {
+				return errNoRemoteServer
+			}
+			return nil
+		},
+	}
+	if err := bindSessionsListCommand(cmd, sessionService); err != nil {
+		return err
+	}
+	if err := bindSessionsTerminateCommand(cmd, userService, sessionService); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindSessionsListCommand(root *cobra.Command, sessionService *app.SessionService) error {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list active sessions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessions, err := sessionService.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tDEVICE\tCREATED\tLAST USED\tEXPIRES")
+			for _, session := range sessions {
+				id := session.ID
+				if session.Current {
+					id += " (current)"
+				}
+				device := session.DeviceID
+				if device == "" {
+					device = "-"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					id, device, formatTime(session.CreatedAt), formatTime(session.LastUsedAt), formatTime(session.ExpiresAt))
+			}
+			return w.Flush()
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindSessionsTerminateCommand(root *cobra.Command, userService *app.UserService, sessionService *app.SessionService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "terminate <id>",
+		Short: "terminate session, its tokens stop working",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if err := sessionService.Terminate(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("session %s terminated\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func BindLogoutCommand(root *cobra.Command, sessionService *app.SessionService) error {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "close session on remote server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sessionService == nil {
+				return errNoRemoteServer
+			}
+			if err := sessionService.Logout(cmd.Context()); err != nil {
+				return err
+			}
+			fmt.Println("logged out. use remote-login to start a new session")
+			return nil
+		},
+	}
+	root.AddCommand(cmd)
+	return nil
+}
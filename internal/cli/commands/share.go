@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// BindShareCommand share record with another user of the remote server
+func BindShareCommand(root *cobra.Command, userService *app.UserService, shareService *app.ShareService) error {
+	var key string
+	var login string
+	var revoke bool
+	cmd := &cobra.Command{
+		Use:   "share <id>",
+		Short: "share record with another user, the record is read only for the recipient",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if shareService == nil {
+				return errNoRemoteServer
+			}
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			if revoke {
+				if err = shareService.Revoke(ctx, args[0], login); err != nil {
+					return err
+				}
+				fmt.Printf("record %s is not shared with %s anymore\n", args[0], login)
+				fmt.Println("whatever the user has already seen stays known, change the secret if needed")
+				return nil
+			}
+			if err = shareService.Ensure(ctx); err != nil {
+				return err
+			}
+			fingerprint, err := shareService.Share(ctx, args[0], login)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ record %s shared with %s\n", args[0], login)
+			fmt.Printf("key fingerprint of %s: %s\n", login, fingerprint)
+			fmt.Println("compare it with the one the user sees in 'keeper shares --mine'")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&login, "with", "w", "", "login of the user to share the record with")
+	cmd.Flags().BoolVar(&revoke, "revoke", false, "stop sharing the record with the user")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "with"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+// BindSharesCommand list records shared with other users
+func BindSharesCommand(root *cobra.Command, userService *app.UserService, shareService *app.ShareService) error {
+	var key string
+	var mine bool
+	cmd := &cobra.Command{
+		Use:   "shares",
+		Short: "list records shared with other users",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if shareService == nil {
+				return errNoRemoteServer
+			}
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			if mine {
+				if err = shareService.Ensure(ctx); err != nil {
+					return err
+				}
+				fingerprint, err := shareService.Fingerprint(ctx)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("your key fingerprint: %s\n", fingerprint)
+				return nil
+			}
+			shares, err := shareService.List(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "RECORD\tSHARED WITH\tSINCE\tKEY FINGERPRINT")
+			for _, share := range shares {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					share.RecordID, share.Login, formatTime(share.CreatedAt), app.ShareKeyFingerprint(share.PublicKey))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().BoolVar(&mine, "mine", false, "print fingerprint of your own key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
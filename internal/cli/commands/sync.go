@@ -12,11 +12,19 @@ func BindSyncCommand(root *cobra.Command, userService *app.UserService, syncServ
 	var key string
 	var pull bool
 	var push bool
+	var onConflict string
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Sync dataManager",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
+			var strategy app.Version
+			if onConflict != "" {
+				var err error
+				if strategy, err = app.ParseVersion(onConflict); err != nil {
+					return err
+				}
+			}
 			masterKey, err := userService.Auth(ctx, key)
 			if err != nil {
 				return err
@@ -24,8 +32,9 @@ func BindSyncCommand(root *cobra.Command, userService *app.UserService, syncServ
 			ctx = common.SetMasterKey(ctx, masterKey)
 			if syncService != nil {
 				return syncService.Sync(ctx, &app.SyncOption{
-					PushOnly: push,
-					PullOnly: pull,
+					PushOnly:   push,
+					PullOnly:   pull,
+					OnConflict: strategy,
 				})
 			}
 			fmt.Println("remote server unavailable or not configured")
@@ -35,6 +44,7 @@ func BindSyncCommand(root *cobra.Command, userService *app.UserService, syncServ
 	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
 	cmd.Flags().BoolVarP(&push, "push", "p", false, "push")
 	cmd.Flags().BoolVarP(&pull, "pull", "f", false, "pull")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", "", "solve conflicts with local, remote, newest or both versions")
 	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
 		return err
 	}
@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/spf13/cobra"
+)
+
+// BindVaultCommand manage vaults shared by a team
+func BindVaultCommand(
+	root *cobra.Command,
+	userService *app.UserService,
+	vaultService *app.VaultService,
+	dataManager *app.DataManager,
+) error {
+	cmd := &cobra.Command{
+		Use:   "vault",
+		Short: "manage vaults shared by a team",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if vaultService == nil {
+				return errNoRemoteServer
+			}
+			return nil
+		},
+	}
+	if err := bindVaultCreateCommand(cmd, userService, vaultService); err != nil {
+		return err
+	}
+	if err := bindVaultInviteCommand(cmd, userService, vaultService); err != nil {
+		return err
+	}
+	if err := bindVaultMembersCommand(cmd, userService, vaultService); err != nil {
+		return err
+	}
+	if err := bindVaultLeaveCommand(cmd, userService, vaultService); err != nil {
+		return err
+	}
+	if err := bindVaultListCommand(cmd, userService, vaultService); err != nil {
+		return err
+	}
+	if err := bindVaultMoveCommand(cmd, userService, vaultService, dataManager); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVaultCreateCommand(root *cobra.Command, userService *app.UserService, vaultService *app.VaultService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "create vault, you become its owner",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			vault, err := vaultService.Create(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ vault %s created, id %s\n", vault.Name, vault.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVaultInviteCommand(root *cobra.Command, userService *app.UserService, vaultService *app.VaultService) error {
+	var key string
+	var login string
+	var roleName string
+	cmd := &cobra.Command{
+		Use:   "invite <vault>",
+		Short: "add user to the vault or change their role",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role, err := core.ParseVaultRole(roleName)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			fingerprint, err := vaultService.Invite(ctx, args[0], login, role)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ %s is %s of vault %s\n", login, role, args[0])
+			fmt.Printf("key fingerprint of %s: %s\n", login, fingerprint)
+			fmt.Println("compare it with the one the user sees in 'keeper shares --mine'")
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&login, "with", "w", "", "login of the user to invite")
+	cmd.Flags().StringVarP(&roleName, "role", "r", core.VaultReader.String(), "role of the user: reader, writer or admin")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "with"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVaultMembersCommand(root *cobra.Command, userService *app.UserService, vaultService *app.VaultService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "members <vault>",
+		Short: "list members of the vault",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			members, err := vaultService.Members(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "LOGIN\tROLE\tSINCE\tKEY FINGERPRINT")
+			for _, member := range members {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					member.Login, member.Role, formatTime(member.CreatedAt), app.ShareKeyFingerprint(member.PublicKey))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVaultLeaveCommand(root *cobra.Command, userService *app.UserService, vaultService *app.VaultService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "leave <vault>",
+		Short: "leave the vault, its records are removed from this device",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := userService.Auth(ctx, key); err != nil {
+				return err
+			}
+			if err := vaultService.Leave(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("you left vault %s\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVaultListCommand(root *cobra.Command, userService *app.UserService, vaultService *app.VaultService) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list vaults you are a member of",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			vaults, err := vaultService.List(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "ID\tNAME\tROLE")
+			for _, vault := range vaults {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", vault.ID, vault.Name, vault.Role)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
+
+func bindVaultMoveCommand(
+	root *cobra.Command,
+	userService *app.UserService,
+	vaultService *app.VaultService,
+	dataManager *app.DataManager,
+) error {
+	var key string
+	var to string
+	var sync bool
+	cmd := &cobra.Command{
+		Use:   "move <id>",
+		Short: "move own record to the vault, it gets a new id",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			vault, err := vaultService.Find(ctx, to)
+			if err != nil {
+				return err
+			}
+			id, err := dataManager.MoveToVault(ctx, args[0], vault.ID, sync)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ record moved to vault %s, new id %s\n", vault.Name, id)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	cmd.Flags().StringVarP(&to, "to", "t", "", "id or name of the vault")
+	cmd.Flags().BoolVarP(&sync, "sync", "s", false, "sync")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "to"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DimKa163/keeper/internal/cli/app"
+	"github.com/DimKa163/keeper/internal/cli/common"
+	"github.com/spf13/cobra"
+)
+
+func BindWatchCommand(root *cobra.Command, userService *app.UserService, syncService app.Syncer) error {
+	var key string
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "pull changes as soon as other devices push them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(context.WithoutCancel(cmd.Context()), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			masterKey, err := userService.Auth(ctx, key)
+			if err != nil {
+				return err
+			}
+			ctx = common.SetMasterKey(ctx, masterKey)
+			watcher, ok := syncService.(app.Watcher)
+			if !ok {
+				fmt.Println("remote server unavailable or not configured")
+				return nil
+			}
+			if err = watcher.Watch(ctx); err != nil {
+				if errors.Is(err, app.ErrServerUnavailable) {
+					fmt.Println("remote server unavailable or not configured")
+					return nil
+				}
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&key, "key", "k", "", "key")
+	if err := cobra.MarkFlagRequired(cmd.Flags(), "key"); err != nil {
+		return err
+	}
+	root.AddCommand(cmd)
+	return nil
+}
@@ -12,6 +12,7 @@ const (
 	key        MasterKey = "github.com/DimKa163/keeper_MasterKey"
 	hostName   HostName  = "github.com/DimKa163/keeper_Hostname"
 	versionKey Version   = "github.com/DimKa163/keeper_version"
+	clientKey  ClientID  = "github.com/DimKa163/keeper_client"
 )
 
 type MasterKey string
@@ -20,6 +21,8 @@ type HostName string
 
 type Version string
 
+type ClientID string
+
 var (
 	ErrMasterKeyNotRegistered = errors.New("keeper: master key not registered")
 )
@@ -54,3 +57,16 @@ func GetVersion(ctx context.Context) int32 {
 	}
 	return -1
 }
+
+// SetClientID set id of the client installation
+func SetClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientKey, id)
+}
+
+// GetClientID id of the client installation, empty when not set
+func GetClientID(ctx context.Context) string {
+	if v, ok := ctx.Value(clientKey).(string); ok {
+		return v
+	}
+	return ""
+}
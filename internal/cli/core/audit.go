@@ -0,0 +1,27 @@
+package core
+
+import "time"
+
+// AuditEvent entry of audit trail on a remote server
+type AuditEvent struct {
+	ID       int64
+	Type     string
+	Login    string
+	DeviceID string
+	ClientID string
+	Peer     string
+	SecretID string
+	Version  int32
+	Bytes    int64
+	Reason   string
+	At       time.Time
+}
+
+// AuditQuery which events to read, own events unless Login or All is set
+type AuditQuery struct {
+	Login    string
+	All      bool
+	Since    time.Time
+	BeforeID int64
+	Limit    int32
+}
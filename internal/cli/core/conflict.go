@@ -17,6 +17,8 @@ type Conflict struct {
 type ConflictItem struct {
 	Record  *Record `json:"record"`
 	Deleted bool
+	// Fields changed on both sides, other fields are already merged
+	Fields []string `json:"fields,omitempty"`
 }
 
 func (c *Conflict) MarshalLocal() ([]byte, error) {
@@ -1,5 +1,5 @@
 package core
 
 type Decoder interface {
-	Decode(data, key []byte) ([]byte, error)
+	Decode(data, key, additionalData []byte) ([]byte, error)
 }
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrNotMergeable = errors.New("secret can't be merged by fields")
+
+// MergeResult three-way merge of secret fields.
+// Local and Remote differ only in conflicting fields, each side keeps its own value there
+type MergeResult struct {
+	Local  []byte
+	Remote []byte
+	Fields []string
+}
+
+// Merge take fields changed on one side only from that side.
+// Fields changed on both sides to different values are left as conflicting
+func Merge(base, local, remote []byte) (*MergeResult, error) {
+	var baseFields, localFields, remoteFields map[string]json.RawMessage
+	if err := json.Unmarshal(base, &baseFields); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotMergeable, err)
+	}
+	if err := json.Unmarshal(local, &localFields); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotMergeable, err)
+	}
+	if err := json.Unmarshal(remote, &remoteFields); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotMergeable, err)
+	}
+	keys := make([]string, 0, len(baseFields)+len(localFields)+len(remoteFields))
+	for _, fields := range []map[string]json.RawMessage{baseFields, localFields, remoteFields} {
+		for key := range fields {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	localMerged := make(map[string]json.RawMessage, len(keys))
+	remoteMerged := make(map[string]json.RawMessage, len(keys))
+	var conflicts []string
+	for _, key := range keys {
+		baseValue, inBase := baseFields[key]
+		localValue, inLocal := localFields[key]
+		remoteValue, inRemote := remoteFields[key]
+		switch {
+		case sameField(localValue, inLocal, remoteValue, inRemote),
+			sameField(remoteValue, inRemote, baseValue, inBase):
+			setField(localMerged, key, localValue, inLocal)
+			setField(remoteMerged, key, localValue, inLocal)
+		case sameField(localValue, inLocal, baseValue, inBase):
+			setField(localMerged, key, remoteValue, inRemote)
+			setField(remoteMerged, key, remoteValue, inRemote)
+		default:
+			conflicts = append(conflicts, key)
+			setField(localMerged, key, localValue, inLocal)
+			setField(remoteMerged, key, remoteValue, inRemote)
+		}
+	}
+	localData, err := json.Marshal(localMerged)
+	if err != nil {
+		return nil, err
+	}
+	remoteData, err := json.Marshal(remoteMerged)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResult{Local: localData, Remote: remoteData, Fields: conflicts}, nil
+}
+
+func sameField(a json.RawMessage, aOk bool, b json.RawMessage, bOk bool) bool {
+	return aOk == bOk && bytes.Equal(a, b)
+}
+
+func setField(fields map[string]json.RawMessage, key string, value json.RawMessage, ok bool) {
+	if ok {
+		fields[key] = value
+	}
+}
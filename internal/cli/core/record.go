@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/beevik/guid"
@@ -16,6 +17,17 @@ const (
 	OtherType
 )
 
+// Role names the part of a record a ciphertext belongs to
+type Role string
+
+const (
+	DekRole  Role = "dek"
+	DataRole Role = "data"
+	FileRole Role = "file"
+	// ShareRole dek sealed to public key of another user
+	ShareRole Role = "share"
+)
+
 type Record struct {
 	ID         string    `json:"id"`
 	CreatedAt  time.Time `json:"created_at"`
@@ -27,6 +39,14 @@ type Record struct {
 	Version    int32     `json:"version"`
 	Deleted    bool      `json:"deleted"`
 	Corrupted  bool      `json:"corrupted"`
+	// Owner login of the user shared the record, empty for own records. Shared record is read only
+	Owner string `json:"owner,omitempty"`
+	// Vault id of the team vault the record belongs to, empty for own records
+	Vault string `json:"vault,omitempty"`
+	// Legacy local record stored before ciphertexts were bound to the record identity, cleared by reseal
+	Legacy bool `json:"-"`
+	// unbound ciphertexts of the legacy record were read without associated data
+	unbound bool
 }
 
 func CreateRecord(tp DataType) *Record {
@@ -37,19 +57,98 @@ func CreateRecord(tp DataType) *Record {
 	}
 }
 
-//func (r *Record) Encode(encoder Encoder, data, masterKey []byte) error {
-//	return encoder.Encode(r, data, masterKey)
-//}
+// AdditionalData binds a ciphertext to the record identity and version, dek sealed to another user has no version
+func (r *Record) AdditionalData(role Role) []byte {
+	if role == ShareRole {
+		return []byte(fmt.Sprintf("keeper|%s|%d|%s", r.ID, r.Type, role))
+	}
+	return []byte(fmt.Sprintf("keeper|%s|%d|%d|%s", r.ID, r.Type, r.Version, role))
+}
+
+// Encode encrypt data with dek and wrap dek with master key. Version must be set before
+func (r *Record) Encode(encoder Encoder, data, dek, masterKey []byte) error {
+	cipherData, err := encoder.Encode(data, dek, r.AdditionalData(DataRole))
+	if err != nil {
+		return err
+	}
+	cipherDek, err := encoder.Encode(dek, masterKey, r.AdditionalData(DekRole))
+	if err != nil {
+		return err
+	}
+	r.Data = cipherData
+	r.Dek = cipherDek
+	r.Legacy = false
+	r.unbound = false
+	return nil
+}
+
+// Rebind re-encrypt record under a new version
+func (r *Record) Rebind(encoder Encoder, decoder Decoder, masterKey []byte, version int32) error {
+	return r.Rekey(encoder, decoder, masterKey, masterKey, version)
+}
+
+// Rekey re-encrypt record under a new version and wrap dek with a new master key
+func (r *Record) Rekey(encoder Encoder, decoder Decoder, oldKey, newKey []byte, version int32) error {
+	dek, err := r.DecodeDek(decoder, oldKey)
+	if err != nil {
+		return err
+	}
+	data, err := r.DecodeData(decoder, dek)
+	if err != nil {
+		return err
+	}
+	r.Version = version
+	return r.Encode(encoder, data, dek, newKey)
+}
+
+// Reseal re-encrypt legacy record under its version, so it's bound to the record identity
+func (r *Record) Reseal(encoder Encoder, decoder Decoder, masterKey []byte) error {
+	return r.Rebind(encoder, decoder, masterKey, r.Version)
+}
+
+// IsShared record was shared with the user by another one
+func (r *Record) IsShared() bool {
+	return r.Owner != ""
+}
+
+// InVault record belongs to a team vault, it is synced under the vault state
+func (r *Record) InVault() bool {
+	return r.Vault != ""
+}
 
 func (r *Record) IsChanged(state *SyncState) bool {
 	return r.Version > state.Value
 }
+
+// DecodeDek unwrap data encryption key. Only own record marked as legacy may be read without associated data
+func (r *Record) DecodeDek(decoder Decoder, masterKey []byte) ([]byte, error) {
+	dek, err := decoder.Decode(r.Dek, masterKey, r.AdditionalData(DekRole))
+	r.unbound = false
+	if err == nil || !r.Legacy || r.IsShared() || r.InVault() {
+		return dek, err
+	}
+	dek, legacyErr := decoder.Decode(r.Dek, masterKey, nil)
+	if legacyErr != nil {
+		return nil, err
+	}
+	r.unbound = true
+	return dek, nil
+}
+
+// DecodeData decrypt data with unwrapped dek, DecodeDek must be called before
+func (r *Record) DecodeData(decoder Decoder, dek []byte) ([]byte, error) {
+	if r.unbound {
+		return decoder.Decode(r.Data, dek, nil)
+	}
+	return decoder.Decode(r.Data, dek, r.AdditionalData(DataRole))
+}
+
 func (r *Record) Decode(decoder Decoder, masterKey []byte) ([]byte, error) {
-	dek, err := decoder.Decode(r.Dek, masterKey)
+	dek, err := r.DecodeDek(decoder, masterKey)
 	if err != nil {
 		return nil, err
 	}
-	data, err := decoder.Decode(r.Data, dek)
+	data, err := r.DecodeData(decoder, dek)
 	if err != nil {
 		return nil, err
 	}
@@ -4,3 +4,12 @@ type SyncState struct {
 	ID    string
 	Value int32
 }
+
+// PullProgress position of an interrupted pull
+type PullProgress struct {
+	ID       string
+	Since    int32
+	Version  int32
+	SecretID string
+	Conflict bool
+}
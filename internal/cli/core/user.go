@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type User struct {
 	ID       string
 	Username string
@@ -7,10 +9,71 @@ type User struct {
 	Salt     []byte
 }
 
+// Recovery master key wrapped with the recovery key, which is split into shares
+type Recovery struct {
+	UserID    string
+	Key       []byte
+	Shares    int
+	Threshold int
+}
+
 type Server struct {
 	ID       int32
 	Address  string
 	Login    string
 	Password string
 	Active   bool
+	// TLS connect over TLS, CA is used instead of system roots when set, pinned key is trusted without CA
+	TLS bool
+	CA  string
+	// Cert and Key client certificate for servers requiring mTLS
+	Cert string
+	Key  string
+	// Fingerprint pinned sha256 of server public key, checked in addition to the certificate chain.
+	// It's pinned explicitly, servers registered before pinning stay unpinned until 'keeper remote trust'
+	Fingerprint string
+}
+
+// Device key pair of the installation registered on a remote server
+type Device struct {
+	ServerID   int32
+	ID         string
+	PrivateKey []byte
+}
+
+// DeviceInfo device registered on a remote server
+type DeviceInfo struct {
+	ID          string
+	Name        string
+	CreatedAt   time.Time
+	LastSeenAt  time.Time
+	SyncVersion int32
+	Revoked     bool
+	Pending     bool
+	Current     bool
+}
+
+// SessionInfo session of the user on a remote server
+type SessionInfo struct {
+	ID         string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	DeviceID   string
+	Current    bool
+}
+
+// ShareKey X25519 key pair secrets are shared to with the user, private key is wrapped with the master key
+type ShareKey struct {
+	ServerID   int32
+	PublicKey  []byte
+	PrivateKey []byte
+}
+
+// ShareInfo record shared with another user
+type ShareInfo struct {
+	RecordID  string
+	Login     string
+	PublicKey []byte
+	CreatedAt time.Time
 }
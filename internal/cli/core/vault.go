@@ -0,0 +1,68 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrUnknownVaultRole = errors.New("unknown vault role, use reader, writer or admin")
+
+// VaultRole what a member can do with the vault, every role can do whatever lower ones do
+type VaultRole int32
+
+const (
+	VaultReader VaultRole = iota
+	VaultWriter
+	VaultAdmin
+	VaultOwner
+)
+
+func (r VaultRole) String() string {
+	switch r {
+	case VaultReader:
+		return "reader"
+	case VaultWriter:
+		return "writer"
+	case VaultAdmin:
+		return "admin"
+	case VaultOwner:
+		return "owner"
+	}
+	return fmt.Sprintf("VaultRole(%d)", int32(r))
+}
+
+// ParseVaultRole parse name of the role a member can be invited with
+func ParseVaultRole(name string) (VaultRole, error) {
+	switch name {
+	case "reader":
+		return VaultReader, nil
+	case "writer":
+		return VaultWriter, nil
+	case "admin":
+		return VaultAdmin, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnknownVaultRole, name)
+}
+
+// Vault vault of a team on the remote server. Key is wrapped with the master key
+type Vault struct {
+	ID       string
+	ServerID int32
+	Name     string
+	Role     VaultRole
+	Key      []byte
+}
+
+// CanWrite member can push records of the vault
+func (v *Vault) CanWrite() bool {
+	return v.Role >= VaultWriter
+}
+
+// VaultMember member of a vault
+type VaultMember struct {
+	Login     string
+	Role      VaultRole
+	PublicKey []byte
+	CreatedAt time.Time
+}
@@ -6,11 +6,18 @@ import (
 	"compress/gzip"
 	"crypto/aes"
 	"crypto/cipher"
+	"encoding/binary"
+	"errors"
 	"io"
 
 	"github.com/DimKa163/keeper/internal/cli/core"
 )
 
+var (
+	ErrInvalidCipherData = errors.New("invalid cipher data")
+	ErrTruncatedFile     = errors.New("encrypted file is truncated")
+)
+
 type AesDecoder struct {
 }
 
@@ -18,7 +25,7 @@ func NewAesDecoder() core.Decoder {
 	return &AesDecoder{}
 }
 
-func (a *AesDecoder) Decode(cipherData, key []byte) ([]byte, error) {
+func (a *AesDecoder) Decode(cipherData, key, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -28,8 +35,11 @@ func (a *AesDecoder) Decode(cipherData, key []byte) ([]byte, error) {
 		return nil, err
 	}
 	nonceSize := gcm.NonceSize()
+	if len(cipherData) < nonceSize {
+		return nil, ErrInvalidCipherData
+	}
 	nonce, data := cipherData[:nonceSize], cipherData[nonceSize:]
-	return gcm.Open(nil, nonce, data, nil)
+	return gcm.Open(nil, nonce, data, additionalData)
 }
 
 func (a *AesDecoder) aesOpen(key, nonce []byte, cipherData []byte) ([]byte, error) {
@@ -55,8 +65,8 @@ func NewGzipDecoder(decoder core.Decoder) core.Decoder {
 	}
 }
 
-func (g GzipDecoder) Decode(data, key []byte) ([]byte, error) {
-	data, err := g.decoder.Decode(data, key)
+func (g GzipDecoder) Decode(data, key, additionalData []byte) ([]byte, error) {
+	data, err := g.decoder.Decode(data, key, additionalData)
 	if err != nil {
 		return nil, err
 	}
@@ -68,33 +78,107 @@ func (g GzipDecoder) Decode(data, key []byte) ([]byte, error) {
 	return io.ReadAll(reader)
 }
 
+// FileDecoder read file written by FileEncoder. File of a legacy record may be of the old format: a single
+// blob sealed without associated data, it's recognized when the first segment can't be read
 type FileDecoder struct {
 	decoder core.Decoder
+	legacy  bool
 	fs      io.ReadCloser
 	dek     []byte
+	ad      []byte
+	buf     []byte
+	index   uint32
+	final   bool
 }
 
 func (f *FileDecoder) Read(p []byte) (n int, err error) {
-	data, err := io.ReadAll(f.fs)
+	for len(f.buf) == 0 {
+		if f.final {
+			return 0, io.EOF
+		}
+		if err = f.next(); err != nil {
+			return 0, err
+		}
+	}
+	n = copy(p, f.buf)
+	f.buf = f.buf[n:]
+	return n, nil
+}
+
+func (f *FileDecoder) next() error {
+	header := make([]byte, 5)
+	if n, err := io.ReadFull(f.fs, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return f.readLegacy(header[:n], ErrTruncatedFile)
+		}
+		return err
+	}
+	final := header[0] == 1
+	size := binary.BigEndian.Uint32(header[1:])
+	if header[0] > 1 || size > uint32(2*fileSegmentSize) {
+		return f.readLegacy(header, ErrInvalidCipherData)
+	}
+	cipherData := make([]byte, size)
+	if n, err := io.ReadFull(f.fs, cipherData); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return f.readLegacy(append(header, cipherData[:n]...), ErrTruncatedFile)
+		}
+		return err
+	}
+	data, err := f.decoder.Decode(cipherData, f.dek, segmentAdditionalData(f.ad, f.index, final))
+	if err != nil {
+		return f.readLegacy(append(header, cipherData...), err)
+	}
+	if final {
+		// nothing may follow the last segment
+		if n, _ := f.fs.Read(make([]byte, 1)); n != 0 {
+			return ErrInvalidCipherData
+		}
+	}
+	f.index++
+	f.final = final
+	f.buf = data
+	return nil
+}
+
+// readLegacy read the file as a single blob of the old format, read is bytes consumed already
+func (f *FileDecoder) readLegacy(read []byte, segmentErr error) error {
+	if !f.legacy || f.index > 0 {
+		return segmentErr
+	}
+	rest, err := io.ReadAll(f.fs)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	d, err := f.decoder.Decode(data, f.dek)
+	data, err := f.decoder.Decode(append(read, rest...), f.dek, nil)
 	if err != nil {
-		return -1, err
+		return segmentErr
 	}
-	n = copy(p, d)
-	return n, nil
+	f.final = true
+	f.buf = data
+	return nil
 }
 
 func (f *FileDecoder) Close() error {
 	return f.fs.Close()
 }
 
-func NewFileDecoder(decoder core.Decoder, fs io.ReadCloser, dek []byte) io.ReadCloser {
+func NewFileDecoder(decoder core.Decoder, fs io.ReadCloser, dek, additionalData []byte) io.ReadCloser {
+	return &FileDecoder{
+		decoder: decoder,
+		fs:      fs,
+		dek:     dek,
+		ad:      additionalData,
+	}
+}
+
+// NewLegacyFileDecoder decoder which also reads file of the old format, it's only for local legacy records
+func NewLegacyFileDecoder(decoder core.Decoder, fs io.ReadCloser, dek, additionalData []byte) io.ReadCloser {
 	return &FileDecoder{
 		decoder: decoder,
+		legacy:  true,
 		fs:      fs,
 		dek:     dek,
+		ad:      additionalData,
 	}
 }
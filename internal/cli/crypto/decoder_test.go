@@ -6,6 +6,8 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,7 +30,7 @@ func TestAesDecoder_Decode(t *testing.T) {
 	cipherData := gcm.Seal(nonce, nonce, plainText, nil)
 
 	decoder := NewAesDecoder()
-	decryptedData, err := decoder.Decode(cipherData, key)
+	decryptedData, err := decoder.Decode(cipherData, key, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, plainText, decryptedData)
 }
@@ -58,7 +60,7 @@ func TestGzipDecoder_Decode(t *testing.T) {
 	cipherData := gcm.Seal(nonce, nonce, compressedData.Bytes(), nil)
 
 	gzipDecoder := NewGzipDecoder(decoder)
-	decryptedData, err := gzipDecoder.Decode(cipherData, key)
+	decryptedData, err := gzipDecoder.Decode(cipherData, key, nil)
 	assert.NoError(t, err)
 
 	assert.Equal(t, plainText, decryptedData)
@@ -83,10 +85,114 @@ func TestAesDecoder_Decode_InvalidKey(t *testing.T) {
 	invalidKey := generateRandomKey()
 
 	decoder := NewAesDecoder()
-	_, err = decoder.Decode(cipherData, invalidKey)
+	_, err = decoder.Decode(cipherData, invalidKey, nil)
 	assert.Error(t, err)
 }
 
+func TestAesDecoder_Decode_InvalidAdditionalData(t *testing.T) {
+	key := generateRandomKey()
+	encoder := NewAesEncoder()
+	cipherData, err := encoder.Encode([]byte("Hello, world!"), key, []byte("keeper|a|0|1|data"))
+	assert.NoError(t, err)
+
+	decoder := NewAesDecoder()
+	_, err = decoder.Decode(cipherData, key, []byte("keeper|b|0|1|data"))
+	assert.Error(t, err)
+}
+
+func TestFileDecoder_Read(t *testing.T) {
+	key := generateRandomKey()
+	ad := []byte("keeper|a|3|file")
+	plainText := make([]byte, fileSegmentSize*2+100)
+	_, err := rand.Read(plainText)
+	assert.NoError(t, err)
+
+	var buf closeBuffer
+	writer := NewFileEncoder(NewAesEncoder(), &buf, key, ad)
+	_, err = writer.Write(plainText)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	reader := NewFileDecoder(NewAesDecoder(), &buf, key, ad)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, plainText, data)
+}
+
+func TestFileDecoder_Read_Truncated(t *testing.T) {
+	key := generateRandomKey()
+	ad := []byte("keeper|a|3|file")
+	plainText := make([]byte, fileSegmentSize+100)
+	_, err := rand.Read(plainText)
+	assert.NoError(t, err)
+
+	var buf closeBuffer
+	writer := NewFileEncoder(NewAesEncoder(), &buf, key, ad)
+	_, err = writer.Write(plainText)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	buf.Truncate(5 + int(binary.BigEndian.Uint32(buf.Bytes()[1:5])))
+
+	reader := NewFileDecoder(NewAesDecoder(), &buf, key, ad)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, ErrTruncatedFile)
+}
+
+func TestFileDecoder_Read_InvalidAdditionalData(t *testing.T) {
+	key := generateRandomKey()
+
+	var buf closeBuffer
+	writer := NewFileEncoder(NewAesEncoder(), &buf, key, []byte("keeper|a|3|file"))
+	_, err := writer.Write([]byte("Hello, world!"))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	reader := NewFileDecoder(NewAesDecoder(), &buf, key, []byte("keeper|b|3|file"))
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+}
+
+func TestFileDecoder_Read_LegacyFile(t *testing.T) {
+	key := generateRandomKey()
+	plainText := make([]byte, fileSegmentSize+100)
+	_, err := rand.Read(plainText)
+	assert.NoError(t, err)
+	// legacy file: one block without associated data
+	encoder := NewGzipEncoder(NewAesEncoder())
+	for _, content := range [][]byte{plainText, []byte("small")} {
+		cipherData, err := encoder.Encode(content, key, nil)
+		assert.NoError(t, err)
+		var buf closeBuffer
+		buf.Write(cipherData)
+
+		reader := NewLegacyFileDecoder(NewGzipDecoder(NewAesDecoder()), &buf, key, []byte("keeper|a|3|file"))
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, content, data)
+	}
+}
+
+func TestFileDecoder_Read_ShouldRejectLegacyFileUnlessAllowed(t *testing.T) {
+	key := generateRandomKey()
+	cipherData, err := NewGzipEncoder(NewAesEncoder()).Encode([]byte("small"), key, nil)
+	assert.NoError(t, err)
+	var buf closeBuffer
+	buf.Write(cipherData)
+
+	reader := NewFileDecoder(NewGzipDecoder(NewAesDecoder()), &buf, key, []byte("keeper|a|3|file"))
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+}
+
+type closeBuffer struct {
+	bytes.Buffer
+}
+
+func (b *closeBuffer) Close() error {
+	return nil
+}
+
 func generateRandomKey() []byte {
 	key := make([]byte, 32)
 	_, err := rand.Read(key)
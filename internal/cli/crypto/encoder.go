@@ -7,10 +7,17 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"strconv"
 
 	"github.com/DimKa163/keeper/internal/cli/core"
+	"github.com/DimKa163/keeper/internal/datatool"
 )
 
+// fileSegmentSize plain text size of one file segment
+const fileSegmentSize = int(datatool.MB)
+
 type AesEncoder struct {
 	dekLength int32
 }
@@ -21,7 +28,7 @@ func NewAesEncoder() core.Encoder {
 	}
 }
 
-func (a *AesEncoder) Encode(data, key []byte) (cipherData []byte, err error) {
+func (a *AesEncoder) Encode(data, key, additionalData []byte) (cipherData []byte, err error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -35,7 +42,7 @@ func (a *AesEncoder) Encode(data, key []byte) (cipherData []byte, err error) {
 		return nil, err
 	}
 
-	cipherData = gcm.Seal(nonce, nonce, data, nil)
+	cipherData = gcm.Seal(nonce, nonce, data, additionalData)
 	return cipherData, nil
 }
 
@@ -49,7 +56,7 @@ func NewGzipEncoder(encoder core.Encoder) core.Encoder {
 	}
 }
 
-func (g *GzipEncoder) Encode(data, key []byte) (cipherData []byte, err error) {
+func (g *GzipEncoder) Encode(data, key, additionalData []byte) (cipherData []byte, err error) {
 	var buf bytes.Buffer
 	gz, err := gzip.NewWriterLevel(&buf, gzip.DefaultCompression)
 	if err != nil {
@@ -62,5 +69,79 @@ func (g *GzipEncoder) Encode(data, key []byte) (cipherData []byte, err error) {
 	if err = gz.Close(); err != nil {
 		return nil, err
 	}
-	return g.encoder.Encode(buf.Bytes(), key)
+	return g.encoder.Encode(buf.Bytes(), key, additionalData)
+}
+
+// FileEncoder write file as a sequence of sealed segments.
+// Every segment is bound to its index and to the final flag, so segments can't be reordered or cut off
+type FileEncoder struct {
+	encoder core.Encoder
+	fs      io.WriteCloser
+	dek     []byte
+	ad      []byte
+	buf     []byte
+	index   uint32
+}
+
+func NewFileEncoder(encoder core.Encoder, fs io.WriteCloser, dek, additionalData []byte) io.WriteCloser {
+	return &FileEncoder{
+		encoder: encoder,
+		fs:      fs,
+		dek:     dek,
+		ad:      additionalData,
+		buf:     make([]byte, 0, fileSegmentSize),
+	}
+}
+
+func (f *FileEncoder) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(f.buf) == fileSegmentSize {
+			if err := f.flush(false); err != nil {
+				return 0, err
+			}
+		}
+		m := min(fileSegmentSize-len(f.buf), len(p))
+		f.buf = append(f.buf, p[:m]...)
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func (f *FileEncoder) Close() error {
+	if err := f.flush(true); err != nil {
+		_ = f.fs.Close()
+		return err
+	}
+	return f.fs.Close()
+}
+
+func (f *FileEncoder) flush(final bool) error {
+	cipherData, err := f.encoder.Encode(f.buf, f.dek, segmentAdditionalData(f.ad, f.index, final))
+	if err != nil {
+		return err
+	}
+	header := make([]byte, 5)
+	if final {
+		header[0] = 1
+	}
+	binary.BigEndian.PutUint32(header[1:], uint32(len(cipherData)))
+	if _, err = f.fs.Write(header); err != nil {
+		return err
+	}
+	if _, err = f.fs.Write(cipherData); err != nil {
+		return err
+	}
+	f.index++
+	f.buf = f.buf[:0]
+	return nil
+}
+
+func segmentAdditionalData(ad []byte, index uint32, final bool) []byte {
+	segment := make([]byte, 0, len(ad)+16)
+	segment = append(segment, ad...)
+	segment = append(segment, '|')
+	segment = strconv.AppendUint(segment, uint64(index), 10)
+	segment = append(segment, '|')
+	return strconv.AppendBool(segment, final)
 }
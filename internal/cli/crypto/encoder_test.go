@@ -11,9 +11,9 @@ func TestAesEncoder_Encode(t *testing.T) {
 	plaintext := []byte("hello world")
 	decoder := NewAesDecoder()
 	encoder := NewAesEncoder()
-	ciphertext, err := encoder.Encode(plaintext, key)
+	ciphertext, err := encoder.Encode(plaintext, key, nil)
 	assert.NoError(t, err)
-	plaintext2, err := decoder.Decode(ciphertext, key)
+	plaintext2, err := decoder.Decode(ciphertext, key, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, plaintext, plaintext2)
 }
@@ -0,0 +1,86 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// EncodeMnemonic print share as words, one word per byte and a checksum word at the end
+func EncodeMnemonic(share []byte) string {
+	sum := sha256.Sum256(share)
+	words := make([]string, 0, len(share)+1)
+	for _, b := range share {
+		words = append(words, wordList[b])
+	}
+	words = append(words, wordList[sum[0]])
+	return strings.Join(words, " ")
+}
+
+// DecodeMnemonic parse words printed by EncodeMnemonic
+func DecodeMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < 2 {
+		return nil, ErrInvalidMnemonic
+	}
+	data := make([]byte, len(words))
+	for i, word := range words {
+		b, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		data[i] = b
+	}
+	share, checksum := data[:len(data)-1], data[len(data)-1]
+	sum := sha256.Sum256(share)
+	if sum[0] != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidMnemonic)
+	}
+	return share, nil
+}
+
+var wordIndex = func() map[string]byte {
+	index := make(map[string]byte, len(wordList))
+	for i, word := range wordList {
+		index[word] = byte(i)
+	}
+	return index
+}()
+
+var wordList = [256]string{
+	"able", "acid", "aged", "also", "area", "army", "away", "baby",
+	"back", "ball", "band", "bank", "base", "bath", "bear", "beat",
+	"been", "beer", "bell", "belt", "best", "bird", "blow", "blue",
+	"boat", "body", "bond", "bone", "book", "boom", "born", "boss",
+	"both", "bowl", "bulk", "burn", "bush", "busy", "cake", "call",
+	"calm", "came", "camp", "card", "care", "case", "cash", "cast",
+	"cell", "chat", "chip", "city", "club", "coal", "coat", "code",
+	"cold", "come", "cook", "cool", "cope", "copy", "core", "cost",
+	"crew", "crop", "dark", "data", "date", "dawn", "days", "dead",
+	"deal", "dear", "debt", "deep", "deny", "desk", "dial", "diet",
+	"disc", "disk", "does", "done", "door", "dose", "down", "draw",
+	"drew", "drop", "dual", "dust", "duty", "each", "earn", "ease",
+	"east", "easy", "edge", "else", "even", "ever", "exit", "face",
+	"fact", "fail", "fair", "fall", "farm", "fast", "fate", "fear",
+	"feed", "feel", "feet", "fell", "felt", "file", "fill", "film",
+	"find", "fine", "fire", "firm", "fish", "five", "flat", "flow",
+	"food", "foot", "form", "fort", "four", "free", "from", "fuel",
+	"full", "fund", "gain", "game", "gate", "gave", "gear", "gift",
+	"girl", "give", "glad", "goal", "goes", "gold", "golf", "gone",
+	"good", "gray", "grew", "grey", "grow", "gulf", "hair", "half",
+	"hall", "hand", "hang", "hard", "harm", "have", "head", "hear",
+	"heat", "held", "help", "here", "hero", "high", "hill", "hire",
+	"hold", "hole", "holy", "home", "hope", "host", "hour", "huge",
+	"hung", "hunt", "hurt", "idea", "inch", "into", "iron", "item",
+	"join", "jump", "jury", "just", "keen", "keep", "kept", "kick",
+	"kind", "king", "knee", "knew", "know", "lack", "lady", "laid",
+	"lake", "land", "lane", "last", "late", "lead", "left", "less",
+	"life", "lift", "like", "line", "link", "list", "live", "load",
+	"loan", "lock", "logo", "long", "look", "lord", "lose", "loss",
+	"lost", "love", "luck", "made", "mail", "main", "make", "male",
+	"many", "mark", "mass", "meal", "mean", "meat", "meet", "menu",
+	"mere", "mile", "milk", "mill", "mind", "mine", "miss", "mode",
+}
@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+)
+
+var (
+	ErrInvalidThreshold = errors.New("threshold must be between 2 and shares count")
+	ErrInvalidShares    = errors.New("invalid shares")
+)
+
+// exp and log tables of GF(2^8) with polynomial x^8 + x^4 + x^3 + x + 1
+var (
+	gfExp [510]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfExp[i+255] = x
+		gfLog[x] = byte(i)
+		// multiply by the generator 3
+		x ^= gfDouble(x)
+	}
+}
+
+func gfDouble(x byte) byte {
+	if x&0x80 != 0 {
+		return x<<1 ^ 0x1b
+	}
+	return x << 1
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// Split split secret into n shares, any k of them restore the secret.
+// First byte of every share is its x coordinate
+func Split(secret []byte, n, k int) ([][]byte, error) {
+	if k < 2 || k > n || n > 255 {
+		return nil, ErrInvalidThreshold
+	}
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][0] = byte(i + 1)
+	}
+	coefficients := make([]byte, k)
+	for j, s := range secret {
+		coefficients[0] = s
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for _, share := range shares {
+			share[j+1] = evaluate(coefficients, share[0])
+		}
+	}
+	return shares, nil
+}
+
+// Combine restore secret from shares with Lagrange interpolation at zero
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrInvalidShares
+	}
+	size := len(shares[0])
+	seen := make(map[byte]struct{}, len(shares))
+	for _, share := range shares {
+		if len(share) != size || size < 2 || share[0] == 0 {
+			return nil, ErrInvalidShares
+		}
+		if _, ok := seen[share[0]]; ok {
+			return nil, ErrInvalidShares
+		}
+		seen[share[0]] = struct{}{}
+	}
+	secret := make([]byte, size-1)
+	for i, share := range shares {
+		basis := byte(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			basis = gfMul(basis, gfDiv(other[0], other[0]^share[0]))
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(share[b+1], basis)
+		}
+	}
+	return secret, nil
+}
+
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coefficients[i]
+	}
+	return y
+}
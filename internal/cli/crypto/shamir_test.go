@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit_CombineAnyThreshold(t *testing.T) {
+	secret := generateRandomKey()
+	shares, err := Split(secret, 5, 3)
+	assert.NoError(t, err)
+	assert.Len(t, shares, 5)
+
+	restored, err := Combine([][]byte{shares[4], shares[0], shares[2]})
+	assert.NoError(t, err)
+	assert.Equal(t, secret, restored)
+
+	restored, err = Combine(shares)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, restored)
+}
+
+func TestSplit_CombineLessThanThreshold(t *testing.T) {
+	secret := generateRandomKey()
+	shares, err := Split(secret, 5, 3)
+	assert.NoError(t, err)
+
+	restored, err := Combine(shares[:2])
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, restored)
+}
+
+func TestSplit_InvalidThreshold(t *testing.T) {
+	_, err := Split(generateRandomKey(), 3, 4)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+}
+
+func TestCombine_DuplicateShares(t *testing.T) {
+	shares, err := Split(generateRandomKey(), 3, 2)
+	assert.NoError(t, err)
+
+	_, err = Combine([][]byte{shares[0], shares[0]})
+	assert.ErrorIs(t, err, ErrInvalidShares)
+}
+
+func TestMnemonic_RoundTrip(t *testing.T) {
+	shares, err := Split(generateRandomKey(), 3, 2)
+	assert.NoError(t, err)
+
+	mnemonic := EncodeMnemonic(shares[1])
+	share, err := DecodeMnemonic(mnemonic + "\n")
+	assert.NoError(t, err)
+	assert.Equal(t, shares[1], share)
+}
+
+func TestMnemonic_InvalidChecksum(t *testing.T) {
+	mnemonic := EncodeMnemonic([]byte{1, 2, 3})
+	_, err := DecodeMnemonic("able " + mnemonic)
+	assert.ErrorIs(t, err, ErrInvalidMnemonic)
+}
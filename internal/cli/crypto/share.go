@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// shareKeyInfo binds derived key to its purpose
+const shareKeyInfo = "keeper share key"
+
+var ErrInvalidShareKey = errors.New("invalid share key")
+
+// GenerateShareKey create X25519 key pair
+func GenerateShareKey() (publicKey, privateKey []byte, err error) {
+	private, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return private.PublicKey().Bytes(), private.Bytes(), nil
+}
+
+// SealKey encrypt key to the public key of the recipient. Ephemeral key pair is used for every key,
+// result is ephemeral public key followed by nonce and cipher text
+func SealKey(key, recipientKey, additionalData []byte) ([]byte, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(recipientKey)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidShareKey, err)
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := shareCipher(ephemeral, recipient, ephemeral.PublicKey().Bytes(), recipientKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := append(ephemeral.PublicKey().Bytes(), nonce...)
+	return gcm.Seal(sealed, nonce, key, additionalData), nil
+}
+
+// OpenKey decrypt key sealed by SealKey with the private key of the recipient
+func OpenKey(sealed, privateKey, additionalData []byte) ([]byte, error) {
+	private, err := ecdh.X25519().NewPrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidShareKey, err)
+	}
+	keySize := len(private.PublicKey().Bytes())
+	if len(sealed) < keySize {
+		return nil, ErrInvalidCipherData
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[:keySize])
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCipherData, err)
+	}
+	gcm, err := shareCipher(private, ephemeral, sealed[:keySize], private.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	rest := sealed[keySize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, ErrInvalidCipherData
+	}
+	return gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], additionalData)
+}
+
+// shareCipher derive AES key from shared secret, both public keys are mixed in
+func shareCipher(private *ecdh.PrivateKey, public *ecdh.PublicKey, ephemeralKey, recipientKey []byte) (cipher.AEAD, error) {
+	secret, err := private.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte{}, ephemeralKey...), recipientKey...)
+	key, err := hkdf.Key(sha256.New, secret, salt, shareKeyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
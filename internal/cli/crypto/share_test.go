@@ -0,0 +1,35 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealKey_ShouldOpenWithRecipientKey(t *testing.T) {
+	public, private, err := GenerateShareKey()
+	assert.NoError(t, err)
+	dek := generateRandomKey()
+	ad := []byte("keeper|id|0|share")
+
+	sealed, err := SealKey(dek, public, ad)
+	assert.NoError(t, err)
+	opened, err := OpenKey(sealed, private, ad)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dek, opened)
+}
+
+func TestSealKey_ShouldNotOpenWithAnotherKeyOrData(t *testing.T) {
+	public, private, err := GenerateShareKey()
+	assert.NoError(t, err)
+	_, stranger, err := GenerateShareKey()
+	assert.NoError(t, err)
+	sealed, err := SealKey(generateRandomKey(), public, []byte("keeper|id|0|share"))
+	assert.NoError(t, err)
+
+	_, err = OpenKey(sealed, stranger, []byte("keeper|id|0|share"))
+	assert.Error(t, err)
+	_, err = OpenKey(sealed, private, []byte("keeper|other|0|share"))
+	assert.Error(t, err)
+}
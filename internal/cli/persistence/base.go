@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+)
+
+const (
+	getBaseRecordStmt = `SELECT id, modified_at, type, data, dek, version, legacy FROM base_records WHERE id = ?`
+
+	getAllBaseRecordStmt = `SELECT id, modified_at, type, data, dek, version, legacy FROM base_records`
+
+	saveBaseRecordStmt = `INSERT INTO base_records (id, modified_at, type, data, dek, version, legacy) VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET modified_at = excluded.modified_at, type = excluded.type,
+	data = excluded.data, dek = excluded.dek, version = excluded.version, legacy = excluded.legacy`
+
+	deleteBaseRecordStmt = `DELETE FROM base_records WHERE id = ?`
+)
+
+// TxGetBaseRecord get revision of record as it was at last sync
+func TxGetBaseRecord(ctx context.Context, tx *sql.Tx, id string) (*core.Record, error) {
+	var r core.Record
+	if err := tx.QueryRowContext(ctx, getBaseRecordStmt, id).Scan(&r.ID,
+		&r.ModifiedAt,
+		&r.Type,
+		&r.Data,
+		&r.Dek,
+		&r.Version,
+		&r.Legacy); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func TxGetAllBaseRecord(ctx context.Context, tx *sql.Tx) ([]*core.Record, error) {
+	rows, err := tx.QueryContext(ctx, getAllBaseRecordStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make([]*core.Record, 0)
+	for rows.Next() {
+		var r core.Record
+		if err = rows.Scan(&r.ID,
+			&r.ModifiedAt,
+			&r.Type,
+			&r.Data,
+			&r.Dek,
+			&r.Version,
+			&r.Legacy); err != nil {
+			return nil, err
+		}
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
+
+// TxSaveBaseRecord remember synced revision of record
+func TxSaveBaseRecord(ctx context.Context, tx *sql.Tx, record *core.Record) error {
+	_, err := tx.ExecContext(ctx, saveBaseRecordStmt,
+		record.ID,
+		record.ModifiedAt,
+		record.Type,
+		record.Data,
+		record.Dek,
+		record.Version,
+		record.Legacy)
+	return err
+}
+
+func TxDeleteBaseRecord(ctx context.Context, tx *sql.Tx, id string) error {
+	_, err := tx.ExecContext(ctx, deleteBaseRecordStmt, id)
+	return err
+}
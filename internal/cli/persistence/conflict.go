@@ -18,6 +18,14 @@ const (
 					local,
     				remote
 					FROM conflicts`
+	getConflictByIDStmt = `SELECT id,
+					created_at,
+					modified_at,
+					record_id,
+					local,
+    				remote
+					FROM conflicts
+					WHERE id = ?`
 	insertConflict = `INSERT INTO conflicts (record_id, local, remote) VALUES (?, ?, ?)`
 
 	conflictCount = `SELECT COUNT(*) FROM conflicts`
@@ -46,13 +54,43 @@ func GetAllConflict(ctx context.Context, db *sql.DB) ([]*core.Conflict, error) {
 		return nil, err
 	}
 	defer row.Close()
+	return scanConflicts(row)
+}
+
+func TxGetAllConflict(ctx context.Context, tx *sql.Tx) ([]*core.Conflict, error) {
+	row, err := tx.QueryContext(ctx, getAllNotSolvedConflicts)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanConflicts(row)
+}
+
+// GetConflictByID get conflict, sql.ErrNoRows if it is solved or doesn't exist
+func GetConflictByID(ctx context.Context, db *sql.DB, id int32) (*core.Conflict, error) {
+	row, err := db.QueryContext(ctx, getConflictByIDStmt, id)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	conflicts, err := scanConflicts(row)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return conflicts[0], nil
+}
+
+func scanConflicts(row *sql.Rows) ([]*core.Conflict, error) {
 	conflicts := make([]*core.Conflict, 0)
 	for row.Next() {
 		var id int32
 		var createdAt, modifiedAt sql.NullTime
 		var recordID string
 		var local, remote []byte
-		if err = row.Scan(&id, &createdAt, &modifiedAt, &recordID, &local, &remote); err != nil {
+		if err := row.Scan(&id, &createdAt, &modifiedAt, &recordID, &local, &remote); err != nil {
 			return nil, err
 		}
 		conflict := &core.Conflict{
@@ -68,17 +106,17 @@ func GetAllConflict(ctx context.Context, db *sql.DB) ([]*core.Conflict, error) {
 			conflict.ModifiedAt = modifiedAt.Time
 		}
 		var localItem core.ConflictItem
-		if err = json.Unmarshal(local, &localItem); err != nil {
+		if err := json.Unmarshal(local, &localItem); err != nil {
 			return nil, err
 		}
 		conflict.Local = &localItem
 		var remoteItem core.ConflictItem
-		if err = json.Unmarshal(remote, &remoteItem); err != nil {
+		if err := json.Unmarshal(remote, &remoteItem); err != nil {
 			return nil, err
 		}
 		conflict.Remote = &remoteItem
 	}
-	return conflicts, nil
+	return conflicts, row.Err()
 }
 
 func DeleteConflict(ctx context.Context, db *sql.DB, id int32) error {
@@ -88,6 +126,21 @@ func DeleteConflict(ctx context.Context, db *sql.DB, id int32) error {
 	return nil
 }
 
+func TxDeleteConflict(ctx context.Context, tx *sql.Tx, id int32) error {
+	if _, err := tx.ExecContext(ctx, deleteConflictStmt, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetConflictCount(ctx context.Context, db *sql.DB) (int64, error) {
+	var count int64
+	if err := db.QueryRowContext(ctx, conflictCount).Scan(&count); err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
 func TxGetConflictCount(ctx context.Context, db *sql.Tx) (int64, error) {
 	var count int64
 	if err := db.QueryRowContext(ctx, conflictCount).Scan(&count); err != nil {
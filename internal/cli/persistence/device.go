@@ -0,0 +1,33 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+)
+
+const (
+	getDeviceStmt  = `SELECT server_id, device_id, private_key FROM server_devices WHERE server_id = ?`
+	saveDeviceStmt = `INSERT INTO server_devices(server_id, device_id, private_key) VALUES (?, ?, ?)
+					ON CONFLICT(server_id) DO UPDATE SET device_id=excluded.device_id, private_key=excluded.private_key`
+)
+
+func GetDevice(ctx context.Context, db *sql.DB, serverID int32) (*core.Device, error) {
+	var device core.Device
+	if err := db.QueryRowContext(ctx, getDeviceStmt, serverID).Scan(
+		&device.ServerID,
+		&device.ID,
+		&device.PrivateKey,
+	); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func SaveDevice(ctx context.Context, db *sql.DB, device *core.Device) error {
+	if _, err := db.ExecContext(ctx, saveDeviceStmt, device.ServerID, device.ID, device.PrivateKey); err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"database/sql"
+	"fmt"
 
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "modernc.org/sqlite"
@@ -15,6 +16,14 @@ func Migrate(db *sql.DB) error {
 			
 			INSERT INTO sync_state(id, value) VALUES ('Record', 0) ON CONFLICT(id) DO NOTHING;
 			
+			CREATE TABLE IF NOT EXISTS pull_progress(
+			    id TEXT PRIMARY KEY,
+			    since INTEGER NOT NULL,
+			    version INTEGER NOT NULL,
+			    secret_id TEXT NOT NULL,
+			    conflict BOOLEAN NOT NULL DEFAULT 0
+			);
+			
 			CREATE TABLE IF NOT EXISTS records (
 			    id          TEXT PRIMARY KEY,
 			    created_at  DATETIME NOT NULL,
@@ -28,6 +37,15 @@ func Migrate(db *sql.DB) error {
 			    corrupted 	BOOLEAN NOT NULL DEFAULT 0
 			);
 			
+			CREATE TABLE IF NOT EXISTS base_records (
+			    id          TEXT PRIMARY KEY,
+			    modified_at DATETIME NOT NULL,
+			    type        INTEGER NOT NULL,
+			    data        BLOB NULL,
+			    dek         BLOB NULL,
+			    version     INT NOT NULL
+			);
+			
 			CREATE TABLE IF NOT EXISTS conflicts(
 			    id INTEGER PRIMARY KEY AUTOINCREMENT,
 			    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -44,16 +62,88 @@ func Migrate(db *sql.DB) error {
 			    salt    BLOB NOT NULL
 			);
 			
+			CREATE TABLE IF NOT EXISTS recovery(
+			    user_id TEXT PRIMARY KEY,
+			    key BLOB NOT NULL,
+			    shares INTEGER NOT NULL,
+			    threshold INTEGER NOT NULL
+			);
+			
+			CREATE TABLE IF NOT EXISTS settings(
+			    key TEXT PRIMARY KEY,
+			    value TEXT NOT NULL
+			);
+			
 			CREATE TABLE IF NOT EXISTS servers(
 			    id INTEGER PRIMARY KEY AUTOINCREMENT,
 			    address TEXT NOT NULL,
 			    login TEXT NOT NULL,
 			    password BLOB NOT NULL,
 			    active BOOLEAN
+			);
+			
+			CREATE TABLE IF NOT EXISTS server_devices(
+			    server_id INTEGER PRIMARY KEY,
+			    device_id TEXT NOT NULL,
+			    private_key BLOB NOT NULL
+			);
+			
+			CREATE TABLE IF NOT EXISTS server_sessions(
+			    server_id INTEGER PRIMARY KEY,
+			    refresh_token TEXT NOT NULL
+			);
+			
+			CREATE TABLE IF NOT EXISTS share_keys(
+			    server_id INTEGER PRIMARY KEY,
+			    public_key BLOB NOT NULL,
+			    private_key BLOB NOT NULL
+			);
+			
+			CREATE TABLE IF NOT EXISTS vaults(
+			    id TEXT PRIMARY KEY,
+			    server_id INTEGER NOT NULL,
+			    name TEXT NOT NULL,
+			    role INTEGER NOT NULL,
+			    vault_key BLOB NOT NULL
 			)`
 	_, err := db.Exec(sql)
 	if err != nil {
 		return err
 	}
+	return addColumns(db)
+}
+
+// addedColumns columns added to existing tables, they are created on start when missing
+var addedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"servers", "tls", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"servers", "ca", "TEXT NOT NULL DEFAULT ''"},
+	{"servers", "cert", "TEXT NOT NULL DEFAULT ''"},
+	{"servers", "key", "TEXT NOT NULL DEFAULT ''"},
+	{"servers", "fingerprint", "TEXT NOT NULL DEFAULT ''"},
+	{"records", "owner", "TEXT NOT NULL DEFAULT ''"},
+	{"records", "vault", "TEXT NOT NULL DEFAULT ''"},
+	// rows stored before ciphertexts were bound to the record identity are marked legacy once
+	{"records", "legacy", "BOOLEAN NOT NULL DEFAULT 1"},
+	{"base_records", "legacy", "BOOLEAN NOT NULL DEFAULT 1"},
+}
+
+func addColumns(db *sql.DB) error {
+	for _, added := range addedColumns {
+		var count int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`,
+			added.table, added.column).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", added.table, added.column, added.definition)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -9,23 +9,33 @@ import (
 
 const (
 	recordExistsStmt = `SELECT EXISTS(SELECT id FROM records WHERE id = $1)`
-	getAllStmt       = `SELECT id, created_at, modified_at, type, big_data, data, dek, version, deleted, corrupted FROM records
+	getAllStmt       = `SELECT id, created_at, modified_at, type, big_data, data, dek, version, deleted, corrupted, owner, vault, legacy FROM records
 				WHERE deleted = ? and corrupted = ?
 				ORDER BY id
 				LIMIT ? OFFSET ?`
-	getRecordByIDStmt = `SELECT id, created_at, modified_at, type, big_data, data,  dek, version, deleted, corrupted FROM records
+	getRecordByIDStmt = `SELECT id, created_at, modified_at, type, big_data, data,  dek, version, deleted, corrupted, owner, vault, legacy FROM records
 			WHERE id = ?`
-	insertStmt = `INSERT INTO records (id, created_at, modified_at, type, big_data, data,  dek,  version) 
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	insertStmt = `INSERT INTO records (id, created_at, modified_at, type, big_data, data,  dek,  version, owner, vault, legacy) 
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	updateStmt = `UPDATE records SET big_data = ?, data = ?, dek = ?, version = ?, deleted = ?, corrupted= ?, modified_at = ? WHERE id = ?`
+	updateStmt = `UPDATE records SET big_data = ?, data = ?, dek = ?, version = ?, deleted = ?, corrupted= ?, modified_at = ?, legacy = ? WHERE id = ?`
 
 	updateVersionStmt = `UPDATE records SET version = ? WHERE id = ?`
 
 	deleteStmt                 = `DELETE FROM records WHERE id = ?`
-	getAllRecordGreaterVersion = `SELECT id, created_at, modified_at, type, big_data, data, dek, deleted, version, corrupted FROM records
-	WHERE version > ? AND corrupted = ?`
+	getAllRecordGreaterVersion = `SELECT id, created_at, modified_at, type, big_data, data, dek, deleted, version, corrupted, legacy FROM records
+	WHERE version > ? AND corrupted = ? AND owner = '' AND vault = ''`
+	getVaultRecordGreaterVersion = `SELECT id, created_at, modified_at, type, big_data, data, dek, deleted, version, corrupted, vault FROM records
+	WHERE vault = ? AND version > ? AND corrupted = ?`
+	getVaultRecordsStmt = `SELECT id, created_at, modified_at, type, big_data, data, dek, deleted, version, corrupted, vault FROM records
+	WHERE vault <> ''`
+	getOwnRecordsStmt = `SELECT id, created_at, modified_at, type, big_data, data, dek, deleted, version, corrupted, legacy FROM records
+	WHERE deleted = ? AND corrupted = ? AND owner = '' AND vault = ''`
+	getSharedRecordsStmt = `SELECT id, created_at, modified_at, type, big_data, data, dek, deleted, version, corrupted, owner FROM records
+	WHERE owner <> ''`
 	updateCorruptedStmt = `UPDATE records SET corrupted = ? WHERE id = ?`
+	countGreaterStmt    = `SELECT COUNT(*) FROM records WHERE version > ? AND corrupted = ? AND owner = '' AND vault = ''`
+	deleteVaultStmt     = `DELETE FROM records WHERE vault = ?`
 )
 
 func GetAllRecord(ctx context.Context, db *sql.DB, limit, offset int32) ([]*core.Record, error) {
@@ -46,7 +56,10 @@ func GetAllRecord(ctx context.Context, db *sql.DB, limit, offset int32) ([]*core
 			&r.Dek,
 			&r.Version,
 			&r.Deleted,
-			&r.Corrupted); err != nil {
+			&r.Corrupted,
+			&r.Owner,
+			&r.Vault,
+			&r.Legacy); err != nil {
 			return nil, err
 		}
 		records = append(records, &r)
@@ -71,7 +84,11 @@ func TxGetAllRecord(ctx context.Context, tx *sql.Tx, limit, offset int) ([]*core
 			&r.Data,
 			&r.Dek,
 			&r.Version,
-			&r.Deleted); err != nil {
+			&r.Deleted,
+			&r.Corrupted,
+			&r.Owner,
+			&r.Vault,
+			&r.Legacy); err != nil {
 			return nil, err
 		}
 		records = append(records, &r)
@@ -97,7 +114,109 @@ func TxGetAllRecordGreater(ctx context.Context, tx *sql.Tx, version int32) ([]*c
 			&r.Dek,
 			&r.Deleted,
 			&r.Version,
-			&r.Corrupted); err != nil {
+			&r.Corrupted,
+			&r.Legacy); err != nil {
+			return nil, err
+		}
+		records = append(records, &r)
+	}
+	return records, nil
+}
+
+// TxGetVaultRecordGreater records of the vault changed after version of the vault
+func TxGetVaultRecordGreater(ctx context.Context, tx *sql.Tx, vault string, version int32) ([]*core.Record, error) {
+	return queryVaultRecords(ctx, tx, getVaultRecordGreaterVersion, vault, version, false)
+}
+
+// TxGetVaultRecords records of every vault, they are pushed under the version of their vault
+func TxGetVaultRecords(ctx context.Context, tx *sql.Tx) ([]*core.Record, error) {
+	return queryVaultRecords(ctx, tx, getVaultRecordsStmt)
+}
+
+// TxDeleteVaultRecords delete local copies of records of the vault
+func TxDeleteVaultRecords(ctx context.Context, tx *sql.Tx, vault string) error {
+	if _, err := tx.ExecContext(ctx, deleteVaultStmt, vault); err != nil {
+		return err
+	}
+	return nil
+}
+
+func queryVaultRecords(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]*core.Record, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make([]*core.Record, 0)
+	for rows.Next() {
+		var r core.Record
+		if err = rows.Scan(&r.ID,
+			&r.CreatedAt,
+			&r.ModifiedAt,
+			&r.Type,
+			&r.BigData,
+			&r.Data,
+			&r.Dek,
+			&r.Deleted,
+			&r.Version,
+			&r.Corrupted,
+			&r.Vault); err != nil {
+			return nil, err
+		}
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
+
+// TxGetOwnRecords records of the user which are neither deleted nor corrupted
+func TxGetOwnRecords(ctx context.Context, tx *sql.Tx) ([]*core.Record, error) {
+	rows, err := tx.QueryContext(ctx, getOwnRecordsStmt, false, false)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make([]*core.Record, 0)
+	for rows.Next() {
+		var r core.Record
+		if err = rows.Scan(&r.ID,
+			&r.CreatedAt,
+			&r.ModifiedAt,
+			&r.Type,
+			&r.BigData,
+			&r.Data,
+			&r.Dek,
+			&r.Deleted,
+			&r.Version,
+			&r.Corrupted,
+			&r.Legacy); err != nil {
+			return nil, err
+		}
+		records = append(records, &r)
+	}
+	return records, rows.Err()
+}
+
+// TxGetSharedRecords records shared with the user by others, they are never pushed
+func TxGetSharedRecords(ctx context.Context, tx *sql.Tx) ([]*core.Record, error) {
+	rows, err := tx.QueryContext(ctx, getSharedRecordsStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make([]*core.Record, 0)
+	for rows.Next() {
+		var r core.Record
+		if err = rows.Scan(&r.ID,
+			&r.CreatedAt,
+			&r.ModifiedAt,
+			&r.Type,
+			&r.BigData,
+			&r.Data,
+			&r.Dek,
+			&r.Deleted,
+			&r.Version,
+			&r.Corrupted,
+			&r.Owner); err != nil {
 			return nil, err
 		}
 		records = append(records, &r)
@@ -105,6 +224,15 @@ func TxGetAllRecordGreater(ctx context.Context, tx *sql.Tx, version int32) ([]*c
 	return records, nil
 }
 
+// CountRecordGreater count records changed after version
+func CountRecordGreater(ctx context.Context, db *sql.DB, version int32) (int64, error) {
+	var count int64
+	if err := db.QueryRowContext(ctx, countGreaterStmt, version, false).Scan(&count); err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
 func TxExists(ctx context.Context, db *sql.Tx, id string) (bool, error) {
 	var exists bool
 	if err := db.QueryRowContext(ctx, recordExistsStmt, id).Scan(&exists); err != nil {
@@ -131,7 +259,10 @@ func GetRecordByID(ctx context.Context, db *sql.DB, id string) (*core.Record, er
 		&r.Dek,
 		&r.Version,
 		&r.Deleted,
-		&r.Corrupted); err != nil {
+		&r.Corrupted,
+		&r.Owner,
+		&r.Vault,
+		&r.Legacy); err != nil {
 		return nil, err
 	}
 	return &r, nil
@@ -147,7 +278,10 @@ func TxGetRecordByID(ctx context.Context, tx *sql.Tx, id string) (*core.Record,
 		&r.Dek,
 		&r.Version,
 		&r.Deleted,
-		&r.Corrupted); err != nil {
+		&r.Corrupted,
+		&r.Owner,
+		&r.Vault,
+		&r.Legacy); err != nil {
 		return nil, err
 	}
 	return &r, nil
@@ -163,6 +297,9 @@ func InsertRecord(ctx context.Context, db *sql.DB, record *core.Record) error {
 		record.Data,
 		record.Dek,
 		record.Version,
+		record.Owner,
+		record.Vault,
+		record.Legacy,
 	); err != nil {
 		return err
 	}
@@ -181,6 +318,9 @@ func TxInsertRecord(ctx context.Context, db *sql.Tx, record *core.Record) error
 		record.Data,
 		record.Dek,
 		record.Version,
+		record.Owner,
+		record.Vault,
+		record.Legacy,
 	); err != nil {
 		return err
 	}
@@ -198,6 +338,7 @@ func UpdateRecord(ctx context.Context, db *sql.DB, record *core.Record) error {
 		record.Deleted,
 		record.Corrupted,
 		record.ModifiedAt,
+		record.Legacy,
 		record.ID,
 	); err != nil {
 		return err
@@ -215,6 +356,7 @@ func TxUpdateRecord(ctx context.Context, db *sql.Tx, record *core.Record) error
 		record.Deleted,
 		record.Corrupted,
 		record.ModifiedAt,
+		record.Legacy,
 		record.ID,
 	); err != nil {
 		return err
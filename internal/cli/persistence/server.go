@@ -8,17 +8,44 @@ import (
 )
 
 const (
-	insertServerStmt = `INSERT INTO servers (address, login, password, active) VALUES (?, ?, ?, ?)`
-	getServerStmt    = `SELECT id, address, login, password, active FROM servers WHERE active = $1 LIMIT 1`
+	insertServerStmt = `INSERT INTO servers (address, login, password, active, tls, ca, cert, key, fingerprint)
+						VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	getServerStmt = `SELECT id, address, login, password, active, tls, ca, cert, key, fingerprint
+						FROM servers WHERE active = $1 LIMIT 1`
 
 	updateActiveServerStmt = `UPDATE servers SET active = $1 WHERE id = $2`
+	updateServerTLSStmt    = `UPDATE servers SET tls = ?, ca = ?, cert = ?, key = ? WHERE id = ?`
+	updateFingerprintStmt  = `UPDATE servers SET fingerprint = ? WHERE id = ?`
+	updateServerLoginStmt  = `UPDATE servers SET login = ? WHERE id = ?`
+	deleteServerStmt       = `DELETE FROM servers WHERE id = ?`
+	deleteServerDeviceStmt = `DELETE FROM server_devices WHERE server_id = ?`
+
+	getServerSessionStmt  = `SELECT refresh_token FROM server_sessions WHERE server_id = ?`
+	saveServerSessionStmt = `INSERT INTO server_sessions(server_id, refresh_token) VALUES (?, ?)
+							ON CONFLICT(server_id) DO UPDATE SET refresh_token=excluded.refresh_token`
+	deleteServerSessionStmt = `DELETE FROM server_sessions WHERE server_id = ?`
+	clearServerPasswordStmt = `UPDATE servers SET password = '' WHERE id = ?`
 )
 
-func InsertServer(ctx context.Context, db *sql.DB, address, login, pass string, active bool) error {
-	if _, err := db.ExecContext(ctx, insertServerStmt, address, login, pass, active); err != nil {
-		return err
+func InsertServer(ctx context.Context, db *sql.DB, server *core.Server) (int32, error) {
+	res, err := db.ExecContext(ctx, insertServerStmt,
+		server.Address,
+		server.Login,
+		server.Password,
+		server.Active,
+		server.TLS,
+		server.CA,
+		server.Cert,
+		server.Key,
+		server.Fingerprint)
+	if err != nil {
+		return -1, err
 	}
-	return nil
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+	return int32(id), nil
 }
 
 func GetServer(ctx context.Context, db *sql.DB, active bool) (*core.Server, error) {
@@ -29,6 +56,11 @@ func GetServer(ctx context.Context, db *sql.DB, active bool) (*core.Server, erro
 		&server.Login,
 		&server.Password,
 		&server.Active,
+		&server.TLS,
+		&server.CA,
+		&server.Cert,
+		&server.Key,
+		&server.Fingerprint,
 	); err != nil {
 		return nil, err
 	}
@@ -41,3 +73,66 @@ func UpdateServer(ctx context.Context, db *sql.DB, id int32, active bool) error
 	}
 	return nil
 }
+
+func UpdateServerTLS(ctx context.Context, db *sql.DB, server *core.Server) error {
+	if _, err := db.ExecContext(ctx, updateServerTLSStmt, server.TLS, server.CA, server.Cert, server.Key, server.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateServerFingerprint pin server public key
+func UpdateServerFingerprint(ctx context.Context, db *sql.DB, id int32, fingerprint string) error {
+	if _, err := db.ExecContext(ctx, updateFingerprintStmt, fingerprint, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetServerSession read refresh token of the server session
+func GetServerSession(ctx context.Context, db *sql.DB, serverID int32) (string, error) {
+	var token string
+	if err := db.QueryRowContext(ctx, getServerSessionStmt, serverID).Scan(&token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func TxSaveServerSession(ctx context.Context, tx *sql.Tx, serverID int32, token string) error {
+	if _, err := tx.ExecContext(ctx, saveServerSessionStmt, serverID, token); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TxClearServerPassword forget the password once the session is saved
+func TxClearServerPassword(ctx context.Context, tx *sql.Tx, serverID int32) error {
+	if _, err := tx.ExecContext(ctx, clearServerPasswordStmt, serverID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteServerSession(ctx context.Context, db *sql.DB, serverID int32) error {
+	if _, err := db.ExecContext(ctx, deleteServerSessionStmt, serverID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func UpdateServerLogin(ctx context.Context, db *sql.DB, id int32, login string) error {
+	if _, err := db.ExecContext(ctx, updateServerLoginStmt, login, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TxDeleteServer remove server with its device and session
+func TxDeleteServer(ctx context.Context, tx *sql.Tx, id int32) error {
+	for _, stmt := range []string{deleteServerSessionStmt, deleteServerDeviceStmt, deleteServerStmt} {
+		if _, err := tx.ExecContext(ctx, stmt, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	ClientIDSetting     = "client_id"
+	DaemonStatusSetting = "daemon_status"
+
+	getSettingStmt  = `SELECT value FROM settings WHERE key = ?`
+	saveSettingStmt = `INSERT INTO settings(key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`
+)
+
+func GetSetting(ctx context.Context, db *sql.DB, key string) (string, error) {
+	var value string
+	if err := db.QueryRowContext(ctx, getSettingStmt, key).Scan(&value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func SaveSetting(ctx context.Context, db *sql.DB, key, value string) error {
+	if _, err := db.ExecContext(ctx, saveSettingStmt, key, value); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+)
+
+const (
+	getShareKeyStmt     = `SELECT server_id, public_key, private_key FROM share_keys WHERE server_id = ?`
+	getAllShareKeysStmt = `SELECT server_id, public_key, private_key FROM share_keys`
+	saveShareKeyStmt    = `INSERT INTO share_keys(server_id, public_key, private_key) VALUES (?, ?, ?)
+					ON CONFLICT(server_id) DO UPDATE SET public_key=excluded.public_key, private_key=excluded.private_key`
+)
+
+func GetShareKey(ctx context.Context, db *sql.DB, serverID int32) (*core.ShareKey, error) {
+	var key core.ShareKey
+	if err := db.QueryRowContext(ctx, getShareKeyStmt, serverID).Scan(
+		&key.ServerID,
+		&key.PublicKey,
+		&key.PrivateKey,
+	); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func SaveShareKey(ctx context.Context, db *sql.DB, key *core.ShareKey) error {
+	if _, err := db.ExecContext(ctx, saveShareKeyStmt, key.ServerID, key.PublicKey, key.PrivateKey); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TxGetAllShareKeys(ctx context.Context, tx *sql.Tx) ([]*core.ShareKey, error) {
+	rows, err := tx.QueryContext(ctx, getAllShareKeysStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := make([]*core.ShareKey, 0)
+	for rows.Next() {
+		var key core.ShareKey
+		if err = rows.Scan(&key.ServerID, &key.PublicKey, &key.PrivateKey); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+	return keys, rows.Err()
+}
+
+func TxSaveShareKey(ctx context.Context, tx *sql.Tx, key *core.ShareKey) error {
+	if _, err := tx.ExecContext(ctx, saveShareKeyStmt, key.ServerID, key.PublicKey, key.PrivateKey); err != nil {
+		return err
+	}
+	return nil
+}
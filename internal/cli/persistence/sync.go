@@ -10,6 +10,12 @@ import (
 const (
 	getStateByNameStmt = `SELECT id, value FROM sync_state WHERE id = ?`
 	upsertStateStmt    = `INSERT INTO sync_state(id, value) VALUES (?, ?) ON CONFLICT(id) DO UPDATE SET value=excluded.value`
+	deleteStateStmt    = `DELETE FROM sync_state WHERE id = ?`
+
+	getPullProgressStmt    = `SELECT id, since, version, secret_id, conflict FROM pull_progress WHERE id = ?`
+	upsertPullProgressStmt = `INSERT INTO pull_progress(id, since, version, secret_id, conflict) VALUES (?, ?, ?, ?, ?) 
+	ON CONFLICT(id) DO UPDATE SET since=excluded.since, version=excluded.version, secret_id=excluded.secret_id, conflict=excluded.conflict`
+	deletePullProgressStmt = `DELETE FROM pull_progress WHERE id = ?`
 )
 
 func GetState(ctx context.Context, db *sql.DB, name string) (*core.SyncState, error) {
@@ -34,3 +40,46 @@ func SaveState(ctx context.Context, db *sql.Tx, state *core.SyncState) error {
 	}
 	return nil
 }
+
+func TxDeleteState(ctx context.Context, db *sql.Tx, name string) error {
+	if _, err := db.ExecContext(ctx, deleteStateStmt, name); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetPullProgress(ctx context.Context, db *sql.DB, name string) (*core.PullProgress, error) {
+	var progress core.PullProgress
+	if err := db.QueryRowContext(ctx, getPullProgressStmt, name).Scan(
+		&progress.ID,
+		&progress.Since,
+		&progress.Version,
+		&progress.SecretID,
+		&progress.Conflict,
+	); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func TxSavePullProgress(ctx context.Context, db *sql.Tx, progress *core.PullProgress) error {
+	if _, err := db.ExecContext(
+		ctx,
+		upsertPullProgressStmt,
+		progress.ID,
+		progress.Since,
+		progress.Version,
+		progress.SecretID,
+		progress.Conflict,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TxDeletePullProgress(ctx context.Context, db *sql.Tx, name string) error {
+	if _, err := db.ExecContext(ctx, deletePullProgressStmt, name); err != nil {
+		return err
+	}
+	return nil
+}
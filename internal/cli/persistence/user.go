@@ -10,6 +10,11 @@ import (
 const (
 	getUserByLoginStmt = `SELECT id, username, password, salt FROM users WHERE username = ?`
 	insertUserStmt     = `INSERT INTO users (id, username, password, salt) VALUES (?, ?, ?, ?)`
+	updateUserStmt     = `UPDATE users SET password = ?, salt = ? WHERE id = ?`
+
+	getRecoveryStmt  = `SELECT user_id, key, shares, threshold FROM recovery WHERE user_id = ?`
+	saveRecoveryStmt = `INSERT INTO recovery (user_id, key, shares, threshold) VALUES (?, ?, ?, ?)
+	ON CONFLICT(user_id) DO UPDATE SET key = excluded.key, shares = excluded.shares, threshold = excluded.threshold`
 )
 
 func GetUser(ctx context.Context, db *sql.DB, login string) (*core.User, error) {
@@ -31,3 +36,37 @@ func InsertUser(ctx context.Context, db *sql.DB, user *core.User) error {
 	}
 	return nil
 }
+
+func TxUpdateUser(ctx context.Context, tx *sql.Tx, user *core.User) error {
+	if _, err := tx.ExecContext(ctx, updateUserStmt, user.Password, user.Salt, user.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetRecovery(ctx context.Context, db *sql.DB, userID string) (*core.Recovery, error) {
+	var recovery core.Recovery
+	if err := db.QueryRowContext(ctx, getRecoveryStmt, userID).Scan(
+		&recovery.UserID,
+		&recovery.Key,
+		&recovery.Shares,
+		&recovery.Threshold,
+	); err != nil {
+		return nil, err
+	}
+	return &recovery, nil
+}
+
+func SaveRecovery(ctx context.Context, db *sql.DB, recovery *core.Recovery) error {
+	if _, err := db.ExecContext(ctx, saveRecoveryStmt, recovery.UserID, recovery.Key, recovery.Shares, recovery.Threshold); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TxSaveRecovery(ctx context.Context, tx *sql.Tx, recovery *core.Recovery) error {
+	if _, err := tx.ExecContext(ctx, saveRecoveryStmt, recovery.UserID, recovery.Key, recovery.Shares, recovery.Threshold); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DimKa163/keeper/internal/cli/core"
+)
+
+const (
+	getVaultStmt     = `SELECT id, server_id, name, role, vault_key FROM vaults WHERE id = ?`
+	getVaultsStmt    = `SELECT id, server_id, name, role, vault_key FROM vaults WHERE server_id = ? ORDER BY name`
+	getAllVaultsStmt = `SELECT id, server_id, name, role, vault_key FROM vaults`
+	saveVaultStmt    = `INSERT INTO vaults(id, server_id, name, role, vault_key) VALUES (?, ?, ?, ?, ?)
+					ON CONFLICT(id) DO UPDATE SET name=excluded.name, role=excluded.role, vault_key=excluded.vault_key`
+	deleteVaultByIDStmt = `DELETE FROM vaults WHERE id = ?`
+)
+
+func GetVault(ctx context.Context, db *sql.DB, id string) (*core.Vault, error) {
+	var vault core.Vault
+	if err := db.QueryRowContext(ctx, getVaultStmt, id).Scan(
+		&vault.ID,
+		&vault.ServerID,
+		&vault.Name,
+		&vault.Role,
+		&vault.Key,
+	); err != nil {
+		return nil, err
+	}
+	return &vault, nil
+}
+
+func TxGetVault(ctx context.Context, tx *sql.Tx, id string) (*core.Vault, error) {
+	var vault core.Vault
+	if err := tx.QueryRowContext(ctx, getVaultStmt, id).Scan(
+		&vault.ID,
+		&vault.ServerID,
+		&vault.Name,
+		&vault.Role,
+		&vault.Key,
+	); err != nil {
+		return nil, err
+	}
+	return &vault, nil
+}
+
+// GetVaults vaults of the remote server the user is a member of
+func GetVaults(ctx context.Context, db *sql.DB, serverID int32) ([]*core.Vault, error) {
+	rows, err := db.QueryContext(ctx, getVaultsStmt, serverID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanVaults(rows)
+}
+
+func TxGetAllVaults(ctx context.Context, tx *sql.Tx) ([]*core.Vault, error) {
+	rows, err := tx.QueryContext(ctx, getAllVaultsStmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanVaults(rows)
+}
+
+func TxSaveVault(ctx context.Context, tx *sql.Tx, vault *core.Vault) error {
+	if _, err := tx.ExecContext(ctx, saveVaultStmt, vault.ID, vault.ServerID, vault.Name, vault.Role, vault.Key); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TxDeleteVault(ctx context.Context, tx *sql.Tx, id string) error {
+	if _, err := tx.ExecContext(ctx, deleteVaultByIDStmt, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func scanVaults(rows *sql.Rows) ([]*core.Vault, error) {
+	vaults := make([]*core.Vault, 0)
+	for rows.Next() {
+		var vault core.Vault
+		if err := rows.Scan(&vault.ID, &vault.ServerID, &vault.Name, &vault.Role, &vault.Key); err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, &vault)
+	}
+	return vaults, rows.Err()
+}
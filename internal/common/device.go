@@ -0,0 +1,10 @@
+package common
+
+import (
+	"fmt"
+)
+
+// DeviceChallenge message signed with device key on login
+func DeviceChallenge(login, deviceID string, timestamp int64) []byte {
+	return []byte(fmt.Sprintf("keeper|login|%s|%s|%d", login, deviceID, timestamp))
+}
@@ -12,11 +12,14 @@ import (
 var (
 	ErrMetadataNotFound     = errors.New("metadata not found")
 	ErrClientVersionMissing = errors.New("client version missing")
+	ErrClientIDMissing      = errors.New("client id missing")
 )
 
 const (
 	ClientVERSION = `client_version`
 	FORCE         = `force_update`
+	ClientID      = `client_id`
+	VaultID       = `vault_id`
 )
 
 func WriteClientVersion(ctx context.Context, version int32) context.Context {
@@ -27,6 +30,15 @@ func WriteForce(ctx context.Context, force bool) context.Context {
 	return metadata.AppendToOutgoingContext(ctx, FORCE, strconv.FormatBool(force))
 }
 
+func WriteClientID(ctx context.Context, id string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ClientID, id)
+}
+
+// WriteVault push to the vault instead of own secrets of the user
+func WriteVault(ctx context.Context, id string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, VaultID, id)
+}
+
 func ReadVersionFromHeader(ctx context.Context) (int32, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -56,3 +68,26 @@ func ReadForceFromHeader(ctx context.Context) (bool, error) {
 	}
 	return false, ErrClientVersionMissing
 }
+
+func ReadClientIDFromHeader(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ErrMetadataNotFound
+	}
+	if id, ok := md[ClientID]; ok && id[0] != "" {
+		return id[0], nil
+	}
+	return "", ErrClientIDMissing
+}
+
+// ReadVaultFromHeader vault the push is made to, empty for own secrets of the user
+func ReadVaultFromHeader(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if id, ok := md[VaultID]; ok {
+		return id[0]
+	}
+	return ""
+}
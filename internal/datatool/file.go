@@ -5,9 +5,19 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// FileInfo stored file of the version
+type FileInfo struct {
+	Name       string
+	Version    int32
+	Size       int64
+	ModifiedAt time.Time
+}
+
 type FileProvider struct {
 	Path string
 }
@@ -39,9 +49,67 @@ func (fp *FileProvider) Rename(fileName string, old, new int32) error {
 	return os.Rename(buildPath(fp.Path, fileName, old), buildPath(fp.Path, fileName, new))
 }
 
+func (fp *FileProvider) Stat(fileName string, version int32) (*FileInfo, error) {
+	info, err := os.Stat(buildPath(fp.Path, fileName, version))
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{Name: fileName, Version: version, Size: info.Size(), ModifiedAt: info.ModTime()}, nil
+}
+
+// Walk call fn for every file named as a version of a file, other files are skipped
+func (fp *FileProvider) Walk(fn func(info *FileInfo) error) error {
+	root := fp.Path
+	if root == "" {
+		root = "."
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		name, version, ok := ParseFileName(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if err = fn(&FileInfo{Name: name, Version: version, Size: info.Size(), ModifiedAt: info.ModTime()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseFileName name and version of the file named by FileName without destination
+func ParseFileName(base string) (string, int32, bool) {
+	name, rest, ok := strings.Cut(base, "_")
+	if !ok || name == "" {
+		return "", 0, false
+	}
+	version, err := strconv.ParseInt(rest, 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return name, int32(version), true
+}
+
 func buildPath(root, name string, version int32, dst ...string) string {
+	return filepath.Join(root, FileName(name, version, dst...))
+}
+
+// FileName name the version of the file is stored under
+func FileName(name string, version int32, dst ...string) string {
 	if dst == nil {
-		return filepath.Join(root, fmt.Sprintf("%s_%d", name, version))
+		return fmt.Sprintf("%s_%d", name, version)
 	}
 	var sb strings.Builder
 	for _, d := range dst {
@@ -50,5 +118,5 @@ func buildPath(root, name string, version int32, dst ...string) string {
 		}
 		sb.WriteString(d)
 	}
-	return filepath.Join(root, fmt.Sprintf("%s_%d_%s", name, version, sb.String()))
+	return fmt.Sprintf("%s_%d_%s", name, version, sb.String())
 }
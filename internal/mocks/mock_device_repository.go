@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: I:\Goland\keeper\internal\server\domain\device.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/DimKa163/keeper/internal/server/domain"
+	guid "github.com/beevik/guid"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeviceRepository is a mock of DeviceRepository interface.
+type MockDeviceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceRepositoryMockRecorder
+}
+
+// MockDeviceRepositoryMockRecorder is the mock recorder for MockDeviceRepository.
+type MockDeviceRepositoryMockRecorder struct {
+	mock *MockDeviceRepository
+}
+
+// NewMockDeviceRepository creates a new mock instance.
+func NewMockDeviceRepository(ctrl *gomock.Controller) *MockDeviceRepository {
+	mock := &MockDeviceRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceRepository) EXPECT() *MockDeviceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockDeviceRepository) Approve(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, id, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockDeviceRepositoryMockRecorder) Approve(ctx, id, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockDeviceRepository)(nil).Approve), ctx, id, userID, at)
+}
+
+// Get mocks base method.
+func (m *MockDeviceRepository) Get(ctx context.Context, id guid.Guid) (*domain.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeviceRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeviceRepository)(nil).Get), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockDeviceRepository) GetAll(ctx context.Context, userID guid.Guid) ([]*domain.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeviceRepositoryMockRecorder) GetAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeviceRepository)(nil).GetAll), ctx, userID)
+}
+
+// Insert mocks base method.
+func (m *MockDeviceRepository) Insert(ctx context.Context, device *domain.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockDeviceRepositoryMockRecorder) Insert(ctx, device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockDeviceRepository)(nil).Insert), ctx, device)
+}
+
+// Rename mocks base method.
+func (m *MockDeviceRepository) Rename(ctx context.Context, id, userID guid.Guid, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, id, userID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockDeviceRepositoryMockRecorder) Rename(ctx, id, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockDeviceRepository)(nil).Rename), ctx, id, userID, name)
+}
+
+// Revoke mocks base method.
+func (m *MockDeviceRepository) Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockDeviceRepositoryMockRecorder) Revoke(ctx, id, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockDeviceRepository)(nil).Revoke), ctx, id, userID, at)
+}
+
+// Touch mocks base method.
+func (m *MockDeviceRepository) Touch(ctx context.Context, id guid.Guid, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockDeviceRepositoryMockRecorder) Touch(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockDeviceRepository)(nil).Touch), ctx, id, at)
+}
+
+// UpdateSyncVersion mocks base method.
+func (m *MockDeviceRepository) UpdateSyncVersion(ctx context.Context, id guid.Guid, version int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSyncVersion", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSyncVersion indicates an expected call of UpdateSyncVersion.
+func (mr *MockDeviceRepositoryMockRecorder) UpdateSyncVersion(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSyncVersion", reflect.TypeOf((*MockDeviceRepository)(nil).UpdateSyncVersion), ctx, id, version)
+}
+
+// UpdateVaultSyncVersion mocks base method.
+func (m *MockDeviceRepository) UpdateVaultSyncVersion(ctx context.Context, id, vaultID guid.Guid, version int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVaultSyncVersion", ctx, id, vaultID, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVaultSyncVersion indicates an expected call of UpdateVaultSyncVersion.
+func (mr *MockDeviceRepositoryMockRecorder) UpdateVaultSyncVersion(ctx, id, vaultID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVaultSyncVersion", reflect.TypeOf((*MockDeviceRepository)(nil).UpdateVaultSyncVersion), ctx, id, vaultID, version)
+}
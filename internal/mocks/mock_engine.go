@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	auth "github.com/DimKa163/keeper/internal/server/domain/auth"
@@ -36,18 +37,18 @@ func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
 }
 
 // GenerateToken mocks base method.
-func (m *MockEngine) GenerateToken(userID guid.Guid) (string, error) {
+func (m *MockEngine) GenerateToken(userID guid.Guid, deviceID, sessionID string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", userID)
+	ret := m.ctrl.Call(m, "GenerateToken", userID, deviceID, sessionID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockEngineMockRecorder) GenerateToken(userID interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) GenerateToken(userID, deviceID, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockEngine)(nil).GenerateToken), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockEngine)(nil).GenerateToken), userID, deviceID, sessionID)
 }
 
 // ReadToken mocks base method.
@@ -64,3 +65,77 @@ func (mr *MockEngineMockRecorder) ReadToken(tokenString interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadToken", reflect.TypeOf((*MockEngine)(nil).ReadToken), tokenString)
 }
+
+// MockDeviceVerifier is a mock of DeviceVerifier interface.
+type MockDeviceVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceVerifierMockRecorder
+}
+
+// MockDeviceVerifierMockRecorder is the mock recorder for MockDeviceVerifier.
+type MockDeviceVerifierMockRecorder struct {
+	mock *MockDeviceVerifier
+}
+
+// NewMockDeviceVerifier creates a new mock instance.
+func NewMockDeviceVerifier(ctrl *gomock.Controller) *MockDeviceVerifier {
+	mock := &MockDeviceVerifier{ctrl: ctrl}
+	mock.recorder = &MockDeviceVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceVerifier) EXPECT() *MockDeviceVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyDevice mocks base method.
+func (m *MockDeviceVerifier) VerifyDevice(ctx context.Context, userID, deviceID guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyDevice", ctx, userID, deviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyDevice indicates an expected call of VerifyDevice.
+func (mr *MockDeviceVerifierMockRecorder) VerifyDevice(ctx, userID, deviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyDevice", reflect.TypeOf((*MockDeviceVerifier)(nil).VerifyDevice), ctx, userID, deviceID)
+}
+
+// MockSessionVerifier is a mock of SessionVerifier interface.
+type MockSessionVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionVerifierMockRecorder
+}
+
+// MockSessionVerifierMockRecorder is the mock recorder for MockSessionVerifier.
+type MockSessionVerifierMockRecorder struct {
+	mock *MockSessionVerifier
+}
+
+// NewMockSessionVerifier creates a new mock instance.
+func NewMockSessionVerifier(ctrl *gomock.Controller) *MockSessionVerifier {
+	mock := &MockSessionVerifier{ctrl: ctrl}
+	mock.recorder = &MockSessionVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionVerifier) EXPECT() *MockSessionVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifySession mocks base method.
+func (m *MockSessionVerifier) VerifySession(ctx context.Context, userID, sessionID guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifySession indicates an expected call of VerifySession.
+func (mr *MockSessionVerifierMockRecorder) VerifySession(ctx, userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySession", reflect.TypeOf((*MockSessionVerifier)(nil).VerifySession), ctx, userID, sessionID)
+}
@@ -8,9 +8,76 @@ import (
 	io "io"
 	reflect "reflect"
 
+	domain "github.com/DimKa163/keeper/internal/server/domain"
 	gomock "github.com/golang/mock/gomock"
 )
 
+// MockBlobWriter is a mock of BlobWriter interface.
+type MockBlobWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobWriterMockRecorder
+}
+
+// MockBlobWriterMockRecorder is the mock recorder for MockBlobWriter.
+type MockBlobWriterMockRecorder struct {
+	mock *MockBlobWriter
+}
+
+// NewMockBlobWriter creates a new mock instance.
+func NewMockBlobWriter(ctrl *gomock.Controller) *MockBlobWriter {
+	mock := &MockBlobWriter{ctrl: ctrl}
+	mock.recorder = &MockBlobWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobWriter) EXPECT() *MockBlobWriterMockRecorder {
+	return m.recorder
+}
+
+// Abort mocks base method.
+func (m *MockBlobWriter) Abort() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abort")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Abort indicates an expected call of Abort.
+func (mr *MockBlobWriterMockRecorder) Abort() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockBlobWriter)(nil).Abort))
+}
+
+// Close mocks base method.
+func (m *MockBlobWriter) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockBlobWriterMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBlobWriter)(nil).Close))
+}
+
+// Write mocks base method.
+func (m *MockBlobWriter) Write(p []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", p)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockBlobWriterMockRecorder) Write(p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockBlobWriter)(nil).Write), p)
+}
+
 // MockFiler is a mock of Filer interface.
 type MockFiler struct {
 	ctrl     *gomock.Controller
@@ -55,14 +122,14 @@ func (mr *MockFilerMockRecorder) OpenRead(fileName, version interface{}, dst ...
 }
 
 // OpenWrite mocks base method.
-func (m *MockFiler) OpenWrite(fileName string, version int32, dst ...string) (io.WriteCloser, error) {
+func (m *MockFiler) OpenWrite(fileName string, version int32, dst ...string) (domain.BlobWriter, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{fileName, version}
 	for _, a := range dst {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "OpenWrite", varargs...)
-	ret0, _ := ret[0].(io.WriteCloser)
+	ret0, _ := ret[0].(domain.BlobWriter)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -92,3 +159,32 @@ func (mr *MockFilerMockRecorder) Remove(fileName, version interface{}, dst ...in
 	varargs := append([]interface{}{fileName, version}, dst...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockFiler)(nil).Remove), varargs...)
 }
+
+// Stat mocks base method.
+func (m *MockFiler) Stat(fileName string, version int32) (*domain.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", fileName, version)
+	ret0, _ := ret[0].(*domain.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockFilerMockRecorder) Stat(fileName, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockFiler)(nil).Stat), fileName, version)
+}
+
+// Walk mocks base method.
+func (m *MockFiler) Walk(fn func(*domain.Blob) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Walk", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Walk indicates an expected call of Walk.
+func (mr *MockFilerMockRecorder) Walk(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Walk", reflect.TypeOf((*MockFiler)(nil).Walk), fn)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/DimKa163/keeper/internal/server/domain"
 	guid "github.com/beevik/guid"
@@ -50,6 +51,21 @@ func (mr *MockSecretRepositoryMockRecorder) Delete(ctx, data interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSecretRepository)(nil).Delete), ctx, data)
 }
 
+// DeleteAll mocks base method.
+func (m *MockSecretRepository) DeleteAll(ctx context.Context, userID guid.Guid) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, userID)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockSecretRepositoryMockRecorder) DeleteAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockSecretRepository)(nil).DeleteAll), ctx, userID)
+}
+
 // Get mocks base method.
 func (m *MockSecretRepository) Get(ctx context.Context, id guid.Guid) (*domain.Secret, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +96,81 @@ func (mr *MockSecretRepositoryMockRecorder) GetAll(ctx, userID, greaterThan inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSecretRepository)(nil).GetAll), ctx, userID, greaterThan)
 }
 
+// GetBigData mocks base method.
+func (m *MockSecretRepository) GetBigData(ctx context.Context) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBigData", ctx)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBigData indicates an expected call of GetBigData.
+func (mr *MockSecretRepositoryMockRecorder) GetBigData(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBigData", reflect.TypeOf((*MockSecretRepository)(nil).GetBigData), ctx)
+}
+
+// GetPage mocks base method.
+func (m *MockSecretRepository) GetPage(ctx context.Context, userID guid.Guid, after domain.SecretCursor, until, limit int32) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPage", ctx, userID, after, until, limit)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPage indicates an expected call of GetPage.
+func (mr *MockSecretRepositoryMockRecorder) GetPage(ctx, userID, after, until, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPage", reflect.TypeOf((*MockSecretRepository)(nil).GetPage), ctx, userID, after, until, limit)
+}
+
+// GetPurgeable mocks base method.
+func (m *MockSecretRepository) GetPurgeable(ctx context.Context, before time.Time) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeable", ctx, before)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurgeable indicates an expected call of GetPurgeable.
+func (mr *MockSecretRepositoryMockRecorder) GetPurgeable(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeable", reflect.TypeOf((*MockSecretRepository)(nil).GetPurgeable), ctx, before)
+}
+
+// GetVaultAll mocks base method.
+func (m *MockSecretRepository) GetVaultAll(ctx context.Context, vaultID guid.Guid, greaterThan int32) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultAll", ctx, vaultID, greaterThan)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultAll indicates an expected call of GetVaultAll.
+func (mr *MockSecretRepositoryMockRecorder) GetVaultAll(ctx, vaultID, greaterThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultAll", reflect.TypeOf((*MockSecretRepository)(nil).GetVaultAll), ctx, vaultID, greaterThan)
+}
+
+// GetVaultPage mocks base method.
+func (m *MockSecretRepository) GetVaultPage(ctx context.Context, vaultID guid.Guid, after domain.SecretCursor, until, limit int32) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultPage", ctx, vaultID, after, until, limit)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultPage indicates an expected call of GetVaultPage.
+func (mr *MockSecretRepositoryMockRecorder) GetVaultPage(ctx, vaultID, after, until, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultPage", reflect.TypeOf((*MockSecretRepository)(nil).GetVaultPage), ctx, vaultID, after, until, limit)
+}
+
 // Insert mocks base method.
 func (m *MockSecretRepository) Insert(ctx context.Context, data *domain.Secret) error {
 	m.ctrl.T.Helper()
@@ -94,6 +185,36 @@ func (mr *MockSecretRepositoryMockRecorder) Insert(ctx, data interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockSecretRepository)(nil).Insert), ctx, data)
 }
 
+// PurgeDeleted mocks base method.
+func (m *MockSecretRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]*domain.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].([]*domain.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockSecretRepositoryMockRecorder) PurgeDeleted(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockSecretRepository)(nil).PurgeDeleted), ctx, before)
+}
+
+// Stats mocks base method.
+func (m *MockSecretRepository) Stats(ctx context.Context) ([]*domain.SecretStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].([]*domain.SecretStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockSecretRepositoryMockRecorder) Stats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockSecretRepository)(nil).Stats), ctx)
+}
+
 // Update mocks base method.
 func (m *MockSecretRepository) Update(ctx context.Context, data *domain.Secret) error {
 	m.ctrl.T.Helper()
@@ -107,3 +228,18 @@ func (mr *MockSecretRepositoryMockRecorder) Update(ctx, data interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSecretRepository)(nil).Update), ctx, data)
 }
+
+// Usage mocks base method.
+func (m *MockSecretRepository) Usage(ctx context.Context, userID guid.Guid) (*domain.SecretUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, userID)
+	ret0, _ := ret[0].(*domain.SecretUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockSecretRepositoryMockRecorder) Usage(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockSecretRepository)(nil).Usage), ctx, userID)
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: I:\Goland\keeper\internal\server\domain\share.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/DimKa163/keeper/internal/server/domain"
+	guid "github.com/beevik/guid"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockShareRepository is a mock of ShareRepository interface.
+type MockShareRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShareRepositoryMockRecorder
+}
+
+// MockShareRepositoryMockRecorder is the mock recorder for MockShareRepository.
+type MockShareRepositoryMockRecorder struct {
+	mock *MockShareRepository
+}
+
+// NewMockShareRepository creates a new mock instance.
+func NewMockShareRepository(ctrl *gomock.Controller) *MockShareRepository {
+	mock := &MockShareRepository{ctrl: ctrl}
+	mock.recorder = &MockShareRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShareRepository) EXPECT() *MockShareRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockShareRepository) Get(ctx context.Context, secretID, recipientID guid.Guid) (*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, secretID, recipientID)
+	ret0, _ := ret[0].(*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockShareRepositoryMockRecorder) Get(ctx, secretID, recipientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockShareRepository)(nil).Get), ctx, secretID, recipientID)
+}
+
+// GetByOwner mocks base method.
+func (m *MockShareRepository) GetByOwner(ctx context.Context, ownerID guid.Guid) ([]*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOwner", ctx, ownerID)
+	ret0, _ := ret[0].([]*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOwner indicates an expected call of GetByOwner.
+func (mr *MockShareRepositoryMockRecorder) GetByOwner(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOwner", reflect.TypeOf((*MockShareRepository)(nil).GetByOwner), ctx, ownerID)
+}
+
+// GetBySecret mocks base method.
+func (m *MockShareRepository) GetBySecret(ctx context.Context, secretID guid.Guid) ([]*domain.Share, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySecret", ctx, secretID)
+	ret0, _ := ret[0].([]*domain.Share)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySecret indicates an expected call of GetBySecret.
+func (mr *MockShareRepositoryMockRecorder) GetBySecret(ctx, secretID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySecret", reflect.TypeOf((*MockShareRepository)(nil).GetBySecret), ctx, secretID)
+}
+
+// Revoke mocks base method.
+func (m *MockShareRepository) Revoke(ctx context.Context, secretID, recipientID guid.Guid, version int32, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, secretID, recipientID, version, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockShareRepositoryMockRecorder) Revoke(ctx, secretID, recipientID, version, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockShareRepository)(nil).Revoke), ctx, secretID, recipientID, version, at)
+}
+
+// Save mocks base method.
+func (m *MockShareRepository) Save(ctx context.Context, share *domain.Share) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockShareRepositoryMockRecorder) Save(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockShareRepository)(nil).Save), ctx, share)
+}
+
+// Update mocks base method.
+func (m *MockShareRepository) Update(ctx context.Context, share *domain.Share) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, share)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockShareRepositoryMockRecorder) Update(ctx, share interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockShareRepository)(nil).Update), ctx, share)
+}
@@ -36,6 +36,20 @@ func (m *MockSyncStateRepository) EXPECT() *MockSyncStateRepositoryMockRecorder
 	return m.recorder
 }
 
+// DeleteAll mocks base method.
+func (m *MockSyncStateRepository) DeleteAll(ctx context.Context, userID guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockSyncStateRepositoryMockRecorder) DeleteAll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockSyncStateRepository)(nil).DeleteAll), ctx, userID)
+}
+
 // Get mocks base method.
 func (m *MockSyncStateRepository) Get(ctx context.Context, id string, user guid.Guid) (*domain.SyncState, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +65,21 @@ func (mr *MockSyncStateRepositoryMockRecorder) Get(ctx, id, user interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSyncStateRepository)(nil).Get), ctx, id, user)
 }
 
+// GetVault mocks base method.
+func (m *MockSyncStateRepository) GetVault(ctx context.Context, vaultID guid.Guid) (*domain.SyncState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVault", ctx, vaultID)
+	ret0, _ := ret[0].(*domain.SyncState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVault indicates an expected call of GetVault.
+func (mr *MockSyncStateRepositoryMockRecorder) GetVault(ctx, vaultID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVault", reflect.TypeOf((*MockSyncStateRepository)(nil).GetVault), ctx, vaultID)
+}
+
 // Insert mocks base method.
 func (m *MockSyncStateRepository) Insert(ctx context.Context, state *domain.SyncState) error {
 	m.ctrl.T.Helper()
@@ -35,6 +35,34 @@ func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
 	return m.recorder
 }
 
+// AuditRepository mocks base method.
+func (m *MockUnitOfWork) AuditRepository() domain.AuditRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditRepository")
+	ret0, _ := ret[0].(domain.AuditRepository)
+	return ret0
+}
+
+// AuditRepository indicates an expected call of AuditRepository.
+func (mr *MockUnitOfWorkMockRecorder) AuditRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditRepository", reflect.TypeOf((*MockUnitOfWork)(nil).AuditRepository))
+}
+
+// DeviceRepository mocks base method.
+func (m *MockUnitOfWork) DeviceRepository() domain.DeviceRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeviceRepository")
+	ret0, _ := ret[0].(domain.DeviceRepository)
+	return ret0
+}
+
+// DeviceRepository indicates an expected call of DeviceRepository.
+func (mr *MockUnitOfWorkMockRecorder) DeviceRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeviceRepository", reflect.TypeOf((*MockUnitOfWork)(nil).DeviceRepository))
+}
+
 // SecretRepository mocks base method.
 func (m *MockUnitOfWork) SecretRepository() domain.SecretRepository {
 	m.ctrl.T.Helper()
@@ -49,6 +77,34 @@ func (mr *MockUnitOfWorkMockRecorder) SecretRepository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecretRepository", reflect.TypeOf((*MockUnitOfWork)(nil).SecretRepository))
 }
 
+// SessionRepository mocks base method.
+func (m *MockUnitOfWork) SessionRepository() domain.SessionRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionRepository")
+	ret0, _ := ret[0].(domain.SessionRepository)
+	return ret0
+}
+
+// SessionRepository indicates an expected call of SessionRepository.
+func (mr *MockUnitOfWorkMockRecorder) SessionRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionRepository", reflect.TypeOf((*MockUnitOfWork)(nil).SessionRepository))
+}
+
+// ShareRepository mocks base method.
+func (m *MockUnitOfWork) ShareRepository() domain.ShareRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShareRepository")
+	ret0, _ := ret[0].(domain.ShareRepository)
+	return ret0
+}
+
+// ShareRepository indicates an expected call of ShareRepository.
+func (mr *MockUnitOfWorkMockRecorder) ShareRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShareRepository", reflect.TypeOf((*MockUnitOfWork)(nil).ShareRepository))
+}
+
 // SyncStateRepository mocks base method.
 func (m *MockUnitOfWork) SyncStateRepository() domain.SyncStateRepository {
 	m.ctrl.T.Helper()
@@ -90,3 +146,17 @@ func (mr *MockUnitOfWorkMockRecorder) UserRepository() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserRepository", reflect.TypeOf((*MockUnitOfWork)(nil).UserRepository))
 }
+
+// VaultRepository mocks base method.
+func (m *MockUnitOfWork) VaultRepository() domain.VaultRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VaultRepository")
+	ret0, _ := ret[0].(domain.VaultRepository)
+	return ret0
+}
+
+// VaultRepository indicates an expected call of VaultRepository.
+func (mr *MockUnitOfWorkMockRecorder) VaultRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VaultRepository", reflect.TypeOf((*MockUnitOfWork)(nil).VaultRepository))
+}
@@ -7,8 +7,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/DimKa163/keeper/internal/server/domain"
+	guid "github.com/beevik/guid"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,6 +37,20 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockUserRepository) Delete(ctx context.Context, id guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), ctx, id)
+}
+
 // Exist mocks base method.
 func (m *MockUserRepository) Exist(ctx context.Context, login string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +81,36 @@ func (mr *MockUserRepositoryMockRecorder) Get(ctx, login interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserRepository)(nil).Get), ctx, login)
 }
 
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, id guid.Guid) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, id)
+}
+
+// GetRecovery mocks base method.
+func (m *MockUserRepository) GetRecovery(ctx context.Context, id guid.Guid) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecovery", ctx, id)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecovery indicates an expected call of GetRecovery.
+func (mr *MockUserRepositoryMockRecorder) GetRecovery(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecovery", reflect.TypeOf((*MockUserRepository)(nil).GetRecovery), ctx, id)
+}
+
 // Insert mocks base method.
 func (m *MockUserRepository) Insert(ctx context.Context, user *domain.User) error {
 	m.ctrl.T.Helper()
@@ -79,6 +125,143 @@ func (mr *MockUserRepositoryMockRecorder) Insert(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockUserRepository)(nil).Insert), ctx, user)
 }
 
+// List mocks base method.
+func (m *MockUserRepository) List(ctx context.Context) ([]*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx)
+}
+
+// SetDisabled mocks base method.
+func (m *MockUserRepository) SetDisabled(ctx context.Context, id guid.Guid, at *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockUserRepositoryMockRecorder) SetDisabled(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockUserRepository)(nil).SetDisabled), ctx, id, at)
+}
+
+// UpdateLogin mocks base method.
+func (m *MockUserRepository) UpdateLogin(ctx context.Context, id guid.Guid, login string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLogin", ctx, id, login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLogin indicates an expected call of UpdateLogin.
+func (mr *MockUserRepositoryMockRecorder) UpdateLogin(ctx, id, login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogin", reflect.TypeOf((*MockUserRepository)(nil).UpdateLogin), ctx, id, login)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id guid.Guid, password, salt []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, id, password, salt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, id, password, salt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, id, password, salt)
+}
+
+// UpdatePublicKey mocks base method.
+func (m *MockUserRepository) UpdatePublicKey(ctx context.Context, id guid.Guid, publicKey []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePublicKey", ctx, id, publicKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePublicKey indicates an expected call of UpdatePublicKey.
+func (mr *MockUserRepositoryMockRecorder) UpdatePublicKey(ctx, id, publicKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePublicKey", reflect.TypeOf((*MockUserRepository)(nil).UpdatePublicKey), ctx, id, publicKey)
+}
+
+// UpdateRecovery mocks base method.
+func (m *MockUserRepository) UpdateRecovery(ctx context.Context, id guid.Guid, recovery []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecovery", ctx, id, recovery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecovery indicates an expected call of UpdateRecovery.
+func (mr *MockUserRepositoryMockRecorder) UpdateRecovery(ctx, id, recovery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecovery", reflect.TypeOf((*MockUserRepository)(nil).UpdateRecovery), ctx, id, recovery)
+}
+
+// UpdateVerifier mocks base method.
+func (m *MockUserRepository) UpdateVerifier(ctx context.Context, id guid.Guid, salt, verifier []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVerifier", ctx, id, salt, verifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVerifier indicates an expected call of UpdateVerifier.
+func (mr *MockUserRepositoryMockRecorder) UpdateVerifier(ctx, id, salt, verifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerifier", reflect.TypeOf((*MockUserRepository)(nil).UpdateVerifier), ctx, id, salt, verifier)
+}
+
+// MockAuthenticator is a mock of Authenticator interface.
+type MockAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorMockRecorder
+}
+
+// MockAuthenticatorMockRecorder is the mock recorder for MockAuthenticator.
+type MockAuthenticatorMockRecorder struct {
+	mock *MockAuthenticator
+}
+
+// NewMockAuthenticator creates a new mock instance.
+func NewMockAuthenticator(ctrl *gomock.Controller) *MockAuthenticator {
+	mock := &MockAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthenticator) Authenticate(ctx context.Context, login string, creds *domain.Credentials) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, login, creds)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthenticatorMockRecorder) Authenticate(ctx, login, creds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthenticator)(nil).Authenticate), ctx, login, creds)
+}
+
 // MockUserService is a mock of UserService interface.
 type MockUserService struct {
 	ctrl     *gomock.Controller
@@ -102,26 +285,129 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 	return m.recorder
 }
 
+// Authenticate mocks base method.
+func (m *MockUserService) Authenticate(ctx context.Context, login string, creds *domain.Credentials) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, login, creds)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockUserServiceMockRecorder) Authenticate(ctx, login, creds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockUserService)(nil).Authenticate), ctx, login, creds)
+}
+
+// BeginLogin mocks base method.
+func (m *MockUserService) BeginLogin(ctx context.Context, login string, publicKey []byte) (*domain.Handshake, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx, login, publicKey)
+	ret0, _ := ret[0].(*domain.Handshake)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockUserServiceMockRecorder) BeginLogin(ctx, login, publicKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockUserService)(nil).BeginLogin), ctx, login, publicKey)
+}
+
+// ChangeLogin mocks base method.
+func (m *MockUserService) ChangeLogin(ctx context.Context, creds *domain.Credentials, newLogin string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeLogin", ctx, creds, newLogin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeLogin indicates an expected call of ChangeLogin.
+func (mr *MockUserServiceMockRecorder) ChangeLogin(ctx, creds, newLogin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeLogin", reflect.TypeOf((*MockUserService)(nil).ChangeLogin), ctx, creds, newLogin)
+}
+
+// ChangePassword mocks base method.
+func (m *MockUserService) ChangePassword(ctx context.Context, creds *domain.Credentials, verifier *domain.Verifier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, creds, verifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserServiceMockRecorder) ChangePassword(ctx, creds, verifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserService)(nil).ChangePassword), ctx, creds, verifier)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockUserService) DeleteAccount(ctx context.Context, creds *domain.Credentials) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, creds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockUserServiceMockRecorder) DeleteAccount(ctx, creds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockUserService)(nil).DeleteAccount), ctx, creds)
+}
+
+// FinishLogin mocks base method.
+func (m *MockUserService) FinishLogin(ctx context.Context, login string, creds *domain.Credentials, proof *domain.DeviceProof) (*domain.Tokens, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", ctx, login, creds, proof)
+	ret0, _ := ret[0].(*domain.Tokens)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockUserServiceMockRecorder) FinishLogin(ctx, login, creds, proof interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockUserService)(nil).FinishLogin), ctx, login, creds, proof)
+}
+
+// GetRecovery mocks base method.
+func (m *MockUserService) GetRecovery(ctx context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecovery", ctx)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecovery indicates an expected call of GetRecovery.
+func (mr *MockUserServiceMockRecorder) GetRecovery(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecovery", reflect.TypeOf((*MockUserService)(nil).GetRecovery), ctx)
+}
+
 // Login mocks base method.
-func (m *MockUserService) Login(ctx context.Context, login, password string) (string, error) {
+func (m *MockUserService) Login(ctx context.Context, login, password string, proof *domain.DeviceProof, upgrade *domain.Verifier) (*domain.Tokens, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, login, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", ctx, login, password, proof, upgrade)
+	ret0, _ := ret[0].(*domain.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserServiceMockRecorder) Login(ctx, login, password interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) Login(ctx, login, password, proof, upgrade interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, login, password, proof, upgrade)
 }
 
 // Register mocks base method.
-func (m *MockUserService) Register(ctx context.Context, login, password string) (string, error) {
+func (m *MockUserService) Register(ctx context.Context, login, password string) (*domain.Tokens, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Register", ctx, login, password)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*domain.Tokens)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -131,3 +417,32 @@ func (mr *MockUserServiceMockRecorder) Register(ctx, login, password interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), ctx, login, password)
 }
+
+// RegisterVerifier mocks base method.
+func (m *MockUserService) RegisterVerifier(ctx context.Context, login string, verifier *domain.Verifier) (*domain.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterVerifier", ctx, login, verifier)
+	ret0, _ := ret[0].(*domain.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterVerifier indicates an expected call of RegisterVerifier.
+func (mr *MockUserServiceMockRecorder) RegisterVerifier(ctx, login, verifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterVerifier", reflect.TypeOf((*MockUserService)(nil).RegisterVerifier), ctx, login, verifier)
+}
+
+// SetRecovery mocks base method.
+func (m *MockUserService) SetRecovery(ctx context.Context, recovery []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRecovery", ctx, recovery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRecovery indicates an expected call of SetRecovery.
+func (mr *MockUserServiceMockRecorder) SetRecovery(ctx, recovery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecovery", reflect.TypeOf((*MockUserService)(nil).SetRecovery), ctx, recovery)
+}
@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: I:\Goland\keeper\internal\server\domain\vault.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/DimKa163/keeper/internal/server/domain"
+	guid "github.com/beevik/guid"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVaultRepository is a mock of VaultRepository interface.
+type MockVaultRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVaultRepositoryMockRecorder
+}
+
+// MockVaultRepositoryMockRecorder is the mock recorder for MockVaultRepository.
+type MockVaultRepositoryMockRecorder struct {
+	mock *MockVaultRepository
+}
+
+// NewMockVaultRepository creates a new mock instance.
+func NewMockVaultRepository(ctrl *gomock.Controller) *MockVaultRepository {
+	mock := &MockVaultRepository{ctrl: ctrl}
+	mock.recorder = &MockVaultRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVaultRepository) EXPECT() *MockVaultRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockVaultRepository) Delete(ctx context.Context, vaultID guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, vaultID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockVaultRepositoryMockRecorder) Delete(ctx, vaultID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVaultRepository)(nil).Delete), ctx, vaultID)
+}
+
+// DeleteMember mocks base method.
+func (m *MockVaultRepository) DeleteMember(ctx context.Context, vaultID, userID guid.Guid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", ctx, vaultID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockVaultRepositoryMockRecorder) DeleteMember(ctx, vaultID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockVaultRepository)(nil).DeleteMember), ctx, vaultID, userID)
+}
+
+// GetByUser mocks base method.
+func (m *MockVaultRepository) GetByUser(ctx context.Context, userID guid.Guid) ([]*domain.VaultMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", ctx, userID)
+	ret0, _ := ret[0].([]*domain.VaultMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockVaultRepositoryMockRecorder) GetByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockVaultRepository)(nil).GetByUser), ctx, userID)
+}
+
+// GetMember mocks base method.
+func (m *MockVaultRepository) GetMember(ctx context.Context, vaultID, userID guid.Guid) (*domain.VaultMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMember", ctx, vaultID, userID)
+	ret0, _ := ret[0].(*domain.VaultMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMember indicates an expected call of GetMember.
+func (mr *MockVaultRepositoryMockRecorder) GetMember(ctx, vaultID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMember", reflect.TypeOf((*MockVaultRepository)(nil).GetMember), ctx, vaultID, userID)
+}
+
+// GetMembers mocks base method.
+func (m *MockVaultRepository) GetMembers(ctx context.Context, vaultID guid.Guid) ([]*domain.VaultMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, vaultID)
+	ret0, _ := ret[0].([]*domain.VaultMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockVaultRepositoryMockRecorder) GetMembers(ctx, vaultID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockVaultRepository)(nil).GetMembers), ctx, vaultID)
+}
+
+// Insert mocks base method.
+func (m *MockVaultRepository) Insert(ctx context.Context, vault *domain.Vault) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, vault)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockVaultRepositoryMockRecorder) Insert(ctx, vault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockVaultRepository)(nil).Insert), ctx, vault)
+}
+
+// SaveMember mocks base method.
+func (m *MockVaultRepository) SaveMember(ctx context.Context, member *domain.VaultMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMember indicates an expected call of SaveMember.
+func (mr *MockVaultRepositoryMockRecorder) SaveMember(ctx, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMember", reflect.TypeOf((*MockVaultRepository)(nil).SaveMember), ctx, member)
+}
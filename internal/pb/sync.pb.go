@@ -171,6 +171,10 @@ type Secret struct {
 	xxx_hidden_Deleted     bool                   `protobuf:"varint,6,opt,name=deleted"`
 	xxx_hidden_Dek         []byte                 `protobuf:"bytes,7,opt,name=dek"`
 	xxx_hidden_Data        []byte                 `protobuf:"bytes,8,opt,name=data"`
+	xxx_hidden_Owner       *string                `protobuf:"bytes,9,opt,name=owner"`
+	xxx_hidden_DataVersion int32                  `protobuf:"varint,10,opt,name=data_version,json=dataVersion"`
+	xxx_hidden_Shares      *[]*ShareKey           `protobuf:"bytes,11,rep,name=shares"`
+	xxx_hidden_VaultId     *string                `protobuf:"bytes,12,opt,name=vault_id,json=vaultId"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
@@ -263,9 +267,45 @@ func (x *Secret) GetData() []byte {
 	return nil
 }
 
+func (x *Secret) GetOwner() string {
+	if x != nil {
+		if x.xxx_hidden_Owner != nil {
+			return *x.xxx_hidden_Owner
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *Secret) GetDataVersion() int32 {
+	if x != nil {
+		return x.xxx_hidden_DataVersion
+	}
+	return 0
+}
+
+func (x *Secret) GetShares() []*ShareKey {
+	if x != nil {
+		if x.xxx_hidden_Shares != nil {
+			return *x.xxx_hidden_Shares
+		}
+	}
+	return nil
+}
+
+func (x *Secret) GetVaultId() string {
+	if x != nil {
+		if x.xxx_hidden_VaultId != nil {
+			return *x.xxx_hidden_VaultId
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *Secret) SetId(v string) {
 	x.xxx_hidden_Id = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 12)
 }
 
 func (x *Secret) SetModifiedAt(v *timestamppb.Timestamp) {
@@ -274,22 +314,22 @@ func (x *Secret) SetModifiedAt(v *timestamppb.Timestamp) {
 
 func (x *Secret) SetType(v SecretType) {
 	x.xxx_hidden_Type = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 12)
 }
 
 func (x *Secret) SetIsBig(v bool) {
 	x.xxx_hidden_IsBig = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 12)
 }
 
 func (x *Secret) SetVersion(v int32) {
 	x.xxx_hidden_Version = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 12)
 }
 
 func (x *Secret) SetDeleted(v bool) {
 	x.xxx_hidden_Deleted = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 12)
 }
 
 func (x *Secret) SetDek(v []byte) {
@@ -297,7 +337,7 @@ func (x *Secret) SetDek(v []byte) {
 		v = []byte{}
 	}
 	x.xxx_hidden_Dek = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 12)
 }
 
 func (x *Secret) SetData(v []byte) {
@@ -305,7 +345,26 @@ func (x *Secret) SetData(v []byte) {
 		v = []byte{}
 	}
 	x.xxx_hidden_Data = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 8)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 12)
+}
+
+func (x *Secret) SetOwner(v string) {
+	x.xxx_hidden_Owner = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 8, 12)
+}
+
+func (x *Secret) SetDataVersion(v int32) {
+	x.xxx_hidden_DataVersion = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 9, 12)
+}
+
+func (x *Secret) SetShares(v []*ShareKey) {
+	x.xxx_hidden_Shares = &v
+}
+
+func (x *Secret) SetVaultId(v string) {
+	x.xxx_hidden_VaultId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 11, 12)
 }
 
 func (x *Secret) HasId() bool {
@@ -364,6 +423,27 @@ func (x *Secret) HasData() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
 }
 
+func (x *Secret) HasOwner() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 8)
+}
+
+func (x *Secret) HasDataVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 9)
+}
+
+func (x *Secret) HasVaultId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 11)
+}
+
 func (x *Secret) ClearId() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Id = nil
@@ -403,17 +483,36 @@ func (x *Secret) ClearData() {
 	x.xxx_hidden_Data = nil
 }
 
+func (x *Secret) ClearOwner() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 8)
+	x.xxx_hidden_Owner = nil
+}
+
+func (x *Secret) ClearDataVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 9)
+	x.xxx_hidden_DataVersion = 0
+}
+
+func (x *Secret) ClearVaultId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 11)
+	x.xxx_hidden_VaultId = nil
+}
+
 type Secret_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Id         *string
-	ModifiedAt *timestamppb.Timestamp
-	Type       *SecretType
-	IsBig      *bool
-	Version    *int32
-	Deleted    *bool
-	Dek        []byte
-	Data       []byte
+	Id          *string
+	ModifiedAt  *timestamppb.Timestamp
+	Type        *SecretType
+	IsBig       *bool
+	Version     *int32
+	Deleted     *bool
+	Dek         []byte
+	Data        []byte
+	Owner       *string
+	DataVersion *int32
+	Shares      []*ShareKey
+	VaultId     *string
 }
 
 func (b0 Secret_builder) Build() *Secret {
@@ -421,34 +520,158 @@ func (b0 Secret_builder) Build() *Secret {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Id != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 12)
 		x.xxx_hidden_Id = b.Id
 	}
 	x.xxx_hidden_ModifiedAt = b.ModifiedAt
 	if b.Type != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 12)
 		x.xxx_hidden_Type = *b.Type
 	}
 	if b.IsBig != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 12)
 		x.xxx_hidden_IsBig = *b.IsBig
 	}
 	if b.Version != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 12)
 		x.xxx_hidden_Version = *b.Version
 	}
 	if b.Deleted != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 12)
 		x.xxx_hidden_Deleted = *b.Deleted
 	}
 	if b.Dek != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 12)
 		x.xxx_hidden_Dek = b.Dek
 	}
 	if b.Data != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 8)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 12)
 		x.xxx_hidden_Data = b.Data
 	}
+	if b.Owner != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 8, 12)
+		x.xxx_hidden_Owner = b.Owner
+	}
+	if b.DataVersion != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 9, 12)
+		x.xxx_hidden_DataVersion = *b.DataVersion
+	}
+	x.xxx_hidden_Shares = &b.Shares
+	if b.VaultId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 11, 12)
+		x.xxx_hidden_VaultId = b.VaultId
+	}
+	return m0
+}
+
+type ShareKey struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,1,opt,name=login"`
+	xxx_hidden_Dek         []byte                 `protobuf:"bytes,2,opt,name=dek"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ShareKey) Reset() {
+	*x = ShareKey{}
+	mi := &file_app_api_proto_sync_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareKey) ProtoMessage() {}
+
+func (x *ShareKey) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_sync_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ShareKey) GetLogin() string {
+	if x != nil {
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ShareKey) GetDek() []byte {
+	if x != nil {
+		return x.xxx_hidden_Dek
+	}
+	return nil
+}
+
+func (x *ShareKey) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *ShareKey) SetDek(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Dek = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *ShareKey) HasLogin() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ShareKey) HasDek() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ShareKey) ClearLogin() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Login = nil
+}
+
+func (x *ShareKey) ClearDek() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Dek = nil
+}
+
+type ShareKey_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Login *string
+	Dek   []byte
+}
+
+func (b0 ShareKey_builder) Build() *ShareKey {
+	m0 := &ShareKey{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Login != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_Login = b.Login
+	}
+	if b.Dek != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_Dek = b.Dek
+	}
 	return m0
 }
 
@@ -467,7 +690,7 @@ type PushOperation struct {
 
 func (x *PushOperation) Reset() {
 	*x = PushOperation{}
-	mi := &file_app_api_proto_sync_proto_msgTypes[1]
+	mi := &file_app_api_proto_sync_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -479,7 +702,7 @@ func (x *PushOperation) String() string {
 func (*PushOperation) ProtoMessage() {}
 
 func (x *PushOperation) ProtoReflect() protoreflect.Message {
-	mi := &file_app_api_proto_sync_proto_msgTypes[1]
+	mi := &file_app_api_proto_sync_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -667,7 +890,7 @@ type Chunk struct {
 
 func (x *Chunk) Reset() {
 	*x = Chunk{}
-	mi := &file_app_api_proto_sync_proto_msgTypes[2]
+	mi := &file_app_api_proto_sync_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -679,7 +902,7 @@ func (x *Chunk) String() string {
 func (*Chunk) ProtoMessage() {}
 
 func (x *Chunk) ProtoReflect() protoreflect.Message {
-	mi := &file_app_api_proto_sync_proto_msgTypes[2]
+	mi := &file_app_api_proto_sync_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -837,7 +1060,7 @@ type PushResponse struct {
 
 func (x *PushResponse) Reset() {
 	*x = PushResponse{}
-	mi := &file_app_api_proto_sync_proto_msgTypes[3]
+	mi := &file_app_api_proto_sync_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -849,7 +1072,7 @@ func (x *PushResponse) String() string {
 func (*PushResponse) ProtoMessage() {}
 
 func (x *PushResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_api_proto_sync_proto_msgTypes[3]
+	mi := &file_app_api_proto_sync_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -902,17 +1125,21 @@ func (b0 PushResponse_builder) Build() *PushResponse {
 }
 
 type PullRequest struct {
-	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Since       int32                  `protobuf:"varint,2,opt,name=since"`
-	XXX_raceDetectHookData protoimpl.RaceDetectHookData
-	XXX_presence           [1]uint32
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	state                   protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Since        int32                  `protobuf:"varint,2,opt,name=since"`
+	xxx_hidden_AfterVersion int32                  `protobuf:"varint,3,opt,name=after_version,json=afterVersion"`
+	xxx_hidden_AfterId      *string                `protobuf:"bytes,4,opt,name=after_id,json=afterId"`
+	xxx_hidden_PageSize     int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize"`
+	xxx_hidden_VaultId      *string                `protobuf:"bytes,6,opt,name=vault_id,json=vaultId"`
+	XXX_raceDetectHookData  protoimpl.RaceDetectHookData
+	XXX_presence            [1]uint32
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *PullRequest) Reset() {
 	*x = PullRequest{}
-	mi := &file_app_api_proto_sync_proto_msgTypes[4]
+	mi := &file_app_api_proto_sync_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -924,7 +1151,7 @@ func (x *PullRequest) String() string {
 func (*PullRequest) ProtoMessage() {}
 
 func (x *PullRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_api_proto_sync_proto_msgTypes[4]
+	mi := &file_app_api_proto_sync_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -942,9 +1169,63 @@ func (x *PullRequest) GetSince() int32 {
 	return 0
 }
 
+func (x *PullRequest) GetAfterVersion() int32 {
+	if x != nil {
+		return x.xxx_hidden_AfterVersion
+	}
+	return 0
+}
+
+func (x *PullRequest) GetAfterId() string {
+	if x != nil {
+		if x.xxx_hidden_AfterId != nil {
+			return *x.xxx_hidden_AfterId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *PullRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.xxx_hidden_PageSize
+	}
+	return 0
+}
+
+func (x *PullRequest) GetVaultId() string {
+	if x != nil {
+		if x.xxx_hidden_VaultId != nil {
+			return *x.xxx_hidden_VaultId
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *PullRequest) SetSince(v int32) {
 	x.xxx_hidden_Since = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 5)
+}
+
+func (x *PullRequest) SetAfterVersion(v int32) {
+	x.xxx_hidden_AfterVersion = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+}
+
+func (x *PullRequest) SetAfterId(v string) {
+	x.xxx_hidden_AfterId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+}
+
+func (x *PullRequest) SetPageSize(v int32) {
+	x.xxx_hidden_PageSize = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 5)
+}
+
+func (x *PullRequest) SetVaultId(v string) {
+	x.xxx_hidden_VaultId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 5)
 }
 
 func (x *PullRequest) HasSince() bool {
@@ -954,15 +1235,67 @@ func (x *PullRequest) HasSince() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
+func (x *PullRequest) HasAfterVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *PullRequest) HasAfterId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *PullRequest) HasPageSize() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *PullRequest) HasVaultId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
 func (x *PullRequest) ClearSince() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Since = 0
 }
 
+func (x *PullRequest) ClearAfterVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_AfterVersion = 0
+}
+
+func (x *PullRequest) ClearAfterId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_AfterId = nil
+}
+
+func (x *PullRequest) ClearPageSize() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_PageSize = 0
+}
+
+func (x *PullRequest) ClearVaultId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_VaultId = nil
+}
+
 type PullRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Since *int32
+	Since        *int32
+	AfterVersion *int32
+	AfterId      *string
+	PageSize     *int32
+	VaultId      *string
 }
 
 func (b0 PullRequest_builder) Build() *PullRequest {
@@ -970,9 +1303,25 @@ func (b0 PullRequest_builder) Build() *PullRequest {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Since != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 5)
 		x.xxx_hidden_Since = *b.Since
 	}
+	if b.AfterVersion != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
+		x.xxx_hidden_AfterVersion = *b.AfterVersion
+	}
+	if b.AfterId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		x.xxx_hidden_AfterId = b.AfterId
+	}
+	if b.PageSize != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 5)
+		x.xxx_hidden_PageSize = *b.PageSize
+	}
+	if b.VaultId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 5)
+		x.xxx_hidden_VaultId = b.VaultId
+	}
 	return m0
 }
 
@@ -988,7 +1337,7 @@ type PullResponse struct {
 
 func (x *PullResponse) Reset() {
 	*x = PullResponse{}
-	mi := &file_app_api_proto_sync_proto_msgTypes[5]
+	mi := &file_app_api_proto_sync_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1000,7 +1349,7 @@ func (x *PullResponse) String() string {
 func (*PullResponse) ProtoMessage() {}
 
 func (x *PullResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_api_proto_sync_proto_msgTypes[5]
+	mi := &file_app_api_proto_sync_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1067,6 +1416,278 @@ func (b0 PullResponse_builder) Build() *PullResponse {
 	return m0
 }
 
+type PullPage struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Secrets     *[]*Secret             `protobuf:"bytes,1,rep,name=secrets"`
+	xxx_hidden_Version     int32                  `protobuf:"varint,2,opt,name=version"`
+	xxx_hidden_Last        bool                   `protobuf:"varint,3,opt,name=last"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *PullPage) Reset() {
+	*x = PullPage{}
+	mi := &file_app_api_proto_sync_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullPage) ProtoMessage() {}
+
+func (x *PullPage) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_sync_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *PullPage) GetSecrets() []*Secret {
+	if x != nil {
+		if x.xxx_hidden_Secrets != nil {
+			return *x.xxx_hidden_Secrets
+		}
+	}
+	return nil
+}
+
+func (x *PullPage) GetVersion() int32 {
+	if x != nil {
+		return x.xxx_hidden_Version
+	}
+	return 0
+}
+
+func (x *PullPage) GetLast() bool {
+	if x != nil {
+		return x.xxx_hidden_Last
+	}
+	return false
+}
+
+func (x *PullPage) SetSecrets(v []*Secret) {
+	x.xxx_hidden_Secrets = &v
+}
+
+func (x *PullPage) SetVersion(v int32) {
+	x.xxx_hidden_Version = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *PullPage) SetLast(v bool) {
+	x.xxx_hidden_Last = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *PullPage) HasVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *PullPage) HasLast() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *PullPage) ClearVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Version = 0
+}
+
+func (x *PullPage) ClearLast() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Last = false
+}
+
+type PullPage_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Secrets []*Secret
+	Version *int32
+	Last    *bool
+}
+
+func (b0 PullPage_builder) Build() *PullPage {
+	m0 := &PullPage{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Secrets = &b.Secrets
+	if b.Version != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_Version = *b.Version
+	}
+	if b.Last != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Last = *b.Last
+	}
+	return m0
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_app_api_proto_sync_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_sync_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type SubscribeRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 SubscribeRequest_builder) Build() *SubscribeRequest {
+	m0 := &SubscribeRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type ChangeEvent struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Version     int32                  `protobuf:"varint,1,opt,name=version"`
+	xxx_hidden_VaultId     *string                `protobuf:"bytes,2,opt,name=vault_id,json=vaultId"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ChangeEvent) Reset() {
+	*x = ChangeEvent{}
+	mi := &file_app_api_proto_sync_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEvent) ProtoMessage() {}
+
+func (x *ChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_sync_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ChangeEvent) GetVersion() int32 {
+	if x != nil {
+		return x.xxx_hidden_Version
+	}
+	return 0
+}
+
+func (x *ChangeEvent) GetVaultId() string {
+	if x != nil {
+		if x.xxx_hidden_VaultId != nil {
+			return *x.xxx_hidden_VaultId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ChangeEvent) SetVersion(v int32) {
+	x.xxx_hidden_Version = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *ChangeEvent) SetVaultId(v string) {
+	x.xxx_hidden_VaultId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *ChangeEvent) HasVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ChangeEvent) HasVaultId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ChangeEvent) ClearVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Version = 0
+}
+
+func (x *ChangeEvent) ClearVaultId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_VaultId = nil
+}
+
+type ChangeEvent_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Version *int32
+	VaultId *string
+}
+
+func (b0 ChangeEvent_builder) Build() *ChangeEvent {
+	m0 := &ChangeEvent{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Version != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_Version = *b.Version
+	}
+	if b.VaultId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_VaultId = b.VaultId
+	}
+	return m0
+}
+
 type PullStreamRequest struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_Id          *string                `protobuf:"bytes,1,opt,name=id"`
@@ -1079,7 +1700,7 @@ type PullStreamRequest struct {
 
 func (x *PullStreamRequest) Reset() {
 	*x = PullStreamRequest{}
-	mi := &file_app_api_proto_sync_proto_msgTypes[6]
+	mi := &file_app_api_proto_sync_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1091,7 +1712,7 @@ func (x *PullStreamRequest) String() string {
 func (*PullStreamRequest) ProtoMessage() {}
 
 func (x *PullStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_api_proto_sync_proto_msgTypes[6]
+	mi := &file_app_api_proto_sync_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1179,7 +1800,7 @@ var File_app_api_proto_sync_proto protoreflect.FileDescriptor
 
 const file_app_api_proto_sync_proto_rawDesc = "" +
 	"\n" +
-	"\x18app/api/proto/sync.proto\x12\x02go\x1a\x1fgoogle/protobuf/timestamp.proto\x1a!google/protobuf/go_features.proto\"\xea\x01\n" +
+	"\x18app/api/proto/sync.proto\x12\x02go\x1a\x1fgoogle/protobuf/timestamp.proto\x1a!google/protobuf/go_features.proto\"\xe4\x02\n" +
 	"\x06Secret\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12;\n" +
 	"\vmodified_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
@@ -1189,7 +1810,15 @@ const file_app_api_proto_sync_proto_rawDesc = "" +
 	"\aversion\x18\x05 \x01(\x05R\aversion\x12\x18\n" +
 	"\adeleted\x18\x06 \x01(\bR\adeleted\x12\x10\n" +
 	"\x03dek\x18\a \x01(\fR\x03dek\x12\x12\n" +
-	"\x04data\x18\b \x01(\fR\x04data\"\xb6\x01\n" +
+	"\x04data\x18\b \x01(\fR\x04data\x12\x14\n" +
+	"\x05owner\x18\t \x01(\tR\x05owner\x12!\n" +
+	"\fdata_version\x18\n" +
+	" \x01(\x05R\vdataVersion\x12$\n" +
+	"\x06shares\x18\v \x03(\v2\f.go.ShareKeyR\x06shares\x12\x19\n" +
+	"\bvault_id\x18\f \x01(\tR\avaultId\"2\n" +
+	"\bShareKey\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x10\n" +
+	"\x03dek\x18\x02 \x01(\fR\x03dek\"\xb6\x01\n" +
 	"\rPushOperation\x12\"\n" +
 	"\x06secret\x18\x01 \x01(\v2\n" +
 	".go.SecretR\x06secret\x12%\n" +
@@ -1204,13 +1833,26 @@ const file_app_api_proto_sync_proto_rawDesc = "" +
 	"\x06buffer\x18\x03 \x01(\fR\x06buffer\x12\x18\n" +
 	"\aversion\x18\x04 \x01(\x05R\aversion\"(\n" +
 	"\fPushResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"#\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x9b\x01\n" +
 	"\vPullRequest\x12\x14\n" +
-	"\x05since\x18\x02 \x01(\x05R\x05since\"N\n" +
+	"\x05since\x18\x02 \x01(\x05R\x05since\x12#\n" +
+	"\rafter_version\x18\x03 \x01(\x05R\fafterVersion\x12\x19\n" +
+	"\bafter_id\x18\x04 \x01(\tR\aafterId\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x19\n" +
+	"\bvault_id\x18\x06 \x01(\tR\avaultId\"N\n" +
 	"\fPullResponse\x12$\n" +
 	"\asecrets\x18\x01 \x03(\v2\n" +
 	".go.SecretR\asecrets\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\x05R\aversion\"=\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"^\n" +
+	"\bPullPage\x12$\n" +
+	"\asecrets\x18\x01 \x03(\v2\n" +
+	".go.SecretR\asecrets\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x12\n" +
+	"\x04last\x18\x03 \x01(\bR\x04last\"\x12\n" +
+	"\x10SubscribeRequest\"B\n" +
+	"\vChangeEvent\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x19\n" +
+	"\bvault_id\x18\x02 \x01(\tR\avaultId\"=\n" +
 	"\x11PullStreamRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\aversion\x18\x02 \x01(\x05R\aversion*?\n" +
@@ -1230,47 +1872,59 @@ const file_app_api_proto_sync_proto_rawDesc = "" +
 	"\tChunkType\x12\f\n" +
 	"\bFilePart\x10\x00\x12\v\n" +
 	"\aEndData\x10\x01\x12\v\n" +
-	"\aErrData\x10\x022\x98\x01\n" +
+	"\aErrData\x10\x022\xfc\x01\n" +
 	"\x04Sync\x123\n" +
 	"\n" +
 	"PushStream\x12\x11.go.PushOperation\x1a\x10.go.PushResponse(\x01\x12)\n" +
 	"\x04Pull\x12\x0f.go.PullRequest\x1a\x10.go.PullResponse\x120\n" +
 	"\n" +
-	"PullStream\x12\x15.go.PullStreamRequest\x1a\t.go.Chunk0\x01B\rZ\x03/pb\x92\x03\x05\xd2>\x02\x10\x03b\beditionsp\xe8\a"
+	"PullStream\x12\x15.go.PullStreamRequest\x1a\t.go.Chunk0\x01\x12,\n" +
+	"\tPullPages\x12\x0f.go.PullRequest\x1a\f.go.PullPage0\x01\x124\n" +
+	"\tSubscribe\x12\x14.go.SubscribeRequest\x1a\x0f.go.ChangeEvent0\x01B\rZ\x03/pb\x92\x03\x05\xd2>\x02\x10\x03b\beditionsp\xe8\a"
 
 var file_app_api_proto_sync_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_app_api_proto_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_app_api_proto_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_app_api_proto_sync_proto_goTypes = []any{
 	(SecretType)(0),               // 0: go.SecretType
 	(OperationType)(0),            // 1: go.OperationType
 	(ChunkType)(0),                // 2: go.ChunkType
 	(*Secret)(nil),                // 3: go.Secret
-	(*PushOperation)(nil),         // 4: go.PushOperation
-	(*Chunk)(nil),                 // 5: go.Chunk
-	(*PushResponse)(nil),          // 6: go.PushResponse
-	(*PullRequest)(nil),           // 7: go.PullRequest
-	(*PullResponse)(nil),          // 8: go.PullResponse
-	(*PullStreamRequest)(nil),     // 9: go.PullStreamRequest
-	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*ShareKey)(nil),              // 4: go.ShareKey
+	(*PushOperation)(nil),         // 5: go.PushOperation
+	(*Chunk)(nil),                 // 6: go.Chunk
+	(*PushResponse)(nil),          // 7: go.PushResponse
+	(*PullRequest)(nil),           // 8: go.PullRequest
+	(*PullResponse)(nil),          // 9: go.PullResponse
+	(*PullPage)(nil),              // 10: go.PullPage
+	(*SubscribeRequest)(nil),      // 11: go.SubscribeRequest
+	(*ChangeEvent)(nil),           // 12: go.ChangeEvent
+	(*PullStreamRequest)(nil),     // 13: go.PullStreamRequest
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
 }
 var file_app_api_proto_sync_proto_depIdxs = []int32{
-	10, // 0: go.Secret.modified_at:type_name -> google.protobuf.Timestamp
+	14, // 0: go.Secret.modified_at:type_name -> google.protobuf.Timestamp
 	0,  // 1: go.Secret.type:type_name -> go.SecretType
-	3,  // 2: go.PushOperation.secret:type_name -> go.Secret
-	1,  // 3: go.PushOperation.type:type_name -> go.OperationType
-	2,  // 4: go.Chunk.type:type_name -> go.ChunkType
-	3,  // 5: go.PullResponse.secrets:type_name -> go.Secret
-	4,  // 6: go.Sync.PushStream:input_type -> go.PushOperation
-	7,  // 7: go.Sync.Pull:input_type -> go.PullRequest
-	9,  // 8: go.Sync.PullStream:input_type -> go.PullStreamRequest
-	6,  // 9: go.Sync.PushStream:output_type -> go.PushResponse
-	8,  // 10: go.Sync.Pull:output_type -> go.PullResponse
-	5,  // 11: go.Sync.PullStream:output_type -> go.Chunk
-	9,  // [9:12] is the sub-list for method output_type
-	6,  // [6:9] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	4,  // 2: go.Secret.shares:type_name -> go.ShareKey
+	3,  // 3: go.PushOperation.secret:type_name -> go.Secret
+	1,  // 4: go.PushOperation.type:type_name -> go.OperationType
+	2,  // 5: go.Chunk.type:type_name -> go.ChunkType
+	3,  // 6: go.PullResponse.secrets:type_name -> go.Secret
+	3,  // 7: go.PullPage.secrets:type_name -> go.Secret
+	5,  // 8: go.Sync.PushStream:input_type -> go.PushOperation
+	8,  // 9: go.Sync.Pull:input_type -> go.PullRequest
+	13, // 10: go.Sync.PullStream:input_type -> go.PullStreamRequest
+	8,  // 11: go.Sync.PullPages:input_type -> go.PullRequest
+	11, // 12: go.Sync.Subscribe:input_type -> go.SubscribeRequest
+	7,  // 13: go.Sync.PushStream:output_type -> go.PushResponse
+	9,  // 14: go.Sync.Pull:output_type -> go.PullResponse
+	6,  // 15: go.Sync.PullStream:output_type -> go.Chunk
+	10, // 16: go.Sync.PullPages:output_type -> go.PullPage
+	12, // 17: go.Sync.Subscribe:output_type -> go.ChangeEvent
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_app_api_proto_sync_proto_init() }
@@ -1284,7 +1938,7 @@ func file_app_api_proto_sync_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_api_proto_sync_proto_rawDesc), len(file_app_api_proto_sync_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   7,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,8 @@ const (
 	Sync_PushStream_FullMethodName = "/go.Sync/PushStream"
 	Sync_Pull_FullMethodName       = "/go.Sync/Pull"
 	Sync_PullStream_FullMethodName = "/go.Sync/PullStream"
+	Sync_PullPages_FullMethodName  = "/go.Sync/PullPages"
+	Sync_Subscribe_FullMethodName  = "/go.Sync/Subscribe"
 )
 
 // SyncClient is the client API for Sync service.
@@ -32,6 +34,8 @@ type SyncClient interface {
 	PushStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PushOperation, PushResponse], error)
 	Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*PullResponse, error)
 	PullStream(ctx context.Context, in *PullStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Chunk], error)
+	PullPages(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PullPage], error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error)
 }
 
 type syncClient struct {
@@ -84,6 +88,44 @@ func (c *syncClient) PullStream(ctx context.Context, in *PullStreamRequest, opts
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Sync_PullStreamClient = grpc.ServerStreamingClient[Chunk]
 
+func (c *syncClient) PullPages(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PullPage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Sync_ServiceDesc.Streams[2], Sync_PullPages_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PullRequest, PullPage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Sync_PullPagesClient = grpc.ServerStreamingClient[PullPage]
+
+func (c *syncClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Sync_ServiceDesc.Streams[3], Sync_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, ChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Sync_SubscribeClient = grpc.ServerStreamingClient[ChangeEvent]
+
 // SyncServer is the server API for Sync service.
 // All implementations must embed UnimplementedSyncServer
 // for forward compatibility.
@@ -91,6 +133,8 @@ type SyncServer interface {
 	PushStream(grpc.ClientStreamingServer[PushOperation, PushResponse]) error
 	Pull(context.Context, *PullRequest) (*PullResponse, error)
 	PullStream(*PullStreamRequest, grpc.ServerStreamingServer[Chunk]) error
+	PullPages(*PullRequest, grpc.ServerStreamingServer[PullPage]) error
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[ChangeEvent]) error
 	mustEmbedUnimplementedSyncServer()
 }
 
@@ -110,6 +154,12 @@ func (UnimplementedSyncServer) Pull(context.Context, *PullRequest) (*PullRespons
 func (UnimplementedSyncServer) PullStream(*PullStreamRequest, grpc.ServerStreamingServer[Chunk]) error {
 	return status.Errorf(codes.Unimplemented, "method PullStream not implemented")
 }
+func (UnimplementedSyncServer) PullPages(*PullRequest, grpc.ServerStreamingServer[PullPage]) error {
+	return status.Errorf(codes.Unimplemented, "method PullPages not implemented")
+}
+func (UnimplementedSyncServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[ChangeEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
 func (UnimplementedSyncServer) mustEmbedUnimplementedSyncServer() {}
 func (UnimplementedSyncServer) testEmbeddedByValue()              {}
 
@@ -167,6 +217,28 @@ func _Sync_PullStream_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Sync_PullStreamServer = grpc.ServerStreamingServer[Chunk]
 
+func _Sync_PullPages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PullRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SyncServer).PullPages(m, &grpc.GenericServerStream[PullRequest, PullPage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Sync_PullPagesServer = grpc.ServerStreamingServer[PullPage]
+
+func _Sync_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SyncServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, ChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Sync_SubscribeServer = grpc.ServerStreamingServer[ChangeEvent]
+
 // Sync_ServiceDesc is the grpc.ServiceDesc for Sync service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -190,6 +262,16 @@ var Sync_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Sync_PullStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PullPages",
+			Handler:       _Sync_PullPages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Sync_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "app/api/proto/sync.proto",
 }
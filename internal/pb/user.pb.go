@@ -26,6 +26,11 @@ type User struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_Login       *string                `protobuf:"bytes,1,opt,name=login"`
 	xxx_hidden_Password    *string                `protobuf:"bytes,2,opt,name=password"`
+	xxx_hidden_DeviceId    *string                `protobuf:"bytes,3,opt,name=device_id,json=deviceId"`
+	xxx_hidden_Timestamp   int64                  `protobuf:"varint,4,opt,name=timestamp"`
+	xxx_hidden_Signature   []byte                 `protobuf:"bytes,5,opt,name=signature"`
+	xxx_hidden_Salt        []byte                 `protobuf:"bytes,6,opt,name=salt"`
+	xxx_hidden_Verifier    []byte                 `protobuf:"bytes,7,opt,name=verifier"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
@@ -77,14 +82,86 @@ func (x *User) GetPassword() string {
 	return ""
 }
 
+func (x *User) GetDeviceId() string {
+	if x != nil {
+		if x.xxx_hidden_DeviceId != nil {
+			return *x.xxx_hidden_DeviceId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *User) GetTimestamp() int64 {
+	if x != nil {
+		return x.xxx_hidden_Timestamp
+	}
+	return 0
+}
+
+func (x *User) GetSignature() []byte {
+	if x != nil {
+		return x.xxx_hidden_Signature
+	}
+	return nil
+}
+
+func (x *User) GetSalt() []byte {
+	if x != nil {
+		return x.xxx_hidden_Salt
+	}
+	return nil
+}
+
+func (x *User) GetVerifier() []byte {
+	if x != nil {
+		return x.xxx_hidden_Verifier
+	}
+	return nil
+}
+
 func (x *User) SetLogin(v string) {
 	x.xxx_hidden_Login = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 7)
 }
 
 func (x *User) SetPassword(v string) {
 	x.xxx_hidden_Password = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 7)
+}
+
+func (x *User) SetDeviceId(v string) {
+	x.xxx_hidden_DeviceId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 7)
+}
+
+func (x *User) SetTimestamp(v int64) {
+	x.xxx_hidden_Timestamp = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 7)
+}
+
+func (x *User) SetSignature(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Signature = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 7)
+}
+
+func (x *User) SetSalt(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Salt = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 7)
+}
+
+func (x *User) SetVerifier(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Verifier = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 7)
 }
 
 func (x *User) HasLogin() bool {
@@ -101,6 +178,41 @@ func (x *User) HasPassword() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
+func (x *User) HasDeviceId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *User) HasTimestamp() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *User) HasSignature() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *User) HasSalt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *User) HasVerifier() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
+}
+
 func (x *User) ClearLogin() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Login = nil
@@ -111,11 +223,41 @@ func (x *User) ClearPassword() {
 	x.xxx_hidden_Password = nil
 }
 
+func (x *User) ClearDeviceId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_DeviceId = nil
+}
+
+func (x *User) ClearTimestamp() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Timestamp = 0
+}
+
+func (x *User) ClearSignature() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Signature = nil
+}
+
+func (x *User) ClearSalt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Salt = nil
+}
+
+func (x *User) ClearVerifier() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_Verifier = nil
+}
+
 type User_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Login    *string
-	Password *string
+	Login     *string
+	Password  *string
+	DeviceId  *string
+	Timestamp *int64
+	Signature []byte
+	Salt      []byte
+	Verifier  []byte
 }
 
 func (b0 User_builder) Build() *User {
@@ -123,39 +265,61 @@ func (b0 User_builder) Build() *User {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Login != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 7)
 		x.xxx_hidden_Login = b.Login
 	}
 	if b.Password != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 7)
 		x.xxx_hidden_Password = b.Password
 	}
+	if b.DeviceId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 7)
+		x.xxx_hidden_DeviceId = b.DeviceId
+	}
+	if b.Timestamp != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 7)
+		x.xxx_hidden_Timestamp = *b.Timestamp
+	}
+	if b.Signature != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 7)
+		x.xxx_hidden_Signature = b.Signature
+	}
+	if b.Salt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 7)
+		x.xxx_hidden_Salt = b.Salt
+	}
+	if b.Verifier != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 7)
+		x.xxx_hidden_Verifier = b.Verifier
+	}
 	return m0
 }
 
-type UserResponse struct {
+type RegisterVerifierRequest struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Token       *string                `protobuf:"bytes,1,opt,name=token"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,1,opt,name=login"`
+	xxx_hidden_Salt        []byte                 `protobuf:"bytes,2,opt,name=salt"`
+	xxx_hidden_Verifier    []byte                 `protobuf:"bytes,3,opt,name=verifier"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
-func (x *UserResponse) Reset() {
-	*x = UserResponse{}
+func (x *RegisterVerifierRequest) Reset() {
+	*x = RegisterVerifierRequest{}
 	mi := &file_app_api_proto_user_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserResponse) String() string {
+func (x *RegisterVerifierRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserResponse) ProtoMessage() {}
+func (*RegisterVerifierRequest) ProtoMessage() {}
 
-func (x *UserResponse) ProtoReflect() protoreflect.Message {
+func (x *RegisterVerifierRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_app_api_proto_user_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -167,79 +331,1964 @@ func (x *UserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *UserResponse) GetToken() string {
+func (x *RegisterVerifierRequest) GetLogin() string {
 	if x != nil {
-		if x.xxx_hidden_Token != nil {
-			return *x.xxx_hidden_Token
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *UserResponse) SetToken(v string) {
-	x.xxx_hidden_Token = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+func (x *RegisterVerifierRequest) GetSalt() []byte {
+	if x != nil {
+		return x.xxx_hidden_Salt
+	}
+	return nil
 }
 
-func (x *UserResponse) HasToken() bool {
+func (x *RegisterVerifierRequest) GetVerifier() []byte {
+	if x != nil {
+		return x.xxx_hidden_Verifier
+	}
+	return nil
+}
+
+func (x *RegisterVerifierRequest) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *RegisterVerifierRequest) SetSalt(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Salt = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *RegisterVerifierRequest) SetVerifier(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Verifier = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *RegisterVerifierRequest) HasLogin() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *UserResponse) ClearToken() {
+func (x *RegisterVerifierRequest) HasSalt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *RegisterVerifierRequest) HasVerifier() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *RegisterVerifierRequest) ClearLogin() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_Token = nil
+	x.xxx_hidden_Login = nil
 }
 
-type UserResponse_builder struct {
+func (x *RegisterVerifierRequest) ClearSalt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Salt = nil
+}
+
+func (x *RegisterVerifierRequest) ClearVerifier() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Verifier = nil
+}
+
+type RegisterVerifierRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Token *string
+	Login    *string
+	Salt     []byte
+	Verifier []byte
 }
 
-func (b0 UserResponse_builder) Build() *UserResponse {
-	m0 := &UserResponse{}
+func (b0 RegisterVerifierRequest_builder) Build() *RegisterVerifierRequest {
+	m0 := &RegisterVerifierRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.Token != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
-		x.xxx_hidden_Token = b.Token
+	if b.Login != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_Login = b.Login
+	}
+	if b.Salt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_Salt = b.Salt
+	}
+	if b.Verifier != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Verifier = b.Verifier
 	}
 	return m0
 }
 
-var File_app_api_proto_user_proto protoreflect.FileDescriptor
+type BeginLoginRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,1,opt,name=login"`
+	xxx_hidden_PublicKey   []byte                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
 
-const file_app_api_proto_user_proto_rawDesc = "" +
-	"\n" +
-	"\x18app/api/proto/user.proto\x12\x02go\x1a!google/protobuf/go_features.proto\"8\n" +
-	"\x04User\x12\x14\n" +
-	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"$\n" +
-	"\fUserResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token2T\n" +
-	"\x05Users\x12#\n" +
-	"\x05Login\x12\b.go.User\x1a\x10.go.UserResponse\x12&\n" +
-	"\bRegister\x12\b.go.User\x1a\x10.go.UserResponseB\rZ\x03/pb\x92\x03\x05\xd2>\x02\x10\x03b\beditionsp\xe8\a"
+func (x *BeginLoginRequest) Reset() {
+	*x = BeginLoginRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginLoginRequest) ProtoMessage() {}
+
+func (x *BeginLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *BeginLoginRequest) GetLogin() string {
+	if x != nil {
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *BeginLoginRequest) GetPublicKey() []byte {
+	if x != nil {
+		return x.xxx_hidden_PublicKey
+	}
+	return nil
+}
+
+func (x *BeginLoginRequest) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *BeginLoginRequest) SetPublicKey(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_PublicKey = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *BeginLoginRequest) HasLogin() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *BeginLoginRequest) HasPublicKey() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *BeginLoginRequest) ClearLogin() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Login = nil
+}
+
+func (x *BeginLoginRequest) ClearPublicKey() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_PublicKey = nil
+}
+
+type BeginLoginRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Login     *string
+	PublicKey []byte
+}
+
+func (b0 BeginLoginRequest_builder) Build() *BeginLoginRequest {
+	m0 := &BeginLoginRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Login != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_Login = b.Login
+	}
+	if b.PublicKey != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_PublicKey = b.PublicKey
+	}
+	return m0
+}
+
+type BeginLoginResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_HandshakeId *string                `protobuf:"bytes,1,opt,name=handshake_id,json=handshakeId"`
+	xxx_hidden_Salt        []byte                 `protobuf:"bytes,2,opt,name=salt"`
+	xxx_hidden_PublicKey   []byte                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *BeginLoginResponse) Reset() {
+	*x = BeginLoginResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginLoginResponse) ProtoMessage() {}
+
+func (x *BeginLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *BeginLoginResponse) GetHandshakeId() string {
+	if x != nil {
+		if x.xxx_hidden_HandshakeId != nil {
+			return *x.xxx_hidden_HandshakeId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *BeginLoginResponse) GetSalt() []byte {
+	if x != nil {
+		return x.xxx_hidden_Salt
+	}
+	return nil
+}
+
+func (x *BeginLoginResponse) GetPublicKey() []byte {
+	if x != nil {
+		return x.xxx_hidden_PublicKey
+	}
+	return nil
+}
+
+func (x *BeginLoginResponse) SetHandshakeId(v string) {
+	x.xxx_hidden_HandshakeId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *BeginLoginResponse) SetSalt(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Salt = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *BeginLoginResponse) SetPublicKey(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_PublicKey = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *BeginLoginResponse) HasHandshakeId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *BeginLoginResponse) HasSalt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *BeginLoginResponse) HasPublicKey() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *BeginLoginResponse) ClearHandshakeId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_HandshakeId = nil
+}
+
+func (x *BeginLoginResponse) ClearSalt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Salt = nil
+}
+
+func (x *BeginLoginResponse) ClearPublicKey() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_PublicKey = nil
+}
+
+type BeginLoginResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	HandshakeId *string
+	Salt        []byte
+	PublicKey   []byte
+}
+
+func (b0 BeginLoginResponse_builder) Build() *BeginLoginResponse {
+	m0 := &BeginLoginResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.HandshakeId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_HandshakeId = b.HandshakeId
+	}
+	if b.Salt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_Salt = b.Salt
+	}
+	if b.PublicKey != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_PublicKey = b.PublicKey
+	}
+	return m0
+}
+
+type FinishLoginRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,1,opt,name=login"`
+	xxx_hidden_HandshakeId *string                `protobuf:"bytes,2,opt,name=handshake_id,json=handshakeId"`
+	xxx_hidden_Proof       []byte                 `protobuf:"bytes,3,opt,name=proof"`
+	xxx_hidden_DeviceId    *string                `protobuf:"bytes,4,opt,name=device_id,json=deviceId"`
+	xxx_hidden_Timestamp   int64                  `protobuf:"varint,5,opt,name=timestamp"`
+	xxx_hidden_Signature   []byte                 `protobuf:"bytes,6,opt,name=signature"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *FinishLoginRequest) Reset() {
+	*x = FinishLoginRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishLoginRequest) ProtoMessage() {}
+
+func (x *FinishLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *FinishLoginRequest) GetLogin() string {
+	if x != nil {
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *FinishLoginRequest) GetHandshakeId() string {
+	if x != nil {
+		if x.xxx_hidden_HandshakeId != nil {
+			return *x.xxx_hidden_HandshakeId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *FinishLoginRequest) GetProof() []byte {
+	if x != nil {
+		return x.xxx_hidden_Proof
+	}
+	return nil
+}
+
+func (x *FinishLoginRequest) GetDeviceId() string {
+	if x != nil {
+		if x.xxx_hidden_DeviceId != nil {
+			return *x.xxx_hidden_DeviceId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *FinishLoginRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.xxx_hidden_Timestamp
+	}
+	return 0
+}
+
+func (x *FinishLoginRequest) GetSignature() []byte {
+	if x != nil {
+		return x.xxx_hidden_Signature
+	}
+	return nil
+}
+
+func (x *FinishLoginRequest) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 6)
+}
+
+func (x *FinishLoginRequest) SetHandshakeId(v string) {
+	x.xxx_hidden_HandshakeId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 6)
+}
+
+func (x *FinishLoginRequest) SetProof(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Proof = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 6)
+}
+
+func (x *FinishLoginRequest) SetDeviceId(v string) {
+	x.xxx_hidden_DeviceId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 6)
+}
+
+func (x *FinishLoginRequest) SetTimestamp(v int64) {
+	x.xxx_hidden_Timestamp = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 6)
+}
+
+func (x *FinishLoginRequest) SetSignature(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Signature = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 6)
+}
+
+func (x *FinishLoginRequest) HasLogin() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *FinishLoginRequest) HasHandshakeId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *FinishLoginRequest) HasProof() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *FinishLoginRequest) HasDeviceId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *FinishLoginRequest) HasTimestamp() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *FinishLoginRequest) HasSignature() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *FinishLoginRequest) ClearLogin() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Login = nil
+}
+
+func (x *FinishLoginRequest) ClearHandshakeId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_HandshakeId = nil
+}
+
+func (x *FinishLoginRequest) ClearProof() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Proof = nil
+}
+
+func (x *FinishLoginRequest) ClearDeviceId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_DeviceId = nil
+}
+
+func (x *FinishLoginRequest) ClearTimestamp() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Timestamp = 0
+}
+
+func (x *FinishLoginRequest) ClearSignature() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Signature = nil
+}
+
+type FinishLoginRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Login       *string
+	HandshakeId *string
+	Proof       []byte
+	DeviceId    *string
+	Timestamp   *int64
+	Signature   []byte
+}
+
+func (b0 FinishLoginRequest_builder) Build() *FinishLoginRequest {
+	m0 := &FinishLoginRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Login != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 6)
+		x.xxx_hidden_Login = b.Login
+	}
+	if b.HandshakeId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 6)
+		x.xxx_hidden_HandshakeId = b.HandshakeId
+	}
+	if b.Proof != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 6)
+		x.xxx_hidden_Proof = b.Proof
+	}
+	if b.DeviceId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 6)
+		x.xxx_hidden_DeviceId = b.DeviceId
+	}
+	if b.Timestamp != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 6)
+		x.xxx_hidden_Timestamp = *b.Timestamp
+	}
+	if b.Signature != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 6)
+		x.xxx_hidden_Signature = b.Signature
+	}
+	return m0
+}
+
+type FinishLoginResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Tokens      *UserResponse          `protobuf:"bytes,1,opt,name=tokens"`
+	xxx_hidden_ServerProof []byte                 `protobuf:"bytes,2,opt,name=server_proof,json=serverProof"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *FinishLoginResponse) Reset() {
+	*x = FinishLoginResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishLoginResponse) ProtoMessage() {}
+
+func (x *FinishLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *FinishLoginResponse) GetTokens() *UserResponse {
+	if x != nil {
+		return x.xxx_hidden_Tokens
+	}
+	return nil
+}
+
+func (x *FinishLoginResponse) GetServerProof() []byte {
+	if x != nil {
+		return x.xxx_hidden_ServerProof
+	}
+	return nil
+}
+
+func (x *FinishLoginResponse) SetTokens(v *UserResponse) {
+	x.xxx_hidden_Tokens = v
+}
+
+func (x *FinishLoginResponse) SetServerProof(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_ServerProof = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *FinishLoginResponse) HasTokens() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Tokens != nil
+}
+
+func (x *FinishLoginResponse) HasServerProof() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *FinishLoginResponse) ClearTokens() {
+	x.xxx_hidden_Tokens = nil
+}
+
+func (x *FinishLoginResponse) ClearServerProof() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_ServerProof = nil
+}
+
+type FinishLoginResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Tokens      *UserResponse
+	ServerProof []byte
+}
+
+func (b0 FinishLoginResponse_builder) Build() *FinishLoginResponse {
+	m0 := &FinishLoginResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Tokens = b.Tokens
+	if b.ServerProof != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_ServerProof = b.ServerProof
+	}
+	return m0
+}
+
+type UserResponse struct {
+	state                   protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Token        *string                `protobuf:"bytes,1,opt,name=token"`
+	xxx_hidden_RefreshToken *string                `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken"`
+	xxx_hidden_ExpiresAt    int64                  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt"`
+	XXX_raceDetectHookData  protoimpl.RaceDetectHookData
+	XXX_presence            [1]uint32
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *UserResponse) Reset() {
+	*x = UserResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserResponse) ProtoMessage() {}
+
+func (x *UserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserResponse) GetToken() string {
+	if x != nil {
+		if x.xxx_hidden_Token != nil {
+			return *x.xxx_hidden_Token
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserResponse) GetRefreshToken() string {
+	if x != nil {
+		if x.xxx_hidden_RefreshToken != nil {
+			return *x.xxx_hidden_RefreshToken
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.xxx_hidden_ExpiresAt
+	}
+	return 0
+}
+
+func (x *UserResponse) SetToken(v string) {
+	x.xxx_hidden_Token = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *UserResponse) SetRefreshToken(v string) {
+	x.xxx_hidden_RefreshToken = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *UserResponse) SetExpiresAt(v int64) {
+	x.xxx_hidden_ExpiresAt = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *UserResponse) HasToken() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *UserResponse) HasRefreshToken() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UserResponse) HasExpiresAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UserResponse) ClearToken() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Token = nil
+}
+
+func (x *UserResponse) ClearRefreshToken() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_RefreshToken = nil
+}
+
+func (x *UserResponse) ClearExpiresAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_ExpiresAt = 0
+}
+
+type UserResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Token        *string
+	RefreshToken *string
+	ExpiresAt    *int64
+}
+
+func (b0 UserResponse_builder) Build() *UserResponse {
+	m0 := &UserResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Token != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_Token = b.Token
+	}
+	if b.RefreshToken != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_RefreshToken = b.RefreshToken
+	}
+	if b.ExpiresAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_ExpiresAt = *b.ExpiresAt
+	}
+	return m0
+}
+
+type RefreshRequest struct {
+	state                   protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_RefreshToken *string                `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken"`
+	XXX_raceDetectHookData  protoimpl.RaceDetectHookData
+	XXX_presence            [1]uint32
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *RefreshRequest) Reset() {
+	*x = RefreshRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRequest) ProtoMessage() {}
+
+func (x *RefreshRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *RefreshRequest) GetRefreshToken() string {
+	if x != nil {
+		if x.xxx_hidden_RefreshToken != nil {
+			return *x.xxx_hidden_RefreshToken
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *RefreshRequest) SetRefreshToken(v string) {
+	x.xxx_hidden_RefreshToken = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+}
+
+func (x *RefreshRequest) HasRefreshToken() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *RefreshRequest) ClearRefreshToken() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_RefreshToken = nil
+}
+
+type RefreshRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	RefreshToken *string
+}
+
+func (b0 RefreshRequest_builder) Build() *RefreshRequest {
+	m0 := &RefreshRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.RefreshToken != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		x.xxx_hidden_RefreshToken = b.RefreshToken
+	}
+	return m0
+}
+
+type ChangePasswordRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Password    *string                `protobuf:"bytes,1,opt,name=password"`
+	xxx_hidden_HandshakeId *string                `protobuf:"bytes,3,opt,name=handshake_id,json=handshakeId"`
+	xxx_hidden_Proof       []byte                 `protobuf:"bytes,4,opt,name=proof"`
+	xxx_hidden_Salt        []byte                 `protobuf:"bytes,5,opt,name=salt"`
+	xxx_hidden_Verifier    []byte                 `protobuf:"bytes,6,opt,name=verifier"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ChangePasswordRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetHandshakeId() string {
+	if x != nil {
+		if x.xxx_hidden_HandshakeId != nil {
+			return *x.xxx_hidden_HandshakeId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetProof() []byte {
+	if x != nil {
+		return x.xxx_hidden_Proof
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) GetSalt() []byte {
+	if x != nil {
+		return x.xxx_hidden_Salt
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) GetVerifier() []byte {
+	if x != nil {
+		return x.xxx_hidden_Verifier
+	}
+	return nil
+}
+
+func (x *ChangePasswordRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 5)
+}
+
+func (x *ChangePasswordRequest) SetHandshakeId(v string) {
+	x.xxx_hidden_HandshakeId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+}
+
+func (x *ChangePasswordRequest) SetProof(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Proof = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+}
+
+func (x *ChangePasswordRequest) SetSalt(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Salt = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 5)
+}
+
+func (x *ChangePasswordRequest) SetVerifier(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Verifier = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 5)
+}
+
+func (x *ChangePasswordRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ChangePasswordRequest) HasHandshakeId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ChangePasswordRequest) HasProof() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *ChangePasswordRequest) HasSalt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *ChangePasswordRequest) HasVerifier() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *ChangePasswordRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *ChangePasswordRequest) ClearHandshakeId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_HandshakeId = nil
+}
+
+func (x *ChangePasswordRequest) ClearProof() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Proof = nil
+}
+
+func (x *ChangePasswordRequest) ClearSalt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Salt = nil
+}
+
+func (x *ChangePasswordRequest) ClearVerifier() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Verifier = nil
+}
+
+type ChangePasswordRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Password    *string
+	HandshakeId *string
+	Proof       []byte
+	Salt        []byte
+	Verifier    []byte
+}
+
+func (b0 ChangePasswordRequest_builder) Build() *ChangePasswordRequest {
+	m0 := &ChangePasswordRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 5)
+		x.xxx_hidden_Password = b.Password
+	}
+	if b.HandshakeId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
+		x.xxx_hidden_HandshakeId = b.HandshakeId
+	}
+	if b.Proof != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		x.xxx_hidden_Proof = b.Proof
+	}
+	if b.Salt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 5)
+		x.xxx_hidden_Salt = b.Salt
+	}
+	if b.Verifier != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 5)
+		x.xxx_hidden_Verifier = b.Verifier
+	}
+	return m0
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type ChangePasswordResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 ChangePasswordResponse_builder) Build() *ChangePasswordResponse {
+	m0 := &ChangePasswordResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type ChangeLoginRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Password    *string                `protobuf:"bytes,1,opt,name=password"`
+	xxx_hidden_NewLogin    *string                `protobuf:"bytes,2,opt,name=new_login,json=newLogin"`
+	xxx_hidden_HandshakeId *string                `protobuf:"bytes,3,opt,name=handshake_id,json=handshakeId"`
+	xxx_hidden_Proof       []byte                 `protobuf:"bytes,4,opt,name=proof"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ChangeLoginRequest) Reset() {
+	*x = ChangeLoginRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeLoginRequest) ProtoMessage() {}
+
+func (x *ChangeLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ChangeLoginRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ChangeLoginRequest) GetNewLogin() string {
+	if x != nil {
+		if x.xxx_hidden_NewLogin != nil {
+			return *x.xxx_hidden_NewLogin
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ChangeLoginRequest) GetHandshakeId() string {
+	if x != nil {
+		if x.xxx_hidden_HandshakeId != nil {
+			return *x.xxx_hidden_HandshakeId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ChangeLoginRequest) GetProof() []byte {
+	if x != nil {
+		return x.xxx_hidden_Proof
+	}
+	return nil
+}
+
+func (x *ChangeLoginRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 4)
+}
+
+func (x *ChangeLoginRequest) SetNewLogin(v string) {
+	x.xxx_hidden_NewLogin = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 4)
+}
+
+func (x *ChangeLoginRequest) SetHandshakeId(v string) {
+	x.xxx_hidden_HandshakeId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 4)
+}
+
+func (x *ChangeLoginRequest) SetProof(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Proof = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 4)
+}
+
+func (x *ChangeLoginRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ChangeLoginRequest) HasNewLogin() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ChangeLoginRequest) HasHandshakeId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *ChangeLoginRequest) HasProof() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *ChangeLoginRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *ChangeLoginRequest) ClearNewLogin() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_NewLogin = nil
+}
+
+func (x *ChangeLoginRequest) ClearHandshakeId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_HandshakeId = nil
+}
+
+func (x *ChangeLoginRequest) ClearProof() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Proof = nil
+}
+
+type ChangeLoginRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Password    *string
+	NewLogin    *string
+	HandshakeId *string
+	Proof       []byte
+}
+
+func (b0 ChangeLoginRequest_builder) Build() *ChangeLoginRequest {
+	m0 := &ChangeLoginRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 4)
+		x.xxx_hidden_Password = b.Password
+	}
+	if b.NewLogin != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 4)
+		x.xxx_hidden_NewLogin = b.NewLogin
+	}
+	if b.HandshakeId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 4)
+		x.xxx_hidden_HandshakeId = b.HandshakeId
+	}
+	if b.Proof != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 4)
+		x.xxx_hidden_Proof = b.Proof
+	}
+	return m0
+}
+
+type ChangeLoginResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeLoginResponse) Reset() {
+	*x = ChangeLoginResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeLoginResponse) ProtoMessage() {}
+
+func (x *ChangeLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type ChangeLoginResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 ChangeLoginResponse_builder) Build() *ChangeLoginResponse {
+	m0 := &ChangeLoginResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type DeleteAccountRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Password    *string                `protobuf:"bytes,1,opt,name=password"`
+	xxx_hidden_HandshakeId *string                `protobuf:"bytes,2,opt,name=handshake_id,json=handshakeId"`
+	xxx_hidden_Proof       []byte                 `protobuf:"bytes,3,opt,name=proof"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequest) Reset() {
+	*x = DeleteAccountRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequest) ProtoMessage() {}
+
+func (x *DeleteAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *DeleteAccountRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *DeleteAccountRequest) GetHandshakeId() string {
+	if x != nil {
+		if x.xxx_hidden_HandshakeId != nil {
+			return *x.xxx_hidden_HandshakeId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *DeleteAccountRequest) GetProof() []byte {
+	if x != nil {
+		return x.xxx_hidden_Proof
+	}
+	return nil
+}
+
+func (x *DeleteAccountRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *DeleteAccountRequest) SetHandshakeId(v string) {
+	x.xxx_hidden_HandshakeId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *DeleteAccountRequest) SetProof(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Proof = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *DeleteAccountRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *DeleteAccountRequest) HasHandshakeId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *DeleteAccountRequest) HasProof() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *DeleteAccountRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *DeleteAccountRequest) ClearHandshakeId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_HandshakeId = nil
+}
+
+func (x *DeleteAccountRequest) ClearProof() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Proof = nil
+}
+
+type DeleteAccountRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Password    *string
+	HandshakeId *string
+	Proof       []byte
+}
+
+func (b0 DeleteAccountRequest_builder) Build() *DeleteAccountRequest {
+	m0 := &DeleteAccountRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_Password = b.Password
+	}
+	if b.HandshakeId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_HandshakeId = b.HandshakeId
+	}
+	if b.Proof != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Proof = b.Proof
+	}
+	return m0
+}
+
+type DeleteAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountResponse) Reset() {
+	*x = DeleteAccountResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountResponse) ProtoMessage() {}
+
+func (x *DeleteAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type DeleteAccountResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 DeleteAccountResponse_builder) Build() *DeleteAccountResponse {
+	m0 := &DeleteAccountResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type SetRecoveryRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Recovery    []byte                 `protobuf:"bytes,1,opt,name=recovery"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *SetRecoveryRequest) Reset() {
+	*x = SetRecoveryRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRecoveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRecoveryRequest) ProtoMessage() {}
+
+func (x *SetRecoveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetRecoveryRequest) GetRecovery() []byte {
+	if x != nil {
+		return x.xxx_hidden_Recovery
+	}
+	return nil
+}
+
+func (x *SetRecoveryRequest) SetRecovery(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Recovery = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+}
+
+func (x *SetRecoveryRequest) HasRecovery() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *SetRecoveryRequest) ClearRecovery() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Recovery = nil
+}
+
+type SetRecoveryRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Recovery []byte
+}
+
+func (b0 SetRecoveryRequest_builder) Build() *SetRecoveryRequest {
+	m0 := &SetRecoveryRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Recovery != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		x.xxx_hidden_Recovery = b.Recovery
+	}
+	return m0
+}
+
+type SetRecoveryResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRecoveryResponse) Reset() {
+	*x = SetRecoveryResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRecoveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRecoveryResponse) ProtoMessage() {}
+
+func (x *SetRecoveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type SetRecoveryResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 SetRecoveryResponse_builder) Build() *SetRecoveryResponse {
+	m0 := &SetRecoveryResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type GetRecoveryRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecoveryRequest) Reset() {
+	*x = GetRecoveryRequest{}
+	mi := &file_app_api_proto_user_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecoveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecoveryRequest) ProtoMessage() {}
+
+func (x *GetRecoveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type GetRecoveryRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 GetRecoveryRequest_builder) Build() *GetRecoveryRequest {
+	m0 := &GetRecoveryRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type GetRecoveryResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Recovery    []byte                 `protobuf:"bytes,1,opt,name=recovery"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetRecoveryResponse) Reset() {
+	*x = GetRecoveryResponse{}
+	mi := &file_app_api_proto_user_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecoveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecoveryResponse) ProtoMessage() {}
+
+func (x *GetRecoveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_api_proto_user_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetRecoveryResponse) GetRecovery() []byte {
+	if x != nil {
+		return x.xxx_hidden_Recovery
+	}
+	return nil
+}
+
+func (x *GetRecoveryResponse) SetRecovery(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Recovery = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+}
+
+func (x *GetRecoveryResponse) HasRecovery() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *GetRecoveryResponse) ClearRecovery() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Recovery = nil
+}
+
+type GetRecoveryResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Recovery []byte
+}
+
+func (b0 GetRecoveryResponse_builder) Build() *GetRecoveryResponse {
+	m0 := &GetRecoveryResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Recovery != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		x.xxx_hidden_Recovery = b.Recovery
+	}
+	return m0
+}
+
+var File_app_api_proto_user_proto protoreflect.FileDescriptor
+
+const file_app_api_proto_user_proto_rawDesc = "" +
+	"\n" +
+	"\x18app/api/proto/user.proto\x12\x02go\x1a!google/protobuf/go_features.proto\"\xc1\x01\n" +
+	"\x04User\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\fR\tsignature\x12\x12\n" +
+	"\x04salt\x18\x06 \x01(\fR\x04salt\x12\x1a\n" +
+	"\bverifier\x18\a \x01(\fR\bverifier\"_\n" +
+	"\x17RegisterVerifierRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\fR\x04salt\x12\x1a\n" +
+	"\bverifier\x18\x03 \x01(\fR\bverifier\"H\n" +
+	"\x11BeginLoginRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\fR\tpublicKey\"j\n" +
+	"\x12BeginLoginResponse\x12!\n" +
+	"\fhandshake_id\x18\x01 \x01(\tR\vhandshakeId\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\fR\x04salt\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\fR\tpublicKey\"\xbc\x01\n" +
+	"\x12FinishLoginRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12!\n" +
+	"\fhandshake_id\x18\x02 \x01(\tR\vhandshakeId\x12\x14\n" +
+	"\x05proof\x18\x03 \x01(\fR\x05proof\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\fR\tsignature\"b\n" +
+	"\x13FinishLoginResponse\x12(\n" +
+	"\x06tokens\x18\x01 \x01(\v2\x10.go.UserResponseR\x06tokens\x12!\n" +
+	"\fserver_proof\x18\x02 \x01(\fR\vserverProof\"h\n" +
+	"\fUserResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\"5\n" +
+	"\x0eRefreshRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x9c\x01\n" +
+	"\x15ChangePasswordRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12!\n" +
+	"\fhandshake_id\x18\x03 \x01(\tR\vhandshakeId\x12\x14\n" +
+	"\x05proof\x18\x04 \x01(\fR\x05proof\x12\x12\n" +
+	"\x04salt\x18\x05 \x01(\fR\x04salt\x12\x1a\n" +
+	"\bverifier\x18\x06 \x01(\fR\bverifier\"\x18\n" +
+	"\x16ChangePasswordResponse\"\x86\x01\n" +
+	"\x12ChangeLoginRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x1b\n" +
+	"\tnew_login\x18\x02 \x01(\tR\bnewLogin\x12!\n" +
+	"\fhandshake_id\x18\x03 \x01(\tR\vhandshakeId\x12\x14\n" +
+	"\x05proof\x18\x04 \x01(\fR\x05proof\"\x15\n" +
+	"\x13ChangeLoginResponse\"k\n" +
+	"\x14DeleteAccountRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12!\n" +
+	"\fhandshake_id\x18\x02 \x01(\tR\vhandshakeId\x12\x14\n" +
+	"\x05proof\x18\x03 \x01(\fR\x05proof\"\x17\n" +
+	"\x15DeleteAccountResponse\"0\n" +
+	"\x12SetRecoveryRequest\x12\x1a\n" +
+	"\brecovery\x18\x01 \x01(\fR\brecovery\"\x15\n" +
+	"\x13SetRecoveryResponse\"\x14\n" +
+	"\x12GetRecoveryRequest\"1\n" +
+	"\x13GetRecoveryResponse\x12\x1a\n" +
+	"\brecovery\x18\x01 \x01(\fR\brecovery2\x94\x05\n" +
+	"\x05Users\x12#\n" +
+	"\x05Login\x12\b.go.User\x1a\x10.go.UserResponse\x12&\n" +
+	"\bRegister\x12\b.go.User\x1a\x10.go.UserResponse\x12A\n" +
+	"\x10RegisterVerifier\x12\x1b.go.RegisterVerifierRequest\x1a\x10.go.UserResponse\x12;\n" +
+	"\n" +
+	"BeginLogin\x12\x15.go.BeginLoginRequest\x1a\x16.go.BeginLoginResponse\x12>\n" +
+	"\vFinishLogin\x12\x16.go.FinishLoginRequest\x1a\x17.go.FinishLoginResponse\x12/\n" +
+	"\aRefresh\x12\x12.go.RefreshRequest\x1a\x10.go.UserResponse\x12G\n" +
+	"\x0eChangePassword\x12\x19.go.ChangePasswordRequest\x1a\x1a.go.ChangePasswordResponse\x12>\n" +
+	"\vChangeLogin\x12\x16.go.ChangeLoginRequest\x1a\x17.go.ChangeLoginResponse\x12D\n" +
+	"\rDeleteAccount\x12\x18.go.DeleteAccountRequest\x1a\x19.go.DeleteAccountResponse\x12>\n" +
+	"\vSetRecovery\x12\x16.go.SetRecoveryRequest\x1a\x17.go.SetRecoveryResponse\x12>\n" +
+	"\vGetRecovery\x12\x16.go.GetRecoveryRequest\x1a\x17.go.GetRecoveryResponseB\rZ\x03/pb\x92\x03\x05\xd2>\x02\x10\x03b\beditionsp\xe8\a"
 
-var file_app_api_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_app_api_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_app_api_proto_user_proto_goTypes = []any{
-	(*User)(nil),         // 0: go.User
-	(*UserResponse)(nil), // 1: go.UserResponse
+	(*User)(nil),                    // 0: go.User
+	(*RegisterVerifierRequest)(nil), // 1: go.RegisterVerifierRequest
+	(*BeginLoginRequest)(nil),       // 2: go.BeginLoginRequest
+	(*BeginLoginResponse)(nil),      // 3: go.BeginLoginResponse
+	(*FinishLoginRequest)(nil),      // 4: go.FinishLoginRequest
+	(*FinishLoginResponse)(nil),     // 5: go.FinishLoginResponse
+	(*UserResponse)(nil),            // 6: go.UserResponse
+	(*RefreshRequest)(nil),          // 7: go.RefreshRequest
+	(*ChangePasswordRequest)(nil),   // 8: go.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),  // 9: go.ChangePasswordResponse
+	(*ChangeLoginRequest)(nil),      // 10: go.ChangeLoginRequest
+	(*ChangeLoginResponse)(nil),     // 11: go.ChangeLoginResponse
+	(*DeleteAccountRequest)(nil),    // 12: go.DeleteAccountRequest
+	(*DeleteAccountResponse)(nil),   // 13: go.DeleteAccountResponse
+	(*SetRecoveryRequest)(nil),      // 14: go.SetRecoveryRequest
+	(*SetRecoveryResponse)(nil),     // 15: go.SetRecoveryResponse
+	(*GetRecoveryRequest)(nil),      // 16: go.GetRecoveryRequest
+	(*GetRecoveryResponse)(nil),     // 17: go.GetRecoveryResponse
 }
 var file_app_api_proto_user_proto_depIdxs = []int32{
-	0, // 0: go.Users.Login:input_type -> go.User
-	0, // 1: go.Users.Register:input_type -> go.User
-	1, // 2: go.Users.Login:output_type -> go.UserResponse
-	1, // 3: go.Users.Register:output_type -> go.UserResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6,  // 0: go.FinishLoginResponse.tokens:type_name -> go.UserResponse
+	0,  // 1: go.Users.Login:input_type -> go.User
+	0,  // 2: go.Users.Register:input_type -> go.User
+	1,  // 3: go.Users.RegisterVerifier:input_type -> go.RegisterVerifierRequest
+	2,  // 4: go.Users.BeginLogin:input_type -> go.BeginLoginRequest
+	4,  // 5: go.Users.FinishLogin:input_type -> go.FinishLoginRequest
+	7,  // 6: go.Users.Refresh:input_type -> go.RefreshRequest
+	8,  // 7: go.Users.ChangePassword:input_type -> go.ChangePasswordRequest
+	10, // 8: go.Users.ChangeLogin:input_type -> go.ChangeLoginRequest
+	12, // 9: go.Users.DeleteAccount:input_type -> go.DeleteAccountRequest
+	14, // 10: go.Users.SetRecovery:input_type -> go.SetRecoveryRequest
+	16, // 11: go.Users.GetRecovery:input_type -> go.GetRecoveryRequest
+	6,  // 12: go.Users.Login:output_type -> go.UserResponse
+	6,  // 13: go.Users.Register:output_type -> go.UserResponse
+	6,  // 14: go.Users.RegisterVerifier:output_type -> go.UserResponse
+	3,  // 15: go.Users.BeginLogin:output_type -> go.BeginLoginResponse
+	5,  // 16: go.Users.FinishLogin:output_type -> go.FinishLoginResponse
+	6,  // 17: go.Users.Refresh:output_type -> go.UserResponse
+	9,  // 18: go.Users.ChangePassword:output_type -> go.ChangePasswordResponse
+	11, // 19: go.Users.ChangeLogin:output_type -> go.ChangeLoginResponse
+	13, // 20: go.Users.DeleteAccount:output_type -> go.DeleteAccountResponse
+	15, // 21: go.Users.SetRecovery:output_type -> go.SetRecoveryResponse
+	17, // 22: go.Users.GetRecovery:output_type -> go.GetRecoveryResponse
+	12, // [12:23] is the sub-list for method output_type
+	1,  // [1:12] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_app_api_proto_user_proto_init() }
@@ -253,7 +2302,7 @@ func file_app_api_proto_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_api_proto_user_proto_rawDesc), len(file_app_api_proto_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
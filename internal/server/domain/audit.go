@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/beevik/guid"
+)
+
+const (
+	// AuditLogin successful login
+	AuditLogin = "login"
+	// AuditLoginFailed wrong login or password
+	AuditLoginFailed = "login_failed"
+	// AuditLoginLocked login attempt while login or peer is locked out
+	AuditLoginLocked = "login_locked"
+	// AuditRegistered new account
+	AuditRegistered = "registered"
+	// AuditTokenRefreshed refresh token exchanged for new tokens
+	AuditTokenRefreshed = "token_refreshed"
+	// AuditPasswordChanged user changed password
+	AuditPasswordChanged = "password_changed"
+	// AuditLoginChanged user renamed login, previous login is the reason
+	AuditLoginChanged = "login_changed"
+	// AuditAccountDeleted user or administrator deleted account
+	AuditAccountDeleted = "account_deleted"
+	// AuditAccountDisabled administrator disabled account
+	AuditAccountDisabled = "account_disabled"
+	// AuditAccountEnabled administrator enabled account
+	AuditAccountEnabled = "account_enabled"
+	// AuditDeviceRegistered new device registered, it waits for approval unless it's the first one
+	AuditDeviceRegistered = "device_registered"
+	// AuditDeviceApproved pending device approved by another device of the user, approved device is the reason
+	AuditDeviceApproved = "device_approved"
+	// AuditPushed secret pushed, one event per secret of the push
+	AuditPushed = "pushed"
+	// AuditPulled secrets pulled, one event per pull
+	AuditPulled = "pulled"
+	// AuditDownloaded file of the secret downloaded
+	AuditDownloaded = "downloaded"
+)
+
+// AuditEvent security relevant action
+type AuditEvent struct {
+	// ID position of the event in audit trail, set on insert
+	ID   int64
+	Type string
+	// UserID empty when user is unknown
+	UserID *guid.Guid
+	Login  string
+	// DeviceID empty when request isn't made by a registered device
+	DeviceID *guid.Guid
+	// Client id of the client installation
+	Client string
+	// Peer address of the client
+	Peer string
+	// SecretID secret pushed or downloaded
+	SecretID *guid.Guid
+	// Version version of the pushed or downloaded secret, sync version pulled up to
+	Version int32
+	// Bytes size of data sent or received
+	Bytes  int64
+	Reason string
+	At     time.Time
+}
+
+// AuditLog record security events
+type AuditLog interface {
+	Record(ctx context.Context, event *AuditEvent)
+}
+
+// AuditFilter which events to read, zero fields don't filter
+type AuditFilter struct {
+	UserID *guid.Guid
+	// Login events of the login, including failed logins of unknown user
+	Login string
+	Since time.Time
+	// BeforeID events older than the id, next page starts before the last id of the previous one
+	BeforeID int64
+	Limit    int32
+}
+
+// AuditRepository append-only audit trail, events are never changed or removed
+type AuditRepository interface {
+	Insert(ctx context.Context, event *AuditEvent) error
+	// Find events matching the filter, newest first
+	Find(ctx context.Context, filter *AuditFilter) ([]*AuditEvent, error)
+}
@@ -2,6 +2,8 @@
 package auth
 
 import (
+	"context"
+
 	"github.com/beevik/guid"
 	"github.com/golang-jwt/jwt/v4"
 )
@@ -9,10 +11,24 @@ import (
 // Claims user information
 type Claims struct {
 	jwt.RegisteredClaims
+	// DeviceID device the token is bound to, empty for tokens issued without device
+	DeviceID string `json:"did,omitempty"`
+	// SessionID session the token is issued for, empty for tokens issued before sessions
+	SessionID string `json:"sid,omitempty"`
 }
 
 // Engine Authentification engine
 type Engine interface {
-	GenerateToken(userID guid.Guid) (string, error)
+	GenerateToken(userID guid.Guid, deviceID, sessionID string) (string, error)
 	ReadToken(tokenString string) (*Claims, error)
 }
+
+// DeviceVerifier check the device of a token is not revoked
+type DeviceVerifier interface {
+	VerifyDevice(ctx context.Context, userID, deviceID guid.Guid) error
+}
+
+// SessionVerifier check the session of a token is not revoked
+type SessionVerifier interface {
+	VerifySession(ctx context.Context, userID, sessionID guid.Guid) error
+}
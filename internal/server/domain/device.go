@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/beevik/guid"
+)
+
+// Device client installation of the user. Tokens issued for the device stop working once it is revoked.
+// SyncVersion is version of own and shared secrets the device pulled up to
+type Device struct {
+	ID          guid.Guid
+	CreatedAt   time.Time
+	UserID      guid.Guid
+	Name        string
+	PublicKey   []byte
+	LastSeenAt  *time.Time
+	SyncVersion int32
+	RevokedAt   *time.Time
+	// ApprovedAt empty while the device waits for approval
+	ApprovedAt *time.Time
+}
+
+func (d *Device) Revoked() bool {
+	return d.RevokedAt != nil
+}
+
+func (d *Device) Pending() bool {
+	return d.ApprovedAt == nil
+}
+
+// DeviceProof login signed by device key
+type DeviceProof struct {
+	DeviceID  guid.Guid
+	Timestamp time.Time
+	Signature []byte
+}
+
+type DeviceRepository interface {
+	Get(ctx context.Context, id guid.Guid) (*Device, error)
+	GetAll(ctx context.Context, userID guid.Guid) ([]*Device, error)
+	Insert(ctx context.Context, device *Device) error
+	Rename(ctx context.Context, id, userID guid.Guid, name string) error
+	Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error
+	// Approve approve pending device, ErrResourceNotFound when there is no such pending device
+	Approve(ctx context.Context, id, userID guid.Guid, at time.Time) error
+	Touch(ctx context.Context, id guid.Guid, at time.Time) error
+	UpdateSyncVersion(ctx context.Context, id guid.Guid, version int32) error
+	// UpdateVaultSyncVersion remember version of the vault the device pulled up to
+	UpdateVaultSyncVersion(ctx context.Context, id, vaultID guid.Guid, version int32) error
+}
@@ -1,11 +1,34 @@
 // Package domain
 package domain
 
-import "io"
+import (
+	"io"
+	"time"
+)
+
+// Blob stored file of the secret version
+type Blob struct {
+	Name       string
+	Version    int32
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// BlobWriter file being written. Close stores the file, Abort drops everything written instead
+type BlobWriter interface {
+	io.WriteCloser
+	Abort() error
+}
 
 type Filer interface {
 	OpenRead(fileName string, version int32, dst ...string) (io.ReadCloser, error)
-	OpenWrite(fileName string, version int32, dst ...string) (io.WriteCloser, error)
+	// OpenWrite start writing the version of the file, file written before under the version is replaced
+	OpenWrite(fileName string, version int32, dst ...string) (BlobWriter, error)
 
 	Remove(fileName string, version int32, dst ...string) error
+
+	// Stat stored file of the version, error satisfies os.IsNotExist when there is no file
+	Stat(fileName string, version int32) (*Blob, error)
+	// Walk call fn for every stored file, walk stops on the first error
+	Walk(fn func(blob *Blob) error) error
 }
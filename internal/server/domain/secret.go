@@ -32,11 +32,64 @@ type Secret struct {
 	Path       string
 	Version    int32
 	Deleted    bool
+	// Owner login of the owner, set for secrets shared with the user only
+	Owner string
+	// DataVersion version payload is bound to, differs from Version for shared secrets
+	DataVersion int32
+	// VaultID vault the secret belongs to, nil for own secrets of the user
+	VaultID *guid.Guid
+	// Size bytes of the file of big data secret
+	Size int64
+	// ChargedTo user whose quota the secret counts to, the one who pushed it last
+	ChargedTo guid.Guid
 }
+
+// SecretUsage storage taken by live secrets charged to the user
+type SecretUsage struct {
+	Secrets   int64
+	BlobBytes int64
+}
+
+// SecretStats secrets charged to the user, vault secrets count to the user who pushed them last
+type SecretStats struct {
+	UserID     guid.Guid
+	Records    int64
+	Tombstones int64
+	// PayloadBytes size of payload and dek stored in database, files aren't counted
+	PayloadBytes int64
+}
+
+// SecretCursor position in secrets ordered by version and id
+type SecretCursor struct {
+	Version int32
+	ID      guid.Guid
+}
+
 type SecretRepository interface {
 	Get(ctx context.Context, id guid.Guid) (*Secret, error)
+	// GetAll read secrets of the user and secrets shared with the user
 	GetAll(ctx context.Context, userID guid.Guid, greaterThan int32) ([]*Secret, error)
+	// GetPage read secrets after cursor with version not greater than until, shared secrets are included
+	GetPage(ctx context.Context, userID guid.Guid, after SecretCursor, until int32, limit int32) ([]*Secret, error)
+	// GetVaultAll read secrets of the vault
+	GetVaultAll(ctx context.Context, vaultID guid.Guid, greaterThan int32) ([]*Secret, error)
+	// GetVaultPage read secrets of the vault after cursor with version not greater than until
+	GetVaultPage(ctx context.Context, vaultID guid.Guid, after SecretCursor, until int32, limit int32) ([]*Secret, error)
+	// Insert new secret is charged to its owner
 	Insert(ctx context.Context, data *Secret) error
 	Update(ctx context.Context, data *Secret) error
 	Delete(ctx context.Context, data *Secret) error
+	// DeleteAll remove own secrets of the user, secrets the user pushed to vaults stay. Returned secrets have only id, version and big data flag
+	DeleteAll(ctx context.Context, userID guid.Guid) ([]*Secret, error)
+	// GetBigData secrets stored as files, only id, user, charged user, vault, version and deleted flag are read
+	GetBigData(ctx context.Context) ([]*Secret, error)
+	// Stats count secrets charged to every user
+	Stats(ctx context.Context) ([]*SecretStats, error)
+	// Usage count live secrets charged to the user and size of their files
+	Usage(ctx context.Context, userID guid.Guid) (*SecretUsage, error)
+	// GetPurgeable deleted secrets PurgeDeleted would remove, only id, version and big data flag are read
+	GetPurgeable(ctx context.Context, before time.Time) ([]*Secret, error)
+	// PurgeDeleted remove deleted secrets modified before the time once every device of the owner and of recipients
+	// has pulled them. Returned secrets have only id, version and big data flag
+	PurgeDeleted(ctx context.Context, before time.Time) ([]*Secret, error)
 }
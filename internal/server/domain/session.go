@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/beevik/guid"
+)
+
+// Session login of the user kept alive by refresh tokens. Access tokens of the session stop working once it is revoked
+type Session struct {
+	ID         guid.Guid
+	CreatedAt  time.Time
+	UserID     guid.Guid
+	DeviceID   *guid.Guid
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+func (s *Session) Revoked() bool {
+	return s.RevokedAt != nil
+}
+
+// RefreshToken one-time token of the session, only hash of the token is stored
+type RefreshToken struct {
+	Hash      []byte
+	SessionID guid.Guid
+	UsedAt    *time.Time
+}
+
+// Tokens issued on login and refresh
+type Tokens struct {
+	Access    string
+	Refresh   string
+	ExpiresAt time.Time
+}
+
+type SessionRepository interface {
+	Get(ctx context.Context, id guid.Guid) (*Session, error)
+	// GetActive sessions of the user not revoked and not expired
+	GetActive(ctx context.Context, userID guid.Guid, now time.Time) ([]*Session, error)
+	Insert(ctx context.Context, session *Session) error
+	Extend(ctx context.Context, id guid.Guid, usedAt, expiresAt time.Time) error
+	Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error
+	GetToken(ctx context.Context, hash []byte) (*RefreshToken, error)
+	InsertToken(ctx context.Context, token *RefreshToken) error
+	// UseToken mark token as used, ErrResourceNotFound when it was used already
+	UseToken(ctx context.Context, hash []byte, at time.Time) error
+}
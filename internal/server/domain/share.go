@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/beevik/guid"
+)
+
+// Share secret given by the owner to another user. Dek of the secret is sealed to recipient public key,
+// recipient gets the secret with the pull under their own version
+type Share struct {
+	SecretID    guid.Guid
+	OwnerID     guid.Guid
+	RecipientID guid.Guid
+	// Recipient login of the recipient, filled on read
+	Recipient string
+	// PublicKey public key of the recipient, filled on read
+	PublicKey []byte
+	Dek       []byte
+	Version   int32
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+func (s *Share) Revoked() bool {
+	return s.RevokedAt != nil
+}
+
+type ShareRepository interface {
+	// Get share of the secret with the recipient, revoked one too
+	Get(ctx context.Context, secretID, recipientID guid.Guid) (*Share, error)
+	// GetBySecret active shares of the secret
+	GetBySecret(ctx context.Context, secretID guid.Guid) ([]*Share, error)
+	// GetByOwner active shares of all secrets of the owner
+	GetByOwner(ctx context.Context, ownerID guid.Guid) ([]*Share, error)
+	// Save insert share or renew revoked one
+	Save(ctx context.Context, share *Share) error
+	// Update set new dek and version of the share
+	Update(ctx context.Context, share *Share) error
+	Revoke(ctx context.Context, secretID, recipientID guid.Guid, version int32, at time.Time) error
+}
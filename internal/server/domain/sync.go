@@ -9,11 +9,18 @@ import (
 type SyncState struct {
 	ID     string
 	UserID guid.Guid
-	Value  int32
+	// VaultID vault the state versions, nil for the state of the user
+	VaultID *guid.Guid
+	Value   int32
 }
 
 type SyncStateRepository interface {
 	Get(ctx context.Context, id string, user guid.Guid) (*SyncState, error)
+	// GetVault state of the vault, it's removed with the vault
+	GetVault(ctx context.Context, vaultID guid.Guid) (*SyncState, error)
+	// Insert write state of the vault when VaultID is set, of the user otherwise
 	Insert(ctx context.Context, state *SyncState) error
+	// Update write state of the vault when VaultID is set, of the user otherwise
 	Update(ctx context.Context, syncState *SyncState) error
+	DeleteAll(ctx context.Context, userID guid.Guid) error
 }
@@ -10,5 +10,15 @@ type UnitOfWork interface {
 
 	SyncStateRepository() SyncStateRepository
 
+	DeviceRepository() DeviceRepository
+
+	SessionRepository() SessionRepository
+
+	ShareRepository() ShareRepository
+
+	VaultRepository() VaultRepository
+
+	AuditRepository() AuditRepository
+
 	Tx(ctx context.Context, fn func(ctx context.Context, work UnitOfWork) error) error
 }
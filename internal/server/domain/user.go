@@ -12,8 +12,16 @@ type User struct {
 	ID        guid.Guid
 	CreatedAt *time.Time
 	Login     string
-	Password  []byte
-	Salt      []byte
+	// Password argon2 hash of legacy account, empty once SRP verifier is set
+	Password []byte
+	// Salt of the password hash or of the verifier
+	Salt []byte
+	// Verifier SRP verifier of the password
+	Verifier []byte
+	// PublicKey X25519 key secrets are shared to, nil until the client publishes it
+	PublicKey []byte
+	// DisabledAt set when administrator disabled the account, disabled user can't login
+	DisabledAt *time.Time
 }
 
 func NewUser(login string, pass, salt []byte) *User {
@@ -26,13 +34,72 @@ func NewUser(login string, pass, salt []byte) *User {
 
 type UserRepository interface {
 	Get(ctx context.Context, login string) (*User, error)
+	GetByID(ctx context.Context, id guid.Guid) (*User, error)
+	// List all users ordered by login
+	List(ctx context.Context) ([]*User, error)
 	Exist(ctx context.Context, login string) (bool, error)
 	Insert(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, id guid.Guid, password, salt []byte) error
+	// UpdateVerifier set SRP verifier, password hash is removed
+	UpdateVerifier(ctx context.Context, id guid.Guid, salt, verifier []byte) error
+	UpdateLogin(ctx context.Context, id guid.Guid, login string) error
+	UpdatePublicKey(ctx context.Context, id guid.Guid, publicKey []byte) error
+	// UpdateRecovery keep master key of the user wrapped with the recovery key
+	UpdateRecovery(ctx context.Context, id guid.Guid, recovery []byte) error
+	// GetRecovery wrapped master key of the user, nil when recovery isn't set
+	GetRecovery(ctx context.Context, id guid.Guid) ([]byte, error)
+	// SetDisabled disable account at the time, nil enables it
+	SetDisabled(ctx context.Context, id guid.Guid, at *time.Time) error
+	// Delete remove user, devices and sessions are removed by cascade
+	Delete(ctx context.Context, id guid.Guid) error
+}
+
+// Credentials proof of the current password: SRP handshake proof, or the password itself for legacy accounts
+type Credentials struct {
+	Password    string
+	HandshakeID string
+	Proof       []byte
+}
+
+// Verifier SRP verifier of the password with its salt
+type Verifier struct {
+	Salt  []byte
+	Value []byte
+}
+
+// Handshake SRP exchange started by the server
+type Handshake struct {
+	ID        string
+	Salt      []byte
+	PublicKey []byte
+}
+
+// Authenticator check credentials of the user
+type Authenticator interface {
+	Authenticate(ctx context.Context, login string, creds *Credentials) (*User, error)
 }
 
 type UserService interface {
-	// Login authenticate users
-	Login(ctx context.Context, login string, password string) (string, error)
-	// Register create new user
-	Register(ctx context.Context, login string, password string) (string, error)
+	Authenticator
+	// Login legacy login with the password. Session is bound to the device when proof is given,
+	// account without verifier is upgraded when upgrade is given
+	Login(ctx context.Context, login string, password string, proof *DeviceProof, upgrade *Verifier) (*Tokens, error)
+	// Register create new legacy user
+	Register(ctx context.Context, login string, password string) (*Tokens, error)
+	// RegisterVerifier create new user authenticated by SRP
+	RegisterVerifier(ctx context.Context, login string, verifier *Verifier) (*Tokens, error)
+	// BeginLogin start SRP handshake with client public key
+	BeginLogin(ctx context.Context, login string, publicKey []byte) (*Handshake, error)
+	// FinishLogin check client proof of the handshake and start a session, server proof is returned with tokens
+	FinishLogin(ctx context.Context, login string, creds *Credentials, proof *DeviceProof) (*Tokens, []byte, error)
+	// ChangePassword set new verifier of current user, other sessions are terminated
+	ChangePassword(ctx context.Context, creds *Credentials, verifier *Verifier) error
+	// ChangeLogin rename current user
+	ChangeLogin(ctx context.Context, creds *Credentials, newLogin string) error
+	// DeleteAccount remove current user with all secrets, sync state and files
+	DeleteAccount(ctx context.Context, creds *Credentials) error
+	// SetRecovery keep master key of current user wrapped with the recovery key, server can't open it
+	SetRecovery(ctx context.Context, recovery []byte) error
+	// GetRecovery wrapped master key of current user, restore on a new device starts with it
+	GetRecovery(ctx context.Context) ([]byte, error)
 }
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beevik/guid"
+)
+
+var ErrUnknownVaultRole = errors.New("unknown vault role")
+
+// VaultRole what a member may do in the vault, every role includes the ones below
+type VaultRole int
+
+const (
+	// ReaderRole pull secrets of the vault
+	ReaderRole VaultRole = iota
+	// WriterRole push secrets to the vault
+	WriterRole
+	// AdminRole invite members and change their roles
+	AdminRole
+	// OwnerRole creator of the vault, the only one who can't leave it
+	OwnerRole
+)
+
+func (r VaultRole) String() string {
+	return [...]string{"reader", "writer", "admin", "owner"}[r]
+}
+
+func ParseVaultRole(role string) (VaultRole, error) {
+	switch role {
+	case "reader":
+		return ReaderRole, nil
+	case "writer":
+		return WriterRole, nil
+	case "admin":
+		return AdminRole, nil
+	case "owner":
+		return OwnerRole, nil
+	}
+	return ReaderRole, ErrUnknownVaultRole
+}
+
+// Vault secrets of a team synced by every member. Vault has its own sync state, secrets are encrypted
+// with the vault key which is sealed to public key of every member
+type Vault struct {
+	ID        guid.Guid
+	Name      string
+	CreatedBy guid.Guid
+	CreatedAt time.Time
+}
+
+type VaultMember struct {
+	VaultID guid.Guid
+	UserID  guid.Guid
+	Role    VaultRole
+	// VaultKey key of the vault sealed to public key of the member
+	VaultKey  []byte
+	CreatedAt time.Time
+	// VaultName name of the vault, filled on read
+	VaultName string
+	// Login login of the member, filled on read
+	Login string
+	// PublicKey public key of the member, filled on read
+	PublicKey []byte
+}
+
+type VaultRepository interface {
+	Insert(ctx context.Context, vault *Vault) error
+	// GetMember membership of the user in the vault, ErrResourceNotFound when the user isn't a member
+	GetMember(ctx context.Context, vaultID, userID guid.Guid) (*VaultMember, error)
+	// GetMembers members of the vault ordered by login
+	GetMembers(ctx context.Context, vaultID guid.Guid) ([]*VaultMember, error)
+	// GetByUser memberships of the user ordered by vault name
+	GetByUser(ctx context.Context, userID guid.Guid) ([]*VaultMember, error)
+	// SaveMember add member or change role and key of existing one
+	SaveMember(ctx context.Context, member *VaultMember) error
+	DeleteMember(ctx context.Context, vaultID, userID guid.Guid) error
+	// Delete remove the vault with its members and secrets
+	Delete(ctx context.Context, vaultID guid.Guid) error
+}
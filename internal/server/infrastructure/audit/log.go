@@ -0,0 +1,33 @@
+// Package audit security events journal
+package audit
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/shared/logging"
+	"go.uber.org/zap"
+)
+
+// LogAuditLog write security events to server log
+type LogAuditLog struct{}
+
+func NewLogAuditLog() *LogAuditLog {
+	return &LogAuditLog{}
+}
+
+func (LogAuditLog) Record(ctx context.Context, event *domain.AuditEvent) {
+	fields := []zap.Field{
+		zap.String("event", event.Type),
+		zap.String("login", event.Login),
+		zap.String("peer", event.Peer),
+		zap.Time("at", event.At),
+	}
+	if event.UserID != nil {
+		fields = append(fields, zap.String("user", event.UserID.String()))
+	}
+	if event.Reason != "" {
+		fields = append(fields, zap.String("reason", event.Reason))
+	}
+	logging.Logger(ctx).Warn("audit", fields...)
+}
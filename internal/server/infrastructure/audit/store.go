@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/shared/logging"
+	"go.uber.org/zap"
+)
+
+// StoreAuditLog write security events to audit trail in storage. Event is written to server log
+// when storage fails, so it isn't lost
+type StoreAuditLog struct {
+	uow      domain.UnitOfWork
+	fallback *LogAuditLog
+}
+
+func NewStoreAuditLog(uow domain.UnitOfWork) *StoreAuditLog {
+	return &StoreAuditLog{uow: uow, fallback: NewLogAuditLog()}
+}
+
+func (sl *StoreAuditLog) Record(ctx context.Context, event *domain.AuditEvent) {
+	// write even when the request is canceled, e.g. by a broken stream
+	if err := sl.uow.AuditRepository().Insert(context.WithoutCancel(ctx), event); err != nil {
+		logging.Logger(ctx).Error("failed to write audit event", zap.Error(err))
+		sl.fallback.Record(ctx, event)
+	}
+}
@@ -2,9 +2,12 @@
 package data
 
 import (
+	"errors"
 	"io"
+	"os"
 
 	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/server/domain"
 )
 
 type FileProvider struct {
@@ -19,10 +22,54 @@ func (f *FileProvider) OpenRead(fileName string, version int32, dst ...string) (
 	return f.fp.OpenRead(fileName, version, dst...)
 }
 
-func (f *FileProvider) OpenWrite(fileName string, version int32, dst ...string) (io.WriteCloser, error) {
-	return f.fp.OpenWrite(fileName, version, dst...)
+func (f *FileProvider) OpenWrite(fileName string, version int32, dst ...string) (domain.BlobWriter, error) {
+	// an aborted upload must not be appended to
+	if err := f.fp.Remove(fileName, version, dst...); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	w, err := f.fp.OpenWrite(fileName, version, dst...)
+	if err != nil {
+		return nil, err
+	}
+	return &fileWriter{WriteCloser: w, remove: func() error {
+		return f.fp.Remove(fileName, version, dst...)
+	}}, nil
 }
 
 func (f *FileProvider) Remove(fileName string, version int32, dst ...string) error {
 	return f.fp.Remove(fileName, version, dst...)
 }
+
+func (f *FileProvider) Stat(fileName string, version int32) (*domain.Blob, error) {
+	info, err := f.fp.Stat(fileName, version)
+	if err != nil {
+		return nil, err
+	}
+	return toBlob(info), nil
+}
+
+func (f *FileProvider) Walk(fn func(blob *domain.Blob) error) error {
+	return f.fp.Walk(func(info *datatool.FileInfo) error {
+		return fn(toBlob(info))
+	})
+}
+
+// fileWriter file on disk, aborted file is removed
+type fileWriter struct {
+	io.WriteCloser
+	remove func() error
+}
+
+func (fw *fileWriter) Abort() error {
+	if err := fw.WriteCloser.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return err
+	}
+	if err := fw.remove(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func toBlob(info *datatool.FileInfo) *domain.Blob {
+	return &domain.Blob{Name: info.Name, Version: info.Version, Size: info.Size, ModifiedAt: info.ModifiedAt}
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"bytes"
+	"cmp"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+// MemoryFileProvider files kept in memory of the process, for embedded servers and tests
+type MemoryFileProvider struct {
+	mu    sync.RWMutex
+	files map[string]*memoryFile
+}
+
+type memoryFile struct {
+	data       []byte
+	modifiedAt time.Time
+}
+
+func NewMemoryFileProvider() *MemoryFileProvider {
+	return &MemoryFileProvider{files: make(map[string]*memoryFile)}
+}
+
+// OpenRead file is read as it was on open, it isn't changed by writes after
+func (m *MemoryFileProvider) OpenRead(fileName string, version int32, dst ...string) (io.ReadCloser, error) {
+	key := datatool.FileName(fileName, version, dst...)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	file, ok := m.files[key]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: key, Err: fs.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(file.data)), nil
+}
+
+// OpenWrite file is stored on close, so it's never seen written halfway
+func (m *MemoryFileProvider) OpenWrite(fileName string, version int32, dst ...string) (domain.BlobWriter, error) {
+	return &memoryWriter{files: m, key: datatool.FileName(fileName, version, dst...)}, nil
+}
+
+func (m *MemoryFileProvider) Remove(fileName string, version int32, dst ...string) error {
+	key := datatool.FileName(fileName, version, dst...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[key]; !ok {
+		return &fs.PathError{Op: "remove", Path: key, Err: fs.ErrNotExist}
+	}
+	delete(m.files, key)
+	return nil
+}
+
+func (m *MemoryFileProvider) Stat(fileName string, version int32) (*domain.Blob, error) {
+	key := datatool.FileName(fileName, version)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	file, ok := m.files[key]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: key, Err: fs.ErrNotExist}
+	}
+	return &domain.Blob{Name: fileName, Version: version, Size: int64(len(file.data)), ModifiedAt: file.modifiedAt}, nil
+}
+
+// Walk call fn for every file named as a version of a file in name order, files may be removed by fn
+func (m *MemoryFileProvider) Walk(fn func(blob *domain.Blob) error) error {
+	m.mu.RLock()
+	blobs := make([]*domain.Blob, 0, len(m.files))
+	for key, file := range m.files {
+		name, version, ok := datatool.ParseFileName(key)
+		if !ok {
+			continue
+		}
+		blobs = append(blobs, &domain.Blob{Name: name, Version: version, Size: int64(len(file.data)), ModifiedAt: file.modifiedAt})
+	}
+	m.mu.RUnlock()
+	slices.SortFunc(blobs, func(a, b *domain.Blob) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+	for _, blob := range blobs {
+		if err := fn(blob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memoryWriter buffer of the file, written file replaces the stored one on close
+type memoryWriter struct {
+	files  *MemoryFileProvider
+	key    string
+	buffer bytes.Buffer
+	closed bool
+}
+
+func (mw *memoryWriter) Write(p []byte) (int, error) {
+	if mw.closed {
+		return 0, fs.ErrClosed
+	}
+	return mw.buffer.Write(p)
+}
+
+func (mw *memoryWriter) Close() error {
+	if mw.closed {
+		return fs.ErrClosed
+	}
+	mw.closed = true
+	mw.files.mu.Lock()
+	defer mw.files.mu.Unlock()
+	mw.files.files[mw.key] = &memoryFile{data: bytes.Clone(mw.buffer.Bytes()), modifiedAt: time.Now()}
+	mw.buffer.Reset()
+	return nil
+}
+
+func (mw *memoryWriter) Abort() error {
+	mw.closed = true
+	mw.buffer.Reset()
+	return nil
+}
@@ -0,0 +1,276 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/DimKa163/keeper/internal/datatool"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	// MinPartSize smallest part S3 accepts in multipart upload, the last part may be smaller
+	MinPartSize     = 5 * datatool.MB
+	DefaultPartSize = 8 * datatool.MB
+)
+
+// S3Config bucket of S3 compatible storage
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Region    string
+	Bucket    string
+	// Prefix of object keys, lets several servers share the bucket
+	Prefix string
+	Secure bool
+	// PartSize size of uploaded parts and of ranges read, DefaultPartSize when it is zero
+	PartSize int64
+}
+
+// S3FileProvider files stored as objects of S3 compatible storage. Files are uploaded in parts
+// and read by ranges, so neither is held in memory as a whole
+type S3FileProvider struct {
+	client   minio.Core
+	bucket   string
+	prefix   string
+	partSize int64
+}
+
+// NewS3FileProvider connect to the storage, bucket is created when it doesn't exist
+func NewS3FileProvider(ctx context.Context, config *S3Config) (*S3FileProvider, error) {
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: config.Secure,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exists, err := client.BucketExists(ctx, config.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err = client.MakeBucket(ctx, config.Bucket, minio.MakeBucketOptions{Region: config.Region}); err != nil {
+			return nil, err
+		}
+	}
+	partSize := config.PartSize
+	if partSize == 0 {
+		partSize = DefaultPartSize
+	}
+	if partSize < MinPartSize {
+		partSize = MinPartSize
+	}
+	return &S3FileProvider{
+		client:   minio.Core{Client: client},
+		bucket:   config.Bucket,
+		prefix:   config.Prefix,
+		partSize: partSize,
+	}, nil
+}
+
+func (s *S3FileProvider) OpenRead(fileName string, version int32, dst ...string) (io.ReadCloser, error) {
+	key := s.key(fileName, version, dst...)
+	info, err := s.client.StatObject(context.Background(), s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, toFileError("open", key, err)
+	}
+	return &rangeReader{s3: s, key: key, etag: info.ETag, size: info.Size}, nil
+}
+
+func (s *S3FileProvider) OpenWrite(fileName string, version int32, dst ...string) (domain.BlobWriter, error) {
+	return &partWriter{s3: s, key: s.key(fileName, version, dst...)}, nil
+}
+
+func (s *S3FileProvider) Remove(fileName string, version int32, dst ...string) error {
+	key := s.key(fileName, version, dst...)
+	return toFileError("remove", key, s.client.RemoveObject(context.Background(), s.bucket, key, minio.RemoveObjectOptions{}))
+}
+
+func (s *S3FileProvider) Stat(fileName string, version int32) (*domain.Blob, error) {
+	key := s.key(fileName, version)
+	info, err := s.client.StatObject(context.Background(), s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, toFileError("stat", key, err)
+	}
+	return &domain.Blob{Name: fileName, Version: version, Size: info.Size, ModifiedAt: info.LastModified}, nil
+}
+
+// Walk call fn for every object under prefix named as a version of a file, other objects are skipped
+func (s *S3FileProvider) Walk(fn func(blob *domain.Blob) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for object := range s.client.Client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix, Recursive: true}) {
+		if object.Err != nil {
+			return object.Err
+		}
+		base := strings.TrimPrefix(object.Key, s.prefix)
+		if strings.Contains(base, "/") {
+			continue
+		}
+		name, version, ok := datatool.ParseFileName(base)
+		if !ok {
+			continue
+		}
+		if err := fn(&domain.Blob{Name: name, Version: version, Size: object.Size, ModifiedAt: object.LastModified}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *S3FileProvider) key(fileName string, version int32, dst ...string) string {
+	return s.prefix + datatool.FileName(fileName, version, dst...)
+}
+
+// rangeReader read the object by ranges of part size. Every range is bound to the etag read on open,
+// so the object replaced meanwhile fails the read instead of mixing versions
+type rangeReader struct {
+	s3     *S3FileProvider
+	key    string
+	etag   string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (rr *rangeReader) Read(p []byte) (int, error) {
+	for {
+		if rr.offset >= rr.size {
+			return 0, io.EOF
+		}
+		if rr.body == nil {
+			if err := rr.next(); err != nil {
+				return 0, err
+			}
+		}
+		n, err := rr.body.Read(p)
+		rr.offset += int64(n)
+		if errors.Is(err, io.EOF) {
+			_ = rr.body.Close()
+			rr.body = nil
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// next open range starting at offset
+func (rr *rangeReader) next() error {
+	end := min(rr.offset+rr.s3.partSize, rr.size) - 1
+	var opts minio.GetObjectOptions
+	if err := opts.SetRange(rr.offset, end); err != nil {
+		return err
+	}
+	if err := opts.SetMatchETag(rr.etag); err != nil {
+		return err
+	}
+	body, _, _, err := rr.s3.client.GetObject(context.Background(), rr.s3.bucket, rr.key, opts)
+	if err != nil {
+		return toFileError("read", rr.key, err)
+	}
+	rr.body = body
+	return nil
+}
+
+func (rr *rangeReader) Close() error {
+	if rr.body == nil {
+		return nil
+	}
+	err := rr.body.Close()
+	rr.body = nil
+	return err
+}
+
+// partWriter upload the file by parts of part size. File smaller than a part is put as a single object,
+// multipart upload starts with the first full part
+type partWriter struct {
+	s3       *S3FileProvider
+	key      string
+	buffer   bytes.Buffer
+	uploadID string
+	parts    []minio.CompletePart
+}
+
+func (pw *partWriter) Write(p []byte) (int, error) {
+	n, _ := pw.buffer.Write(p)
+	for int64(pw.buffer.Len()) >= pw.s3.partSize {
+		if err := pw.upload(pw.s3.partSize); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+func (pw *partWriter) Close() error {
+	ctx := context.Background()
+	if pw.uploadID == "" {
+		size := int64(pw.buffer.Len())
+		_, err := pw.s3.client.PutObject(ctx, pw.s3.bucket, pw.key, bytes.NewReader(pw.buffer.Bytes()), size, "", "", minio.PutObjectOptions{})
+		return err
+	}
+	if pw.buffer.Len() > 0 {
+		if err := pw.upload(int64(pw.buffer.Len())); err != nil {
+			return err
+		}
+	}
+	_, err := pw.s3.client.CompleteMultipartUpload(ctx, pw.s3.bucket, pw.key, pw.uploadID, pw.parts, minio.PutObjectOptions{})
+	if err != nil {
+		return err
+	}
+	pw.uploadID = ""
+	return nil
+}
+
+func (pw *partWriter) Abort() error {
+	pw.buffer.Reset()
+	if pw.uploadID == "" {
+		return nil
+	}
+	err := pw.s3.client.AbortMultipartUpload(context.Background(), pw.s3.bucket, pw.key, pw.uploadID)
+	pw.uploadID = ""
+	return err
+}
+
+// upload send part of the size from the buffer
+func (pw *partWriter) upload(size int64) error {
+	ctx := context.Background()
+	if pw.uploadID == "" {
+		uploadID, err := pw.s3.client.NewMultipartUpload(ctx, pw.s3.bucket, pw.key, minio.PutObjectOptions{})
+		if err != nil {
+			return err
+		}
+		pw.uploadID = uploadID
+	}
+	number := len(pw.parts) + 1
+	part, err := pw.s3.client.PutObjectPart(ctx, pw.s3.bucket, pw.key, pw.uploadID, number,
+		bytes.NewReader(pw.buffer.Next(int(size))), size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return err
+	}
+	pw.parts = append(pw.parts, minio.CompletePart{PartNumber: number, ETag: part.ETag})
+	return nil
+}
+
+// toFileError missing object satisfies os.IsNotExist as missing file does
+func toFileError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	response := minio.ToErrorResponse(err)
+	if response.Code == minio.NoSuchKey || response.StatusCode == http.StatusNotFound {
+		return &fs.PathError{Op: op, Path: key, Err: fs.ErrNotExist}
+	}
+	return err
+}
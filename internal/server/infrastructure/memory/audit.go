@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+type AuditRepository struct {
+	db *view
+}
+
+func NewAuditRepository(db *view) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Insert id of the event is its position in the trail
+func (ar *AuditRepository) Insert(ctx context.Context, event *domain.AuditEvent) error {
+	return ar.db.write(ctx, func(t *tables) error {
+		stored := *event
+		stored.ID = int64(len(t.audit)) + 1
+		t.audit = append(t.audit, &stored)
+		event.ID = stored.ID
+		return nil
+	})
+}
+
+// Find login of events without one is taken from the user, it's empty when the account was deleted
+func (ar *AuditRepository) Find(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	events := make([]*domain.AuditEvent, 0)
+	_ = ar.db.read(func(t *tables) error {
+		for i := len(t.audit) - 1; i >= 0 && len(events) < int(filter.Limit); i-- {
+			stored := t.audit[i]
+			if filter.BeforeID != 0 && stored.ID >= filter.BeforeID {
+				continue
+			}
+			if filter.UserID != nil && (stored.UserID == nil || *stored.UserID != *filter.UserID) {
+				continue
+			}
+			if !filter.Since.IsZero() && stored.At.Before(filter.Since) {
+				continue
+			}
+			var login string
+			if stored.UserID != nil {
+				if user, ok := t.users[*stored.UserID]; ok {
+					login = user.Login
+				}
+			}
+			if filter.Login != "" && stored.Login != filter.Login && login != filter.Login {
+				continue
+			}
+			event := *stored
+			if event.Login == "" {
+				event.Login = login
+			}
+			events = append(events, &event)
+		}
+		return nil
+	})
+	return events, nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type DeviceRepository struct {
+	db *view
+}
+
+func NewDeviceRepository(db *view) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+func (dr *DeviceRepository) Get(ctx context.Context, id guid.Guid) (*domain.Device, error) {
+	var device domain.Device
+	err := dr.db.read(func(t *tables) error {
+		found, ok := t.devices[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		device = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (dr *DeviceRepository) GetAll(ctx context.Context, userID guid.Guid) ([]*domain.Device, error) {
+	devices := make([]*domain.Device, 0)
+	_ = dr.db.read(func(t *tables) error {
+		for _, device := range t.devices {
+			if device.UserID == userID {
+				item := *device
+				devices = append(devices, &item)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(devices, func(a, b *domain.Device) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return compareGuid(a.ID, b.ID)
+	})
+	return devices, nil
+}
+
+func (dr *DeviceRepository) Insert(ctx context.Context, device *domain.Device) error {
+	stored := &domain.Device{
+		ID:         *guid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UserID:     device.UserID,
+		Name:       device.Name,
+		PublicKey:  bytes.Clone(device.PublicKey),
+		ApprovedAt: device.ApprovedAt,
+	}
+	err := dr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.users[device.UserID]; !ok {
+			return fmt.Errorf("%w: user %s", ErrReference, device.UserID)
+		}
+		t.devices[stored.ID] = stored
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	device.ID = stored.ID
+	device.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (dr *DeviceRepository) Rename(ctx context.Context, id, userID guid.Guid, name string) error {
+	return dr.update(ctx, id, func(device *domain.Device) bool {
+		if device.UserID != userID {
+			return false
+		}
+		device.Name = name
+		return true
+	})
+}
+
+func (dr *DeviceRepository) Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	return dr.update(ctx, id, func(device *domain.Device) bool {
+		if device.UserID != userID || device.RevokedAt != nil {
+			return false
+		}
+		device.RevokedAt = &at
+		return true
+	})
+}
+
+func (dr *DeviceRepository) Approve(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	return dr.update(ctx, id, func(device *domain.Device) bool {
+		if device.UserID != userID || device.ApprovedAt != nil || device.RevokedAt != nil {
+			return false
+		}
+		device.ApprovedAt = &at
+		return true
+	})
+}
+
+func (dr *DeviceRepository) Touch(ctx context.Context, id guid.Guid, at time.Time) error {
+	return dr.update(ctx, id, func(device *domain.Device) bool {
+		device.LastSeenAt = &at
+		return true
+	})
+}
+
+func (dr *DeviceRepository) UpdateSyncVersion(ctx context.Context, id guid.Guid, version int32) error {
+	return dr.update(ctx, id, func(device *domain.Device) bool {
+		device.SyncVersion = version
+		return true
+	})
+}
+
+func (dr *DeviceRepository) UpdateVaultSyncVersion(ctx context.Context, id, vaultID guid.Guid, version int32) error {
+	return dr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.devices[id]; !ok {
+			return persistence.ErrResourceNotFound
+		}
+		t.vaultVersions[vaultVersionKey{deviceID: id, vaultID: vaultID}] = &version
+		return nil
+	})
+}
+
+// update change the device, ErrResourceNotFound when there is no device or fn doesn't change it
+func (dr *DeviceRepository) update(ctx context.Context, id guid.Guid, fn func(device *domain.Device) bool) error {
+	return dr.db.write(ctx, func(t *tables) error {
+		device, ok := t.devices[id]
+		if !ok || !fn(device) {
+			return persistence.ErrResourceNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,390 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type SecretRepository struct {
+	db *view
+}
+
+func NewSecretRepository(db *view) *SecretRepository {
+	return &SecretRepository{db: db}
+}
+
+// Get secret isn't locked, transaction holds the write lock of the whole store
+func (sdr *SecretRepository) Get(ctx context.Context, id guid.Guid) (*domain.Secret, error) {
+	var secret domain.Secret
+	err := sdr.db.read(func(t *tables) error {
+		found, ok := t.secrets[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		secret = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (sdr *SecretRepository) GetAll(ctx context.Context, userID guid.Guid, greater int32) ([]*domain.Secret, error) {
+	secrets := sdr.feed(userID, func(secret *domain.Secret) bool {
+		return secret.Version > greater
+	})
+	slices.SortFunc(secrets, compareModified)
+	return secrets, nil
+}
+
+func (sdr *SecretRepository) GetPage(
+	ctx context.Context,
+	userID guid.Guid,
+	after domain.SecretCursor,
+	until int32,
+	limit int32,
+) ([]*domain.Secret, error) {
+	return page(sdr.feed(userID, afterCursor(after, until)), limit), nil
+}
+
+func (sdr *SecretRepository) GetVaultAll(ctx context.Context, vaultID guid.Guid, greater int32) ([]*domain.Secret, error) {
+	secrets := sdr.vaultFeed(vaultID, func(secret *domain.Secret) bool {
+		return secret.Version > greater
+	})
+	slices.SortFunc(secrets, compareModified)
+	return secrets, nil
+}
+
+func (sdr *SecretRepository) GetVaultPage(
+	ctx context.Context,
+	vaultID guid.Guid,
+	after domain.SecretCursor,
+	until int32,
+	limit int32,
+) ([]*domain.Secret, error) {
+	return page(sdr.vaultFeed(vaultID, afterCursor(after, until)), limit), nil
+}
+
+func (sdr *SecretRepository) Insert(ctx context.Context, data *domain.Secret) error {
+	createdAt := time.Now().UTC()
+	return sdr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.secrets[data.ID]; ok {
+			return fmt.Errorf("%w: secret %s", ErrDuplicate, data.ID)
+		}
+		if data.VaultID != nil {
+			if _, ok := t.vaults[*data.VaultID]; !ok {
+				return fmt.Errorf("%w: vault %s", ErrReference, data.VaultID)
+			}
+		}
+		t.secrets[data.ID] = &domain.Secret{
+			ID:         data.ID,
+			CreatedAt:  createdAt,
+			ModifiedAt: data.ModifiedAt,
+			UserID:     data.UserID,
+			Type:       data.Type,
+			BigData:    data.BigData,
+			Dek:        bytes.Clone(data.Dek),
+			Payload:    bytes.Clone(data.Payload),
+			Path:       data.Path,
+			Version:    data.Version,
+			Deleted:    data.Deleted,
+			VaultID:    clonePtr(data.VaultID),
+			Size:       data.Size,
+			ChargedTo:  data.UserID,
+		}
+		return nil
+	})
+}
+
+func (sdr *SecretRepository) Update(ctx context.Context, data *domain.Secret) error {
+	return sdr.db.write(ctx, func(t *tables) error {
+		secret, ok := t.secrets[data.ID]
+		if !ok {
+			return nil
+		}
+		secret.UserID = data.UserID
+		secret.BigData = data.BigData
+		secret.Type = data.Type
+		secret.Payload = bytes.Clone(data.Payload)
+		secret.Dek = bytes.Clone(data.Dek)
+		secret.Version = data.Version
+		secret.ModifiedAt = data.ModifiedAt
+		secret.Size = data.Size
+		secret.ChargedTo = data.ChargedTo
+		return nil
+	})
+}
+
+func (sdr *SecretRepository) Delete(ctx context.Context, data *domain.Secret) error {
+	return sdr.db.write(ctx, func(t *tables) error {
+		if secret, ok := t.secrets[data.ID]; ok {
+			secret.Deleted = data.Deleted
+			secret.Version = data.Version
+		}
+		return nil
+	})
+}
+
+func (sdr *SecretRepository) DeleteAll(ctx context.Context, userID guid.Guid) ([]*domain.Secret, error) {
+	removed := make([]*domain.Secret, 0)
+	err := sdr.db.write(ctx, func(t *tables) error {
+		for id, secret := range t.secrets {
+			if secret.UserID != userID || secret.VaultID != nil {
+				continue
+			}
+			removed = append(removed, &domain.Secret{ID: id, UserID: userID, BigData: secret.BigData, Version: secret.Version})
+			t.deleteSecret(id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (sdr *SecretRepository) GetBigData(ctx context.Context) ([]*domain.Secret, error) {
+	secrets := make([]*domain.Secret, 0)
+	_ = sdr.db.read(func(t *tables) error {
+		for _, secret := range t.secrets {
+			if !secret.BigData {
+				continue
+			}
+			secrets = append(secrets, &domain.Secret{
+				ID:        secret.ID,
+				UserID:    secret.UserID,
+				ChargedTo: secret.ChargedTo,
+				VaultID:   secret.VaultID,
+				BigData:   true,
+				Version:   secret.Version,
+				Deleted:   secret.Deleted,
+			})
+		}
+		return nil
+	})
+	return secrets, nil
+}
+
+func (sdr *SecretRepository) Stats(ctx context.Context) ([]*domain.SecretStats, error) {
+	stats := make([]*domain.SecretStats, 0)
+	_ = sdr.db.read(func(t *tables) error {
+		byUser := make(map[guid.Guid]*domain.SecretStats)
+		for _, secret := range t.secrets {
+			item, ok := byUser[secret.ChargedTo]
+			if !ok {
+				item = &domain.SecretStats{UserID: secret.ChargedTo}
+				byUser[secret.ChargedTo] = item
+				stats = append(stats, item)
+			}
+			if secret.Deleted {
+				item.Tombstones++
+			} else {
+				item.Records++
+			}
+			item.PayloadBytes += int64(len(secret.Payload) + len(secret.Dek))
+		}
+		return nil
+	})
+	slices.SortFunc(stats, func(a, b *domain.SecretStats) int {
+		return compareGuid(a.UserID, b.UserID)
+	})
+	return stats, nil
+}
+
+func (sdr *SecretRepository) Usage(ctx context.Context, userID guid.Guid) (*domain.SecretUsage, error) {
+	var usage domain.SecretUsage
+	_ = sdr.db.read(func(t *tables) error {
+		for _, secret := range t.secrets {
+			if secret.ChargedTo == userID && !secret.Deleted {
+				usage.Secrets++
+				usage.BlobBytes += secret.Size
+			}
+		}
+		return nil
+	})
+	return &usage, nil
+}
+
+func (sdr *SecretRepository) GetPurgeable(ctx context.Context, before time.Time) ([]*domain.Secret, error) {
+	secrets := make([]*domain.Secret, 0)
+	_ = sdr.db.read(func(t *tables) error {
+		for _, secret := range t.secrets {
+			if purgeable(t, secret, before) {
+				secrets = append(secrets, tombstone(secret))
+			}
+		}
+		return nil
+	})
+	return secrets, nil
+}
+
+func (sdr *SecretRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]*domain.Secret, error) {
+	removed := make([]*domain.Secret, 0)
+	err := sdr.db.write(ctx, func(t *tables) error {
+		for _, secret := range t.secrets {
+			if purgeable(t, secret, before) {
+				removed = append(removed, tombstone(secret))
+			}
+		}
+		for _, secret := range removed {
+			t.deleteSecret(secret.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// feed secrets of the user and secrets shared with the user. Shared secret comes under version
+// of the share with dek sealed to the user, revoked share looks like deleted secret
+func (sdr *SecretRepository) feed(userID guid.Guid, match func(secret *domain.Secret) bool) []*domain.Secret {
+	secrets := make([]*domain.Secret, 0)
+	_ = sdr.db.read(func(t *tables) error {
+		for _, stored := range t.secrets {
+			if stored.UserID != userID || stored.VaultID != nil {
+				continue
+			}
+			if secret := feedItem(stored); match(secret) {
+				secrets = append(secrets, secret)
+			}
+		}
+		for key, share := range t.shares {
+			if key.recipientID != userID {
+				continue
+			}
+			stored, ok := t.secrets[key.secretID]
+			if !ok {
+				continue
+			}
+			owner, ok := t.users[stored.UserID]
+			if !ok {
+				continue
+			}
+			secret := feedItem(stored)
+			secret.Version = share.Version
+			secret.Owner = owner.Login
+			if share.Revoked() {
+				secret.Payload = nil
+				secret.Dek = nil
+				secret.Deleted = true
+			} else {
+				secret.Dek = share.Dek
+			}
+			if match(secret) {
+				secrets = append(secrets, secret)
+			}
+		}
+		return nil
+	})
+	return secrets
+}
+
+// vaultFeed secrets of the vault, every member gets them under version of the vault
+func (sdr *SecretRepository) vaultFeed(vaultID guid.Guid, match func(secret *domain.Secret) bool) []*domain.Secret {
+	secrets := make([]*domain.Secret, 0)
+	_ = sdr.db.read(func(t *tables) error {
+		for _, stored := range t.secrets {
+			if stored.VaultID == nil || *stored.VaultID != vaultID {
+				continue
+			}
+			if secret := feedItem(stored); match(secret) {
+				secrets = append(secrets, secret)
+			}
+		}
+		return nil
+	})
+	return secrets
+}
+
+// feedItem copy of the secret as feed reads it, size isn't read
+func feedItem(stored *domain.Secret) *domain.Secret {
+	secret := *stored
+	secret.DataVersion = stored.Version
+	secret.Size = 0
+	return &secret
+}
+
+// compareModified order of secrets by modification time, secrets modified at once are ordered by version and id
+func compareModified(a, b *domain.Secret) int {
+	if c := a.ModifiedAt.Compare(b.ModifiedAt); c != 0 {
+		return c
+	}
+	return compareCursor(a, b)
+}
+
+// compareCursor order of secrets by version and id
+func compareCursor(a, b *domain.Secret) int {
+	if c := cmp.Compare(a.Version, b.Version); c != 0 {
+		return c
+	}
+	return compareGuid(a.ID, b.ID)
+}
+
+// afterCursor secrets after cursor with version not greater than until
+func afterCursor(after domain.SecretCursor, until int32) func(secret *domain.Secret) bool {
+	return func(secret *domain.Secret) bool {
+		if secret.Version > until || secret.Version < after.Version {
+			return false
+		}
+		return secret.Version > after.Version || compareGuid(secret.ID, after.ID) > 0
+	}
+}
+
+// page first secrets ordered by version and id
+func page(secrets []*domain.Secret, limit int32) []*domain.Secret {
+	slices.SortFunc(secrets, compareCursor)
+	if int(limit) < len(secrets) {
+		secrets = secrets[:max(limit, 0)]
+	}
+	return secrets
+}
+
+// purgeable deleted secret modified before the time that every active device of the owner and of recipients
+// has pulled, or every active device of members for a vault secret
+func purgeable(t *tables, secret *domain.Secret, before time.Time) bool {
+	if !secret.Deleted || !secret.ModifiedAt.Before(before) {
+		return false
+	}
+	for _, device := range t.devices {
+		if device.Revoked() {
+			continue
+		}
+		if secret.VaultID == nil && device.UserID == secret.UserID && device.SyncVersion < secret.Version {
+			return false
+		}
+		if share, ok := t.shares[shareKey{secretID: secret.ID, recipientID: device.UserID}]; ok && device.SyncVersion < share.Version {
+			return false
+		}
+		if secret.VaultID != nil && vaultVersion(t, device.ID, *secret.VaultID, device.UserID) < secret.Version {
+			return false
+		}
+	}
+	return true
+}
+
+// vaultVersion version of the vault the device pulled up to, max for devices of users out of the vault
+func vaultVersion(t *tables, deviceID, vaultID, userID guid.Guid) int32 {
+	if _, ok := t.members[memberKey{vaultID: vaultID, userID: userID}]; !ok {
+		return math.MaxInt32
+	}
+	if version, ok := t.vaultVersions[vaultVersionKey{deviceID: deviceID, vaultID: vaultID}]; ok {
+		return *version
+	}
+	return 0
+}
+
+func tombstone(secret *domain.Secret) *domain.Secret {
+	return &domain.Secret{ID: secret.ID, BigData: secret.BigData, Version: secret.Version, Deleted: true}
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRepository_ShouldReadOwnAndSharedSecrets(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	owner := insertTestUser(t, uow, "dima")
+	recipient := insertTestUser(t, uow, "vova")
+	modifiedAt := time.Date(2025, 3, 1, 10, 30, 15, 123456000, time.UTC)
+	own := &domain.Secret{ID: *guid.New(), ModifiedAt: modifiedAt, UserID: owner.ID, Type: domain.BankCardType,
+		Payload: []byte("payload"), Dek: []byte("dek"), Version: 1}
+	shared := &domain.Secret{ID: *guid.New(), ModifiedAt: modifiedAt, UserID: recipient.ID, Type: domain.TextType,
+		Payload: []byte("shared"), Dek: []byte("owner dek"), Version: 1}
+	repository := uow.SecretRepository()
+	assert.NoError(t, repository.Insert(ctx, own))
+	assert.NoError(t, repository.Insert(ctx, shared))
+	share := &domain.Share{SecretID: shared.ID, OwnerID: recipient.ID, RecipientID: owner.ID, Dek: []byte("sealed"), Version: 2}
+	assert.NoError(t, uow.ShareRepository().Save(ctx, share))
+
+	stored, err := repository.Get(ctx, own.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, own.ID, stored.ID)
+	assert.Equal(t, modifiedAt, stored.ModifiedAt)
+	assert.Equal(t, domain.BankCardType, stored.Type)
+	assert.Nil(t, stored.VaultID)
+	assert.False(t, stored.CreatedAt.IsZero())
+
+	secrets, err := repository.GetAll(ctx, owner.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, secrets, 2)
+	page, err := repository.GetPage(ctx, owner.ID, domain.SecretCursor{Version: 1, ID: own.ID}, 2, 10)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, shared.ID, page[0].ID)
+	assert.Equal(t, "vova", page[0].Owner)
+	assert.Equal(t, int32(2), page[0].Version)
+	assert.Equal(t, int32(1), page[0].DataVersion)
+	assert.Equal(t, []byte("sealed"), page[0].Dek)
+
+	assert.NoError(t, uow.ShareRepository().Revoke(ctx, shared.ID, owner.ID, 3, modifiedAt))
+	secrets, err = repository.GetAll(ctx, owner.ID, 2)
+	assert.NoError(t, err)
+	assert.Len(t, secrets, 1)
+	assert.True(t, secrets[0].Deleted)
+	assert.Nil(t, secrets[0].Payload)
+
+	_, err = repository.Get(ctx, *guid.New())
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+}
+
+func TestSecretRepository_GetPageShouldOrderByVersionAndID(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	user := insertTestUser(t, uow, "dima")
+	repository := uow.SecretRepository()
+	for version := int32(1); version <= 3; version++ {
+		for range 2 {
+			assert.NoError(t, repository.Insert(ctx, &domain.Secret{ID: *guid.New(), UserID: user.ID, Version: version}))
+		}
+	}
+
+	var read []*domain.Secret
+	cursor := domain.SecretCursor{}
+	for {
+		page, err := repository.GetPage(ctx, user.ID, cursor, 2, 3)
+		assert.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		read = append(read, page...)
+		last := page[len(page)-1]
+		cursor = domain.SecretCursor{Version: last.Version, ID: last.ID}
+	}
+
+	assert.Len(t, read, 4)
+	for i := 1; i < len(read); i++ {
+		assert.Negative(t, compareCursor(read[i-1], read[i]))
+	}
+	assert.Equal(t, int32(2), read[3].Version)
+}
+
+func TestSecretRepository_ShouldPurgeDeletedSecretsPulledByDevices(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	user := insertTestUser(t, uow, "dima")
+	device := &domain.Device{UserID: user.ID, Name: "laptop", PublicKey: []byte("key")}
+	assert.NoError(t, uow.DeviceRepository().Insert(ctx, device))
+	now := time.Now().UTC()
+	repository := uow.SecretRepository()
+	deleted := &domain.Secret{ID: *guid.New(), ModifiedAt: now.Add(-time.Hour), UserID: user.ID, BigData: true, Version: 2, Deleted: true}
+	live := &domain.Secret{ID: *guid.New(), ModifiedAt: now.Add(-time.Hour), UserID: user.ID, Version: 1, Size: 10}
+	assert.NoError(t, repository.Insert(ctx, deleted))
+	assert.NoError(t, repository.Insert(ctx, live))
+
+	purgeable, err := repository.GetPurgeable(ctx, now)
+	assert.NoError(t, err)
+	assert.Empty(t, purgeable)
+	assert.NoError(t, uow.DeviceRepository().UpdateSyncVersion(ctx, device.ID, 2))
+	purgeable, err = repository.GetPurgeable(ctx, now.Add(-2*time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, purgeable)
+
+	purged, err := repository.PurgeDeleted(ctx, now)
+	assert.NoError(t, err)
+	assert.Len(t, purged, 1)
+	assert.Equal(t, deleted.ID, purged[0].ID)
+	assert.True(t, purged[0].BigData)
+	_, err = repository.Get(ctx, deleted.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+
+	usage, err := repository.Usage(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.SecretUsage{Secrets: 1, BlobBytes: 10}, usage)
+}
+
+func TestSecretRepository_ShouldKeepDeletedVaultSecretsTillMembersPull(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	owner := insertTestUser(t, uow, "dima")
+	member := insertTestUser(t, uow, "vova")
+	vault := &domain.Vault{ID: *guid.New(), Name: "team", CreatedBy: owner.ID}
+	assert.NoError(t, uow.VaultRepository().Insert(ctx, vault))
+	devices := make([]*domain.Device, 0, 2)
+	for _, user := range []*domain.User{owner, member} {
+		assert.NoError(t, uow.VaultRepository().SaveMember(ctx, &domain.VaultMember{
+			VaultID: vault.ID, UserID: user.ID, Role: domain.WriterRole, VaultKey: []byte("key"),
+		}))
+		device := &domain.Device{UserID: user.ID, Name: "laptop", PublicKey: []byte("key")}
+		assert.NoError(t, uow.DeviceRepository().Insert(ctx, device))
+		devices = append(devices, device)
+	}
+	now := time.Now().UTC()
+	repository := uow.SecretRepository()
+	deleted := &domain.Secret{ID: *guid.New(), ModifiedAt: now.Add(-time.Hour), UserID: owner.ID, VaultID: &vault.ID, Version: 3, Deleted: true}
+	assert.NoError(t, repository.Insert(ctx, deleted))
+	// own sync version, not the vault one
+	assert.NoError(t, uow.DeviceRepository().UpdateSyncVersion(ctx, devices[1].ID, 3))
+	assert.NoError(t, uow.DeviceRepository().UpdateVaultSyncVersion(ctx, devices[0].ID, vault.ID, 3))
+
+	purgeable, err := repository.GetPurgeable(ctx, now)
+	assert.NoError(t, err)
+	assert.Empty(t, purgeable)
+
+	assert.NoError(t, uow.DeviceRepository().UpdateVaultSyncVersion(ctx, devices[1].ID, vault.ID, 3))
+	purged, err := repository.PurgeDeleted(ctx, now)
+	assert.NoError(t, err)
+	assert.Len(t, purged, 1)
+	assert.Equal(t, deleted.ID, purged[0].ID)
+}
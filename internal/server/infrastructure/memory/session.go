@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type SessionRepository struct {
+	db *view
+}
+
+func NewSessionRepository(db *view) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (sr *SessionRepository) Get(ctx context.Context, id guid.Guid) (*domain.Session, error) {
+	var session domain.Session
+	err := sr.db.read(func(t *tables) error {
+		found, ok := t.sessions[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		session = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (sr *SessionRepository) GetActive(ctx context.Context, userID guid.Guid, now time.Time) ([]*domain.Session, error) {
+	sessions := make([]*domain.Session, 0)
+	_ = sr.db.read(func(t *tables) error {
+		for _, session := range t.sessions {
+			if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+				item := *session
+				sessions = append(sessions, &item)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(sessions, func(a, b *domain.Session) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return compareGuid(a.ID, b.ID)
+	})
+	return sessions, nil
+}
+
+func (sr *SessionRepository) Insert(ctx context.Context, session *domain.Session) error {
+	stored := &domain.Session{
+		ID:         *guid.New(),
+		CreatedAt:  time.Now().UTC(),
+		UserID:     session.UserID,
+		DeviceID:   clonePtr(session.DeviceID),
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+	err := sr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.users[session.UserID]; !ok {
+			return fmt.Errorf("%w: user %s", ErrReference, session.UserID)
+		}
+		if session.DeviceID != nil {
+			if _, ok := t.devices[*session.DeviceID]; !ok {
+				return fmt.Errorf("%w: device %s", ErrReference, session.DeviceID)
+			}
+		}
+		t.sessions[stored.ID] = stored
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	session.ID = stored.ID
+	session.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (sr *SessionRepository) Extend(ctx context.Context, id guid.Guid, usedAt, expiresAt time.Time) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		session, ok := t.sessions[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		session.LastUsedAt = usedAt
+		session.ExpiresAt = expiresAt
+		return nil
+	})
+}
+
+func (sr *SessionRepository) Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		session, ok := t.sessions[id]
+		if !ok || session.UserID != userID || session.RevokedAt != nil {
+			return persistence.ErrResourceNotFound
+		}
+		session.RevokedAt = &at
+		return nil
+	})
+}
+
+func (sr *SessionRepository) GetToken(ctx context.Context, hash []byte) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := sr.db.read(func(t *tables) error {
+		found, ok := t.tokens[string(hash)]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		token = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (sr *SessionRepository) InsertToken(ctx context.Context, token *domain.RefreshToken) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.tokens[string(token.Hash)]; ok {
+			return fmt.Errorf("%w: refresh token", ErrDuplicate)
+		}
+		if _, ok := t.sessions[token.SessionID]; !ok {
+			return fmt.Errorf("%w: session %s", ErrReference, token.SessionID)
+		}
+		t.tokens[string(token.Hash)] = &domain.RefreshToken{Hash: bytes.Clone(token.Hash), SessionID: token.SessionID}
+		return nil
+	})
+}
+
+func (sr *SessionRepository) UseToken(ctx context.Context, hash []byte, at time.Time) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		token, ok := t.tokens[string(hash)]
+		if !ok || token.UsedAt != nil {
+			return persistence.ErrResourceNotFound
+		}
+		token.UsedAt = &at
+		return nil
+	})
+}
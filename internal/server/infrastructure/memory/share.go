@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type ShareRepository struct {
+	db *view
+}
+
+func NewShareRepository(db *view) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+func (sr *ShareRepository) Get(ctx context.Context, secretID, recipientID guid.Guid) (*domain.Share, error) {
+	var share *domain.Share
+	err := sr.db.read(func(t *tables) error {
+		found, ok := t.shares[shareKey{secretID: secretID, recipientID: recipientID}]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		if share = readShare(t, found); share == nil {
+			return persistence.ErrResourceNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (sr *ShareRepository) GetBySecret(ctx context.Context, secretID guid.Guid) ([]*domain.Share, error) {
+	shares := sr.query(func(share *domain.Share) bool {
+		return share.SecretID == secretID && share.RevokedAt == nil
+	})
+	slices.SortFunc(shares, func(a, b *domain.Share) int {
+		return strings.Compare(a.Recipient, b.Recipient)
+	})
+	return shares, nil
+}
+
+func (sr *ShareRepository) GetByOwner(ctx context.Context, ownerID guid.Guid) ([]*domain.Share, error) {
+	shares := sr.query(func(share *domain.Share) bool {
+		return share.OwnerID == ownerID && share.RevokedAt == nil
+	})
+	slices.SortFunc(shares, func(a, b *domain.Share) int {
+		if c := compareGuid(a.SecretID, b.SecretID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Recipient, b.Recipient)
+	})
+	return shares, nil
+}
+
+// Save share renewed is given new dek, version and creation time, owner stays
+func (sr *ShareRepository) Save(ctx context.Context, share *domain.Share) error {
+	createdAt := time.Now().UTC()
+	err := sr.db.write(ctx, func(t *tables) error {
+		key := shareKey{secretID: share.SecretID, recipientID: share.RecipientID}
+		if stored, ok := t.shares[key]; ok {
+			stored.Dek = bytes.Clone(share.Dek)
+			stored.Version = share.Version
+			stored.CreatedAt = createdAt
+			stored.RevokedAt = nil
+			return nil
+		}
+		if _, ok := t.secrets[share.SecretID]; !ok {
+			return fmt.Errorf("%w: secret %s", ErrReference, share.SecretID)
+		}
+		if _, ok := t.users[share.RecipientID]; !ok {
+			return fmt.Errorf("%w: user %s", ErrReference, share.RecipientID)
+		}
+		t.shares[key] = &domain.Share{
+			SecretID:    share.SecretID,
+			OwnerID:     share.OwnerID,
+			RecipientID: share.RecipientID,
+			Dek:         bytes.Clone(share.Dek),
+			Version:     share.Version,
+			CreatedAt:   createdAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	share.CreatedAt = createdAt
+	share.RevokedAt = nil
+	return nil
+}
+
+func (sr *ShareRepository) Update(ctx context.Context, share *domain.Share) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		stored, ok := t.shares[shareKey{secretID: share.SecretID, recipientID: share.RecipientID}]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		stored.Dek = bytes.Clone(share.Dek)
+		stored.Version = share.Version
+		return nil
+	})
+}
+
+func (sr *ShareRepository) Revoke(ctx context.Context, secretID, recipientID guid.Guid, version int32, at time.Time) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		stored, ok := t.shares[shareKey{secretID: secretID, recipientID: recipientID}]
+		if !ok || stored.RevokedAt != nil {
+			return persistence.ErrResourceNotFound
+		}
+		stored.Version = version
+		stored.RevokedAt = &at
+		return nil
+	})
+}
+
+func (sr *ShareRepository) query(match func(share *domain.Share) bool) []*domain.Share {
+	shares := make([]*domain.Share, 0)
+	_ = sr.db.read(func(t *tables) error {
+		for _, stored := range t.shares {
+			if !match(stored) {
+				continue
+			}
+			if share := readShare(t, stored); share != nil {
+				shares = append(shares, share)
+			}
+		}
+		return nil
+	})
+	return shares
+}
+
+// readShare copy of the share with login and public key of the recipient, nil when there is no recipient
+func readShare(t *tables, stored *domain.Share) *domain.Share {
+	recipient, ok := t.users[stored.RecipientID]
+	if !ok {
+		return nil
+	}
+	share := *stored
+	share.Recipient = recipient.Login
+	share.PublicKey = recipient.PublicKey
+	return &share
+}
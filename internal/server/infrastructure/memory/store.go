@@ -0,0 +1,238 @@
+// Package memory server storage kept in memory of the process, for embedded servers and tests
+package memory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+// Scheme of DATABASE url selecting in-memory storage, the rest of url is ignored
+const Scheme = "memory://"
+
+var (
+	// ErrDuplicate unique key of the record is taken, as unique constraint of the database is violated
+	ErrDuplicate = errors.New("resource already exists")
+	// ErrReference record refers to missing one, as foreign key of the database is violated
+	ErrReference = errors.New("referenced resource not found")
+)
+
+// IsMemory database url selects in-memory storage
+func IsMemory(database string) bool {
+	return strings.HasPrefix(database, Scheme)
+}
+
+// Store records of the server. Transaction works on a copy which replaces the records on commit
+type Store struct {
+	mu     sync.RWMutex
+	tables *tables
+	writer chan struct{}
+}
+
+func NewStore() *Store {
+	return &Store{tables: newTables(), writer: make(chan struct{}, 1)}
+}
+
+// Ping store is always available
+func (s *Store) Ping(_ context.Context) error {
+	return nil
+}
+
+// lock take the write lock, it's released by the returned func
+func (s *Store) lock(ctx context.Context) (func(), error) {
+	select {
+	case s.writer <- struct{}{}:
+		return func() { <-s.writer }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// snapshot copy of committed records
+func (s *Store) snapshot() *tables {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tables.clone()
+}
+
+func (s *Store) commit(t *tables) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables = t
+}
+
+type syncKey struct {
+	id     string
+	userID guid.Guid
+}
+
+type shareKey struct {
+	secretID    guid.Guid
+	recipientID guid.Guid
+}
+
+type memberKey struct {
+	vaultID guid.Guid
+	userID  guid.Guid
+}
+
+type vaultVersionKey struct {
+	deviceID guid.Guid
+	vaultID  guid.Guid
+}
+
+// tables records of the store. Records are never changed in place out of the store,
+// they are copied on read and on write
+type tables struct {
+	users      map[guid.Guid]*domain.User
+	secrets    map[guid.Guid]*domain.Secret
+	syncStates map[syncKey]*domain.SyncState
+	// vaultStates sync states of vaults
+	vaultStates map[guid.Guid]*domain.SyncState
+	devices     map[guid.Guid]*domain.Device
+	sessions    map[guid.Guid]*domain.Session
+	tokens      map[string]*domain.RefreshToken
+	shares      map[shareKey]*domain.Share
+	vaults      map[guid.Guid]*domain.Vault
+	members     map[memberKey]*domain.VaultMember
+	// vaultVersions versions of vaults devices pulled up to
+	vaultVersions map[vaultVersionKey]*int32
+	// recoveries wrapped master keys of users
+	recoveries map[guid.Guid]*[]byte
+	audit      []*domain.AuditEvent
+}
+
+func newTables() *tables {
+	return &tables{
+		users:         make(map[guid.Guid]*domain.User),
+		secrets:       make(map[guid.Guid]*domain.Secret),
+		syncStates:    make(map[syncKey]*domain.SyncState),
+		vaultStates:   make(map[guid.Guid]*domain.SyncState),
+		devices:       make(map[guid.Guid]*domain.Device),
+		sessions:      make(map[guid.Guid]*domain.Session),
+		tokens:        make(map[string]*domain.RefreshToken),
+		shares:        make(map[shareKey]*domain.Share),
+		vaults:        make(map[guid.Guid]*domain.Vault),
+		members:       make(map[memberKey]*domain.VaultMember),
+		vaultVersions: make(map[vaultVersionKey]*int32),
+		recoveries:    make(map[guid.Guid]*[]byte),
+	}
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		users:         cloneMap(t.users),
+		secrets:       cloneMap(t.secrets),
+		syncStates:    cloneMap(t.syncStates),
+		vaultStates:   cloneMap(t.vaultStates),
+		devices:       cloneMap(t.devices),
+		sessions:      cloneMap(t.sessions),
+		tokens:        cloneMap(t.tokens),
+		shares:        cloneMap(t.shares),
+		vaults:        cloneMap(t.vaults),
+		members:       cloneMap(t.members),
+		vaultVersions: cloneMap(t.vaultVersions),
+		recoveries:    cloneMap(t.recoveries),
+		// audit events are append-only, sharing the slice is safe
+		audit: slices.Clip(t.audit),
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]*V) map[K]*V {
+	c := make(map[K]*V, len(m))
+	for k, v := range m {
+		value := *v
+		c[k] = &value
+	}
+	return c
+}
+
+// userByLogin user with the login, nil when there is none
+func (t *tables) userByLogin(login string) *domain.User {
+	for _, user := range t.users {
+		if user.Login == login {
+			return user
+		}
+	}
+	return nil
+}
+
+// deleteSecret remove secret with its shares
+func (t *tables) deleteSecret(id guid.Guid) {
+	delete(t.secrets, id)
+	for key := range t.shares {
+		if key.secretID == id {
+			delete(t.shares, key)
+		}
+	}
+}
+
+// deleteSession remove session with its refresh tokens
+func (t *tables) deleteSession(id guid.Guid) {
+	delete(t.sessions, id)
+	for key, token := range t.tokens {
+		if token.SessionID == id {
+			delete(t.tokens, key)
+		}
+	}
+}
+
+// txn records of the transaction, it replaces records of the store or of the outer transaction on commit
+type txn struct {
+	mu     sync.Mutex
+	tables *tables
+}
+
+// view records repositories work with: records of the transaction, committed records out of transaction
+type view struct {
+	store *Store
+	tx    *txn
+}
+
+func (v *view) read(fn func(t *tables) error) error {
+	if v.tx != nil {
+		v.tx.mu.Lock()
+		defer v.tx.mu.Unlock()
+		return fn(v.tx.tables)
+	}
+	v.store.mu.RLock()
+	defer v.store.mu.RUnlock()
+	return fn(v.store.tables)
+}
+
+// write change records. Out of transaction the write lock is taken, fn must check everything
+// before the first change since committed records are changed in place
+func (v *view) write(ctx context.Context, fn func(t *tables) error) error {
+	if v.tx != nil {
+		v.tx.mu.Lock()
+		defer v.tx.mu.Unlock()
+		return fn(v.tx.tables)
+	}
+	release, err := v.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	v.store.mu.Lock()
+	defer v.store.mu.Unlock()
+	return fn(v.store.tables)
+}
+
+// compareGuid order of ids as uuid columns of the database order them
+func compareGuid(a, b guid.Guid) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type SyncStateRepository struct {
+	db *view
+}
+
+func NewSyncStateRepository(db *view) *SyncStateRepository {
+	return &SyncStateRepository{db: db}
+}
+
+// Get state isn't locked, transaction holds the write lock of the whole store
+func (sr *SyncStateRepository) Get(ctx context.Context, id string, userID guid.Guid) (*domain.SyncState, error) {
+	var syncState domain.SyncState
+	err := sr.db.read(func(t *tables) error {
+		found, ok := t.syncStates[syncKey{id: id, userID: userID}]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		syncState = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &syncState, nil
+}
+
+func (sr *SyncStateRepository) GetVault(ctx context.Context, vaultID guid.Guid) (*domain.SyncState, error) {
+	var syncState domain.SyncState
+	err := sr.db.read(func(t *tables) error {
+		found, ok := t.vaultStates[vaultID]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		syncState = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &syncState, nil
+}
+
+func (sr *SyncStateRepository) Insert(ctx context.Context, syncState *domain.SyncState) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		if syncState.VaultID != nil {
+			vaultID := *syncState.VaultID
+			if _, ok := t.vaults[vaultID]; !ok {
+				return fmt.Errorf("%w: vault %s", ErrReference, vaultID)
+			}
+			if _, ok := t.vaultStates[vaultID]; ok {
+				return fmt.Errorf("%w: sync state of vault %s", ErrDuplicate, vaultID)
+			}
+			t.vaultStates[vaultID] = &domain.SyncState{VaultID: &vaultID, Value: syncState.Value}
+			return nil
+		}
+		key := syncKey{id: syncState.ID, userID: syncState.UserID}
+		if _, ok := t.syncStates[key]; ok {
+			return fmt.Errorf("%w: sync state %s of %s", ErrDuplicate, syncState.ID, syncState.UserID)
+		}
+		state := *syncState
+		t.syncStates[key] = &state
+		return nil
+	})
+}
+
+func (sr *SyncStateRepository) Update(ctx context.Context, syncState *domain.SyncState) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		if syncState.VaultID != nil {
+			if state, ok := t.vaultStates[*syncState.VaultID]; ok {
+				state.Value = syncState.Value
+			}
+			return nil
+		}
+		if state, ok := t.syncStates[syncKey{id: syncState.ID, userID: syncState.UserID}]; ok {
+			state.Value = syncState.Value
+		}
+		return nil
+	})
+}
+
+func (sr *SyncStateRepository) DeleteAll(ctx context.Context, userID guid.Guid) error {
+	return sr.db.write(ctx, func(t *tables) error {
+		for key := range t.syncStates {
+			if key.userID == userID {
+				delete(t.syncStates, key)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+type UnitOfWork struct {
+	db *view
+}
+
+func NewUnitOfWork(store *Store) *UnitOfWork {
+	return &UnitOfWork{db: &view{store: store}}
+}
+
+func (u *UnitOfWork) UserRepository() domain.UserRepository {
+	return NewUserRepository(u.db)
+}
+
+func (u *UnitOfWork) SecretRepository() domain.SecretRepository {
+	return NewSecretRepository(u.db)
+}
+
+func (u *UnitOfWork) SyncStateRepository() domain.SyncStateRepository {
+	return NewSyncStateRepository(u.db)
+}
+
+func (u *UnitOfWork) DeviceRepository() domain.DeviceRepository {
+	return NewDeviceRepository(u.db)
+}
+
+func (u *UnitOfWork) SessionRepository() domain.SessionRepository {
+	return NewSessionRepository(u.db)
+}
+
+func (u *UnitOfWork) ShareRepository() domain.ShareRepository {
+	return NewShareRepository(u.db)
+}
+
+func (u *UnitOfWork) VaultRepository() domain.VaultRepository {
+	return NewVaultRepository(u.db)
+}
+
+func (u *UnitOfWork) AuditRepository() domain.AuditRepository {
+	return NewAuditRepository(u.db)
+}
+
+// Tx run fn on a copy of the records holding the write lock, fn must write with work only
+func (u *UnitOfWork) Tx(ctx context.Context, fn func(ctx context.Context, work domain.UnitOfWork) error) error {
+	if u.db.tx != nil {
+		return u.nested(ctx, fn)
+	}
+	release, err := u.db.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	tx := &txn{tables: u.db.store.snapshot()}
+	if err = fn(ctx, &UnitOfWork{db: &view{store: u.db.store, tx: tx}}); err != nil {
+		return err
+	}
+	u.db.store.commit(tx.tables)
+	return nil
+}
+
+func (u *UnitOfWork) nested(ctx context.Context, fn func(ctx context.Context, work domain.UnitOfWork) error) error {
+	outer := u.db.tx
+	outer.mu.Lock()
+	tx := &txn{tables: outer.tables.clone()}
+	outer.mu.Unlock()
+	if err := fn(ctx, &UnitOfWork{db: &view{store: u.db.store, tx: tx}}); err != nil {
+		return err
+	}
+	outer.mu.Lock()
+	defer outer.mu.Unlock()
+	outer.tables = tx.tables
+	return nil
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_TxShouldRollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	user := insertTestUser(t, uow, "dima")
+	failure := errors.New("failure")
+
+	err := uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		if err := work.UserRepository().Insert(ctx, domain.NewUser("vova", []byte("pwd"), []byte("salt"))); err != nil {
+			return err
+		}
+		if err := work.UserRepository().UpdateLogin(ctx, user.ID, "dmitry"); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	_, err = uow.UserRepository().Get(ctx, "vova")
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	stored, err := uow.UserRepository().GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "dima", stored.Login)
+}
+
+func TestUnitOfWork_NestedTxShouldRollbackToOuterTx(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	failure := errors.New("failure")
+
+	err := uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		if err := work.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))); err != nil {
+			return err
+		}
+		err := work.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+			if err := work.UserRepository().Insert(ctx, domain.NewUser("vova", []byte("pwd"), []byte("salt"))); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		return work.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+			return work.UserRepository().Insert(ctx, domain.NewUser("petya", []byte("pwd"), []byte("salt")))
+		})
+	})
+
+	assert.NoError(t, err)
+	users, err := uow.UserRepository().List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "dima", users[0].Login)
+	assert.Equal(t, "petya", users[1].Login)
+}
+
+func TestUnitOfWork_TxShouldBeSerialized(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	userID := *guid.New()
+	assert.NoError(t, uow.SyncStateRepository().Insert(ctx, &domain.SyncState{ID: "Record", UserID: userID}))
+
+	const pushes = 20
+	var wg sync.WaitGroup
+	for range pushes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+				state, err := work.SyncStateRepository().Get(ctx, "Record", userID)
+				if err != nil {
+					return err
+				}
+				state.Value++
+				return work.SyncStateRepository().Update(ctx, state)
+			}))
+		}()
+	}
+	wg.Wait()
+
+	state, err := uow.SyncStateRepository().Get(ctx, "Record", userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(pushes), state.Value)
+}
+
+func TestUnitOfWork_WriteShouldWaitForTx(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+			if err := work.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))); err != nil {
+				return err
+			}
+			close(started)
+			<-finish
+			return nil
+		})
+	}()
+	<-started
+	written := make(chan error)
+	go func() {
+		written <- uow.UserRepository().Insert(ctx, domain.NewUser("vova", []byte("pwd"), []byte("salt")))
+	}()
+	// reads don't wait for the transaction and don't see its changes
+	exist, err := uow.UserRepository().Exist(ctx, "dima")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	select {
+	case <-written:
+		t.Fatal("write should wait for transaction")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(finish)
+
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-written)
+	users, err := uow.UserRepository().List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+}
+
+func TestUserRepository_ShouldKeepConstraints(t *testing.T) {
+	ctx := context.Background()
+	uow := NewUnitOfWork(NewStore())
+	user := insertTestUser(t, uow, "dima")
+	device := &domain.Device{UserID: user.ID, Name: "laptop", PublicKey: []byte("key")}
+	assert.NoError(t, uow.DeviceRepository().Insert(ctx, device))
+	session := &domain.Session{UserID: user.ID, DeviceID: &device.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, uow.SessionRepository().Insert(ctx, session))
+	assert.NoError(t, uow.SessionRepository().InsertToken(ctx, &domain.RefreshToken{Hash: []byte("hash"), SessionID: session.ID}))
+
+	assert.ErrorIs(t, uow.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))), ErrDuplicate)
+	assert.ErrorIs(t, uow.DeviceRepository().Insert(ctx, &domain.Device{UserID: *guid.New()}), ErrReference)
+	stored, err := uow.UserRepository().Get(ctx, "dima")
+	assert.NoError(t, err)
+	stored.Login = "vova"
+	exist, err := uow.UserRepository().Exist(ctx, "vova")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	assert.NoError(t, uow.UserRepository().Delete(ctx, user.ID))
+	_, err = uow.DeviceRepository().Get(ctx, device.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	_, err = uow.SessionRepository().Get(ctx, session.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	_, err = uow.SessionRepository().GetToken(ctx, []byte("hash"))
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	assert.ErrorIs(t, uow.UserRepository().Delete(ctx, user.ID), persistence.ErrResourceNotFound)
+}
+
+func insertTestUser(t *testing.T, uow *UnitOfWork, login string) *domain.User {
+	user := domain.NewUser(login, []byte("pwd"), []byte("salt"))
+	if err := uow.UserRepository().Insert(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
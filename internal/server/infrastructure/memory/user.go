@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type UserRepository struct {
+	db *view
+}
+
+func NewUserRepository(db *view) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (ur *UserRepository) Get(ctx context.Context, login string) (*domain.User, error) {
+	var user domain.User
+	err := ur.db.read(func(t *tables) error {
+		found := t.userByLogin(login)
+		if found == nil {
+			return persistence.ErrResourceNotFound
+		}
+		user = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *UserRepository) GetByID(ctx context.Context, id guid.Guid) (*domain.User, error) {
+	var user domain.User
+	err := ur.db.read(func(t *tables) error {
+		found, ok := t.users[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		user = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
+	users := make([]*domain.User, 0)
+	_ = ur.db.read(func(t *tables) error {
+		for _, user := range t.users {
+			item := *user
+			users = append(users, &item)
+		}
+		return nil
+	})
+	slices.SortFunc(users, func(a, b *domain.User) int {
+		return strings.Compare(a.Login, b.Login)
+	})
+	return users, nil
+}
+
+func (ur *UserRepository) Exist(ctx context.Context, login string) (bool, error) {
+	var exist bool
+	_ = ur.db.read(func(t *tables) error {
+		exist = t.userByLogin(login) != nil
+		return nil
+	})
+	return exist, nil
+}
+
+// Insert id of the user is generated when it isn't set
+func (ur *UserRepository) Insert(ctx context.Context, user *domain.User) error {
+	id := user.ID
+	if id == (guid.Guid{}) {
+		id = *guid.New()
+	}
+	createdAt := time.Now().UTC()
+	err := ur.db.write(ctx, func(t *tables) error {
+		if _, ok := t.users[id]; ok {
+			return fmt.Errorf("%w: user %s", ErrDuplicate, id)
+		}
+		if t.userByLogin(user.Login) != nil {
+			return fmt.Errorf("%w: login %s", ErrDuplicate, user.Login)
+		}
+		t.users[id] = &domain.User{
+			ID:        id,
+			CreatedAt: &createdAt,
+			Login:     user.Login,
+			Password:  bytes.Clone(user.Password),
+			Salt:      bytes.Clone(user.Salt),
+			Verifier:  bytes.Clone(user.Verifier),
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	user.ID = id
+	return nil
+}
+
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id guid.Guid, password, salt []byte) error {
+	return ur.update(ctx, id, func(user *domain.User) {
+		user.Password = bytes.Clone(password)
+		user.Salt = bytes.Clone(salt)
+	})
+}
+
+func (ur *UserRepository) UpdateVerifier(ctx context.Context, id guid.Guid, salt, verifier []byte) error {
+	return ur.update(ctx, id, func(user *domain.User) {
+		user.Salt = bytes.Clone(salt)
+		user.Verifier = bytes.Clone(verifier)
+		user.Password = nil
+	})
+}
+
+func (ur *UserRepository) UpdateLogin(ctx context.Context, id guid.Guid, login string) error {
+	return ur.db.write(ctx, func(t *tables) error {
+		user, ok := t.users[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		if taken := t.userByLogin(login); taken != nil && taken.ID != id {
+			return fmt.Errorf("%w: login %s", ErrDuplicate, login)
+		}
+		user.Login = login
+		return nil
+	})
+}
+
+func (ur *UserRepository) UpdatePublicKey(ctx context.Context, id guid.Guid, publicKey []byte) error {
+	return ur.update(ctx, id, func(user *domain.User) {
+		user.PublicKey = bytes.Clone(publicKey)
+	})
+}
+
+func (ur *UserRepository) UpdateRecovery(ctx context.Context, id guid.Guid, recovery []byte) error {
+	return ur.db.write(ctx, func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return persistence.ErrResourceNotFound
+		}
+		value := bytes.Clone(recovery)
+		t.recoveries[id] = &value
+		return nil
+	})
+}
+
+func (ur *UserRepository) GetRecovery(ctx context.Context, id guid.Guid) ([]byte, error) {
+	var recovery []byte
+	err := ur.db.read(func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return persistence.ErrResourceNotFound
+		}
+		if value, ok := t.recoveries[id]; ok {
+			recovery = bytes.Clone(*value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+func (ur *UserRepository) SetDisabled(ctx context.Context, id guid.Guid, at *time.Time) error {
+	return ur.update(ctx, id, func(user *domain.User) {
+		user.DisabledAt = clonePtr(at)
+	})
+}
+
+// Delete devices, sessions, memberships and shares with the user are removed with the user, as by cascade
+func (ur *UserRepository) Delete(ctx context.Context, id guid.Guid) error {
+	return ur.db.write(ctx, func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return persistence.ErrResourceNotFound
+		}
+		delete(t.users, id)
+		delete(t.recoveries, id)
+		for sessionID, session := range t.sessions {
+			if session.UserID == id {
+				t.deleteSession(sessionID)
+			}
+		}
+		for deviceID, device := range t.devices {
+			if device.UserID == id {
+				delete(t.devices, deviceID)
+			}
+		}
+		for key := range t.members {
+			if key.userID == id {
+				delete(t.members, key)
+			}
+		}
+		for key := range t.shares {
+			if key.recipientID == id {
+				delete(t.shares, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (ur *UserRepository) update(ctx context.Context, id guid.Guid, fn func(user *domain.User)) error {
+	return ur.db.write(ctx, func(t *tables) error {
+		user, ok := t.users[id]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		fn(user)
+		return nil
+	})
+}
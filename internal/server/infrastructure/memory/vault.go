@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+type VaultRepository struct {
+	db *view
+}
+
+func NewVaultRepository(db *view) *VaultRepository {
+	return &VaultRepository{db: db}
+}
+
+func (vr *VaultRepository) Insert(ctx context.Context, vault *domain.Vault) error {
+	createdAt := time.Now().UTC()
+	err := vr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.vaults[vault.ID]; ok {
+			return fmt.Errorf("%w: vault %s", ErrDuplicate, vault.ID)
+		}
+		t.vaults[vault.ID] = &domain.Vault{ID: vault.ID, Name: vault.Name, CreatedBy: vault.CreatedBy, CreatedAt: createdAt}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	vault.CreatedAt = createdAt
+	return nil
+}
+
+func (vr *VaultRepository) GetMember(ctx context.Context, vaultID, userID guid.Guid) (*domain.VaultMember, error) {
+	var member *domain.VaultMember
+	err := vr.db.read(func(t *tables) error {
+		found, ok := t.members[memberKey{vaultID: vaultID, userID: userID}]
+		if !ok {
+			return persistence.ErrResourceNotFound
+		}
+		if member = readMember(t, found); member == nil {
+			return persistence.ErrResourceNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (vr *VaultRepository) GetMembers(ctx context.Context, vaultID guid.Guid) ([]*domain.VaultMember, error) {
+	members := vr.query(func(member *domain.VaultMember) bool {
+		return member.VaultID == vaultID
+	})
+	slices.SortFunc(members, func(a, b *domain.VaultMember) int {
+		return strings.Compare(a.Login, b.Login)
+	})
+	return members, nil
+}
+
+func (vr *VaultRepository) GetByUser(ctx context.Context, userID guid.Guid) ([]*domain.VaultMember, error) {
+	members := vr.query(func(member *domain.VaultMember) bool {
+		return member.UserID == userID
+	})
+	slices.SortFunc(members, func(a, b *domain.VaultMember) int {
+		if c := strings.Compare(a.VaultName, b.VaultName); c != 0 {
+			return c
+		}
+		return compareGuid(a.VaultID, b.VaultID)
+	})
+	return members, nil
+}
+
+// SaveMember existing member keeps creation time
+func (vr *VaultRepository) SaveMember(ctx context.Context, member *domain.VaultMember) error {
+	var createdAt time.Time
+	err := vr.db.write(ctx, func(t *tables) error {
+		key := memberKey{vaultID: member.VaultID, userID: member.UserID}
+		if stored, ok := t.members[key]; ok {
+			stored.Role = member.Role
+			stored.VaultKey = bytes.Clone(member.VaultKey)
+			createdAt = stored.CreatedAt
+			return nil
+		}
+		if _, ok := t.vaults[member.VaultID]; !ok {
+			return fmt.Errorf("%w: vault %s", ErrReference, member.VaultID)
+		}
+		if _, ok := t.users[member.UserID]; !ok {
+			return fmt.Errorf("%w: user %s", ErrReference, member.UserID)
+		}
+		createdAt = time.Now().UTC()
+		t.members[key] = &domain.VaultMember{
+			VaultID:   member.VaultID,
+			UserID:    member.UserID,
+			Role:      member.Role,
+			VaultKey:  bytes.Clone(member.VaultKey),
+			CreatedAt: createdAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	member.CreatedAt = createdAt
+	return nil
+}
+
+func (vr *VaultRepository) DeleteMember(ctx context.Context, vaultID, userID guid.Guid) error {
+	return vr.db.write(ctx, func(t *tables) error {
+		key := memberKey{vaultID: vaultID, userID: userID}
+		if _, ok := t.members[key]; !ok {
+			return persistence.ErrResourceNotFound
+		}
+		delete(t.members, key)
+		return nil
+	})
+}
+
+func (vr *VaultRepository) Delete(ctx context.Context, vaultID guid.Guid) error {
+	return vr.db.write(ctx, func(t *tables) error {
+		if _, ok := t.vaults[vaultID]; !ok {
+			return persistence.ErrResourceNotFound
+		}
+		delete(t.vaults, vaultID)
+		delete(t.vaultStates, vaultID)
+		for key := range t.members {
+			if key.vaultID == vaultID {
+				delete(t.members, key)
+			}
+		}
+		for id, secret := range t.secrets {
+			if secret.VaultID != nil && *secret.VaultID == vaultID {
+				t.deleteSecret(id)
+			}
+		}
+		for key := range t.vaultVersions {
+			if key.vaultID == vaultID {
+				delete(t.vaultVersions, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (vr *VaultRepository) query(match func(member *domain.VaultMember) bool) []*domain.VaultMember {
+	members := make([]*domain.VaultMember, 0)
+	_ = vr.db.read(func(t *tables) error {
+		for _, stored := range t.members {
+			if !match(stored) {
+				continue
+			}
+			if member := readMember(t, stored); member != nil {
+				members = append(members, member)
+			}
+		}
+		return nil
+	})
+	return members
+}
+
+// readMember copy of the member with name of the vault, login and public key of the user,
+// nil when there is no vault or user
+func readMember(t *tables, stored *domain.VaultMember) *domain.VaultMember {
+	vault, ok := t.vaults[stored.VaultID]
+	if !ok {
+		return nil
+	}
+	user, ok := t.users[stored.UserID]
+	if !ok {
+		return nil
+	}
+	member := *stored
+	member.VaultName = vault.Name
+	member.Login = user.Login
+	member.PublicKey = user.PublicKey
+	return &member
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+// AuditLog count bytes synced and failed logins from security events, events are passed to the next log
+type AuditLog struct {
+	next    domain.AuditLog
+	metrics *Metrics
+}
+
+func NewAuditLog(next domain.AuditLog, metrics *Metrics) *AuditLog {
+	return &AuditLog{next: next, metrics: metrics}
+}
+
+func (al *AuditLog) Record(ctx context.Context, event *domain.AuditEvent) {
+	switch event.Type {
+	case domain.AuditPushed:
+		al.synced("push", event.Bytes)
+	case domain.AuditPulled:
+		al.synced("pull", event.Bytes)
+	case domain.AuditDownloaded:
+		al.synced("download", event.Bytes)
+	case domain.AuditLoginFailed:
+		al.metrics.logins.WithLabelValues("failed").Inc()
+	case domain.AuditLoginLocked:
+		al.metrics.logins.WithLabelValues("locked").Inc()
+	}
+	al.next.Record(ctx, event)
+}
+
+func (al *AuditLog) synced(direction string, bytes int64) {
+	// counters can't go down
+	al.metrics.bytes.WithLabelValues(direction).Add(float64(max(bytes, 0)))
+}
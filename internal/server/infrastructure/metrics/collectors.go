@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector stats of Postgres connection pool read on scrape
+type PoolCollector struct {
+	pool            *pgxpool.Pool
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	return &PoolCollector{
+		pool:            pool,
+		acquiredConns:   desc("db_pool_acquired_connections", "Connections in use."),
+		idleConns:       desc("db_pool_idle_connections", "Idle connections."),
+		totalConns:      desc("db_pool_connections", "Connections open, in use, idle and being established."),
+		maxConns:        desc("db_pool_max_connections", "Maximum size of the pool."),
+		acquireCount:    desc("db_pool_acquires_total", "Connections acquired from the pool."),
+		acquireDuration: desc("db_pool_acquire_duration_seconds_total", "Time spent acquiring connections."),
+		emptyAcquire:    desc("db_pool_empty_acquires_total", "Acquires that waited for a connection because the pool was empty."),
+		canceledAcquire: desc("db_pool_canceled_acquires_total", "Acquires canceled by context."),
+	}
+}
+
+func (pc *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(pc, ch)
+}
+
+func (pc *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := pc.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(pc.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(pc.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(pc.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(pc.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(pc.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(pc.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(pc.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(pc.canceledAcquire, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
+// GCCollector results of garbage collector read on scrape. Blob storage usage is known after
+// the first successful run, it isn't reported before
+type GCCollector struct {
+	collector    *usecase.GarbageCollector
+	runs         *prometheus.Desc
+	failures     *prometheus.Desc
+	tombstones   *prometheus.Desc
+	blobs        *prometheus.Desc
+	blobBytes    *prometheus.Desc
+	lastRun      *prometheus.Desc
+	lastDuration *prometheus.Desc
+	storedBlobs  *prometheus.Desc
+	storedBytes  *prometheus.Desc
+}
+
+func NewGCCollector(collector *usecase.GarbageCollector) *GCCollector {
+	return &GCCollector{
+		collector:    collector,
+		runs:         desc("gc_runs_total", "Garbage collector runs."),
+		failures:     desc("gc_failures_total", "Garbage collector runs failed."),
+		tombstones:   desc("gc_tombstones_removed_total", "Deleted secrets purged."),
+		blobs:        desc("gc_blobs_removed_total", "Files removed from blob storage."),
+		blobBytes:    desc("gc_blob_bytes_removed_total", "Bytes of files removed from blob storage."),
+		lastRun:      desc("gc_last_run_timestamp_seconds", "Start of the last garbage collector run."),
+		lastDuration: desc("gc_last_duration_seconds", "Duration of the last garbage collector run."),
+		storedBlobs:  desc("blob_storage_files", "Files of secrets in blob storage after the last garbage collector run."),
+		storedBytes:  desc("blob_storage_bytes", "Bytes of files of secrets in blob storage after the last garbage collector run."),
+	}
+}
+
+func (gc *GCCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(gc, ch)
+}
+
+func (gc *GCCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := gc.collector.Metrics()
+	ch <- prometheus.MustNewConstMetric(gc.runs, prometheus.CounterValue, float64(metrics.Runs))
+	ch <- prometheus.MustNewConstMetric(gc.failures, prometheus.CounterValue, float64(metrics.Failures))
+	ch <- prometheus.MustNewConstMetric(gc.tombstones, prometheus.CounterValue, float64(metrics.Tombstones))
+	ch <- prometheus.MustNewConstMetric(gc.blobs, prometheus.CounterValue, float64(metrics.Blobs))
+	ch <- prometheus.MustNewConstMetric(gc.blobBytes, prometheus.CounterValue, float64(metrics.BlobBytes))
+	if metrics.LastRun.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(gc.lastRun, prometheus.GaugeValue, float64(metrics.LastRun.Unix()))
+	ch <- prometheus.MustNewConstMetric(gc.lastDuration, prometheus.GaugeValue, metrics.LastDuration.Seconds())
+	if metrics.Runs == metrics.Failures {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(gc.storedBlobs, prometheus.GaugeValue, float64(metrics.Stored.Blobs))
+	ch <- prometheus.MustNewConstMetric(gc.storedBytes, prometheus.GaugeValue, float64(metrics.Stored.Bytes))
+}
+
+func desc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, nil, nil)
+}
@@ -0,0 +1,90 @@
+// Package metrics server metrics in Prometheus format
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+const namespace = "keeper"
+
+// Metrics registry of server metrics, served by Handler
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	streams  *prometheus.GaugeVec
+	bytes    *prometheus.CounterVec
+	logins   *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "RPCs handled by the server by method, type and status code.",
+		}, []string{"method", "type", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Time the server took to handle RPC, streaming RPC is measured until the stream is closed.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "type"}),
+		streams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "grpc_active_streams",
+			Help:      "Streaming RPCs in progress by method.",
+		}, []string{"method"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sync_bytes_total",
+			Help:      "Bytes of secrets pushed, pulled and downloaded by clients.",
+		}, []string{"direction"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_failures_total",
+			Help:      "Failed logins by reason, failed password or locked out login.",
+		}, []string{"reason"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.latency,
+		m.streams,
+		m.bytes,
+		m.logins,
+	)
+	return m
+}
+
+// Handled count RPC of the type, unary or stream, finished with the code
+func (m *Metrics) Handled(method, kind string, code codes.Code, elapsed time.Duration) {
+	m.requests.WithLabelValues(method, kind, code.String()).Inc()
+	m.latency.WithLabelValues(method, kind).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) StreamStarted(method string) {
+	m.streams.WithLabelValues(method).Inc()
+}
+
+func (m *Metrics) StreamFinished(method string) {
+	m.streams.WithLabelValues(method).Dec()
+}
+
+// Register add collector of metrics read on scrape, such as database pool stats
+func (m *Metrics) Register(collector prometheus.Collector) error {
+	return m.registry.Register(collector)
+}
+
+// Handler serve metrics of the registry
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAuditLog_ShouldCountBytesAndFailedLogins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	next := mocks.NewMockAuditLog(ctrl)
+	next.EXPECT().Record(ctx, gomock.Any()).Times(6)
+	m := New()
+	sut := NewAuditLog(next, m)
+
+	sut.Record(ctx, &domain.AuditEvent{Type: domain.AuditPushed, Bytes: 100})
+	sut.Record(ctx, &domain.AuditEvent{Type: domain.AuditPushed, Bytes: 50})
+	sut.Record(ctx, &domain.AuditEvent{Type: domain.AuditDownloaded, Bytes: 1000})
+	sut.Record(ctx, &domain.AuditEvent{Type: domain.AuditLoginFailed})
+	sut.Record(ctx, &domain.AuditEvent{Type: domain.AuditLoginLocked})
+	sut.Record(ctx, &domain.AuditEvent{Type: domain.AuditLogin})
+
+	assert.Equal(t, 150.0, testutil.ToFloat64(m.bytes.WithLabelValues("push")))
+	assert.Equal(t, 1000.0, testutil.ToFloat64(m.bytes.WithLabelValues("download")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.logins.WithLabelValues("failed")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.logins.WithLabelValues("locked")))
+}
+
+func TestMetrics_ShouldCountRPCsAndActiveStreams(t *testing.T) {
+	sut := New()
+
+	sut.Handled("/go.Sync/Pull", "unary", codes.OK, 10*time.Millisecond)
+	sut.Handled("/go.Sync/Pull", "unary", codes.Unauthenticated, time.Millisecond)
+	sut.StreamStarted("/go.Sync/PushStream")
+	sut.StreamStarted("/go.Sync/PushStream")
+	sut.StreamFinished("/go.Sync/PushStream")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(sut.requests.WithLabelValues("/go.Sync/Pull", "unary", "Unauthenticated")))
+	assert.Equal(t, 1, testutil.CollectAndCount(sut.latency))
+	assert.Equal(t, 1.0, testutil.ToFloat64(sut.streams.WithLabelValues("/go.Sync/PushStream")))
+}
+
+func TestGCCollector_ShouldReportStorageAfterRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().GetPurgeable(ctx, gomock.Any()).Return(nil, nil)
+	secretRepository.EXPECT().GetBigData(ctx).Return(nil, nil)
+	uow.EXPECT().SecretRepository().Return(secretRepository).Times(2)
+	filer := mocks.NewMockFiler(ctrl)
+	filer.EXPECT().Walk(gomock.Any()).DoAndReturn(func(fn func(blob *domain.Blob) error) error {
+		// too fresh to be collected
+		return fn(&domain.Blob{Name: "0d6c2b6a-6f0b-4b8c-9a3e-3f1d5a0c7e21", Version: 1, Size: 2048, ModifiedAt: time.Now()})
+	})
+	collector := usecase.NewGarbageCollector(uow, filer, &usecase.GCConfig{Grace: time.Hour, DryRun: true})
+	sut := NewGCCollector(collector)
+	assert.Equal(t, 5, testutil.CollectAndCount(sut))
+
+	_, err := collector.Collect(ctx)
+
+	assert.NoError(t, err)
+	expected := `
+# HELP keeper_blob_storage_bytes Bytes of files of secrets in blob storage after the last garbage collector run.
+# TYPE keeper_blob_storage_bytes gauge
+keeper_blob_storage_bytes 2048
+# HELP keeper_blob_storage_files Files of secrets in blob storage after the last garbage collector run.
+# TYPE keeper_blob_storage_files gauge
+keeper_blob_storage_files 1
+# HELP keeper_gc_runs_total Garbage collector runs.
+# TYPE keeper_gc_runs_total counter
+keeper_gc_runs_total 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(sut, strings.NewReader(expected),
+		"keeper_blob_storage_bytes", "keeper_blob_storage_files", "keeper_gc_runs_total"))
+}
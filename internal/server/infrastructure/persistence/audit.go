@@ -0,0 +1,94 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/shared/db"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	insertAuditQUERY = `INSERT INTO audit (type, user_id, login, device_id, client_id, peer, secret_id, version, bytes, reason, created_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
+	// findAuditQUERY login of events without one is taken from the user, it's empty when the account was deleted
+	findAuditQUERY = `SELECT a.id, a.type, a.user_id, CASE WHEN a.login = '' THEN COALESCE(u.login, '') ELSE a.login END,
+					a.device_id, a.client_id, a.peer, a.secret_id, a.version, a.bytes, a.reason, a.created_at
+					FROM audit a
+					LEFT JOIN users u ON u.id = a.user_id
+					WHERE ($1::uuid IS NULL OR a.user_id = $1)
+					AND ($2::text = '' OR a.login = $2 OR u.login = $2)
+					AND ($3::timestamptz IS NULL OR a.created_at >= $3)
+					AND ($4::bigint = 0 OR a.id < $4)
+					ORDER BY a.id DESC
+					LIMIT $5`
+)
+
+type AuditRepository struct {
+	db db.QueryExecutor
+}
+
+func NewAuditRepository(db db.QueryExecutor) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (ar *AuditRepository) Insert(ctx context.Context, event *domain.AuditEvent) error {
+	return ar.db.QueryRow(ctx, insertAuditQUERY,
+		event.Type,
+		event.UserID,
+		event.Login,
+		event.DeviceID,
+		event.Client,
+		event.Peer,
+		event.SecretID,
+		event.Version,
+		event.Bytes,
+		event.Reason,
+		event.At).Scan(&event.ID)
+}
+
+func (ar *AuditRepository) Find(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	var since *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	rows, err := ar.db.Query(ctx, findAuditQUERY, filter.UserID, filter.Login, since, filter.BeforeID, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	events := make([]*domain.AuditEvent, 0)
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func scanAuditEvent(row pgx.Row) (*domain.AuditEvent, error) {
+	var event domain.AuditEvent
+	var userID, deviceID, secretID *guid.Guid
+	if err := row.Scan(&event.ID,
+		&event.Type,
+		&userID,
+		&event.Login,
+		&deviceID,
+		&event.Client,
+		&event.Peer,
+		&secretID,
+		&event.Version,
+		&event.Bytes,
+		&event.Reason,
+		&event.At); err != nil {
+		return nil, err
+	}
+	event.UserID = userID
+	event.DeviceID = deviceID
+	event.SecretID = secretID
+	return &event, nil
+}
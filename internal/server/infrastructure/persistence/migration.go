@@ -11,22 +11,59 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// MigrationStatus version of the schema, dirty when the last migration failed halfway
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+}
+
 func Migrate(pgx *pgxpool.Pool, path string) error {
-	var err error
-	db, err := sql.Open("postgres", pgx.Config().ConnString())
+	m, err := newMigrate(pgx, path)
 	if err != nil {
 		return err
 	}
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return err
 	}
-	m, err := migrate.NewWithDatabaseInstance(fmt.Sprintf("file://%s", path), "postgres", driver)
+	return nil
+}
+
+// MigrateDown roll back the given number of the last migrations
+func MigrateDown(pgx *pgxpool.Pool, path string, steps int) error {
+	m, err := newMigrate(pgx, path)
 	if err != nil {
 		return err
 	}
-	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+	if err = m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return err
 	}
 	return nil
 }
+
+// GetMigrationStatus version is zero when no migration was applied
+func GetMigrationStatus(pgx *pgxpool.Pool, path string) (*MigrationStatus, error) {
+	m, err := newMigrate(pgx, path)
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := m.Version()
+	if err != nil {
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return &MigrationStatus{}, nil
+		}
+		return nil, err
+	}
+	return &MigrationStatus{Version: version, Dirty: dirty}, nil
+}
+
+func newMigrate(pgx *pgxpool.Pool, path string) (*migrate.Migrate, error) {
+	db, err := sql.Open("postgres", pgx.Config().ConnString())
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithDatabaseInstance(fmt.Sprintf("file://%s", path), "postgres", driver)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/DimKa163/keeper/internal/server/domain"
 	"github.com/DimKa163/keeper/internal/server/shared/db"
@@ -23,10 +24,22 @@ const (
     				dek,
     				path,
     				version,
-    				deleted
+    				deleted,
+    				vault_id,
+    				size,
+    				charged_to
 					FROM secret 
 					WHERE id = $1 FOR UPDATE`
-	getAllSecretQUERY = `SELECT 
+	getAllSecretQUERY = `SELECT * FROM (` + secretFeedQUERY + `) AS feed
+					WHERE version > $2
+					ORDER BY modified_at ASC`
+	getSecretPageQUERY = `SELECT * FROM (` + secretFeedQUERY + `) AS feed
+					WHERE (version, id) > ($2, $3) AND version <= $4
+					ORDER BY version, id
+					LIMIT $5`
+	// secretFeedQUERY secrets of the user and secrets shared with the user. Shared secret comes under version
+	// of the share with dek sealed to the user, revoked share looks like deleted secret
+	secretFeedQUERY = `SELECT 
     				id, 
     				created_at,
     				modified_at,
@@ -37,10 +50,57 @@ const (
     				dek, 
     				path,
     				version,
-    				deleted
+    				deleted,
+    				'' AS owner,
+    				version AS data_version,
+    				vault_id
 					FROM secret 
-					WHERE user_id = $1 AND version > $2
+					WHERE user_id = $1 AND vault_id IS NULL
+					UNION ALL
+					SELECT 
+    				s.id, 
+    				s.created_at,
+    				s.modified_at,
+    				s.user_id, 
+    				s.big_data, 
+    				s.secret_type, 
+    				CASE WHEN sh.revoked_at IS NULL THEN s.payload END,
+    				CASE WHEN sh.revoked_at IS NULL THEN sh.dek END, 
+    				s.path,
+    				sh.version,
+    				s.deleted OR sh.revoked_at IS NOT NULL,
+    				u.login,
+    				s.version,
+    				s.vault_id
+					FROM share sh
+					JOIN secret s ON s.id = sh.secret_id
+					JOIN users u ON u.id = s.user_id
+					WHERE sh.recipient_id = $1`
+	getAllVaultSecretQUERY = `SELECT * FROM (` + vaultFeedQUERY + `) AS feed
+					WHERE version > $2
 					ORDER BY modified_at ASC`
+	getVaultSecretPageQUERY = `SELECT * FROM (` + vaultFeedQUERY + `) AS feed
+					WHERE (version, id) > ($2, $3) AND version <= $4
+					ORDER BY version, id
+					LIMIT $5`
+	// vaultFeedQUERY secrets of the vault, every member gets them under version of the vault
+	vaultFeedQUERY = `SELECT 
+    				id, 
+    				created_at,
+    				modified_at,
+    				user_id, 
+    				big_data, 
+    				secret_type, 
+    				payload,
+    				dek, 
+    				path,
+    				version,
+    				deleted,
+    				'' AS owner,
+    				version AS data_version,
+    				vault_id
+					FROM secret 
+					WHERE vault_id = $1`
 	insertSecretQUERY = `INSERT INTO secret (
 				 	id,
                   	modified_at,
@@ -51,8 +111,11 @@ const (
     				dek, 
                   	path,
     				version,
-                  	deleted)
-    				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+                  	deleted,
+                  	vault_id,
+                  	size,
+                  	charged_to)
+    				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $3)`
 	updateSecretQUERY = `UPDATE secret 
 							SET
 							user_id = $2,
@@ -61,9 +124,36 @@ const (
 							payload = $5,
 							dek = $6,
 							version = $7,
-							modified_at = $8
+							modified_at = $8,
+							size = $9,
+							charged_to = $10
 							WHERE id = $1`
-	deleteSecretQUERY = `UPDATE secret SET deleted = $2, version=$3 WHERE id = $1`
+	deleteSecretQUERY      = `UPDATE secret SET deleted = $2, version=$3 WHERE id = $1`
+	deleteUserSecretsQUERY = `DELETE FROM secret WHERE user_id = $1 AND vault_id IS NULL RETURNING id, big_data, version`
+	getBigDataSecretsQUERY = `SELECT id, user_id, charged_to, vault_id, COALESCE(version, 0), COALESCE(deleted, FALSE)
+							FROM secret WHERE big_data`
+	secretStatsQUERY = `SELECT charged_to,
+							COUNT(*) FILTER (WHERE NOT COALESCE(deleted, FALSE)),
+							COUNT(*) FILTER (WHERE COALESCE(deleted, FALSE)),
+							COALESCE(SUM(COALESCE(octet_length(payload), 0) + COALESCE(octet_length(dek), 0)), 0)
+							FROM secret GROUP BY charged_to`
+	secretUsageQUERY = `SELECT COUNT(*), COALESCE(SUM(size), 0)
+							FROM secret WHERE charged_to = $1 AND NOT COALESCE(deleted, FALSE)`
+	// purgeableSecretsWHERE deleted secrets modified before $1 that every active device of the owner and of recipients
+	// has pulled, or every active device of members for a vault secret
+	purgeableSecretsWHERE = ` WHERE s.deleted AND s.modified_at < $1
+							AND (s.vault_id IS NOT NULL OR NOT EXISTS (
+								SELECT 1 FROM device d
+								WHERE d.user_id = s.user_id AND d.revoked_at IS NULL AND d.sync_version < s.version))
+							AND (s.vault_id IS NULL OR NOT EXISTS (
+								SELECT 1 FROM vault_member m JOIN device d ON d.user_id = m.user_id
+								LEFT JOIN device_vault_version dv ON dv.device_id = d.id AND dv.vault_id = m.vault_id
+								WHERE m.vault_id = s.vault_id AND d.revoked_at IS NULL AND COALESCE(dv.version, 0) < s.version))
+							AND NOT EXISTS (
+								SELECT 1 FROM share sh JOIN device d ON d.user_id = sh.recipient_id
+								WHERE sh.secret_id = s.id AND d.revoked_at IS NULL AND d.sync_version < sh.version)`
+	getPurgeableSecretsQUERY = `SELECT s.id, s.big_data, COALESCE(s.version, 0) FROM secret s` + purgeableSecretsWHERE
+	purgeDeletedSecretsQUERY = `DELETE FROM secret s` + purgeableSecretsWHERE + ` RETURNING s.id, s.big_data, COALESCE(s.version, 0)`
 )
 
 type SecretRepository struct {
@@ -87,6 +177,9 @@ func (sdr *SecretRepository) Get(ctx context.Context, dataID guid.Guid) (*domain
 	var path string
 	var version int32
 	var deleted bool
+	var vaultID *guid.Guid
+	var size int64
+	var chargedTo guid.Guid
 	if err := sdr.db.QueryRow(ctx, getSecretQUERY, dataID).
 		Scan(&id,
 			&createdAt,
@@ -98,7 +191,10 @@ func (sdr *SecretRepository) Get(ctx context.Context, dataID guid.Guid) (*domain
 			&dek,
 			&path,
 			&version,
-			&deleted); err != nil {
+			&deleted,
+			&vaultID,
+			&size,
+			&chargedTo); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrResourceNotFound
 		}
@@ -128,6 +224,9 @@ func (sdr *SecretRepository) Get(ctx context.Context, dataID guid.Guid) (*domain
 	storedData.Path = path
 	storedData.Version = version
 	storedData.Deleted = deleted
+	storedData.VaultID = vaultID
+	storedData.Size = size
+	storedData.ChargedTo = chargedTo
 	return &storedData, nil
 }
 
@@ -137,60 +236,46 @@ func (sdr *SecretRepository) GetAll(ctx context.Context, userID guid.Guid, great
 		return nil, err
 	}
 	defer row.Close()
-	slice := make([]*domain.Secret, 0)
-	for row.Next() {
-		var data domain.Secret
-		var id guid.Guid
-		var createdAt sql.NullTime
-		var modifiedAt sql.NullTime
-		var usID guid.Guid
-		var typeStr string
-		var bigData bool
-		var payload []byte
-		var dek []byte
-		var path string
-		var version int32
-		var deleted bool
-		if err := row.Scan(&id,
-			&createdAt,
-			&modifiedAt,
-			&usID,
-			&bigData,
-			&typeStr,
-			&payload,
-			&dek,
-			&path,
-			&version,
-			&deleted); err != nil {
-			return nil, err
-		}
-		slice = append(slice, &data)
-		data.ID = id
-		if createdAt.Valid {
-			data.CreatedAt = createdAt.Time
-		}
-		if modifiedAt.Valid {
-			data.ModifiedAt = modifiedAt.Time
-		}
-		data.UserID = userID
-		switch typeStr {
-		case "login_pass":
-			data.Type = domain.LoginPassType
-		case "text":
-			data.Type = domain.TextType
-		case "bank_card":
-			data.Type = domain.BankCardType
-		case "other":
-			data.Type = domain.OtherType
-		}
-		data.BigData = bigData
-		data.Payload = payload
-		data.Dek = dek
-		data.Path = path
-		data.Version = version
-		data.Deleted = deleted
+	return scanSecrets(row)
+}
+
+func (sdr *SecretRepository) GetPage(
+	ctx context.Context,
+	userID guid.Guid,
+	after domain.SecretCursor,
+	until int32,
+	limit int32,
+) ([]*domain.Secret, error) {
+	row, err := sdr.db.Query(ctx, getSecretPageQUERY, userID, after.Version, after.ID, until, limit)
+	if err != nil {
+		return nil, err
 	}
-	return slice, nil
+	defer row.Close()
+	return scanSecrets(row)
+}
+
+func (sdr *SecretRepository) GetVaultAll(ctx context.Context, vaultID guid.Guid, greater int32) ([]*domain.Secret, error) {
+	row, err := sdr.db.Query(ctx, getAllVaultSecretQUERY, vaultID, greater)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanSecrets(row)
+}
+
+func (sdr *SecretRepository) GetVaultPage(
+	ctx context.Context,
+	vaultID guid.Guid,
+	after domain.SecretCursor,
+	until int32,
+	limit int32,
+) ([]*domain.Secret, error) {
+	row, err := sdr.db.Query(ctx, getVaultSecretPageQUERY, vaultID, after.Version, after.ID, until, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	return scanSecrets(row)
 }
 
 func (sdr *SecretRepository) Insert(ctx context.Context, data *domain.Secret) error {
@@ -207,6 +292,8 @@ func (sdr *SecretRepository) Insert(ctx context.Context, data *domain.Secret) er
 		data.Path,
 		data.Version,
 		data.Deleted,
+		data.VaultID,
+		data.Size,
 	); err != nil {
 		return err
 	}
@@ -225,6 +312,8 @@ func (sdr *SecretRepository) Update(ctx context.Context, data *domain.Secret) er
 		data.Dek,
 		data.Version,
 		data.ModifiedAt,
+		data.Size,
+		data.ChargedTo,
 	); err != nil {
 		return err
 	}
@@ -243,3 +332,153 @@ func (sdr *SecretRepository) Delete(ctx context.Context, data *domain.Secret) er
 	}
 	return nil
 }
+
+func (sdr *SecretRepository) DeleteAll(ctx context.Context, userID guid.Guid) ([]*domain.Secret, error) {
+	row, err := sdr.db.Query(ctx, deleteUserSecretsQUERY, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+	removed := make([]*domain.Secret, 0)
+	for row.Next() {
+		secret := domain.Secret{UserID: userID}
+		if err = row.Scan(&secret.ID, &secret.BigData, &secret.Version); err != nil {
+			return nil, err
+		}
+		removed = append(removed, &secret)
+	}
+	return removed, row.Err()
+}
+
+func (sdr *SecretRepository) GetBigData(ctx context.Context) ([]*domain.Secret, error) {
+	rows, err := sdr.db.Query(ctx, getBigDataSecretsQUERY)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	secrets := make([]*domain.Secret, 0)
+	for rows.Next() {
+		secret := domain.Secret{BigData: true}
+		if err = rows.Scan(&secret.ID, &secret.UserID, &secret.ChargedTo, &secret.VaultID, &secret.Version, &secret.Deleted); err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, &secret)
+	}
+	return secrets, rows.Err()
+}
+
+func (sdr *SecretRepository) Stats(ctx context.Context) ([]*domain.SecretStats, error) {
+	rows, err := sdr.db.Query(ctx, secretStatsQUERY)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	stats := make([]*domain.SecretStats, 0)
+	for rows.Next() {
+		var item domain.SecretStats
+		if err = rows.Scan(&item.UserID, &item.Records, &item.Tombstones, &item.PayloadBytes); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &item)
+	}
+	return stats, rows.Err()
+}
+
+func (sdr *SecretRepository) Usage(ctx context.Context, userID guid.Guid) (*domain.SecretUsage, error) {
+	var usage domain.SecretUsage
+	if err := sdr.db.QueryRow(ctx, secretUsageQUERY, userID).Scan(&usage.Secrets, &usage.BlobBytes); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (sdr *SecretRepository) GetPurgeable(ctx context.Context, before time.Time) ([]*domain.Secret, error) {
+	return sdr.queryTombstones(ctx, getPurgeableSecretsQUERY, before)
+}
+
+func (sdr *SecretRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]*domain.Secret, error) {
+	return sdr.queryTombstones(ctx, purgeDeletedSecretsQUERY, before)
+}
+
+func (sdr *SecretRepository) queryTombstones(ctx context.Context, query string, before time.Time) ([]*domain.Secret, error) {
+	rows, err := sdr.db.Query(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	removed := make([]*domain.Secret, 0)
+	for rows.Next() {
+		secret := domain.Secret{Deleted: true}
+		if err = rows.Scan(&secret.ID, &secret.BigData, &secret.Version); err != nil {
+			return nil, err
+		}
+		removed = append(removed, &secret)
+	}
+	return removed, rows.Err()
+}
+
+func scanSecrets(row pgx.Rows) ([]*domain.Secret, error) {
+	slice := make([]*domain.Secret, 0)
+	for row.Next() {
+		var data domain.Secret
+		var id guid.Guid
+		var createdAt sql.NullTime
+		var modifiedAt sql.NullTime
+		var usID guid.Guid
+		var typeStr string
+		var bigData bool
+		var payload []byte
+		var dek []byte
+		var path string
+		var version int32
+		var deleted bool
+		var owner string
+		var dataVersion int32
+		var vaultID *guid.Guid
+		if err := row.Scan(&id,
+			&createdAt,
+			&modifiedAt,
+			&usID,
+			&bigData,
+			&typeStr,
+			&payload,
+			&dek,
+			&path,
+			&version,
+			&deleted,
+			&owner,
+			&dataVersion,
+			&vaultID); err != nil {
+			return nil, err
+		}
+		slice = append(slice, &data)
+		data.ID = id
+		if createdAt.Valid {
+			data.CreatedAt = createdAt.Time
+		}
+		if modifiedAt.Valid {
+			data.ModifiedAt = modifiedAt.Time
+		}
+		data.UserID = usID
+		switch typeStr {
+		case "login_pass":
+			data.Type = domain.LoginPassType
+		case "text":
+			data.Type = domain.TextType
+		case "bank_card":
+			data.Type = domain.BankCardType
+		case "other":
+			data.Type = domain.OtherType
+		}
+		data.BigData = bigData
+		data.Payload = payload
+		data.Dek = dek
+		data.Path = path
+		data.Version = version
+		data.Deleted = deleted
+		data.Owner = owner
+		data.DataVersion = dataVersion
+		data.VaultID = vaultID
+	}
+	return slice, nil
+}
@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/shared/db"
+	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	getSessionQUERY = `SELECT id, created_at, user_id, device_id, last_used_at, expires_at, revoked_at
+					FROM session WHERE id = $1`
+	getActiveSessionQUERY = `SELECT id, created_at, user_id, device_id, last_used_at, expires_at, revoked_at
+					FROM session WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+					ORDER BY created_at`
+	insertSessionQUERY = `INSERT INTO session (user_id, device_id, last_used_at, expires_at) VALUES ($1, $2, $3, $4)
+					RETURNING id, created_at`
+	extendSessionQUERY      = `UPDATE session SET last_used_at = $2, expires_at = $3 WHERE id = $1`
+	revokeSessionQUERY      = `UPDATE session SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	getRefreshTokenQUERY    = `SELECT hash, session_id, used_at FROM refresh_token WHERE hash = $1`
+	insertRefreshTokenQUERY = `INSERT INTO refresh_token (hash, session_id) VALUES ($1, $2)`
+	useRefreshTokenQUERY    = `UPDATE refresh_token SET used_at = $2 WHERE hash = $1 AND used_at IS NULL`
+)
+
+type SessionRepository struct {
+	db db.QueryExecutor
+}
+
+func NewSessionRepository(db db.QueryExecutor) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (sr *SessionRepository) Get(ctx context.Context, id guid.Guid) (*domain.Session, error) {
+	session, err := scanSession(sr.db.QueryRow(ctx, getSessionQUERY, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+func (sr *SessionRepository) GetActive(ctx context.Context, userID guid.Guid, now time.Time) ([]*domain.Session, error) {
+	rows, err := sr.db.Query(ctx, getActiveSessionQUERY, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	sessions := make([]*domain.Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (sr *SessionRepository) Insert(ctx context.Context, session *domain.Session) error {
+	var createdAt sql.NullTime
+	if err := sr.db.QueryRow(ctx, insertSessionQUERY,
+		session.UserID,
+		session.DeviceID,
+		session.LastUsedAt,
+		session.ExpiresAt).Scan(&session.ID, &createdAt); err != nil {
+		return err
+	}
+	if createdAt.Valid {
+		session.CreatedAt = createdAt.Time
+	}
+	return nil
+}
+
+func (sr *SessionRepository) Extend(ctx context.Context, id guid.Guid, usedAt, expiresAt time.Time) error {
+	return sr.exec(ctx, extendSessionQUERY, id, usedAt, expiresAt)
+}
+
+func (sr *SessionRepository) Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	return sr.exec(ctx, revokeSessionQUERY, id, userID, at)
+}
+
+func (sr *SessionRepository) GetToken(ctx context.Context, hash []byte) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	var usedAt sql.NullTime
+	if err := sr.db.QueryRow(ctx, getRefreshTokenQUERY, hash).Scan(&token.Hash, &token.SessionID, &usedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	return &token, nil
+}
+
+func (sr *SessionRepository) InsertToken(ctx context.Context, token *domain.RefreshToken) error {
+	_, err := sr.db.Exec(ctx, insertRefreshTokenQUERY, token.Hash, token.SessionID)
+	return err
+}
+
+func (sr *SessionRepository) UseToken(ctx context.Context, hash []byte, at time.Time) error {
+	return sr.exec(ctx, useRefreshTokenQUERY, hash, at)
+}
+
+// exec run update, ErrResourceNotFound when nothing was changed
+func (sr *SessionRepository) exec(ctx context.Context, query string, args ...any) error {
+	tag, err := sr.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+func scanSession(row pgx.Row) (*domain.Session, error) {
+	var session domain.Session
+	var createdAt, revokedAt sql.NullTime
+	var deviceID *guid.Guid
+	if err := row.Scan(&session.ID,
+		&createdAt,
+		&session.UserID,
+		&deviceID,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&revokedAt); err != nil {
+		return nil, err
+	}
+	session.DeviceID = deviceID
+	if createdAt.Valid {
+		session.CreatedAt = createdAt.Time
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return &session, nil
+}
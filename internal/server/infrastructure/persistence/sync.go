@@ -9,9 +9,14 @@ import (
 )
 
 const (
-	getStateQUERY    = `SELECT id, user_id, value FROM sync_state WHERE id = $1 AND user_id = $2 FOR UPDATE;`
-	insertStateQuery = `INSERT INTO sync_state VALUES ($1, $2, $3);`
-	updateStateQUERY = `UPDATE sync_state SET value = $1 WHERE id = $2 and user_id = $3;`
+	getStateQUERY     = `SELECT id, user_id, value FROM sync_state WHERE id = $1 AND user_id = $2 FOR UPDATE;`
+	insertStateQuery  = `INSERT INTO sync_state VALUES ($1, $2, $3);`
+	updateStateQUERY  = `UPDATE sync_state SET value = $1 WHERE id = $2 and user_id = $3;`
+	deleteStatesQUERY = `DELETE FROM sync_state WHERE user_id = $1;`
+
+	getVaultStateQUERY    = `SELECT vault_id, value FROM vault_sync_state WHERE vault_id = $1 FOR UPDATE;`
+	insertVaultStateQUERY = `INSERT INTO vault_sync_state (vault_id, value) VALUES ($1, $2);`
+	updateVaultStateQUERY = `UPDATE vault_sync_state SET value = $1 WHERE vault_id = $2;`
 )
 
 type SyncStateRepository struct {
@@ -30,7 +35,19 @@ func (sr *SyncStateRepository) Get(ctx context.Context, id string, userID guid.G
 	return &syncState, nil
 }
 
+func (sr *SyncStateRepository) GetVault(ctx context.Context, vaultID guid.Guid) (*domain.SyncState, error) {
+	syncState := domain.SyncState{VaultID: new(guid.Guid)}
+	if err := sr.db.QueryRow(ctx, getVaultStateQUERY, vaultID).Scan(syncState.VaultID, &syncState.Value); err != nil {
+		return nil, err
+	}
+	return &syncState, nil
+}
+
 func (sr *SyncStateRepository) Insert(ctx context.Context, syncState *domain.SyncState) error {
+	if syncState.VaultID != nil {
+		_, err := sr.db.Exec(ctx, insertVaultStateQUERY, *syncState.VaultID, syncState.Value)
+		return err
+	}
 	if _, err := sr.db.Exec(ctx, insertStateQuery, syncState.ID, syncState.UserID, syncState.Value); err != nil {
 		return err
 	}
@@ -38,8 +55,19 @@ func (sr *SyncStateRepository) Insert(ctx context.Context, syncState *domain.Syn
 }
 
 func (sr *SyncStateRepository) Update(ctx context.Context, syncState *domain.SyncState) error {
+	if syncState.VaultID != nil {
+		_, err := sr.db.Exec(ctx, updateVaultStateQUERY, syncState.Value, *syncState.VaultID)
+		return err
+	}
 	if _, err := sr.db.Exec(ctx, updateStateQUERY, syncState.Value, syncState.ID, syncState.UserID); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (sr *SyncStateRepository) DeleteAll(ctx context.Context, userID guid.Guid) error {
+	if _, err := sr.db.Exec(ctx, deleteStatesQUERY, userID); err != nil {
+		return err
+	}
+	return nil
+}
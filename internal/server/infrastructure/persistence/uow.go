@@ -28,6 +28,26 @@ func (u *UnitOfWork) SyncStateRepository() domain.SyncStateRepository {
 	return NewSyncStateRepository(u.db)
 }
 
+func (u *UnitOfWork) DeviceRepository() domain.DeviceRepository {
+	return NewDeviceRepository(u.db)
+}
+
+func (u *UnitOfWork) SessionRepository() domain.SessionRepository {
+	return NewSessionRepository(u.db)
+}
+
+func (u *UnitOfWork) ShareRepository() domain.ShareRepository {
+	return NewShareRepository(u.db)
+}
+
+func (u *UnitOfWork) VaultRepository() domain.VaultRepository {
+	return NewVaultRepository(u.db)
+}
+
+func (u *UnitOfWork) AuditRepository() domain.AuditRepository {
+	return NewAuditRepository(u.db)
+}
+
 func (u *UnitOfWork) Tx(ctx context.Context, fn func(ctx context.Context, work domain.UnitOfWork) error) error {
 	var err error
 	tx, err := u.db.Begin(ctx)
@@ -3,16 +3,29 @@ package persistence
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/DimKa163/keeper/internal/server/domain"
 	"github.com/DimKa163/keeper/internal/server/shared/db"
 	"github.com/beevik/guid"
+	"github.com/jackc/pgx/v5"
 )
 
 const (
-	getUserByLoginQUERY = "SELECT id, created_at, login, password, salt FROM users WHERE login = $1"
-	existQUERY          = "SELECT EXISTS(SELECT id FROM users WHERE login = $1)"
-	insertQueryQUERY    = "INSERT INTO users (login, password, salt) VALUES ($1, $2, $3) RETURNING id"
+	getUserByLoginQUERY      = "SELECT id, created_at, login, password, salt, verifier, public_key, disabled_at FROM users WHERE login = $1"
+	getUserByIDQUERY         = "SELECT id, created_at, login, password, salt, verifier, public_key, disabled_at FROM users WHERE id = $1"
+	listUsersQUERY           = "SELECT id, created_at, login, password, salt, verifier, public_key, disabled_at FROM users ORDER BY login"
+	existQUERY               = "SELECT EXISTS(SELECT id FROM users WHERE login = $1)"
+	insertQueryQUERY         = "INSERT INTO users (login, password, salt, verifier) VALUES ($1, $2, $3, $4) RETURNING id"
+	updateUserPasswordQUERY  = "UPDATE users SET password = $2, salt = $3 WHERE id = $1"
+	updateUserVerifierQUERY  = "UPDATE users SET salt = $2, verifier = $3, password = NULL WHERE id = $1"
+	updateUserLoginQUERY     = "UPDATE users SET login = $2 WHERE id = $1"
+	updateUserPublicKeyQUERY = "UPDATE users SET public_key = $2 WHERE id = $1"
+	updateUserDisabledQUERY  = "UPDATE users SET disabled_at = $2 WHERE id = $1"
+	updateUserRecoveryQUERY  = "UPDATE users SET recovery = $2 WHERE id = $1"
+	getUserRecoveryQUERY     = "SELECT recovery FROM users WHERE id = $1"
+	deleteUserQUERY          = "DELETE FROM users WHERE id = $1"
 )
 
 type userRepository struct {
@@ -24,27 +37,42 @@ func NewUserRepository(db db.QueryExecutor) *userRepository {
 }
 
 func (ur *userRepository) Get(ctx context.Context, login string) (*domain.User, error) {
-	var user domain.User
-	var id guid.Guid
-	var createdAt sql.NullTime
-	var lgn string
-	var pwd []byte
-	var salt []byte
-	if err := ur.db.QueryRow(ctx, getUserByLoginQUERY, login).Scan(&id,
-		&createdAt,
-		&lgn,
-		&pwd,
-		&salt); err != nil {
+	user, err := scanUser(ur.db.QueryRow(ctx, getUserByLoginQUERY, login))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
 		return nil, err
 	}
-	user.ID = id
-	if createdAt.Valid {
-		user.CreatedAt = &createdAt.Time
+	return user, nil
+}
+
+func (ur *userRepository) GetByID(ctx context.Context, id guid.Guid) (*domain.User, error) {
+	user, err := scanUser(ur.db.QueryRow(ctx, getUserByIDQUERY, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
 	}
-	user.Login = login
-	user.Password = pwd
-	user.Salt = salt
-	return &user, nil
+	return user, nil
+}
+
+func (ur *userRepository) List(ctx context.Context) ([]*domain.User, error) {
+	rows, err := ur.db.Query(ctx, listUsersQUERY)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
 }
 
 func (ur *userRepository) Exist(ctx context.Context, login string) (bool, error) {
@@ -62,8 +90,94 @@ func (ur *userRepository) Insert(ctx context.Context, user *domain.User) error {
 		user.Login,
 		user.Password,
 		user.Salt,
+		user.Verifier,
 	); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (ur *userRepository) UpdatePassword(ctx context.Context, id guid.Guid, password, salt []byte) error {
+	return ur.exec(ctx, updateUserPasswordQUERY, id, password, salt)
+}
+
+func (ur *userRepository) UpdateVerifier(ctx context.Context, id guid.Guid, salt, verifier []byte) error {
+	return ur.exec(ctx, updateUserVerifierQUERY, id, salt, verifier)
+}
+
+func (ur *userRepository) UpdateLogin(ctx context.Context, id guid.Guid, login string) error {
+	return ur.exec(ctx, updateUserLoginQUERY, id, login)
+}
+
+func (ur *userRepository) UpdatePublicKey(ctx context.Context, id guid.Guid, publicKey []byte) error {
+	return ur.exec(ctx, updateUserPublicKeyQUERY, id, publicKey)
+}
+
+func (ur *userRepository) UpdateRecovery(ctx context.Context, id guid.Guid, recovery []byte) error {
+	return ur.exec(ctx, updateUserRecoveryQUERY, id, recovery)
+}
+
+func (ur *userRepository) GetRecovery(ctx context.Context, id guid.Guid) ([]byte, error) {
+	var recovery []byte
+	if err := ur.db.QueryRow(ctx, getUserRecoveryQUERY, id).Scan(&recovery); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrResourceNotFound
+		}
+		return nil, err
+	}
+	return recovery, nil
+}
+
+func (ur *userRepository) SetDisabled(ctx context.Context, id guid.Guid, at *time.Time) error {
+	return ur.exec(ctx, updateUserDisabledQUERY, id, at)
+}
+
+func (ur *userRepository) Delete(ctx context.Context, id guid.Guid) error {
+	return ur.exec(ctx, deleteUserQUERY, id)
+}
+
+// exec run update, ErrResourceNotFound when nothing was changed
+func (ur *userRepository) exec(ctx context.Context, query string, args ...any) error {
+	tag, err := ur.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	var id guid.Guid
+	var createdAt, disabledAt sql.NullTime
+	var lgn string
+	var pwd []byte
+	var salt []byte
+	var verifier []byte
+	var publicKey []byte
+	if err := row.Scan(&id,
+		&createdAt,
+		&lgn,
+		&pwd,
+		&salt,
+		&verifier,
+		&publicKey,
+		&disabledAt); err != nil {
+		return nil, err
+	}
+	user.ID = id
+	if createdAt.Valid {
+		user.CreatedAt = &createdAt.Time
+	}
+	user.Login = lgn
+	user.Password = pwd
+	user.Salt = salt
+	user.Verifier = verifier
+	user.PublicKey = publicKey
+	if disabledAt.Valid {
+		user.DisabledAt = &disabledAt.Time
+	}
+	return &user, nil
+}
@@ -3,6 +3,7 @@ package security
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -12,9 +13,16 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+var ErrUnknownKey = errors.New("unknown signing key")
+
 type JWTConfig struct {
 	TokenExpiration time.Duration
-	SecretKey       []byte
+	// SecretKey HS256 shared secret, used when there are no asymmetric keys
+	SecretKey []byte
+	// Keys asymmetric keys, token is verified with the key named in its kid header
+	Keys []*Key
+	// SigningKeyID key new tokens are signed with
+	SigningKeyID string
 }
 type JWTEngine struct {
 	*JWTConfig
@@ -24,16 +32,22 @@ func NewJWTEngine(config *JWTConfig) *JWTEngine {
 	return &JWTEngine{config}
 }
 
-func (engine *JWTEngine) GenerateToken(userID guid.Guid) (string, error) {
-	if engine.SecretKey == nil {
-		return "", errors.New("secret key is required")
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+func (engine *JWTEngine) GenerateToken(userID guid.Guid, deviceID, sessionID string) (string, error) {
+	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(engine.TokenExpiration)),
 			ID:        userID.String(),
 		},
-	})
+		DeviceID:  deviceID,
+		SessionID: sessionID,
+	}
+	if len(engine.Keys) > 0 {
+		return engine.sign(claims)
+	}
+	if engine.SecretKey == nil {
+		return "", errors.New("secret key is required")
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(engine.SecretKey)
 	if err != nil {
@@ -44,13 +58,11 @@ func (engine *JWTEngine) GenerateToken(userID guid.Guid) (string, error) {
 }
 
 func (engine *JWTEngine) ReadToken(tokenString string) (*auth.Claims, error) {
-	if engine.SecretKey == nil {
+	if len(engine.Keys) == 0 && engine.SecretKey == nil {
 		return nil, errors.New("secret key is required")
 	}
 	var claims auth.Claims
-	token, err := jwt.ParseWithClaims(strings.ReplaceAll(tokenString, "Bearer ", ""), &claims, func(token *jwt.Token) (interface{}, error) {
-		return engine.SecretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(strings.ReplaceAll(tokenString, "Bearer ", ""), &claims, engine.verificationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -59,3 +71,43 @@ func (engine *JWTEngine) ReadToken(tokenString string) (*auth.Claims, error) {
 	}
 	return &claims, nil
 }
+
+// sign token with the signing key, its id goes to kid header
+func (engine *JWTEngine) sign(claims auth.Claims) (string, error) {
+	key := engine.key(engine.SigningKeyID)
+	if key == nil || key.Private == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// verificationKey choose key by kid header. Algorithm must match the key,
+// so public key can't be used as HMAC secret
+func (engine *JWTEngine) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		if _, hmac := token.Method.(*jwt.SigningMethodHMAC); !hmac || engine.SecretKey == nil {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return engine.SecretKey, nil
+	}
+	key := engine.key(kid)
+	if key == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.Public, nil
+}
+
+func (engine *JWTEngine) key(id string) *Key {
+	for _, key := range engine.Keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
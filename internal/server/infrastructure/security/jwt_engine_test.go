@@ -16,7 +16,7 @@ func TestJWTEngine_GenerateToken(t *testing.T) {
 	}
 	sut := createJWTEngine(config)
 
-	token, err := sut.GenerateToken(*guid.New())
+	token, err := sut.GenerateToken(*guid.New(), "", "")
 	if err != nil {
 		t.Fatalf("generate tokent return err: %v", err)
 	}
@@ -33,10 +33,30 @@ func TestJWTEngine_GenerateToken_WithEmptyKeyShouldBeError(t *testing.T) {
 	}
 	sut := createJWTEngine(config)
 
-	_, err := sut.GenerateToken(*guid.New())
+	_, err := sut.GenerateToken(*guid.New(), "", "")
 	assert.Error(t, err)
 }
 
 func createJWTEngine(config *JWTConfig) *JWTEngine {
 	return &JWTEngine{config}
 }
+
+func TestJWTEngine_ReadToken_ShouldKeepDevice(t *testing.T) {
+	config := &JWTConfig{
+		TokenExpiration: time.Minute,
+		SecretKey:       []byte("secret"),
+	}
+	sut := createJWTEngine(config)
+	deviceID := guid.NewString()
+	sessionID := guid.NewString()
+	token, err := sut.GenerateToken(*guid.New(), deviceID, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := sut.ReadToken(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, deviceID, claims.DeviceID)
+	assert.Equal(t, sessionID, claims.SessionID)
+}
@@ -0,0 +1,145 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported key type, only ed25519 and rsa are supported")
+	ErrNoSigningKey   = errors.New("no signing key")
+)
+
+const (
+	// keyExt extension of key files, name of the file without extension is key id
+	keyExt = ".pem"
+	// keyIDLayout id of generated key is its creation time, so the newest key sorts last
+	keyIDLayout = "2006-01-02-150405"
+)
+
+// Key asymmetric key of JWT engine. Private is nil for keys only verifying tokens
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// ParseKey read ed25519 or rsa key from PEM block. Private keys are PKCS#8 or PKCS#1, public keys are PKIX or PKCS#1
+func ParseKey(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: PEM block not found", id)
+	}
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unexpected PEM block %s", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	default:
+		return nil, fmt.Errorf("key %s: %w", id, ErrUnsupportedKey)
+	}
+	return key, nil
+}
+
+// LoadKeys read all *.pem keys from the directory, file name is key id
+func LoadKeys(dir string) ([]*Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+keyExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseKey(strings.TrimSuffix(filepath.Base(path), keyExt), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SigningKeyID id of the key tokens are signed with: the given one or private key with the greatest id.
+// Naming keys by creation time makes the newest key signing
+func SigningKeyID(keys []*Key, id string) (string, error) {
+	if id != "" {
+		for _, key := range keys {
+			if key.ID == id {
+				if key.Private == nil {
+					return "", fmt.Errorf("key %s: private key required for signing", id)
+				}
+				return id, nil
+			}
+		}
+		return "", fmt.Errorf("key %s: %w", id, ErrNoSigningKey)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].Private != nil {
+			return keys[i].ID, nil
+		}
+	}
+	return "", ErrNoSigningKey
+}
+
+// GenerateKey write new ed25519 private key to the directory, the key is named by the time,
+// so it becomes the signing key once the server is restarted
+func GenerateKey(dir string, now time.Time) (string, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+	id := now.UTC().Format(keyIDLayout)
+	file, err := os.OpenFile(filepath.Join(dir, id+keyExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err = pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+	return id, file.Close()
+}
@@ -0,0 +1,163 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/beevik/guid"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTEngine_EdDSA_ShouldVerifyToken(t *testing.T) {
+	key := createEd25519Key(t, "2026-01")
+	sut := NewJWTEngine(&JWTConfig{TokenExpiration: time.Minute, Keys: []*Key{key}, SigningKeyID: key.ID})
+	userID := *guid.New()
+
+	token, err := sut.GenerateToken(userID, "", "")
+	assert.NoError(t, err)
+	claims, err := sut.ReadToken(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, userID.String(), claims.ID)
+	assert.Equal(t, key.ID, readKid(t, token))
+}
+
+func TestJWTEngine_RS256_ShouldVerifyToken(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+	key, err := ParseKey("rsa", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sut := NewJWTEngine(&JWTConfig{TokenExpiration: time.Minute, Keys: []*Key{key}, SigningKeyID: key.ID})
+
+	token, err := sut.GenerateToken(*guid.New(), "", "")
+	assert.NoError(t, err)
+	_, err = sut.ReadToken(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodRS256, key.Method)
+}
+
+func TestJWTEngine_Rotation_ShouldAcceptOldKeyUntilRetired(t *testing.T) {
+	oldKey := createEd25519Key(t, "2026-01")
+	newKey := createEd25519Key(t, "2026-02")
+	before := NewJWTEngine(&JWTConfig{TokenExpiration: time.Minute, Keys: []*Key{oldKey}, SigningKeyID: oldKey.ID})
+	token, err := before.GenerateToken(*guid.New(), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := NewJWTEngine(&JWTConfig{TokenExpiration: time.Minute, Keys: []*Key{oldKey, newKey}, SigningKeyID: newKey.ID})
+	retired := NewJWTEngine(&JWTConfig{TokenExpiration: time.Minute, Keys: []*Key{newKey}, SigningKeyID: newKey.ID})
+
+	_, err = rotated.ReadToken(token)
+	assert.NoError(t, err)
+	newToken, err := rotated.GenerateToken(*guid.New(), "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, newKey.ID, readKid(t, newToken))
+	_, err = retired.ReadToken(token)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestJWTEngine_ReadToken_HMACWithPublicKeyShouldFail(t *testing.T) {
+	key := createEd25519Key(t, "2026-01")
+	sut := NewJWTEngine(&JWTConfig{TokenExpiration: time.Minute, Keys: []*Key{key}, SigningKeyID: key.ID})
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{ID: guid.NewString()})
+	token.Header["kid"] = key.ID
+	forged, err := token.SignedString([]byte(key.Public.(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sut.ReadToken(forged)
+
+	assert.Error(t, err)
+}
+
+func TestLoadKeys_ShouldChooseNewestPrivateKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "2026-01", createEd25519PEM(t, false))
+	writeKey(t, dir, "2026-02", createEd25519PEM(t, false))
+	// retired key still verifies
+	writeKey(t, dir, "2025-12", createEd25519PEM(t, true))
+
+	keys, err := LoadKeys(dir)
+	assert.NoError(t, err)
+	id, err := SigningKeyID(keys, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, keys, 3)
+	assert.Equal(t, "2026-02", id)
+	_, err = SigningKeyID(keys, "2025-12")
+	assert.Error(t, err)
+}
+
+func TestGenerateKey_ShouldBecomeSigningKey(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "2026-01", createEd25519PEM(t, false))
+
+	id, err := GenerateKey(dir, time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-19-123000", id)
+	keys, err := LoadKeys(dir)
+	assert.NoError(t, err)
+	signing, err := SigningKeyID(keys, "")
+	assert.NoError(t, err)
+	assert.Equal(t, id, signing)
+	_, err = GenerateKey(dir, time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC))
+	assert.Error(t, err)
+}
+
+func createEd25519Key(t *testing.T, id string) *Key {
+	key, err := ParseKey(id, createEd25519PEM(t, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func createEd25519PEM(t *testing.T, public bool) []byte {
+	pub, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if public {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func writeKey(t *testing.T, dir, id string, data []byte) {
+	if err := os.WriteFile(filepath.Join(dir, id+keyExt), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readKid(t *testing.T, token string) string {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
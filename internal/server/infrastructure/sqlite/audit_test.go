@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_FindShouldFilterEventsNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	user := insertTestUser(t, uow, "dima")
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	repository := uow.AuditRepository()
+	events := []*domain.AuditEvent{
+		{Type: domain.AuditLoginFailed, Login: "dima", At: at},
+		{Type: domain.AuditLogin, UserID: &user.ID, At: at.Add(time.Minute)},
+		{Type: domain.AuditLoginFailed, Login: "vova", At: at.Add(2 * time.Minute)},
+	}
+	for _, event := range events {
+		assert.NoError(t, repository.Insert(ctx, event))
+	}
+
+	found, err := repository.Find(ctx, &domain.AuditFilter{Login: "dima", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, events[1].ID, found[0].ID)
+	assert.Equal(t, "dima", found[0].Login)
+	assert.Equal(t, user.ID, *found[0].UserID)
+	assert.Equal(t, at.Add(time.Minute), found[0].At)
+
+	found, err = repository.Find(ctx, &domain.AuditFilter{Since: at.Add(time.Minute), BeforeID: events[2].ID, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, events[1].ID, found[0].ID)
+
+	_, err = uow.db.Exec(ctx, "DELETE FROM audit")
+	assert.ErrorContains(t, err, "append-only")
+}
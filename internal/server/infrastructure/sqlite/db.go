@@ -0,0 +1,364 @@
+// Package sqlite server storage in a single SQLite file, for small installations without Postgres
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	_ "modernc.org/sqlite"
+)
+
+// Scheme of DATABASE url selecting SQLite, the rest of url is path to the file
+const Scheme = "sqlite://"
+
+// timeFormat times are stored as UTC text of fixed width, so they compare in time order
+const timeFormat = "2006-01-02 15:04:05.000000"
+
+// nowSQL current time in timeFormat, SQLite keeps milliseconds only
+const nowSQL = "strftime('%Y-%m-%d %H:%M:%f000', 'now')"
+
+// pragmas set on every connection. Transactions begin immediate, so they take the write lock on begin,
+// as FOR UPDATE does in Postgres, and writer of another process is waited for instead of failing at once
+const pragmas = "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_txlock=immediate"
+
+// IsSQLite database url selects SQLite
+func IsSQLite(database string) bool {
+	return strings.HasPrefix(database, Scheme)
+}
+
+// DB SQLite database of the server. SQLite has a single writer, so writes of the process are queued
+// on the write lock instead of waiting on busy database, readers aren't blocked
+type DB struct {
+	db     *sql.DB
+	writer chan struct{}
+}
+
+// Open open database file of sqlite://path url, the file is created when it doesn't exist
+func Open(database string) (*DB, error) {
+	path := strings.TrimPrefix(database, Scheme)
+	if path == "" {
+		return nil, errors.New("path to database file is required")
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s%s%s", path, separator, pragmas))
+	if err != nil {
+		return nil, err
+	}
+	return &DB{db: db, writer: make(chan struct{}, 1)}, nil
+}
+
+func (db *DB) Ping(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+func (db *DB) Close() error {
+	return db.db.Close()
+}
+
+// lock take the write lock, it's released by the returned func
+func (db *DB) lock(ctx context.Context) (func(), error) {
+	select {
+	case db.writer <- struct{}{}:
+		return func() { <-db.writer }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// executor run statements on the database or in transaction. Statements made with context of a transaction
+// run in it
+type executor struct {
+	db *DB
+	q  querier
+	tx *sql.Tx
+}
+
+// txKey context key of the transaction of the database
+type txKey struct {
+	db *DB
+}
+
+// Exec run write statement
+func (e *executor) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	q, release, err := e.writer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return q.ExecContext(ctx, query, toArgs(args)...)
+}
+
+// Query run read statement
+func (e *executor) Query(ctx context.Context, query string, args ...any) (*rows, error) {
+	return e.query(ctx, e.reader(ctx), func() {}, query, args...)
+}
+
+// QueryRow run read statement returning a row
+func (e *executor) QueryRow(ctx context.Context, query string, args ...any) *row {
+	return e.queryRow(ctx, e.reader(ctx), func() {}, query, args...)
+}
+
+// QueryWrite run write statement returning rows
+func (e *executor) QueryWrite(ctx context.Context, query string, args ...any) (*rows, error) {
+	q, release, err := e.writer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return e.query(ctx, q, release, query, args...)
+}
+
+// QueryRowWrite run write statement returning a row
+func (e *executor) QueryRowWrite(ctx context.Context, query string, args ...any) *row {
+	q, release, err := e.writer(ctx)
+	if err != nil {
+		return &row{err: err}
+	}
+	return e.queryRow(ctx, q, release, query, args...)
+}
+
+func (e *executor) query(ctx context.Context, q querier, release func(), query string, args ...any) (*rows, error) {
+	r, err := q.QueryContext(ctx, query, toArgs(args)...)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &rows{rows: r, release: release}, nil
+}
+
+func (e *executor) queryRow(ctx context.Context, q querier, release func(), query string, args ...any) *row {
+	return &row{row: q.QueryRowContext(ctx, query, toArgs(args)...), release: release}
+}
+
+// reader querier of the statement, transaction of context when there is one
+func (e *executor) reader(ctx context.Context) querier {
+	if tx := e.active(ctx); tx != nil {
+		return tx.q
+	}
+	return e.q
+}
+
+// writer querier of the statement and release of the write lock taken for it
+func (e *executor) writer(ctx context.Context) (querier, func(), error) {
+	if tx := e.active(ctx); tx != nil {
+		return tx.q, func() {}, nil
+	}
+	release, err := e.db.lock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.q, release, nil
+}
+
+// active transaction executor runs in, own or the one of context
+func (e *executor) active(ctx context.Context) *executor {
+	if e.tx != nil {
+		return e
+	}
+	if work, ok := ctx.Value(txKey{db: e.db}).(*UnitOfWork); ok {
+		return work.db
+	}
+	return nil
+}
+
+// execChanged run update, ErrResourceNotFound when nothing was changed
+func execChanged(ctx context.Context, db *executor, query string, args ...any) error {
+	result, err := db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return persistence.ErrResourceNotFound
+	}
+	return nil
+}
+
+type rows struct {
+	rows    *sql.Rows
+	release func()
+}
+
+func (r *rows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *rows) Scan(dest ...any) error {
+	return r.rows.Scan(toDest(dest)...)
+}
+
+func (r *rows) Err() error {
+	return r.rows.Err()
+}
+
+func (r *rows) Close() {
+	_ = r.rows.Close()
+	r.release()
+}
+
+type row struct {
+	row     *sql.Row
+	release func()
+	err     error
+}
+
+// Scan ErrResourceNotFound when there is no row
+func (r *row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.release()
+	if err := r.row.Scan(toDest(dest)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return persistence.ErrResourceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// toArgs ids are stored as text, times as text of timeFormat
+func toArgs(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case guid.Guid:
+			converted[i] = v.String()
+		case *guid.Guid:
+			if v != nil {
+				converted[i] = v.String()
+			}
+		case time.Time:
+			converted[i] = v.UTC().Format(timeFormat)
+		case *time.Time:
+			if v != nil {
+				converted[i] = v.UTC().Format(timeFormat)
+			}
+		default:
+			converted[i] = arg
+		}
+	}
+	return converted
+}
+
+// toDest read ids and times back from text
+func toDest(dest []any) []any {
+	converted := make([]any, len(dest))
+	for i, d := range dest {
+		switch v := d.(type) {
+		case *guid.Guid:
+			converted[i] = &guidScanner{dest: v}
+		case **guid.Guid:
+			converted[i] = &nullGuidScanner{dest: v}
+		case *time.Time:
+			converted[i] = &timeScanner{dest: v}
+		case *sql.NullTime:
+			converted[i] = &nullTimeScanner{dest: v}
+		default:
+			converted[i] = d
+		}
+	}
+	return converted
+}
+
+type guidScanner struct {
+	dest *guid.Guid
+}
+
+func (s *guidScanner) Scan(value any) error {
+	id, err := parseGuid(value)
+	if err != nil {
+		return err
+	}
+	if id == nil {
+		return errors.New("id is null")
+	}
+	*s.dest = *id
+	return nil
+}
+
+type nullGuidScanner struct {
+	dest **guid.Guid
+}
+
+func (s *nullGuidScanner) Scan(value any) error {
+	id, err := parseGuid(value)
+	if err != nil {
+		return err
+	}
+	*s.dest = id
+	return nil
+}
+
+func parseGuid(value any) (*guid.Guid, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return guid.ParseString(v)
+	case []byte:
+		return guid.ParseString(string(v))
+	default:
+		return nil, fmt.Errorf("unsupported id type %T", value)
+	}
+}
+
+type timeScanner struct {
+	dest *time.Time
+}
+
+func (s *timeScanner) Scan(value any) error {
+	var t sql.NullTime
+	if err := (&nullTimeScanner{dest: &t}).Scan(value); err != nil {
+		return err
+	}
+	if !t.Valid {
+		return errors.New("time is null")
+	}
+	*s.dest = t.Time
+	return nil
+}
+
+// nullTimeScanner driver parses time of columns declared as timestamp, values of expressions come as text
+type nullTimeScanner struct {
+	dest *sql.NullTime
+}
+
+func (s *nullTimeScanner) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*s.dest = sql.NullTime{}
+	case time.Time:
+		*s.dest = sql.NullTime{Time: v.UTC(), Valid: true}
+	case string:
+		t, err := time.Parse("2006-01-02 15:04:05.999999999", v)
+		if err != nil {
+			return err
+		}
+		*s.dest = sql.NullTime{Time: t, Valid: true}
+	default:
+		return fmt.Errorf("unsupported time type %T", value)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+const (
+	getDeviceQUERY = `SELECT id, created_at, user_id, name, public_key, last_seen_at, sync_version, revoked_at, approved_at
+					FROM device WHERE id = ?1`
+	getAllDeviceQUERY = `SELECT id, created_at, user_id, name, public_key, last_seen_at, sync_version, revoked_at, approved_at
+					FROM device WHERE user_id = ?1
+					ORDER BY created_at`
+	insertDeviceQUERY             = `INSERT INTO device (id, user_id, name, public_key, approved_at) VALUES (?1, ?2, ?3, ?4, ?5) RETURNING created_at`
+	renameDeviceQUERY             = `UPDATE device SET name = ?3 WHERE id = ?1 AND user_id = ?2`
+	revokeDeviceQUERY             = `UPDATE device SET revoked_at = ?3 WHERE id = ?1 AND user_id = ?2 AND revoked_at IS NULL`
+	touchDeviceQUERY              = `UPDATE device SET last_seen_at = ?2 WHERE id = ?1`
+	updateDeviceSyncVersionQUERY  = `UPDATE device SET sync_version = ?2 WHERE id = ?1`
+	updateDeviceVaultVersionQUERY = `INSERT INTO device_vault_version (device_id, vault_id, version)
+					SELECT id, ?2, ?3 FROM device WHERE id = ?1
+					ON CONFLICT (device_id, vault_id) DO UPDATE SET version = excluded.version`
+	approveDeviceQUERY = `UPDATE device SET approved_at = ?3
+					WHERE id = ?1 AND user_id = ?2 AND approved_at IS NULL AND revoked_at IS NULL`
+)
+
+type DeviceRepository struct {
+	db *executor
+}
+
+func NewDeviceRepository(db *executor) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+func (dr *DeviceRepository) Get(ctx context.Context, id guid.Guid) (*domain.Device, error) {
+	return scanDevice(dr.db.QueryRow(ctx, getDeviceQUERY, id))
+}
+
+func (dr *DeviceRepository) GetAll(ctx context.Context, userID guid.Guid) ([]*domain.Device, error) {
+	rows, err := dr.db.Query(ctx, getAllDeviceQUERY, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	devices := make([]*domain.Device, 0)
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
+
+func (dr *DeviceRepository) Insert(ctx context.Context, device *domain.Device) error {
+	id := *guid.New()
+	var createdAt sql.NullTime
+	if err := dr.db.QueryRowWrite(ctx, insertDeviceQUERY, id, device.UserID, device.Name, device.PublicKey, device.ApprovedAt).
+		Scan(&createdAt); err != nil {
+		return err
+	}
+	device.ID = id
+	if createdAt.Valid {
+		device.CreatedAt = createdAt.Time
+	}
+	return nil
+}
+
+func (dr *DeviceRepository) Rename(ctx context.Context, id, userID guid.Guid, name string) error {
+	return execChanged(ctx, dr.db, renameDeviceQUERY, id, userID, name)
+}
+
+func (dr *DeviceRepository) Revoke(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	return execChanged(ctx, dr.db, revokeDeviceQUERY, id, userID, at)
+}
+
+func (dr *DeviceRepository) Approve(ctx context.Context, id, userID guid.Guid, at time.Time) error {
+	return execChanged(ctx, dr.db, approveDeviceQUERY, id, userID, at)
+}
+
+func (dr *DeviceRepository) Touch(ctx context.Context, id guid.Guid, at time.Time) error {
+	return execChanged(ctx, dr.db, touchDeviceQUERY, id, at)
+}
+
+func (dr *DeviceRepository) UpdateSyncVersion(ctx context.Context, id guid.Guid, version int32) error {
+	return execChanged(ctx, dr.db, updateDeviceSyncVersionQUERY, id, version)
+}
+
+func (dr *DeviceRepository) UpdateVaultSyncVersion(ctx context.Context, id, vaultID guid.Guid, version int32) error {
+	return execChanged(ctx, dr.db, updateDeviceVaultVersionQUERY, id, vaultID, version)
+}
+
+func scanDevice(row scanner) (*domain.Device, error) {
+	var device domain.Device
+	var createdAt, lastSeenAt, revokedAt, approvedAt sql.NullTime
+	if err := row.Scan(&device.ID,
+		&createdAt,
+		&device.UserID,
+		&device.Name,
+		&device.PublicKey,
+		&lastSeenAt,
+		&device.SyncVersion,
+		&revokedAt,
+		&approvedAt); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		device.CreatedAt = createdAt.Time
+	}
+	if lastSeenAt.Valid {
+		device.LastSeenAt = &lastSeenAt.Time
+	}
+	if revokedAt.Valid {
+		device.RevokedAt = &revokedAt.Time
+	}
+	if approvedAt.Valid {
+		device.ApprovedAt = &approvedAt.Time
+	}
+	return &device, nil
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/golang-migrate/migrate/v4"
+	sqlitedb "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// migrations schema of SQLite database is built into the server, so the single binary is enough to run it
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate apply migrations of the directory, built in migrations when path is empty
+func Migrate(db *DB, path string) error {
+	m, err := newMigrate(db, path)
+	if err != nil {
+		return err
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// MigrateDown roll back the given number of the last migrations
+func MigrateDown(db *DB, path string, steps int) error {
+	m, err := newMigrate(db, path)
+	if err != nil {
+		return err
+	}
+	if err = m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// GetMigrationStatus version is zero when no migration was applied
+func GetMigrationStatus(db *DB, path string) (*persistence.MigrationStatus, error) {
+	m, err := newMigrate(db, path)
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := m.Version()
+	if err != nil {
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return &persistence.MigrationStatus{}, nil
+		}
+		return nil, err
+	}
+	return &persistence.MigrationStatus{Version: version, Dirty: dirty}, nil
+}
+
+func newMigrate(db *DB, path string) (*migrate.Migrate, error) {
+	driver, err := sqlitedb.WithInstance(db.db, &sqlitedb.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		return migrate.NewWithDatabaseInstance(fmt.Sprintf("file://%s", path), "sqlite", driver)
+	}
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", source, "sqlite", driver)
+}
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS audit;
+DROP TABLE IF EXISTS share;
+DROP TABLE IF EXISTS refresh_token;
+DROP TABLE IF EXISTS session;
+DROP TABLE IF EXISTS device;
+DROP TABLE IF EXISTS sync_state;
+DROP TABLE IF EXISTS secret;
+DROP TABLE IF EXISTS vault_member;
+DROP TABLE IF EXISTS vault;
+DROP TABLE IF EXISTS users;
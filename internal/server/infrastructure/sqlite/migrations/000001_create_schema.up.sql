@@ -0,0 +1,136 @@
+CREATE TABLE IF NOT EXISTS users(
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    login TEXT NOT NULL,
+    password BLOB NULL,
+    salt BLOB NOT NULL,
+    verifier BLOB NULL,
+    public_key BLOB NULL,
+    disabled_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_login_uix ON users(login);
+
+CREATE TABLE IF NOT EXISTS vault(
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now'))
+);
+
+CREATE TABLE IF NOT EXISTS vault_member(
+    vault_id TEXT NOT NULL REFERENCES vault(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL,
+    vault_key BLOB NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+
+    PRIMARY KEY(vault_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS vault_member_user_idx ON vault_member (user_id);
+
+CREATE TABLE IF NOT EXISTS secret(
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    modified_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    user_id TEXT NOT NULL,
+    big_data BOOLEAN NOT NULL,
+    secret_type TEXT NOT NULL CHECK (secret_type IN ('login_pass', 'text', 'bank_card', 'other')),
+    payload BLOB,
+    dek BLOB,
+    path TEXT,
+    version INTEGER NOT NULL DEFAULT 0,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    vault_id TEXT NULL REFERENCES vault(id) ON DELETE CASCADE,
+    size INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS secret_user_version_id_idx ON secret (user_id, version, id);
+CREATE INDEX IF NOT EXISTS secret_vault_version_idx ON secret (vault_id, version, id) WHERE vault_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS secret_deleted_idx ON secret (modified_at) WHERE deleted;
+
+CREATE TABLE IF NOT EXISTS sync_state(
+    id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    value INTEGER,
+
+    PRIMARY KEY(id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS device(
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    public_key BLOB NOT NULL,
+    last_seen_at TIMESTAMP,
+    sync_version INTEGER NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS device_user_idx ON device (user_id);
+
+CREATE TABLE IF NOT EXISTS session(
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id TEXT NULL REFERENCES device(id) ON DELETE CASCADE,
+    last_used_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS session_user_idx ON session (user_id);
+
+CREATE TABLE IF NOT EXISTS refresh_token(
+    hash BLOB PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES session(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS refresh_token_session_idx ON refresh_token (session_id);
+
+CREATE TABLE IF NOT EXISTS share(
+    secret_id TEXT NOT NULL REFERENCES secret(id) ON DELETE CASCADE,
+    recipient_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    owner_id TEXT NOT NULL,
+    dek BLOB NOT NULL,
+    version INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now')),
+    revoked_at TIMESTAMP,
+
+    PRIMARY KEY(secret_id, recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS share_recipient_version_idx ON share (recipient_id, version, secret_id);
+CREATE INDEX IF NOT EXISTS share_owner_idx ON share (owner_id);
+
+CREATE TABLE IF NOT EXISTS audit(
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type TEXT NOT NULL,
+    user_id TEXT NULL,
+    login TEXT NOT NULL DEFAULT '',
+    device_id TEXT NULL,
+    client_id TEXT NOT NULL DEFAULT '',
+    peer TEXT NOT NULL DEFAULT '',
+    secret_id TEXT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    bytes INTEGER NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS audit_user_idx ON audit (user_id, id);
+CREATE INDEX IF NOT EXISTS audit_login_idx ON audit (login, id);
+
+CREATE TRIGGER IF NOT EXISTS audit_append_only_update BEFORE UPDATE ON audit
+BEGIN
+    SELECT RAISE(ABORT, 'audit is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_append_only_delete BEFORE DELETE ON audit
+BEGIN
+    SELECT RAISE(ABORT, 'audit is append-only');
+END;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS device_vault_version(
+    device_id TEXT NOT NULL REFERENCES device(id) ON DELETE CASCADE,
+    vault_id TEXT NOT NULL REFERENCES vault(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+
+    PRIMARY KEY(device_id, vault_id)
+);
+
+CREATE INDEX IF NOT EXISTS device_vault_version_vault_idx ON device_vault_version (vault_id);
@@ -0,0 +1,4 @@
+INSERT OR IGNORE INTO sync_state (id, user_id, value)
+SELECT 'Secret', vault_id, value FROM vault_sync_state;
+
+DROP TABLE IF EXISTS vault_sync_state;
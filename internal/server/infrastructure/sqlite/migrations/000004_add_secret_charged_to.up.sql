@@ -0,0 +1,4 @@
+ALTER TABLE secret ADD COLUMN charged_to TEXT NULL;
+UPDATE secret SET charged_to = user_id;
+
+CREATE INDEX IF NOT EXISTS secret_charged_to_idx ON secret (charged_to);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN recovery BLOB NULL;
@@ -0,0 +1 @@
+ALTER TABLE device DROP COLUMN approved_at;
@@ -0,0 +1,2 @@
+ALTER TABLE device ADD COLUMN approved_at TIMESTAMP NULL;
+UPDATE device SET approved_at = created_at WHERE approved_at IS NULL;
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+const (
+	getShareQUERY = `SELECT sh.secret_id, sh.owner_id, sh.recipient_id, u.login, u.public_key, sh.dek, sh.version, sh.created_at, sh.revoked_at
+					FROM share sh JOIN users u ON u.id = sh.recipient_id
+					WHERE sh.secret_id = ?1 AND sh.recipient_id = ?2`
+	getSecretSharesQUERY = `SELECT sh.secret_id, sh.owner_id, sh.recipient_id, u.login, u.public_key, sh.dek, sh.version, sh.created_at, sh.revoked_at
+					FROM share sh JOIN users u ON u.id = sh.recipient_id
+					WHERE sh.secret_id = ?1 AND sh.revoked_at IS NULL
+					ORDER BY u.login`
+	getOwnerSharesQUERY = `SELECT sh.secret_id, sh.owner_id, sh.recipient_id, u.login, u.public_key, sh.dek, sh.version, sh.created_at, sh.revoked_at
+					FROM share sh JOIN users u ON u.id = sh.recipient_id
+					WHERE sh.owner_id = ?1 AND sh.revoked_at IS NULL
+					ORDER BY sh.secret_id, u.login`
+	saveShareQUERY = `INSERT INTO share (secret_id, recipient_id, owner_id, dek, version) VALUES (?1, ?2, ?3, ?4, ?5)
+					ON CONFLICT (secret_id, recipient_id) DO UPDATE
+					SET dek = excluded.dek, version = excluded.version, created_at = ` + nowSQL + `, revoked_at = NULL
+					RETURNING created_at`
+	updateShareQUERY = `UPDATE share SET dek = ?3, version = ?4 WHERE secret_id = ?1 AND recipient_id = ?2`
+	revokeShareQUERY = `UPDATE share SET version = ?3, revoked_at = ?4 WHERE secret_id = ?1 AND recipient_id = ?2 AND revoked_at IS NULL`
+)
+
+type ShareRepository struct {
+	db *executor
+}
+
+func NewShareRepository(db *executor) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+func (sr *ShareRepository) Get(ctx context.Context, secretID, recipientID guid.Guid) (*domain.Share, error) {
+	return scanShare(sr.db.QueryRow(ctx, getShareQUERY, secretID, recipientID))
+}
+
+func (sr *ShareRepository) GetBySecret(ctx context.Context, secretID guid.Guid) ([]*domain.Share, error) {
+	return sr.query(ctx, getSecretSharesQUERY, secretID)
+}
+
+func (sr *ShareRepository) GetByOwner(ctx context.Context, ownerID guid.Guid) ([]*domain.Share, error) {
+	return sr.query(ctx, getOwnerSharesQUERY, ownerID)
+}
+
+func (sr *ShareRepository) Save(ctx context.Context, share *domain.Share) error {
+	var createdAt sql.NullTime
+	if err := sr.db.QueryRowWrite(ctx, saveShareQUERY, share.SecretID, share.RecipientID, share.OwnerID, share.Dek, share.Version).
+		Scan(&createdAt); err != nil {
+		return err
+	}
+	if createdAt.Valid {
+		share.CreatedAt = createdAt.Time
+	}
+	share.RevokedAt = nil
+	return nil
+}
+
+func (sr *ShareRepository) Update(ctx context.Context, share *domain.Share) error {
+	return execChanged(ctx, sr.db, updateShareQUERY, share.SecretID, share.RecipientID, share.Dek, share.Version)
+}
+
+func (sr *ShareRepository) Revoke(ctx context.Context, secretID, recipientID guid.Guid, version int32, at time.Time) error {
+	return execChanged(ctx, sr.db, revokeShareQUERY, secretID, recipientID, version, at)
+}
+
+func (sr *ShareRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Share, error) {
+	rows, err := sr.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	shares := make([]*domain.Share, 0)
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+func scanShare(row scanner) (*domain.Share, error) {
+	var share domain.Share
+	var createdAt sql.NullTime
+	var revokedAt sql.NullTime
+	if err := row.Scan(&share.SecretID,
+		&share.OwnerID,
+		&share.RecipientID,
+		&share.Recipient,
+		&share.PublicKey,
+		&share.Dek,
+		&share.Version,
+		&createdAt,
+		&revokedAt); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		share.CreatedAt = createdAt.Time
+	}
+	if revokedAt.Valid {
+		share.RevokedAt = &revokedAt.Time
+	}
+	return &share, nil
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+const (
+	// getStateQUERY row isn't locked, transaction holds the write lock of the whole database
+	getStateQUERY     = `SELECT id, user_id, value FROM sync_state WHERE id = ?1 AND user_id = ?2`
+	insertStateQUERY  = `INSERT INTO sync_state (id, user_id, value) VALUES (?1, ?2, ?3)`
+	updateStateQUERY  = `UPDATE sync_state SET value = ?1 WHERE id = ?2 AND user_id = ?3`
+	deleteStatesQUERY = `DELETE FROM sync_state WHERE user_id = ?1`
+
+	getVaultStateQUERY    = `SELECT vault_id, value FROM vault_sync_state WHERE vault_id = ?1`
+	insertVaultStateQUERY = `INSERT INTO vault_sync_state (vault_id, value) VALUES (?1, ?2)`
+	updateVaultStateQUERY = `UPDATE vault_sync_state SET value = ?1 WHERE vault_id = ?2`
+)
+
+type SyncStateRepository struct {
+	db *executor
+}
+
+func NewSyncStateRepository(db *executor) *SyncStateRepository {
+	return &SyncStateRepository{db: db}
+}
+
+func (sr *SyncStateRepository) Get(ctx context.Context, id string, userID guid.Guid) (*domain.SyncState, error) {
+	var syncState domain.SyncState
+	if err := sr.db.QueryRow(ctx, getStateQUERY, id, userID).Scan(&syncState.ID, &syncState.UserID, &syncState.Value); err != nil {
+		return nil, err
+	}
+	return &syncState, nil
+}
+
+func (sr *SyncStateRepository) GetVault(ctx context.Context, vaultID guid.Guid) (*domain.SyncState, error) {
+	syncState := domain.SyncState{VaultID: new(guid.Guid)}
+	if err := sr.db.QueryRow(ctx, getVaultStateQUERY, vaultID).Scan(syncState.VaultID, &syncState.Value); err != nil {
+		return nil, err
+	}
+	return &syncState, nil
+}
+
+func (sr *SyncStateRepository) Insert(ctx context.Context, syncState *domain.SyncState) error {
+	if syncState.VaultID != nil {
+		_, err := sr.db.Exec(ctx, insertVaultStateQUERY, *syncState.VaultID, syncState.Value)
+		return err
+	}
+	_, err := sr.db.Exec(ctx, insertStateQUERY, syncState.ID, syncState.UserID, syncState.Value)
+	return err
+}
+
+func (sr *SyncStateRepository) Update(ctx context.Context, syncState *domain.SyncState) error {
+	if syncState.VaultID != nil {
+		_, err := sr.db.Exec(ctx, updateVaultStateQUERY, syncState.Value, *syncState.VaultID)
+		return err
+	}
+	_, err := sr.db.Exec(ctx, updateStateQUERY, syncState.Value, syncState.ID, syncState.UserID)
+	return err
+}
+
+func (sr *SyncStateRepository) DeleteAll(ctx context.Context, userID guid.Guid) error {
+	_, err := sr.db.Exec(ctx, deleteStatesQUERY, userID)
+	return err
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncStateRepository_VaultStateShouldBeKeptApartFromUsers(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	owner := insertTestUser(t, uow, "dima")
+	vault := &domain.Vault{ID: *guid.New(), Name: "team", CreatedBy: owner.ID}
+	assert.NoError(t, uow.VaultRepository().Insert(ctx, vault))
+	repository := uow.SyncStateRepository()
+	assert.NoError(t, repository.Insert(ctx, &domain.SyncState{ID: "Secret", UserID: owner.ID, Value: 5}))
+	assert.NoError(t, repository.Insert(ctx, &domain.SyncState{VaultID: &vault.ID}))
+	assert.NoError(t, repository.Update(ctx, &domain.SyncState{VaultID: &vault.ID, Value: 2}))
+
+	state, err := repository.GetVault(ctx, vault.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), state.Value)
+	assert.Equal(t, vault.ID, *state.VaultID)
+	_, err = repository.Get(ctx, "Secret", vault.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+
+	assert.NoError(t, uow.VaultRepository().Delete(ctx, vault.ID))
+	_, err = repository.GetVault(ctx, vault.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	state, err = repository.Get(ctx, "Secret", owner.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), state.Value)
+}
@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+type UnitOfWork struct {
+	db *executor
+	// savepoints nested transactions started in the transaction
+	savepoints int
+}
+
+func NewUnitOfWork(db *DB) *UnitOfWork {
+	return &UnitOfWork{db: &executor{db: db, q: db.db}}
+}
+
+func (u *UnitOfWork) UserRepository() domain.UserRepository {
+	return NewUserRepository(u.db)
+}
+
+func (u *UnitOfWork) SecretRepository() domain.SecretRepository {
+	return NewSecretRepository(u.db)
+}
+
+func (u *UnitOfWork) SyncStateRepository() domain.SyncStateRepository {
+	return NewSyncStateRepository(u.db)
+}
+
+func (u *UnitOfWork) DeviceRepository() domain.DeviceRepository {
+	return NewDeviceRepository(u.db)
+}
+
+func (u *UnitOfWork) SessionRepository() domain.SessionRepository {
+	return NewSessionRepository(u.db)
+}
+
+func (u *UnitOfWork) ShareRepository() domain.ShareRepository {
+	return NewShareRepository(u.db)
+}
+
+func (u *UnitOfWork) VaultRepository() domain.VaultRepository {
+	return NewVaultRepository(u.db)
+}
+
+func (u *UnitOfWork) AuditRepository() domain.AuditRepository {
+	return NewAuditRepository(u.db)
+}
+
+// Tx run fn in transaction holding the write lock, fn must not wait for clients.
+// Nested transaction is a savepoint
+func (u *UnitOfWork) Tx(ctx context.Context, fn func(ctx context.Context, work domain.UnitOfWork) error) error {
+	if u.db.tx != nil {
+		return u.savepoint(ctx, fn)
+	}
+	if work, ok := ctx.Value(txKey{db: u.db.db}).(*UnitOfWork); ok {
+		return work.savepoint(ctx, fn)
+	}
+	release, err := u.db.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	tx, err := u.db.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	work := &UnitOfWork{db: &executor{db: u.db.db, q: tx, tx: tx}}
+	if err = fn(context.WithValue(ctx, txKey{db: u.db.db}, work), work); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (u *UnitOfWork) savepoint(ctx context.Context, fn func(ctx context.Context, work domain.UnitOfWork) error) error {
+	u.savepoints++
+	name := fmt.Sprintf("sp_%d", u.savepoints)
+	if _, err := u.db.Exec(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+	if err := fn(ctx, &UnitOfWork{db: u.db, savepoints: u.savepoints}); err != nil {
+		_, _ = u.db.Exec(ctx, "ROLLBACK TO "+name)
+		_, _ = u.db.Exec(ctx, "RELEASE "+name)
+		return err
+	}
+	_, err := u.db.Exec(ctx, "RELEASE "+name)
+	return err
+}
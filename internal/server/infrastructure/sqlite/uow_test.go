@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_TxShouldRollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	failure := errors.New("failure")
+
+	err := uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		if err := work.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	_, err = uow.UserRepository().Get(ctx, "dima")
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+}
+
+func TestUnitOfWork_NestedTxShouldRollbackToSavepoint(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	failure := errors.New("failure")
+
+	err := uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		if err := work.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))); err != nil {
+			return err
+		}
+		err := work.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+			if err := work.UserRepository().Insert(ctx, domain.NewUser("vova", []byte("pwd"), []byte("salt"))); err != nil {
+				return err
+			}
+			return failure
+		})
+		assert.ErrorIs(t, err, failure)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	exist, err := uow.UserRepository().Exist(ctx, "dima")
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	exist, err = uow.UserRepository().Exist(ctx, "vova")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func TestUnitOfWork_TxShouldBeSerialized(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	userID := *guid.New()
+	assert.NoError(t, uow.SyncStateRepository().Insert(ctx, &domain.SyncState{ID: "Record", UserID: userID}))
+
+	const pushes = 20
+	var wg sync.WaitGroup
+	for range pushes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+				state, err := work.SyncStateRepository().Get(ctx, "Record", userID)
+				if err != nil {
+					return err
+				}
+				state.Value++
+				return work.SyncStateRepository().Update(ctx, state)
+			}))
+		}()
+	}
+	wg.Wait()
+
+	state, err := uow.SyncStateRepository().Get(ctx, "Record", userID)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(pushes), state.Value)
+}
+
+func TestUnitOfWork_WriteShouldWaitForTx(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+			if err := work.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))); err != nil {
+				return err
+			}
+			close(started)
+			<-finish
+			return nil
+		})
+	}()
+	<-started
+	written := make(chan error)
+	go func() {
+		written <- uow.UserRepository().Insert(ctx, domain.NewUser("vova", []byte("pwd"), []byte("salt")))
+	}()
+	exist, err := uow.UserRepository().Exist(ctx, "dima")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	select {
+	case <-written:
+		t.Fatal("write should wait for transaction")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(finish)
+
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-written)
+	users, err := uow.UserRepository().List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+}
+
+func TestUnitOfWork_WriteWithTxContextShouldRunInTx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	uow := newTestUnitOfWork(t)
+	failure := errors.New("failure")
+
+	err := uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		// the outer unit of work reuses the lock of the transaction in context
+		if err := uow.UserRepository().Insert(ctx, domain.NewUser("dima", []byte("pwd"), []byte("salt"))); err != nil {
+			return err
+		}
+		exist, err := work.UserRepository().Exist(ctx, "dima")
+		if err != nil {
+			return err
+		}
+		assert.True(t, exist)
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	exist, err := uow.UserRepository().Exist(context.Background(), "dima")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
+
+func newTestUnitOfWork(t *testing.T) *UnitOfWork {
+	db, err := Open(Scheme + filepath.Join(t.TempDir(), "keeper.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	if err = Migrate(db, ""); err != nil {
+		t.Fatal(err)
+	}
+	return NewUnitOfWork(db)
+}
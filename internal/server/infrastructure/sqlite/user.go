@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+const (
+	getUserByLoginQUERY      = "SELECT id, created_at, login, password, salt, verifier, public_key, disabled_at FROM users WHERE login = ?1"
+	getUserByIDQUERY         = "SELECT id, created_at, login, password, salt, verifier, public_key, disabled_at FROM users WHERE id = ?1"
+	listUsersQUERY           = "SELECT id, created_at, login, password, salt, verifier, public_key, disabled_at FROM users ORDER BY login"
+	existQUERY               = "SELECT EXISTS(SELECT id FROM users WHERE login = ?1)"
+	insertUserQUERY          = "INSERT INTO users (id, login, password, salt, verifier) VALUES (?1, ?2, ?3, ?4, ?5)"
+	updateUserPasswordQUERY  = "UPDATE users SET password = ?2, salt = ?3 WHERE id = ?1"
+	updateUserVerifierQUERY  = "UPDATE users SET salt = ?2, verifier = ?3, password = NULL WHERE id = ?1"
+	updateUserLoginQUERY     = "UPDATE users SET login = ?2 WHERE id = ?1"
+	updateUserPublicKeyQUERY = "UPDATE users SET public_key = ?2 WHERE id = ?1"
+	updateUserDisabledQUERY  = "UPDATE users SET disabled_at = ?2 WHERE id = ?1"
+	updateUserRecoveryQUERY  = "UPDATE users SET recovery = ?2 WHERE id = ?1"
+	getUserRecoveryQUERY     = "SELECT recovery FROM users WHERE id = ?1"
+	deleteUserQUERY          = "DELETE FROM users WHERE id = ?1"
+)
+
+type UserRepository struct {
+	db *executor
+}
+
+func NewUserRepository(db *executor) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (ur *UserRepository) Get(ctx context.Context, login string) (*domain.User, error) {
+	return scanUser(ur.db.QueryRow(ctx, getUserByLoginQUERY, login))
+}
+
+func (ur *UserRepository) GetByID(ctx context.Context, id guid.Guid) (*domain.User, error) {
+	return scanUser(ur.db.QueryRow(ctx, getUserByIDQUERY, id))
+}
+
+func (ur *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
+	rows, err := ur.db.Query(ctx, listUsersQUERY)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (ur *UserRepository) Exist(ctx context.Context, login string) (bool, error) {
+	var exist bool
+	if err := ur.db.QueryRow(ctx, existQUERY, login).Scan(&exist); err != nil {
+		return false, err
+	}
+	return exist, nil
+}
+
+// Insert id of the user is generated when it isn't set
+func (ur *UserRepository) Insert(ctx context.Context, user *domain.User) error {
+	id := user.ID
+	if id == (guid.Guid{}) {
+		id = *guid.New()
+	}
+	if _, err := ur.db.Exec(ctx, insertUserQUERY, id, user.Login, user.Password, user.Salt, user.Verifier); err != nil {
+		return err
+	}
+	user.ID = id
+	return nil
+}
+
+func (ur *UserRepository) UpdatePassword(ctx context.Context, id guid.Guid, password, salt []byte) error {
+	return execChanged(ctx, ur.db, updateUserPasswordQUERY, id, password, salt)
+}
+
+func (ur *UserRepository) UpdateVerifier(ctx context.Context, id guid.Guid, salt, verifier []byte) error {
+	return execChanged(ctx, ur.db, updateUserVerifierQUERY, id, salt, verifier)
+}
+
+func (ur *UserRepository) UpdateLogin(ctx context.Context, id guid.Guid, login string) error {
+	return execChanged(ctx, ur.db, updateUserLoginQUERY, id, login)
+}
+
+func (ur *UserRepository) UpdatePublicKey(ctx context.Context, id guid.Guid, publicKey []byte) error {
+	return execChanged(ctx, ur.db, updateUserPublicKeyQUERY, id, publicKey)
+}
+
+func (ur *UserRepository) UpdateRecovery(ctx context.Context, id guid.Guid, recovery []byte) error {
+	return execChanged(ctx, ur.db, updateUserRecoveryQUERY, id, recovery)
+}
+
+func (ur *UserRepository) GetRecovery(ctx context.Context, id guid.Guid) ([]byte, error) {
+	var recovery []byte
+	if err := ur.db.QueryRow(ctx, getUserRecoveryQUERY, id).Scan(&recovery); err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+func (ur *UserRepository) SetDisabled(ctx context.Context, id guid.Guid, at *time.Time) error {
+	return execChanged(ctx, ur.db, updateUserDisabledQUERY, id, at)
+}
+
+func (ur *UserRepository) Delete(ctx context.Context, id guid.Guid) error {
+	return execChanged(ctx, ur.db, deleteUserQUERY, id)
+}
+
+func scanUser(row scanner) (*domain.User, error) {
+	var user domain.User
+	var createdAt, disabledAt sql.NullTime
+	if err := row.Scan(&user.ID,
+		&createdAt,
+		&user.Login,
+		&user.Password,
+		&user.Salt,
+		&user.Verifier,
+		&user.PublicKey,
+		&disabledAt); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		user.CreatedAt = &createdAt.Time
+	}
+	if disabledAt.Valid {
+		user.DisabledAt = &disabledAt.Time
+	}
+	return &user, nil
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRepository_DeleteShouldRemoveDevicesAndSessions(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	user := insertTestUser(t, uow, "dima")
+	device := &domain.Device{UserID: user.ID, Name: "laptop", PublicKey: []byte("key")}
+	assert.NoError(t, uow.DeviceRepository().Insert(ctx, device))
+	now := time.Now().UTC()
+	session := &domain.Session{UserID: user.ID, DeviceID: &device.ID, LastUsedAt: now, ExpiresAt: now.Add(time.Hour)}
+	assert.NoError(t, uow.SessionRepository().Insert(ctx, session))
+	active, err := uow.SessionRepository().GetActive(ctx, user.ID, now)
+	assert.NoError(t, err)
+	assert.Len(t, active, 1)
+	assert.Equal(t, device.ID, *active[0].DeviceID)
+
+	assert.NoError(t, uow.UserRepository().Delete(ctx, user.ID))
+
+	_, err = uow.DeviceRepository().Get(ctx, device.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	_, err = uow.SessionRepository().Get(ctx, session.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+	assert.ErrorIs(t, uow.UserRepository().Delete(ctx, user.ID), persistence.ErrResourceNotFound)
+}
+
+func TestUserRepository_RecoveryShouldBeKeptWithUser(t *testing.T) {
+	ctx := context.Background()
+	uow := newTestUnitOfWork(t)
+	user := insertTestUser(t, uow, "dima")
+
+	recovery, err := uow.UserRepository().GetRecovery(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, recovery)
+	assert.NoError(t, uow.UserRepository().UpdateRecovery(ctx, user.ID, []byte("wrapped")))
+	recovery, err = uow.UserRepository().GetRecovery(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("wrapped"), recovery)
+
+	assert.NoError(t, uow.UserRepository().Delete(ctx, user.ID))
+	_, err = uow.UserRepository().GetRecovery(ctx, user.ID)
+	assert.ErrorIs(t, err, persistence.ErrResourceNotFound)
+}
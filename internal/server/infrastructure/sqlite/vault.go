@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+const (
+	insertVaultQUERY = `INSERT INTO vault (id, name, created_by) VALUES (?1, ?2, ?3) RETURNING created_at`
+	getMemberQUERY   = `SELECT m.vault_id, m.user_id, m.role, m.vault_key, m.created_at, v.name, u.login, u.public_key
+					FROM vault_member m
+					JOIN vault v ON v.id = m.vault_id
+					JOIN users u ON u.id = m.user_id
+					WHERE m.vault_id = ?1 AND m.user_id = ?2`
+	getMembersQUERY = `SELECT m.vault_id, m.user_id, m.role, m.vault_key, m.created_at, v.name, u.login, u.public_key
+					FROM vault_member m
+					JOIN vault v ON v.id = m.vault_id
+					JOIN users u ON u.id = m.user_id
+					WHERE m.vault_id = ?1
+					ORDER BY u.login`
+	getUserVaultsQUERY = `SELECT m.vault_id, m.user_id, m.role, m.vault_key, m.created_at, v.name, u.login, u.public_key
+					FROM vault_member m
+					JOIN vault v ON v.id = m.vault_id
+					JOIN users u ON u.id = m.user_id
+					WHERE m.user_id = ?1
+					ORDER BY v.name, v.id`
+	saveMemberQUERY = `INSERT INTO vault_member (vault_id, user_id, role, vault_key) VALUES (?1, ?2, ?3, ?4)
+					ON CONFLICT (vault_id, user_id) DO UPDATE
+					SET role = excluded.role, vault_key = excluded.vault_key
+					RETURNING created_at`
+	deleteMemberQUERY = `DELETE FROM vault_member WHERE vault_id = ?1 AND user_id = ?2`
+	deleteVaultQUERY  = `DELETE FROM vault WHERE id = ?1`
+)
+
+type VaultRepository struct {
+	db *executor
+}
+
+func NewVaultRepository(db *executor) *VaultRepository {
+	return &VaultRepository{db: db}
+}
+
+func (vr *VaultRepository) Insert(ctx context.Context, vault *domain.Vault) error {
+	var createdAt sql.NullTime
+	if err := vr.db.QueryRowWrite(ctx, insertVaultQUERY, vault.ID, vault.Name, vault.CreatedBy).Scan(&createdAt); err != nil {
+		return err
+	}
+	if createdAt.Valid {
+		vault.CreatedAt = createdAt.Time
+	}
+	return nil
+}
+
+func (vr *VaultRepository) GetMember(ctx context.Context, vaultID, userID guid.Guid) (*domain.VaultMember, error) {
+	return scanMember(vr.db.QueryRow(ctx, getMemberQUERY, vaultID, userID))
+}
+
+func (vr *VaultRepository) GetMembers(ctx context.Context, vaultID guid.Guid) ([]*domain.VaultMember, error) {
+	return vr.query(ctx, getMembersQUERY, vaultID)
+}
+
+func (vr *VaultRepository) GetByUser(ctx context.Context, userID guid.Guid) ([]*domain.VaultMember, error) {
+	return vr.query(ctx, getUserVaultsQUERY, userID)
+}
+
+func (vr *VaultRepository) SaveMember(ctx context.Context, member *domain.VaultMember) error {
+	var createdAt sql.NullTime
+	if err := vr.db.QueryRowWrite(ctx, saveMemberQUERY, member.VaultID, member.UserID, member.Role.String(), member.VaultKey).
+		Scan(&createdAt); err != nil {
+		return err
+	}
+	if createdAt.Valid {
+		member.CreatedAt = createdAt.Time
+	}
+	return nil
+}
+
+func (vr *VaultRepository) DeleteMember(ctx context.Context, vaultID, userID guid.Guid) error {
+	return execChanged(ctx, vr.db, deleteMemberQUERY, vaultID, userID)
+}
+
+func (vr *VaultRepository) Delete(ctx context.Context, vaultID guid.Guid) error {
+	return execChanged(ctx, vr.db, deleteVaultQUERY, vaultID)
+}
+
+func (vr *VaultRepository) query(ctx context.Context, query string, args ...any) ([]*domain.VaultMember, error) {
+	rows, err := vr.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	members := make([]*domain.VaultMember, 0)
+	for rows.Next() {
+		member, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func scanMember(row scanner) (*domain.VaultMember, error) {
+	var member domain.VaultMember
+	var role string
+	var createdAt sql.NullTime
+	if err := row.Scan(&member.VaultID,
+		&member.UserID,
+		&role,
+		&member.VaultKey,
+		&createdAt,
+		&member.VaultName,
+		&member.Login,
+		&member.PublicKey); err != nil {
+		return nil, err
+	}
+	var err error
+	if member.Role, err = domain.ParseVaultRole(role); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		member.CreatedAt = createdAt.Time
+	}
+	return &member, nil
+}
@@ -0,0 +1,76 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AuditServer struct {
+	app *usecase.AuditService
+	pb.UnimplementedAuditsServer
+}
+
+func NewAuditServer(app *usecase.AuditService) *AuditServer {
+	return &AuditServer{app: app}
+}
+
+func (as *AuditServer) Bind(server *grpc.Server) {
+	pb.RegisterAuditsServer(server, as)
+}
+
+func (as *AuditServer) Audit(ctx context.Context, in *pb.AuditRequest) (*pb.AuditResponse, error) {
+	query := &usecase.AuditQuery{
+		Login:    in.GetLogin(),
+		All:      in.GetAll(),
+		BeforeID: in.GetBeforeId(),
+		Limit:    in.GetLimit(),
+	}
+	if in.HasSince() {
+		query.Since = in.GetSince().AsTime()
+	}
+	events, err := as.app.Query(ctx, query)
+	if err != nil {
+		if errors.Is(err, usecase.ErrAuditForbidden) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	items := make([]*pb.AuditEvent, 0, len(events))
+	for _, event := range events {
+		items = append(items, toAuditEvent(event))
+	}
+	var response pb.AuditResponse
+	response.SetEvents(items)
+	return &response, nil
+}
+
+func toAuditEvent(event *domain.AuditEvent) *pb.AuditEvent {
+	var item pb.AuditEvent
+	item.SetId(event.ID)
+	item.SetType(event.Type)
+	if event.UserID != nil {
+		item.SetUserId(event.UserID.String())
+	}
+	item.SetLogin(event.Login)
+	if event.DeviceID != nil {
+		item.SetDeviceId(event.DeviceID.String())
+	}
+	item.SetClientId(event.Client)
+	item.SetPeer(event.Peer)
+	if event.SecretID != nil {
+		item.SetSecretId(event.SecretID.String())
+	}
+	item.SetVersion(event.Version)
+	item.SetBytes(event.Bytes)
+	item.SetReason(event.Reason)
+	item.SetAt(timestamppb.New(event.At))
+	return &item
+}
@@ -2,9 +2,12 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 
+	"github.com/DimKa163/keeper/internal/common"
 	"github.com/DimKa163/keeper/internal/server/domain/auth"
 	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/DimKa163/keeper/internal/server/usecase"
 	"github.com/beevik/guid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -12,55 +15,31 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func UnaryIdentifyInterceptor(engine auth.Engine, skip map[string]bool) grpc.UnaryServerInterceptor {
+// Verifiers check device and session of a token are still valid
+type Verifiers struct {
+	Devices  auth.DeviceVerifier
+	Sessions auth.SessionVerifier
+}
+
+func UnaryIdentifyInterceptor(engine auth.Engine, verifiers *Verifiers, skip map[string]bool) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		if _, ok := skip[info.FullMethod]; ok {
 			return handler(ctx, req)
 		}
-		mtdata, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
-		}
-		val := mtdata.Get("authorization")
-		if len(val) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "authorization not found in context")
-		}
-		cl, err := engine.ReadToken(val[0])
+		ctx, err := identify(ctx, engine, verifiers)
 		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "authorization expired")
+			return nil, err
 		}
-		id, err := guid.ParseString(cl.ID)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "unrecognized user id")
-		}
-		ctx = sh.SetUser(ctx, *id)
 		return handler(ctx, req)
 	}
 }
 
-func StreamIdentifyInterceptor(engine auth.Engine) grpc.StreamServerInterceptor {
+func StreamIdentifyInterceptor(engine auth.Engine, verifiers *Verifiers) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		ctx := ss.Context()
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return status.Error(codes.Unauthenticated, "missing metadata")
-		}
-		if !ok {
-			return status.Error(codes.Unauthenticated, "metadata not found in context")
-		}
-		val := md.Get("authorization")
-		if len(val) == 0 {
-			return status.Error(codes.Unauthenticated, "authorization not found in context")
-		}
-		cl, err := engine.ReadToken(val[0])
-		if err != nil {
-			return status.Error(codes.Unauthenticated, "authorization expired")
-		}
-		id, err := guid.ParseString(cl.ID)
+		ctx, err := identify(ss.Context(), engine, verifiers)
 		if err != nil {
-			return status.Error(codes.Internal, "unrecognized user id")
+			return err
 		}
-		ctx = sh.SetUser(ctx, *id)
 		return handler(srv, &wrappedServerStream{
 			ServerStream: ss,
 			ctx:          ctx,
@@ -68,6 +47,59 @@ func StreamIdentifyInterceptor(engine auth.Engine) grpc.StreamServerInterceptor
 	}
 }
 
+// identify read user, device and session from token, tokens of revoked devices and sessions are rejected.
+// Token without device comes from a login of user without devices, login of user with devices must be signed
+func identify(ctx context.Context, engine auth.Engine, verifiers *Verifiers) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "metadata not found in context")
+	}
+	val := md.Get("authorization")
+	if len(val) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization not found in context")
+	}
+	cl, err := engine.ReadToken(val[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "authorization expired")
+	}
+	id, err := guid.ParseString(cl.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "unrecognized user id")
+	}
+	ctx = sh.SetUser(ctx, *id)
+	if cl.DeviceID != "" {
+		deviceID, err := guid.ParseString(cl.DeviceID)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "unrecognized device id")
+		}
+		if err = verifiers.Devices.VerifyDevice(ctx, *id, *deviceID); err != nil {
+			if errors.Is(err, usecase.ErrDeviceRevoked) || errors.Is(err, usecase.ErrDeviceNotFound) ||
+				errors.Is(err, usecase.ErrDevicePending) {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		ctx = sh.SetDevice(ctx, *deviceID)
+	}
+	if cl.SessionID != "" {
+		sessionID, err := guid.ParseString(cl.SessionID)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "unrecognized session id")
+		}
+		if err = verifiers.Sessions.VerifySession(ctx, *id, *sessionID); err != nil {
+			if errors.Is(err, usecase.ErrSessionRevoked) || errors.Is(err, usecase.ErrSessionNotFound) {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		ctx = sh.SetSession(ctx, *sessionID)
+	}
+	if clientID, err := common.ReadClientIDFromHeader(ctx); err == nil {
+		ctx = sh.SetClient(ctx, clientID)
+	}
+	return ctx, nil
+}
+
 type wrappedServerStream struct {
 	grpc.ServerStream
 	ctx context.Context
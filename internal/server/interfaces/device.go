@@ -0,0 +1,132 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/beevik/guid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type DeviceServer struct {
+	app *usecase.DeviceService
+	pb.UnimplementedDevicesServer
+}
+
+func NewDeviceServer(app *usecase.DeviceService) *DeviceServer {
+	return &DeviceServer{app: app}
+}
+
+func (ds *DeviceServer) Bind(server *grpc.Server) {
+	pb.RegisterDevicesServer(server, ds)
+}
+
+func (ds *DeviceServer) Register(ctx context.Context, in *pb.RegisterDeviceRequest) (*pb.RegisterDeviceResponse, error) {
+	if !in.HasLogin() || (!in.HasPassword() && !in.HasHandshakeId()) || !in.HasName() {
+		return nil, status.Error(codes.InvalidArgument, "login, password or handshake and name required")
+	}
+	creds := toCredentials(in.GetPassword(), in.GetHandshakeId(), in.GetProof())
+	device, err := ds.app.Register(ctx, in.GetLogin(), creds, in.GetName(), in.GetPublicKey())
+	if err != nil {
+		return nil, toDeviceError(err)
+	}
+	var response pb.RegisterDeviceResponse
+	response.SetId(device.ID.String())
+	response.SetPending(device.Pending())
+	return &response, nil
+}
+
+func (ds *DeviceServer) List(ctx context.Context, _ *pb.ListDevicesRequest) (*pb.ListDevicesResponse, error) {
+	devices, err := ds.app.List(ctx)
+	if err != nil {
+		return nil, toDeviceError(err)
+	}
+	current, _ := sh.Device(ctx)
+	items := make([]*pb.Device, 0, len(devices))
+	for _, device := range devices {
+		items = append(items, toDevice(device, current))
+	}
+	var response pb.ListDevicesResponse
+	response.SetDevices(items)
+	return &response, nil
+}
+
+func (ds *DeviceServer) Rename(ctx context.Context, in *pb.RenameDeviceRequest) (*pb.Device, error) {
+	id, err := guid.ParseString(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid device id")
+	}
+	if in.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name required")
+	}
+	device, err := ds.app.Rename(ctx, *id, in.GetName())
+	if err != nil {
+		return nil, toDeviceError(err)
+	}
+	current, _ := sh.Device(ctx)
+	return toDevice(device, current), nil
+}
+
+func (ds *DeviceServer) Revoke(ctx context.Context, in *pb.RevokeDeviceRequest) (*pb.RevokeDeviceResponse, error) {
+	id, err := guid.ParseString(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid device id")
+	}
+	if err = ds.app.Revoke(ctx, *id); err != nil {
+		return nil, toDeviceError(err)
+	}
+	return &pb.RevokeDeviceResponse{}, nil
+}
+
+func (ds *DeviceServer) Approve(ctx context.Context, in *pb.ApproveDeviceRequest) (*pb.ApproveDeviceResponse, error) {
+	id, err := guid.ParseString(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid device id")
+	}
+	if err = ds.app.Approve(ctx, *id); err != nil {
+		return nil, toDeviceError(err)
+	}
+	return &pb.ApproveDeviceResponse{}, nil
+}
+
+func toDevice(device *domain.Device, current guid.Guid) *pb.Device {
+	var item pb.Device
+	item.SetId(device.ID.String())
+	item.SetName(device.Name)
+	item.SetCreatedAt(timestamppb.New(device.CreatedAt))
+	if device.LastSeenAt != nil {
+		item.SetLastSeenAt(timestamppb.New(*device.LastSeenAt))
+	}
+	item.SetSyncVersion(device.SyncVersion)
+	item.SetRevoked(device.Revoked())
+	item.SetPending(device.Pending())
+	item.SetCurrent(device.ID == current)
+	return &item
+}
+
+func toDeviceError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, usecase.ErrInvalidCredentials.Error())
+	case errors.Is(err, usecase.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, usecase.ErrLegacyLoginDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, usecase.ErrDeviceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidPublicKey):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrApproverRequired):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
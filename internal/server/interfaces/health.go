@@ -5,16 +5,20 @@ import (
 	"context"
 
 	"github.com/DimKa163/keeper/internal/pb"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/grpc"
 )
 
+// Pinger database connection, pgxpool.Pool and sqlite.DB are
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 type HealthService struct {
-	db *pgxpool.Pool
+	db Pinger
 	pb.UnimplementedHealthServiceServer
 }
 
-func NewHealthService(db *pgxpool.Pool) *HealthService {
+func NewHealthService(db Pinger) *HealthService {
 	return &HealthService{db: db}
 }
 
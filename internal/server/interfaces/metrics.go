@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCMetrics where RPCs handled by the server are counted, metrics.Metrics is
+type RPCMetrics interface {
+	Handled(method, kind string, code codes.Code, elapsed time.Duration)
+	StreamStarted(method string)
+	StreamFinished(method string)
+}
+
+// UnaryMetricsInterceptor count RPC with its status code and latency, RPC rejected by later interceptors is counted too
+func UnaryMetricsInterceptor(metrics RPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		startTime := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.Handled(info.FullMethod, "unary", status.Code(err), time.Since(startTime))
+		return resp, err
+	}
+}
+
+// StreamMetricsInterceptor count stream while it's open and when it's closed with status code
+func StreamMetricsInterceptor(metrics RPCMetrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		startTime := time.Now()
+		metrics.StreamStarted(info.FullMethod)
+		defer metrics.StreamFinished(info.FullMethod)
+		err := handler(srv, ss)
+		metrics.Handled(info.FullMethod, "stream", status.Code(err), time.Since(startTime))
+		return err
+	}
+}
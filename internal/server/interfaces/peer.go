@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"net"
+
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryPeerInterceptor put client address to context, port is dropped so
+// all connections of the same host are counted together
+func UnaryPeerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(withPeer(ctx), req)
+	}
+}
+
+// StreamPeerInterceptor put client address to context of the stream
+func StreamPeerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedServerStream{
+			ServerStream: ss,
+			ctx:          withPeer(ss.Context()),
+		})
+	}
+}
+
+func withPeer(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ctx
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return sh.SetPeer(ctx, host)
+}
@@ -0,0 +1,82 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/beevik/guid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type SessionServer struct {
+	app *usecase.SessionService
+	pb.UnimplementedSessionsServer
+}
+
+func NewSessionServer(app *usecase.SessionService) *SessionServer {
+	return &SessionServer{app: app}
+}
+
+func (ss *SessionServer) Bind(server *grpc.Server) {
+	pb.RegisterSessionsServer(server, ss)
+}
+
+func (ss *SessionServer) List(ctx context.Context, _ *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	sessions, err := ss.app.List(ctx)
+	if err != nil {
+		return nil, toSessionError(err)
+	}
+	current, _ := sh.Session(ctx)
+	items := make([]*pb.Session, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, toSession(session, current))
+	}
+	var response pb.ListSessionsResponse
+	response.SetSessions(items)
+	return &response, nil
+}
+
+func (ss *SessionServer) Terminate(ctx context.Context, in *pb.TerminateSessionRequest) (*pb.TerminateSessionResponse, error) {
+	id, err := guid.ParseString(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid session id")
+	}
+	if err = ss.app.Terminate(ctx, *id); err != nil {
+		return nil, toSessionError(err)
+	}
+	return &pb.TerminateSessionResponse{}, nil
+}
+
+func (ss *SessionServer) Logout(ctx context.Context, _ *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	if err := ss.app.Logout(ctx); err != nil {
+		return nil, toSessionError(err)
+	}
+	return &pb.LogoutResponse{}, nil
+}
+
+func toSession(session *domain.Session, current guid.Guid) *pb.Session {
+	var item pb.Session
+	item.SetId(session.ID.String())
+	item.SetCreatedAt(timestamppb.New(session.CreatedAt))
+	item.SetLastUsedAt(timestamppb.New(session.LastUsedAt))
+	item.SetExpiresAt(timestamppb.New(session.ExpiresAt))
+	if session.DeviceID != nil {
+		item.SetDeviceId(session.DeviceID.String())
+	}
+	item.SetCurrent(session.ID == current)
+	return &item
+}
+
+func toSessionError(err error) error {
+	if errors.Is(err, usecase.ErrSessionNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,110 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/beevik/guid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ShareServer struct {
+	app *usecase.ShareService
+	pb.UnimplementedSharesServer
+}
+
+func NewShareServer(app *usecase.ShareService) *ShareServer {
+	return &ShareServer{app: app}
+}
+
+func (ss *ShareServer) Bind(server *grpc.Server) {
+	pb.RegisterSharesServer(server, ss)
+}
+
+func (ss *ShareServer) SetPublicKey(ctx context.Context, in *pb.SetPublicKeyRequest) (*pb.SetPublicKeyResponse, error) {
+	if err := ss.app.SetPublicKey(ctx, in.GetPublicKey()); err != nil {
+		return nil, toShareError(err)
+	}
+	return &pb.SetPublicKeyResponse{}, nil
+}
+
+func (ss *ShareServer) GetPublicKey(ctx context.Context, in *pb.GetPublicKeyRequest) (*pb.GetPublicKeyResponse, error) {
+	key, err := ss.app.GetPublicKey(ctx, in.GetLogin())
+	if err != nil {
+		return nil, toShareError(err)
+	}
+	var response pb.GetPublicKeyResponse
+	response.SetLogin(in.GetLogin())
+	response.SetPublicKey(key)
+	return &response, nil
+}
+
+func (ss *ShareServer) Share(ctx context.Context, in *pb.ShareRequest) (*pb.ShareResponse, error) {
+	id, err := guid.ParseString(in.GetSecretId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid secret id")
+	}
+	if err = ss.app.Share(ctx, *id, in.GetLogin(), in.GetDek()); err != nil {
+		return nil, toShareError(err)
+	}
+	return &pb.ShareResponse{}, nil
+}
+
+func (ss *ShareServer) Revoke(ctx context.Context, in *pb.RevokeShareRequest) (*pb.RevokeShareResponse, error) {
+	id, err := guid.ParseString(in.GetSecretId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid secret id")
+	}
+	if err = ss.app.Revoke(ctx, *id, in.GetLogin()); err != nil {
+		return nil, toShareError(err)
+	}
+	return &pb.RevokeShareResponse{}, nil
+}
+
+func (ss *ShareServer) List(ctx context.Context, _ *pb.ListSharesRequest) (*pb.ListSharesResponse, error) {
+	shares, err := ss.app.List(ctx)
+	if err != nil {
+		return nil, toShareError(err)
+	}
+	items := make([]*pb.ShareInfo, 0, len(shares))
+	for _, share := range shares {
+		items = append(items, toShareInfo(share))
+	}
+	var response pb.ListSharesResponse
+	response.SetShares(items)
+	return &response, nil
+}
+
+func toShareInfo(share *domain.Share) *pb.ShareInfo {
+	var item pb.ShareInfo
+	item.SetSecretId(share.SecretID.String())
+	item.SetLogin(share.Recipient)
+	item.SetPublicKey(share.PublicKey)
+	item.SetCreatedAt(timestamppb.New(share.CreatedAt))
+	return &item
+}
+
+func toShareError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrSecretNotFound),
+		errors.Is(err, usecase.ErrUserNotFound),
+		errors.Is(err, usecase.ErrShareNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrSecretNotOwned):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, usecase.ErrInvalidShareKey),
+		errors.Is(err, usecase.ErrSealedDekRequired),
+		errors.Is(err, usecase.ErrShareWithSelf):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrNoPublicKey):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/DimKa163/keeper/internal/datatool"
 	"github.com/DimKa163/keeper/internal/pb"
 	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
 	"github.com/DimKa163/keeper/internal/server/shared/logging"
 	"github.com/DimKa163/keeper/internal/server/usecase"
 	"github.com/beevik/guid"
@@ -46,10 +47,19 @@ func (ss *SyncServer) PushStream(stream pb.Sync_PushStreamServer) error {
 	if err != nil {
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
-	if err = ss.app.ValidateVersion(ctx, v); err != nil && !force {
-		return status.Error(codes.FailedPrecondition, err.Error())
+	ctx, err = withVault(ctx, common.ReadVaultFromHeader(ctx))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
 	}
-	if err = ss.app.Push(ctx, func(ctx context.Context) (*usecase.Push, error) {
+	if err = ss.app.ValidateVersion(ctx, v); err != nil {
+		if !errors.Is(err, usecase.ErrVersionConflict) {
+			return toPushError(err)
+		}
+		if !force {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	if err = ss.app.Push(ctx, v, func(ctx context.Context) (*usecase.Push, error) {
 		var op *pb.PushOperation
 		op, err = stream.Recv()
 		if err != nil {
@@ -85,7 +95,14 @@ func (ss *SyncServer) PushStream(stream pb.Sync_PushStreamServer) error {
 			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("unknown operation type: %v", op.GetType()))
 		}
 	}); err != nil && !errors.Is(err, io.EOF) {
-		return status.Error(codes.Internal, err.Error())
+		// the version moved after the check, retry the forced push
+		if errors.Is(err, usecase.ErrVersionConflict) {
+			if force {
+				return status.Error(codes.Aborted, err.Error())
+			}
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return toPushError(err)
 	}
 	var resp pb.PushResponse
 	resp.SetSuccess(true)
@@ -94,9 +111,13 @@ func (ss *SyncServer) PushStream(stream pb.Sync_PushStreamServer) error {
 
 func (ss *SyncServer) Pull(ctx context.Context, request *pb.PullRequest) (*pb.PullResponse, error) {
 	var response pb.PullResponse
+	ctx, err := withVault(ctx, request.GetVaultId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	data, v, err := ss.app.Poll(ctx, request.GetSince())
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toPullError(err)
 	}
 	response.SetVersion(v)
 	secrets := make([]*pb.Secret, len(data))
@@ -108,6 +129,56 @@ func (ss *SyncServer) Pull(ctx context.Context, request *pb.PullRequest) (*pb.Pu
 	return &response, nil
 }
 
+func (ss *SyncServer) PullPages(request *pb.PullRequest, stream pb.Sync_PullPagesServer) error {
+	ctx, err := withVault(stream.Context(), request.GetVaultId())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	after := domain.SecretCursor{Version: request.GetAfterVersion()}
+	if request.GetAfterId() != "" {
+		id, err := guid.ParseString(request.GetAfterId())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		after.ID = *id
+	}
+	if err := ss.app.PollPages(ctx, request.GetSince(), after, request.GetPageSize(), func(ctx context.Context, data []*domain.Secret, version int32, last bool) error {
+		var page pb.PullPage
+		secrets := make([]*pb.Secret, len(data))
+		for i, item := range data {
+			secrets[i] = toSecret1(item)
+		}
+		page.SetSecrets(secrets)
+		page.SetVersion(version)
+		page.SetLast(last)
+		return stream.Send(&page)
+	}); err != nil {
+		return toPullError(err)
+	}
+	return nil
+}
+
+func (ss *SyncServer) Subscribe(_ *pb.SubscribeRequest, stream pb.Sync_SubscribeServer) error {
+	ctx := stream.Context()
+	if err := ss.app.Subscribe(ctx, func(ctx context.Context, change usecase.ChangeEvent) error {
+		var event pb.ChangeEvent
+		event.SetVersion(change.Version)
+		if change.VaultID != nil {
+			event.SetVaultId(change.VaultID.String())
+		}
+		return stream.Send(&event)
+	}); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if errors.Is(err, usecase.ErrSubscriberLagged) {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
 func (ss *SyncServer) PullStream(in *pb.PullStreamRequest, stream pb.Sync_PullStreamServer) error {
 	ctx := stream.Context()
 	logger := logging.Logger(ctx)
@@ -115,7 +186,13 @@ func (ss *SyncServer) PullStream(in *pb.PullStreamRequest, stream pb.Sync_PullSt
 	if err != nil {
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
-	reader, err := ss.app.File(*id, in.GetVersion())
+	reader, err := ss.app.File(ctx, *id, in.GetVersion())
+	if err != nil {
+		if errors.Is(err, usecase.ErrSecretNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
 	defer func(file io.ReadCloser) {
 		err = file.Close()
 		if err != nil {
@@ -142,6 +219,60 @@ func (ss *SyncServer) PullStream(in *pb.PullStreamRequest, stream pb.Sync_PullSt
 	return nil
 }
 
+// withVault set vault of the request to context, own secrets of the user are synced when id is empty
+func withVault(ctx context.Context, id string) (context.Context, error) {
+	if id == "" {
+		return ctx, nil
+	}
+	vaultID, err := guid.ParseString(id)
+	if err != nil {
+		return nil, err
+	}
+	return auth.SetVault(ctx, *vaultID), nil
+}
+
+func toPullError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrVaultNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrVaultRoleTooLow):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toPushError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrVaultNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrSecretNotOwned),
+		errors.Is(err, usecase.ErrVaultRoleTooLow):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, usecase.ErrShareKeyRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, usecase.ErrSecretsQuota),
+		errors.Is(err, usecase.ErrStorageQuota),
+		errors.Is(err, usecase.ErrBlobTooLarge),
+		errors.Is(err, usecase.ErrMessageTooLarge):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// toShares dek of the secret sealed to every recipient by login
+func toShares(secret *pb.Secret) map[string][]byte {
+	if len(secret.GetShares()) == 0 {
+		return nil
+	}
+	shares := make(map[string][]byte, len(secret.GetShares()))
+	for _, share := range secret.GetShares() {
+		shares[share.GetLogin()] = share.GetDek()
+	}
+	return shares
+}
+
 func toDefault(op *pb.PushOperation) (*usecase.Push, error) {
 	var push usecase.Push
 	secret := op.GetSecret()
@@ -157,12 +288,13 @@ func toDefault(op *pb.PushOperation) (*usecase.Push, error) {
 		Data:       secret.GetData(),
 		Version:    secret.GetVersion(),
 		Deleted:    secret.GetDeleted(),
+		Shares:     toShares(secret),
 	}
 	switch secret.GetType() {
 	case pb.SecretType_LoginPass:
 		data.Type = domain.LoginPassType
 	case pb.SecretType_Text:
-		data.Type = domain.LoginPassType
+		data.Type = domain.TextType
 	case pb.SecretType_BankCard:
 		data.Type = domain.BankCardType
 	case pb.SecretType_Binary:
@@ -230,6 +362,7 @@ func toEndFile(op *pb.PushOperation) (*usecase.Push, error) {
 		Version:    secret.GetVersion(),
 		Type:       domain.OtherType,
 		Deleted:    secret.GetDeleted(),
+		Shares:     toShares(secret),
 	}
 	push.Secret = data
 	return &push, nil
@@ -244,6 +377,11 @@ func toSecret1(data *domain.Secret) *pb.Secret {
 	secret.SetDek(data.Dek)
 	secret.SetData(data.Payload)
 	secret.SetDeleted(data.Deleted)
+	secret.SetOwner(data.Owner)
+	secret.SetDataVersion(data.DataVersion)
+	if data.VaultID != nil {
+		secret.SetVaultId(data.VaultID.String())
+	}
 	switch data.Type {
 	case domain.LoginPassType:
 		secret.SetType(pb.SecretType_LoginPass)
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDefault_ShouldMapSecretType(t *testing.T) {
+	cases := []struct {
+		secretType pb.SecretType
+		expected   domain.SecretType
+	}{
+		{pb.SecretType_LoginPass, domain.LoginPassType},
+		{pb.SecretType_Text, domain.TextType},
+		{pb.SecretType_BankCard, domain.BankCardType},
+		{pb.SecretType_Binary, domain.OtherType},
+	}
+	for _, c := range cases {
+		t.Run(c.secretType.String(), func(t *testing.T) {
+			var secret pb.Secret
+			secret.SetId(guid.NewString())
+			secret.SetType(c.secretType)
+			var op pb.PushOperation
+			op.SetType(pb.OperationType_Default)
+			op.SetSecret(&secret)
+
+			push, err := toDefault(&op)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, push.Secret.Type)
+		})
+	}
+}
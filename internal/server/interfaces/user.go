@@ -3,24 +3,29 @@ package interfaces
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/DimKa163/keeper/internal/server/usecase"
 
 	"github.com/DimKa163/keeper/internal/pb"
 
 	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/domain/auth"
+	"github.com/DimKa163/keeper/internal/srp"
+	"github.com/beevik/guid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type UsersServer struct {
-	app domain.UserService
+	app      domain.UserService
+	sessions *usecase.SessionService
 	pb.UnimplementedUsersServer
 }
 
-func NewUserServer(app domain.UserService) *UsersServer {
-	return &UsersServer{app: app}
+func NewUserServer(app domain.UserService, sessions *usecase.SessionService) *UsersServer {
+	return &UsersServer{app: app, sessions: sessions}
 }
 
 func (us *UsersServer) Bind(server *grpc.Server) {
@@ -28,46 +33,256 @@ func (us *UsersServer) Bind(server *grpc.Server) {
 }
 
 func (us *UsersServer) Login(ctx context.Context, in *pb.User) (*pb.UserResponse, error) {
-	var response pb.UserResponse
 	if err := validate(in); err != nil {
 		return nil, err
 	}
 
-	token, err := us.app.Login(ctx, in.GetLogin(), in.GetPassword())
+	proof, err := toDeviceProof(in)
 	if err != nil {
-		if errors.Is(err, usecase.ErrUserNotFound) {
-			return nil, status.Error(codes.Unauthenticated, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	response.SetToken(token)
-
-	return &response, nil
+	var upgrade *domain.Verifier
+	if in.HasSalt() && in.HasVerifier() {
+		upgrade = &domain.Verifier{Salt: in.GetSalt(), Value: in.GetVerifier()}
+	}
+	tokens, err := us.app.Login(ctx, in.GetLogin(), in.GetPassword(), proof, upgrade)
+	if err != nil {
+		return nil, toLoginError(err)
+	}
+	return toUserResponse(tokens), nil
 }
 
 func (us *UsersServer) Register(ctx context.Context, in *pb.User) (*pb.UserResponse, error) {
-	var response pb.UserResponse
 	if err := validate(in); err != nil {
 		return nil, err
 	}
-	token, err := us.app.Register(ctx, in.GetLogin(), in.GetPassword())
+	tokens, err := us.app.Register(ctx, in.GetLogin(), in.GetPassword())
+	if err != nil {
+		return nil, toRegisterError(err)
+	}
+	return toUserResponse(tokens), nil
+}
+
+func (us *UsersServer) RegisterVerifier(ctx context.Context, in *pb.RegisterVerifierRequest) (*pb.UserResponse, error) {
+	if !in.HasLogin() || !in.HasSalt() || !in.HasVerifier() {
+		return nil, status.Error(codes.InvalidArgument, "login, salt and verifier required")
+	}
+	tokens, err := us.app.RegisterVerifier(ctx, in.GetLogin(), &domain.Verifier{Salt: in.GetSalt(), Value: in.GetVerifier()})
+	if err != nil {
+		return nil, toRegisterError(err)
+	}
+	return toUserResponse(tokens), nil
+}
+
+func (us *UsersServer) BeginLogin(ctx context.Context, in *pb.BeginLoginRequest) (*pb.BeginLoginResponse, error) {
+	if !in.HasLogin() || !in.HasPublicKey() {
+		return nil, status.Error(codes.InvalidArgument, "login and public key required")
+	}
+	handshake, err := us.app.BeginLogin(ctx, in.GetLogin(), in.GetPublicKey())
+	if err != nil {
+		return nil, toLoginError(err)
+	}
+	var response pb.BeginLoginResponse
+	response.SetHandshakeId(handshake.ID)
+	response.SetSalt(handshake.Salt)
+	response.SetPublicKey(handshake.PublicKey)
+	return &response, nil
+}
+
+func (us *UsersServer) FinishLogin(ctx context.Context, in *pb.FinishLoginRequest) (*pb.FinishLoginResponse, error) {
+	if !in.HasLogin() || !in.HasHandshakeId() || !in.HasProof() {
+		return nil, status.Error(codes.InvalidArgument, "login, handshake id and proof required")
+	}
+	proof, err := toDeviceProof(in)
+	if err != nil {
+		return nil, err
+	}
+	creds := &domain.Credentials{HandshakeID: in.GetHandshakeId(), Proof: in.GetProof()}
+	tokens, serverProof, err := us.app.FinishLogin(ctx, in.GetLogin(), creds, proof)
 	if err != nil {
-		if errors.Is(err, usecase.ErrLoginAlreadyExists) {
-			return nil, status.Error(codes.AlreadyExists, err.Error())
+		return nil, toLoginError(err)
+	}
+	var response pb.FinishLoginResponse
+	response.SetTokens(toUserResponse(tokens))
+	response.SetServerProof(serverProof)
+	return &response, nil
+}
+
+func (us *UsersServer) Refresh(ctx context.Context, in *pb.RefreshRequest) (*pb.UserResponse, error) {
+	if !in.HasRefreshToken() {
+		return nil, status.Error(codes.InvalidArgument, "refresh token required")
+	}
+	tokens, err := us.sessions.Refresh(ctx, in.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) ||
+			errors.Is(err, usecase.ErrRefreshTokenReused) ||
+			errors.Is(err, usecase.ErrSessionRevoked) ||
+			errors.Is(err, usecase.ErrSessionExpired) ||
+			errors.Is(err, usecase.ErrDeviceNotFound) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if errors.Is(err, usecase.ErrDeviceRevoked) || errors.Is(err, usecase.ErrDevicePending) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	response.SetToken(token)
+	return toUserResponse(tokens), nil
+}
+
+func (us *UsersServer) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	if (!in.HasPassword() && !in.HasHandshakeId()) || !in.HasSalt() || !in.HasVerifier() {
+		return nil, status.Error(codes.InvalidArgument, "password or handshake, salt and verifier required")
+	}
+	creds := toCredentials(in.GetPassword(), in.GetHandshakeId(), in.GetProof())
+	if err := us.app.ChangePassword(ctx, creds, &domain.Verifier{Salt: in.GetSalt(), Value: in.GetVerifier()}); err != nil {
+		return nil, toAccountError(err)
+	}
+	return &pb.ChangePasswordResponse{}, nil
+}
+
+func (us *UsersServer) ChangeLogin(ctx context.Context, in *pb.ChangeLoginRequest) (*pb.ChangeLoginResponse, error) {
+	if (!in.HasPassword() && !in.HasHandshakeId()) || !in.HasNewLogin() {
+		return nil, status.Error(codes.InvalidArgument, "password or handshake and new login required")
+	}
+	creds := toCredentials(in.GetPassword(), in.GetHandshakeId(), in.GetProof())
+	if err := us.app.ChangeLogin(ctx, creds, in.GetNewLogin()); err != nil {
+		return nil, toAccountError(err)
+	}
+	return &pb.ChangeLoginResponse{}, nil
+}
+
+func (us *UsersServer) DeleteAccount(ctx context.Context, in *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	if !in.HasPassword() && !in.HasHandshakeId() {
+		return nil, status.Error(codes.InvalidArgument, "password or handshake required")
+	}
+	if err := us.app.DeleteAccount(ctx, toCredentials(in.GetPassword(), in.GetHandshakeId(), in.GetProof())); err != nil {
+		return nil, toAccountError(err)
+	}
+	return &pb.DeleteAccountResponse{}, nil
+}
+
+func (us *UsersServer) SetRecovery(ctx context.Context, in *pb.SetRecoveryRequest) (*pb.SetRecoveryResponse, error) {
+	if err := us.app.SetRecovery(ctx, in.GetRecovery()); err != nil {
+		return nil, toAccountError(err)
+	}
+	return &pb.SetRecoveryResponse{}, nil
+}
+
+func (us *UsersServer) GetRecovery(ctx context.Context, _ *pb.GetRecoveryRequest) (*pb.GetRecoveryResponse, error) {
+	recovery, err := us.app.GetRecovery(ctx)
+	if err != nil {
+		return nil, toAccountError(err)
+	}
+	var response pb.GetRecoveryResponse
+	response.SetRecovery(recovery)
 	return &response, nil
 }
 
+func toAccountError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidCredentials):
+		return status.Error(codes.PermissionDenied, auth.ErrInvalidPassword.Error())
+	case errors.Is(err, usecase.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, usecase.ErrWeakPassword), errors.Is(err, srp.ErrInvalidVerifier):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrLegacyLoginDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, usecase.ErrLoginAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrRecoveryNotSet):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrRecoveryRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrAccountDisabled):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toLoginError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidCredentials):
+		// don't tell why, so logins can't be enumerated
+		return status.Error(codes.Unauthenticated, usecase.ErrInvalidCredentials.Error())
+	case errors.Is(err, usecase.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, usecase.ErrLegacyLoginDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, srp.ErrInvalidVerifier):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrDeviceNotFound), errors.Is(err, usecase.ErrInvalidDeviceProof),
+		errors.Is(err, usecase.ErrDeviceProofRequired):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, usecase.ErrDeviceRevoked), errors.Is(err, usecase.ErrDevicePending),
+		errors.Is(err, usecase.ErrAccountDisabled):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toRegisterError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrLoginAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, usecase.ErrWeakPassword), errors.Is(err, srp.ErrInvalidVerifier):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrLegacyLoginDisabled):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// toCredentials proof of the current password, handshake is preferred over the password
+func toCredentials(password, handshakeID string, proof []byte) *domain.Credentials {
+	if handshakeID != "" {
+		return &domain.Credentials{HandshakeID: handshakeID, Proof: proof}
+	}
+	return &domain.Credentials{Password: password}
+}
+
+func toUserResponse(tokens *domain.Tokens) *pb.UserResponse {
+	var response pb.UserResponse
+	response.SetToken(tokens.Access)
+	response.SetRefreshToken(tokens.Refresh)
+	response.SetExpiresAt(tokens.ExpiresAt.Unix())
+	return &response
+}
+
+// signedLogin login request signed with the device key
+type signedLogin interface {
+	HasDeviceId() bool
+	GetDeviceId() string
+	GetTimestamp() int64
+	GetSignature() []byte
+}
+
+// toDeviceProof read login signature, nil for clients without device
+func toDeviceProof(in signedLogin) (*domain.DeviceProof, error) {
+	if !in.HasDeviceId() {
+		return nil, nil
+	}
+	id, err := guid.ParseString(in.GetDeviceId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid device id")
+	}
+	return &domain.DeviceProof{
+		DeviceID:  *id,
+		Timestamp: time.Unix(in.GetTimestamp(), 0),
+		Signature: in.GetSignature(),
+	}, nil
+}
+
 func validate(in *pb.User) error {
 	errs := make([]error, 0)
 	if !in.HasLogin() {
-		errs[0] = errors.New("login required")
+		errs = append(errs, errors.New("login required"))
 	}
 	if !in.HasPassword() {
-		errs[1] = errors.New("password required")
+		errs = append(errs, errors.New("password required"))
 	}
 	if len(errs) != 0 {
 		return status.Error(codes.InvalidArgument, errors.Join(errs...).Error())
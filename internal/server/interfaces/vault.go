@@ -0,0 +1,140 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DimKa163/keeper/internal/pb"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/usecase"
+	"github.com/beevik/guid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type VaultServer struct {
+	app *usecase.VaultService
+	pb.UnimplementedVaultsServer
+}
+
+func NewVaultServer(app *usecase.VaultService) *VaultServer {
+	return &VaultServer{app: app}
+}
+
+func (vs *VaultServer) Bind(server *grpc.Server) {
+	pb.RegisterVaultsServer(server, vs)
+}
+
+func (vs *VaultServer) Create(ctx context.Context, in *pb.CreateVaultRequest) (*pb.CreateVaultResponse, error) {
+	id, err := guid.ParseString(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid vault id")
+	}
+	member, err := vs.app.Create(ctx, *id, in.GetName(), in.GetVaultKey())
+	if err != nil {
+		return nil, toVaultError(err)
+	}
+	var response pb.CreateVaultResponse
+	response.SetVault(toVault(member))
+	return &response, nil
+}
+
+func (vs *VaultServer) Invite(ctx context.Context, in *pb.InviteRequest) (*pb.InviteResponse, error) {
+	id, err := guid.ParseString(in.GetVaultId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid vault id")
+	}
+	member, err := vs.app.Invite(ctx, *id, in.GetLogin(), domain.VaultRole(in.GetRole()), in.GetVaultKey())
+	if err != nil {
+		return nil, toVaultError(err)
+	}
+	var response pb.InviteResponse
+	response.SetMember(toVaultMember(member))
+	return &response, nil
+}
+
+func (vs *VaultServer) Members(ctx context.Context, in *pb.MembersRequest) (*pb.MembersResponse, error) {
+	id, err := guid.ParseString(in.GetVaultId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid vault id")
+	}
+	members, err := vs.app.Members(ctx, *id)
+	if err != nil {
+		return nil, toVaultError(err)
+	}
+	items := make([]*pb.VaultMember, 0, len(members))
+	for _, member := range members {
+		items = append(items, toVaultMember(member))
+	}
+	var response pb.MembersResponse
+	response.SetMembers(items)
+	return &response, nil
+}
+
+func (vs *VaultServer) Leave(ctx context.Context, in *pb.LeaveVaultRequest) (*pb.LeaveVaultResponse, error) {
+	id, err := guid.ParseString(in.GetVaultId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid vault id")
+	}
+	if err = vs.app.Leave(ctx, *id); err != nil {
+		return nil, toVaultError(err)
+	}
+	return &pb.LeaveVaultResponse{}, nil
+}
+
+func (vs *VaultServer) List(ctx context.Context, _ *pb.ListVaultsRequest) (*pb.ListVaultsResponse, error) {
+	members, err := vs.app.List(ctx)
+	if err != nil {
+		return nil, toVaultError(err)
+	}
+	items := make([]*pb.Vault, 0, len(members))
+	for _, member := range members {
+		items = append(items, toVault(member))
+	}
+	var response pb.ListVaultsResponse
+	response.SetVaults(items)
+	return &response, nil
+}
+
+// toVault vault as the member sees it, with the key sealed to the member
+func toVault(member *domain.VaultMember) *pb.Vault {
+	var vault pb.Vault
+	vault.SetId(member.VaultID.String())
+	vault.SetName(member.VaultName)
+	vault.SetRole(pb.VaultRole(member.Role))
+	vault.SetVaultKey(member.VaultKey)
+	vault.SetCreatedAt(timestamppb.New(member.CreatedAt))
+	return &vault
+}
+
+func toVaultMember(member *domain.VaultMember) *pb.VaultMember {
+	var item pb.VaultMember
+	item.SetLogin(member.Login)
+	item.SetRole(pb.VaultRole(member.Role))
+	item.SetPublicKey(member.PublicKey)
+	item.SetCreatedAt(timestamppb.New(member.CreatedAt))
+	return &item
+}
+
+func toVaultError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrVaultNotFound),
+		errors.Is(err, usecase.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrVaultRoleTooLow),
+		errors.Is(err, usecase.ErrVaultOwnerRole):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, usecase.ErrVaultNameRequired),
+		errors.Is(err, usecase.ErrVaultKeyRequired),
+		errors.Is(err, usecase.ErrAlreadyVaultMember),
+		errors.Is(err, domain.ErrUnknownVaultRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrVaultOwnerLeave),
+		errors.Is(err, usecase.ErrNoPublicKey):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
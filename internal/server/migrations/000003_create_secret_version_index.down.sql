@@ -0,0 +1 @@
+DROP INDEX IF EXISTS secret_user_version_id_idx;
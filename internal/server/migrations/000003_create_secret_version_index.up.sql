@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS secret_user_version_id_idx ON secret (user_id, version, id);
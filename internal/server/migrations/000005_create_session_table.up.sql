@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS session(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id UUID NULL REFERENCES device(id) ON DELETE CASCADE,
+    last_used_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS session_user_idx ON session (user_id);
+
+CREATE TABLE IF NOT EXISTS refresh_token(
+    hash BYTEA PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES session(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS refresh_token_session_idx ON refresh_token (session_id);
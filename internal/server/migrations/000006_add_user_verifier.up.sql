@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verifier BYTEA NULL;
+ALTER TABLE users ALTER COLUMN password DROP NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS share;
+ALTER TABLE users DROP COLUMN IF EXISTS public_key;
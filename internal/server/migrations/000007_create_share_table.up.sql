@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS public_key BYTEA NULL;
+
+CREATE TABLE IF NOT EXISTS share(
+    secret_id UUID NOT NULL REFERENCES secret(id) ON DELETE CASCADE,
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL,
+    dek BYTEA NOT NULL,
+    version INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ,
+
+    PRIMARY KEY(secret_id, recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS share_recipient_version_idx ON share (recipient_id, version, secret_id);
+CREATE INDEX IF NOT EXISTS share_owner_idx ON share (owner_id);
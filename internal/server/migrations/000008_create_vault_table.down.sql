@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS secret_vault_version_idx;
+DELETE FROM secret WHERE vault_id IS NOT NULL;
+ALTER TABLE secret DROP COLUMN IF EXISTS vault_id;
+DELETE FROM sync_state WHERE user_id IN (SELECT id FROM vault);
+DROP TABLE IF EXISTS vault_member;
+DROP TABLE IF EXISTS vault;
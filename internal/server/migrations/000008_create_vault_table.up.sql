@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS vault(
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS vault_member(
+    vault_id UUID NOT NULL REFERENCES vault(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(10) NOT NULL,
+    vault_key BYTEA NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY(vault_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS vault_member_user_idx ON vault_member (user_id);
+
+ALTER TABLE secret ADD COLUMN IF NOT EXISTS vault_id UUID NULL REFERENCES vault(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS secret_vault_version_idx ON secret (vault_id, version, id) WHERE vault_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS audit_append_only ON audit;
+DROP FUNCTION IF EXISTS audit_append_only();
+DROP TABLE IF EXISTS audit;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS audit(
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    user_id UUID NULL,
+    login TEXT NOT NULL DEFAULT '',
+    device_id UUID NULL,
+    client_id TEXT NOT NULL DEFAULT '',
+    peer TEXT NOT NULL DEFAULT '',
+    secret_id UUID NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_user_idx ON audit (user_id, id);
+CREATE INDEX IF NOT EXISTS audit_login_idx ON audit (login, id);
+
+CREATE OR REPLACE FUNCTION audit_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_append_only
+    BEFORE UPDATE OR DELETE ON audit
+    FOR EACH ROW EXECUTE FUNCTION audit_append_only();
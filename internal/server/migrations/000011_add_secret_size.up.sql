@@ -0,0 +1 @@
+ALTER TABLE secret ADD COLUMN IF NOT EXISTS size BIGINT NOT NULL DEFAULT 0;
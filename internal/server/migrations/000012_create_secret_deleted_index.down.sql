@@ -0,0 +1 @@
+DROP INDEX IF EXISTS secret_deleted_idx;
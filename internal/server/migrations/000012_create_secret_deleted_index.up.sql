@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS secret_deleted_idx ON secret (modified_at) WHERE deleted;
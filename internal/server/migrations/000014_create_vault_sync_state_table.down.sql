@@ -0,0 +1,5 @@
+INSERT INTO sync_state (id, user_id, value)
+SELECT 'Secret', vault_id, value FROM vault_sync_state
+ON CONFLICT (id, user_id) DO NOTHING;
+
+DROP TABLE IF EXISTS vault_sync_state;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS vault_sync_state(
+    vault_id UUID PRIMARY KEY REFERENCES vault(id) ON DELETE CASCADE,
+    value INT NOT NULL DEFAULT 0
+);
+
+INSERT INTO vault_sync_state (vault_id, value)
+SELECT s.user_id, COALESCE(s.value, 0) FROM sync_state s JOIN vault v ON v.id = s.user_id WHERE s.id = 'Secret'
+ON CONFLICT (vault_id) DO NOTHING;
+
+DELETE FROM sync_state WHERE id = 'Secret' AND user_id IN (SELECT id FROM vault);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS secret_charged_to_idx;
+ALTER TABLE secret DROP COLUMN IF EXISTS charged_to;
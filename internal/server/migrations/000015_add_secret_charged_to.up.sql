@@ -0,0 +1,5 @@
+ALTER TABLE secret ADD COLUMN IF NOT EXISTS charged_to UUID NULL;
+UPDATE secret SET charged_to = user_id WHERE charged_to IS NULL;
+ALTER TABLE secret ALTER COLUMN charged_to SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS secret_charged_to_idx ON secret (charged_to);
@@ -0,0 +1,2 @@
+ALTER TABLE device ADD COLUMN IF NOT EXISTS approved_at TIMESTAMPTZ NULL;
+UPDATE device SET approved_at = created_at WHERE approved_at IS NULL;
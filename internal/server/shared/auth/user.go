@@ -13,7 +13,12 @@ var ErrUserNotFound = errors.New("user not found in context")
 type UserID string
 
 const (
-	user UserID = "userID"
+	user    UserID = "userID"
+	client  UserID = "clientID"
+	device  UserID = "deviceID"
+	session UserID = "sessionID"
+	peer    UserID = "peer"
+	vault   UserID = "vaultID"
 )
 
 // User get user id from context
@@ -30,3 +35,58 @@ func SetUser(ctx context.Context, userID guid.Guid) context.Context {
 	ctx = context.WithValue(ctx, user, userID)
 	return ctx
 }
+
+// Client get id of the client installation from context, empty when client didn't introduce itself
+func Client(ctx context.Context) string {
+	id, _ := ctx.Value(client).(string)
+	return id
+}
+
+// SetClient set id of the client installation to context
+func SetClient(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, client, id)
+}
+
+// Device get device the request token is bound to
+func Device(ctx context.Context) (guid.Guid, bool) {
+	id, ok := ctx.Value(device).(guid.Guid)
+	return id, ok
+}
+
+// SetDevice set device of the request to context
+func SetDevice(ctx context.Context, id guid.Guid) context.Context {
+	return context.WithValue(ctx, device, id)
+}
+
+// Session get session the request token is issued for
+func Session(ctx context.Context) (guid.Guid, bool) {
+	id, ok := ctx.Value(session).(guid.Guid)
+	return id, ok
+}
+
+// SetSession set session of the request to context
+func SetSession(ctx context.Context, id guid.Guid) context.Context {
+	return context.WithValue(ctx, session, id)
+}
+
+// Peer get address of the client, empty when unknown
+func Peer(ctx context.Context) string {
+	addr, _ := ctx.Value(peer).(string)
+	return addr
+}
+
+// SetPeer set address of the client to context
+func SetPeer(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, peer, addr)
+}
+
+// Vault get vault the request is made to, own secrets of the user are synced when there is none
+func Vault(ctx context.Context) (guid.Guid, bool) {
+	id, ok := ctx.Value(vault).(guid.Guid)
+	return id, ok
+}
+
+// SetVault set vault of the request to context
+func SetVault(ctx context.Context, id guid.Guid) context.Context {
+	return context.WithValue(ctx, vault, id)
+}
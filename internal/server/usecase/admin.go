@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+)
+
+// adminReason reason of audit events made by administrator from the server console
+const adminReason = "by administrator"
+
+// UserStats what the user stores on the server
+type UserStats struct {
+	User       *domain.User
+	Records    int64
+	Tombstones int64
+	// PayloadBytes size of records stored in database
+	PayloadBytes int64
+	Blobs        int64
+	BlobBytes    int64
+}
+
+// BlobReport result of blob storage check
+type BlobReport struct {
+	// Checked number of secrets stored as files
+	Checked int
+	// Missing secrets whose file of the current version isn't stored
+	Missing []*domain.Secret
+	// Orphans stored files no secret refers to
+	Orphans []*domain.Blob
+}
+
+// AdminService server maintenance, it works on storage directly and is run from the server console
+type AdminService struct {
+	uow   domain.UnitOfWork
+	fp    domain.Filer
+	audit domain.AuditLog
+}
+
+func NewAdminService(uow domain.UnitOfWork, fp domain.Filer, audit domain.AuditLog) *AdminService {
+	return &AdminService{uow: uow, fp: fp, audit: audit}
+}
+
+func (as *AdminService) Users(ctx context.Context) ([]*domain.User, error) {
+	return as.uow.UserRepository().List(ctx)
+}
+
+// DisableUser forbid login of the user and revoke all sessions, so tokens stop working once they expire
+func (as *AdminService) DisableUser(ctx context.Context, login string) error {
+	user, err := as.user(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err = as.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		now := time.Now().UTC()
+		if err := work.UserRepository().SetDisabled(ctx, user.ID, &now); err != nil {
+			return err
+		}
+		sessions, err := work.SessionRepository().GetActive(ctx, user.ID, now)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if err = work.SessionRepository().Revoke(ctx, session.ID, user.ID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	recordAudit(ctx, as.audit, &domain.AuditEvent{Type: domain.AuditAccountDisabled, UserID: &user.ID, Login: user.Login, Reason: adminReason})
+	return nil
+}
+
+func (as *AdminService) EnableUser(ctx context.Context, login string) error {
+	user, err := as.user(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err = as.uow.UserRepository().SetDisabled(ctx, user.ID, nil); err != nil {
+		return err
+	}
+	recordAudit(ctx, as.audit, &domain.AuditEvent{Type: domain.AuditAccountEnabled, UserID: &user.ID, Login: user.Login, Reason: adminReason})
+	return nil
+}
+
+// DeleteUser remove the user with own secrets and files, secrets the user pushed to vaults stay
+func (as *AdminService) DeleteUser(ctx context.Context, login string) error {
+	user, err := as.user(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err = deleteAccount(ctx, as.uow, as.fp, user.ID); err != nil {
+		return err
+	}
+	recordAudit(ctx, as.audit, &domain.AuditEvent{Type: domain.AuditAccountDeleted, UserID: &user.ID, Login: user.Login, Reason: adminReason})
+	return nil
+}
+
+// Stats records and files of every user ordered by login, files are counted to the user who pushed them
+func (as *AdminService) Stats(ctx context.Context) ([]*UserStats, error) {
+	users, err := as.uow.UserRepository().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[guid.Guid]*UserStats, len(users))
+	stats := make([]*UserStats, len(users))
+	for i, user := range users {
+		stats[i] = &UserStats{User: user}
+		byUser[user.ID] = stats[i]
+	}
+	counts, err := as.uow.SecretRepository().Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		if item, ok := byUser[count.UserID]; ok {
+			item.Records = count.Records
+			item.Tombstones = count.Tombstones
+			item.PayloadBytes = count.PayloadBytes
+		}
+	}
+	secrets, err := as.uow.SecretRepository().GetBigData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		item, ok := byUser[secret.ChargedTo]
+		if !ok || secret.Deleted {
+			continue
+		}
+		blob, err := as.fp.Stat(secret.ID.String(), secret.Version)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		item.Blobs++
+		item.BlobBytes += blob.Size
+	}
+	return stats, nil
+}
+
+// VerifyBlobs find secrets without file and files without secret. Files changed within grace
+// may belong to uploads in progress, they aren't reported as orphans
+func (as *AdminService) VerifyBlobs(ctx context.Context, grace time.Duration) (*BlobReport, error) {
+	secrets, err := as.uow.SecretRepository().GetBigData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &BlobReport{}
+	for _, secret := range secrets {
+		// the file goes with the deleted secret
+		if secret.Deleted {
+			continue
+		}
+		report.Checked++
+		if _, err = as.fp.Stat(secret.ID.String(), secret.Version); err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			report.Missing = append(report.Missing, secret)
+		}
+	}
+	report.Orphans, _, err = orphanBlobs(as.fp, secrets, time.Now().Add(-grace))
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (as *AdminService) user(ctx context.Context, login string) (*domain.User, error) {
+	user, err := as.uow.UserRepository().Get(ctx, login)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// orphanBlobs files changed before the time that don't match current version of a live secret,
+// usage counts every file of a secret walked
+func orphanBlobs(fp domain.Filer, secrets []*domain.Secret, before time.Time) ([]*domain.Blob, BlobUsage, error) {
+	live := make(map[string]int32, len(secrets))
+	for _, secret := range secrets {
+		if !secret.Deleted {
+			live[secret.ID.String()] = secret.Version
+		}
+	}
+	orphans := make([]*domain.Blob, 0)
+	var usage BlobUsage
+	err := fp.Walk(func(blob *domain.Blob) error {
+		if _, err := guid.ParseString(blob.Name); err != nil {
+			return nil
+		}
+		usage.Blobs++
+		usage.Bytes += blob.Size
+		if !blob.ModifiedAt.Before(before) {
+			return nil
+		}
+		if version, ok := live[blob.Name]; ok && version == blob.Version {
+			return nil
+		}
+		orphans = append(orphans, blob)
+		return nil
+	})
+	return orphans, usage, err
+}
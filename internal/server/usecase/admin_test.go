@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminService_DisableUserShouldRevokeSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	user := &domain.User{ID: *guid.New(), Login: "dima"}
+	sessions := []*domain.Session{{ID: *guid.New(), UserID: user.ID}, {ID: *guid.New(), UserID: user.ID}}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	userRepository := mocks.NewMockUserRepository(ctrl)
+	userRepository.EXPECT().Get(ctx, user.Login).Return(user, nil)
+	userRepository.EXPECT().SetDisabled(ctx, user.ID, gomock.Not(gomock.Nil())).Return(nil)
+	sessionRepository := mocks.NewMockSessionRepository(ctrl)
+	sessionRepository.EXPECT().GetActive(ctx, user.ID, gomock.Any()).Return(sessions, nil)
+	sessionRepository.EXPECT().Revoke(ctx, sessions[0].ID, user.ID, gomock.Any()).Return(nil)
+	sessionRepository.EXPECT().Revoke(ctx, sessions[1].ID, user.ID, gomock.Any()).Return(nil)
+	txUow.EXPECT().UserRepository().Return(userRepository).AnyTimes()
+	txUow.EXPECT().SessionRepository().Return(sessionRepository).AnyTimes()
+	audit := &recordingAuditLog{}
+	sut := NewAdminService(newMockUow(txUow), mocks.NewMockFiler(ctrl), audit)
+
+	err := sut.DisableUser(ctx, user.Login)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.AuditAccountDisabled}, audit.types())
+}
+
+func TestAdminService_DeleteUserShouldFailForUnknownLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	userRepository := mocks.NewMockUserRepository(ctrl)
+	userRepository.EXPECT().Get(ctx, "ghost").Return(nil, persistence.ErrResourceNotFound)
+	uow.EXPECT().UserRepository().Return(userRepository)
+	sut := NewAdminService(uow, mocks.NewMockFiler(ctrl), &recordingAuditLog{})
+
+	err := sut.DeleteUser(ctx, "ghost")
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestAdminService_VerifyBlobsShouldReportMissingAndOrphanFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	stored := &domain.Secret{ID: *guid.New(), BigData: true, Version: 3}
+	missing := &domain.Secret{ID: *guid.New(), BigData: true, Version: 5}
+	old := time.Now().Add(-2 * time.Hour)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().GetBigData(ctx).Return([]*domain.Secret{stored, missing}, nil)
+	uow.EXPECT().SecretRepository().Return(secretRepository)
+	filer := mocks.NewMockFiler(ctrl)
+	filer.EXPECT().Stat(stored.ID.String(), int32(3)).Return(&domain.Blob{Name: stored.ID.String(), Version: 3}, nil)
+	filer.EXPECT().Stat(missing.ID.String(), int32(5)).Return(nil, fs.ErrNotExist)
+	blobs := []*domain.Blob{
+		{Name: stored.ID.String(), Version: 3, ModifiedAt: old},
+		// previous file version left by a crash
+		{Name: stored.ID.String(), Version: 2, ModifiedAt: old},
+		// upload in progress
+		{Name: guid.New().String(), Version: 1, ModifiedAt: time.Now()},
+	}
+	filer.EXPECT().Walk(gomock.Any()).DoAndReturn(func(fn func(blob *domain.Blob) error) error {
+		for _, blob := range blobs {
+			if err := fn(blob); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	sut := NewAdminService(uow, filer, &recordingAuditLog{})
+
+	report, err := sut.VerifyBlobs(ctx, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Checked)
+	assert.Equal(t, []*domain.Secret{missing}, report.Missing)
+	assert.Equal(t, []*domain.Blob{blobs[1]}, report.Orphans)
+}
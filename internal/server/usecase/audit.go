@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+)
+
+const (
+	DefaultAuditLimit int32 = 100
+	MaxAuditLimit     int32 = 1000
+)
+
+var ErrAuditForbidden = errors.New("only administrators can read audit of other users")
+
+// AuditQuery which part of audit trail to read. User reads own events, administrator
+// reads events of any login or of everyone
+type AuditQuery struct {
+	Login    string
+	All      bool
+	Since    time.Time
+	BeforeID int64
+	Limit    int32
+}
+
+type AuditService struct {
+	uow domain.UnitOfWork
+	// admins logins allowed to read audit of other users
+	admins map[string]bool
+}
+
+func NewAuditService(uow domain.UnitOfWork, admins []string) *AuditService {
+	set := make(map[string]bool, len(admins))
+	for _, login := range admins {
+		set[login] = true
+	}
+	return &AuditService{uow: uow, admins: set}
+}
+
+// Query events newest first, page ends before BeforeID
+func (as *AuditService) Query(ctx context.Context, query *AuditQuery) ([]*domain.AuditEvent, error) {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := as.uow.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 || limit > MaxAuditLimit {
+		limit = DefaultAuditLimit
+	}
+	filter := &domain.AuditFilter{Since: query.Since, BeforeID: query.BeforeID, Limit: limit}
+	switch {
+	case !query.All && (query.Login == "" || query.Login == user.Login):
+		filter.UserID = &user.ID
+	case !as.admins[user.Login]:
+		return nil, ErrAuditForbidden
+	case !query.All:
+		filter.Login = query.Login
+	}
+	return as.uow.AuditRepository().Find(ctx, filter)
+}
+
+// recordAudit fill who, from where and when of the event and write it, nothing is written without audit log
+func recordAudit(ctx context.Context, log domain.AuditLog, event *domain.AuditEvent) {
+	if log == nil {
+		return
+	}
+	if event.UserID == nil {
+		if userID, err := sh.User(ctx); err == nil {
+			event.UserID = &userID
+		}
+	}
+	if event.DeviceID == nil {
+		if deviceID, ok := sh.Device(ctx); ok {
+			event.DeviceID = &deviceID
+		}
+	}
+	event.Client = sh.Client(ctx)
+	event.Peer = sh.Peer(ctx)
+	event.At = time.Now().UTC()
+	log.Record(ctx, event)
+}
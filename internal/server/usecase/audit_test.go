@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditService_QueryShouldReadOwnEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	user := &domain.User{ID: *guid.New(), Login: "alice"}
+	ctx := auth.SetUser(context.Background(), user.ID)
+	uow := auditUow(ctrl, user)
+	auditRepository := mocks.NewMockAuditRepository(ctrl)
+	auditRepository.EXPECT().Find(ctx, &domain.AuditFilter{UserID: &user.ID, Limit: DefaultAuditLimit}).
+		Return([]*domain.AuditEvent{{ID: 1, Type: domain.AuditLogin}}, nil)
+	uow.EXPECT().AuditRepository().Return(auditRepository)
+	sut := NewAuditService(uow, nil)
+
+	events, err := sut.Query(ctx, &AuditQuery{Login: "alice"})
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestAuditService_QueryShouldForbidOthersToUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	user := &domain.User{ID: *guid.New(), Login: "alice"}
+	ctx := auth.SetUser(context.Background(), user.ID)
+	sut := NewAuditService(auditUow(ctrl, user), []string{"root"})
+
+	_, err := sut.Query(ctx, &AuditQuery{Login: "bob"})
+	assert.ErrorIs(t, err, ErrAuditForbidden)
+	_, err = sut.Query(ctx, &AuditQuery{All: true})
+	assert.ErrorIs(t, err, ErrAuditForbidden)
+}
+
+func TestAuditService_QueryShouldReadEventsOfLoginForAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	user := &domain.User{ID: *guid.New(), Login: "root"}
+	ctx := auth.SetUser(context.Background(), user.ID)
+	uow := auditUow(ctrl, user)
+	since := time.Now().Add(-time.Hour)
+	auditRepository := mocks.NewMockAuditRepository(ctrl)
+	auditRepository.EXPECT().Find(ctx, &domain.AuditFilter{Login: "bob", Since: since, BeforeID: 10, Limit: MaxAuditLimit}).
+		Return([]*domain.AuditEvent{}, nil)
+	uow.EXPECT().AuditRepository().Return(auditRepository)
+	sut := NewAuditService(uow, []string{"root"})
+
+	_, err := sut.Query(ctx, &AuditQuery{Login: "bob", Since: since, BeforeID: 10, Limit: MaxAuditLimit})
+
+	assert.NoError(t, err)
+}
+
+func TestSyncService_PushShouldRecordEventPerSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userID := *guid.New()
+	deviceID := *guid.New()
+	ctx := auth.SetPeer(auth.SetDevice(auth.SetUser(context.Background(), userID), deviceID), "10.0.0.1")
+	first := &Push{Type: DefaultOperation, Secret: &Secret{ID: *guid.New(), Type: domain.TextType, Data: make([]byte, 100), Dek: make([]byte, 32)}}
+	second := &Push{Type: DefaultOperation, Secret: &Secret{ID: *guid.New(), Type: domain.TextType, Data: make([]byte, 10), Dek: make([]byte, 32)}}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	syncRepository := mocks.NewMockSyncStateRepository(ctrl)
+	syncRepository.EXPECT().Get(ctx, syncTypeName, userID).Return(&domain.SyncState{ID: syncTypeName, Value: 4}, nil)
+	syncRepository.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().Get(ctx, gomock.Any()).Return(nil, persistence.ErrResourceNotFound).Times(2)
+	secretRepository.EXPECT().Insert(ctx, gomock.Any()).Return(nil).Times(2)
+	txUow.EXPECT().SyncStateRepository().Return(syncRepository)
+	txUow.EXPECT().SecretRepository().Return(secretRepository).Times(2)
+	audit := &recordingAuditLog{}
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{Audit: audit})
+
+	err := sut.Push(ctx, 4, newMockStream([]*Push{first, second}).Next)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.AuditPushed, domain.AuditPushed}, audit.types())
+	assert.Equal(t, first.Secret.ID, *audit.events[0].SecretID)
+	assert.Equal(t, int64(132), audit.events[0].Bytes)
+	assert.Equal(t, int32(5), audit.events[0].Version)
+	assert.Equal(t, userID, *audit.events[0].UserID)
+	assert.Equal(t, deviceID, *audit.events[0].DeviceID)
+	assert.Equal(t, "10.0.0.1", audit.events[0].Peer)
+	assert.Equal(t, second.Secret.ID, *audit.events[1].SecretID)
+	assert.Equal(t, int64(42), audit.events[1].Bytes)
+}
+
+func TestSyncService_PollShouldRecordOnePullEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userID := *guid.New()
+	deviceID := *guid.New()
+	ctx := auth.SetDevice(auth.SetUser(context.Background(), userID), deviceID)
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().GetAll(ctx, userID, int32(2)).Return([]*domain.Secret{
+		{ID: *guid.New(), Payload: make([]byte, 20), Dek: make([]byte, 32)},
+		{ID: *guid.New(), Payload: make([]byte, 8), Dek: make([]byte, 32)},
+	}, nil)
+	syncRepository := mocks.NewMockSyncStateRepository(ctrl)
+	syncRepository.EXPECT().Get(ctx, syncTypeName, userID).Return(&domain.SyncState{ID: syncTypeName, Value: 7}, nil)
+	txUow.EXPECT().SecretRepository().Return(secretRepository)
+	txUow.EXPECT().SyncStateRepository().Return(syncRepository)
+	deviceRepository := mocks.NewMockDeviceRepository(ctrl)
+	deviceRepository.EXPECT().UpdateSyncVersion(ctx, deviceID, int32(7)).Return(nil)
+	txUow.EXPECT().DeviceRepository().Return(deviceRepository)
+	audit := &recordingAuditLog{}
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{Audit: audit})
+
+	_, _, err := sut.Poll(ctx, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.AuditPulled}, audit.types())
+	assert.Equal(t, int32(7), audit.events[0].Version)
+	assert.Equal(t, int64(92), audit.events[0].Bytes)
+	assert.Equal(t, "since 2, 2 secrets", audit.events[0].Reason)
+}
+
+func auditUow(ctrl *gomock.Controller, user *domain.User) *mocks.MockUnitOfWork {
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	userRepository := mocks.NewMockUserRepository(ctrl)
+	userRepository.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+	uow.EXPECT().UserRepository().Return(userRepository).AnyTimes()
+	return uow
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+// BlobCopyResult files copied between storages
+type BlobCopyResult struct {
+	Copied  int
+	Skipped int
+	Bytes   int64
+}
+
+// CopyBlobs copy every file of one storage to another, files already copied with the same size are skipped,
+// so interrupted copy can be run again. Source storage is left as is
+func CopyBlobs(ctx context.Context, from, to domain.Filer) (*BlobCopyResult, error) {
+	result := &BlobCopyResult{}
+	err := from.Walk(func(blob *domain.Blob) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		copied, err := to.Stat(blob.Name, blob.Version)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if copied != nil && copied.Size == blob.Size {
+			result.Skipped++
+			return nil
+		}
+		n, err := copyBlob(from, to, blob)
+		if err != nil {
+			return err
+		}
+		result.Copied++
+		result.Bytes += n
+		return nil
+	})
+	return result, err
+}
+
+func copyBlob(from, to domain.Filer, blob *domain.Blob) (int64, error) {
+	src, err := from.OpenRead(blob.Name, blob.Version)
+	if err != nil {
+		return 0, err
+	}
+	defer func(src io.ReadCloser) {
+		_ = src.Close()
+	}(src)
+	dst, err := to.OpenWrite(blob.Name, blob.Version)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(dst, src)
+	if err != nil {
+		_ = dst.Abort()
+		return 0, err
+	}
+	return n, dst.Close()
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyBlobs_ShouldSkipCopiedFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	copied := &domain.Blob{Name: guid.NewString(), Version: 2, Size: 4}
+	missing := &domain.Blob{Name: guid.NewString(), Version: 3, Size: 7}
+	from := mocks.NewMockFiler(ctrl)
+	from.EXPECT().Walk(gomock.Any()).DoAndReturn(func(fn func(blob *domain.Blob) error) error {
+		for _, blob := range []*domain.Blob{copied, missing} {
+			if err := fn(blob); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	from.EXPECT().OpenRead(missing.Name, missing.Version).Return(io.NopCloser(strings.NewReader("content")), nil)
+	writer := mocks.NewMockBlobWriter(ctrl)
+	writer.EXPECT().Write([]byte("content")).Return(7, nil)
+	writer.EXPECT().Close().Return(nil)
+	to := mocks.NewMockFiler(ctrl)
+	to.EXPECT().Stat(copied.Name, copied.Version).Return(&domain.Blob{Name: copied.Name, Version: 2, Size: 4}, nil)
+	to.EXPECT().Stat(missing.Name, missing.Version).Return(nil, &fs.PathError{Op: "stat", Path: missing.Name, Err: fs.ErrNotExist})
+	to.EXPECT().OpenWrite(missing.Name, missing.Version).Return(writer, nil)
+
+	result, err := CopyBlobs(ctx, from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &BlobCopyResult{Copied: 1, Skipped: 1, Bytes: 7}, result)
+}
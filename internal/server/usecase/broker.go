@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/beevik/guid"
+)
+
+// subscriberQueueSize events waiting for a subscriber, the one falling further behind is dropped
+const subscriberQueueSize = 64
+
+// ErrSubscriberLagged subscriber didn't keep up with events, it subscribes again and pulls everything
+var ErrSubscriberLagged = errors.New("subscriber fell behind")
+
+// ChangeEvent sync state of user or of a vault the user is a member of changed
+type ChangeEvent struct {
+	Version  int32
+	ClientID string
+	// VaultID vault whose sync state changed, nil for own secrets of the user
+	VaultID *guid.Guid
+}
+
+type subscriber struct {
+	clientID string
+	events   chan ChangeEvent
+}
+
+// ChangeBroker deliver change events to subscribed clients of the same user
+type ChangeBroker struct {
+	mu          sync.Mutex
+	subscribers map[guid.Guid]map[*subscriber]struct{}
+}
+
+func NewChangeBroker() *ChangeBroker {
+	return &ChangeBroker{
+		subscribers: make(map[guid.Guid]map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe register client. Events published by the same client are not delivered to it.
+// Channel is closed when the client falls behind by more than subscriberQueueSize events
+func (b *ChangeBroker) Subscribe(userID guid.Guid, clientID string) (<-chan ChangeEvent, func()) {
+	sub := &subscriber{
+		clientID: clientID,
+		events:   make(chan ChangeEvent, subscriberQueueSize),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs, ok := b.subscribers[userID]
+	if !ok {
+		subs = make(map[*subscriber]struct{})
+		b.subscribers[userID] = subs
+	}
+	subs[sub] = struct{}{}
+	return sub.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(userID, sub)
+	}
+}
+
+// Publish send event to every client of user except the author.
+// Subscriber with full queue is dropped instead of losing the event silently
+func (b *ChangeBroker) Publish(userID guid.Guid, event ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers[userID] {
+		if event.ClientID != "" && sub.clientID == event.ClientID {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.remove(userID, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (b *ChangeBroker) remove(userID guid.Guid, sub *subscriber) {
+	subs := b.subscribers[userID]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/beevik/guid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeBroker_PublishShouldSkipAuthor(t *testing.T) {
+	broker := NewChangeBroker()
+	userID := *guid.New()
+	author, cancelAuthor := broker.Subscribe(userID, "laptop")
+	defer cancelAuthor()
+	other, cancelOther := broker.Subscribe(userID, "phone")
+	defer cancelOther()
+	stranger, cancelStranger := broker.Subscribe(*guid.New(), "phone")
+	defer cancelStranger()
+
+	broker.Publish(userID, ChangeEvent{Version: 3, ClientID: "laptop"})
+
+	assert.Len(t, author, 0)
+	assert.Len(t, stranger, 0)
+	assert.Equal(t, ChangeEvent{Version: 3, ClientID: "laptop"}, <-other)
+}
+
+func TestChangeBroker_PublishShouldKeepEveryScope(t *testing.T) {
+	broker := NewChangeBroker()
+	userID := *guid.New()
+	vaultID := *guid.New()
+	events, cancel := broker.Subscribe(userID, "phone")
+	defer cancel()
+
+	broker.Publish(userID, ChangeEvent{Version: 1})
+	broker.Publish(userID, ChangeEvent{Version: 7, VaultID: &vaultID})
+
+	assert.Equal(t, ChangeEvent{Version: 1}, <-events)
+	assert.Equal(t, ChangeEvent{Version: 7, VaultID: &vaultID}, <-events)
+}
+
+func TestChangeBroker_PublishShouldDropLaggingSubscriber(t *testing.T) {
+	broker := NewChangeBroker()
+	userID := *guid.New()
+	events, cancel := broker.Subscribe(userID, "phone")
+	defer cancel()
+	fast, cancelFast := broker.Subscribe(userID, "laptop")
+	defer cancelFast()
+
+	for i := 0; i <= subscriberQueueSize; i++ {
+		broker.Publish(userID, ChangeEvent{Version: int32(i)})
+		<-fast
+	}
+
+	for i := 0; i < subscriberQueueSize; i++ {
+		assert.Equal(t, int32(i), (<-events).Version)
+	}
+	_, ok := <-events
+	assert.False(t, ok)
+	broker.Publish(userID, ChangeEvent{Version: 100})
+	assert.Equal(t, int32(100), (<-fast).Version)
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/common"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+)
+
+var (
+	ErrDeviceNotFound = errors.New("device not found")
+	ErrDeviceRevoked  = errors.New("device revoked")
+	ErrDevicePending  = errors.New("device waits for approval from another device")
+	// ErrApproverRequired approval isn't made from an approved device of the user
+	ErrApproverRequired   = errors.New("device must be approved from another approved device")
+	ErrInvalidDeviceProof = errors.New("invalid device signature")
+	ErrInvalidPublicKey   = errors.New("invalid device public key")
+	// ErrDeviceProofRequired login isn't signed, but the user has registered devices
+	ErrDeviceProofRequired = errors.New("device signature required")
+)
+
+const (
+	// DeviceProofWindow how far the signed login timestamp may differ from server time
+	DeviceProofWindow = 5 * time.Minute
+	// deviceTouchInterval last seen time is updated not often than the interval
+	deviceTouchInterval = time.Minute
+)
+
+type DeviceService struct {
+	uow           domain.UnitOfWork
+	authenticator domain.Authenticator
+	audit         domain.AuditLog
+}
+
+func NewDeviceService(uow domain.UnitOfWork, authenticator domain.Authenticator, audit domain.AuditLog) *DeviceService {
+	return &DeviceService{uow: uow, authenticator: authenticator, audit: audit}
+}
+
+// Register add a device of the user authenticated by SRP handshake or legacy password.
+// The password alone is enough only for the first device, next ones wait for approval from an approved device
+func (ds *DeviceService) Register(ctx context.Context, login string, creds *domain.Credentials, name string, publicKey []byte) (*domain.Device, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	user, err := ds.authenticator.Authenticate(ctx, login, creds)
+	if err != nil {
+		return nil, err
+	}
+	repository := ds.uow.DeviceRepository()
+	devices, err := repository.GetAll(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	device := &domain.Device{
+		UserID:    user.ID,
+		Name:      name,
+		PublicKey: publicKey,
+	}
+	if !slices.ContainsFunc(devices, func(d *domain.Device) bool { return !d.Revoked() && !d.Pending() }) {
+		now := time.Now().UTC()
+		device.ApprovedAt = &now
+	}
+	if err = repository.Insert(ctx, device); err != nil {
+		return nil, err
+	}
+	event := &domain.AuditEvent{Type: domain.AuditDeviceRegistered, UserID: &user.ID, Login: user.Login, DeviceID: &device.ID}
+	if device.Pending() {
+		event.Reason = "pending"
+	}
+	recordAudit(ctx, ds.audit, event)
+	return device, nil
+}
+
+// Approve let pending device of the user login. The request must come from another approved device
+func (ds *DeviceService) Approve(ctx context.Context, id guid.Guid) error {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return err
+	}
+	approver, ok := sh.Device(ctx)
+	if !ok || approver == id {
+		return ErrApproverRequired
+	}
+	repository := ds.uow.DeviceRepository()
+	if _, err = getDevice(ctx, repository, userID, approver); err != nil {
+		return ErrApproverRequired
+	}
+	if err = repository.Approve(ctx, id, userID, time.Now().UTC()); err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return ErrDeviceNotFound
+		}
+		return err
+	}
+	recordAudit(ctx, ds.audit, &domain.AuditEvent{Type: domain.AuditDeviceApproved, Reason: id.String()})
+	return nil
+}
+
+// List devices of the user
+func (ds *DeviceService) List(ctx context.Context) ([]*domain.Device, error) {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ds.uow.DeviceRepository().GetAll(ctx, userID)
+}
+
+func (ds *DeviceService) Rename(ctx context.Context, id guid.Guid, name string) (*domain.Device, error) {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repository := ds.uow.DeviceRepository()
+	if err = repository.Rename(ctx, id, userID, name); err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return repository.Get(ctx, id)
+}
+
+// Revoke cut device off. Tokens issued for it are rejected starting from the next request
+func (ds *DeviceService) Revoke(ctx context.Context, id guid.Guid) error {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return err
+	}
+	if err = ds.uow.DeviceRepository().Revoke(ctx, id, userID, time.Now().UTC()); err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return ErrDeviceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// VerifyDevice check device belongs to the user, is approved and is not revoked
+func (ds *DeviceService) VerifyDevice(ctx context.Context, userID, deviceID guid.Guid) error {
+	repository := ds.uow.DeviceRepository()
+	device, err := getDevice(ctx, repository, userID, deviceID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if device.LastSeenAt != nil && now.Sub(*device.LastSeenAt) < deviceTouchInterval {
+		return nil
+	}
+	return repository.Touch(ctx, deviceID, now)
+}
+
+// verifyDeviceProof check login was signed by an active device of the user
+func verifyDeviceProof(
+	ctx context.Context,
+	repository domain.DeviceRepository,
+	user *domain.User,
+	proof *domain.DeviceProof,
+	now time.Time,
+) error {
+	device, err := getDevice(ctx, repository, user.ID, proof.DeviceID)
+	if err != nil {
+		return err
+	}
+	if diff := now.Sub(proof.Timestamp); diff > DeviceProofWindow || diff < -DeviceProofWindow {
+		return ErrInvalidDeviceProof
+	}
+	challenge := common.DeviceChallenge(user.Login, device.ID.String(), proof.Timestamp.Unix())
+	if !ed25519.Verify(device.PublicKey, challenge, proof.Signature) {
+		return ErrInvalidDeviceProof
+	}
+	return nil
+}
+
+// requireDeviceProof refuse login without device signature once the user has registered devices,
+// revoked ones included, so revoked device can't get a session by leaving the signature out
+func requireDeviceProof(ctx context.Context, repository domain.DeviceRepository, userID guid.Guid) error {
+	devices, err := repository.GetAll(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(devices) > 0 {
+		return ErrDeviceProofRequired
+	}
+	return nil
+}
+
+func getDevice(ctx context.Context, repository domain.DeviceRepository, userID, deviceID guid.Guid) (*domain.Device, error) {
+	device, err := repository.Get(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	if device.UserID != userID {
+		return nil, ErrDeviceNotFound
+	}
+	if device.Revoked() {
+		return nil, ErrDeviceRevoked
+	}
+	if device.Pending() {
+		return nil, ErrDevicePending
+	}
+	return device, nil
+}
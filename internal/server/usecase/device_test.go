@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/common"
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserService_Login_WithDeviceShouldBindToken(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
+	user, device, proof := createDeviceProof(t, "dima")
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockRepo.EXPECT().Exist(ctx, user.Login).Return(true, nil)
+	mockRepo.EXPECT().Get(ctx, user.Login).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	mockDevices.EXPECT().Get(ctx, device.ID).Return(device, nil)
+	mockSessions.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, session *domain.Session) error {
+		assert.Equal(t, device.ID, *session.DeviceID)
+		return nil
+	})
+	mockSessions.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, device.ID.String(), gomock.Any()).Return("token", nil)
+
+	tokens, err := userService.Login(ctx, user.Login, "qwerty", proof, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token", tokens.Access)
+}
+
+func TestUserService_Login_WithRevokedDeviceShouldFail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
+	user, device, proof := createDeviceProof(t, "dima")
+	revokedAt := time.Now()
+	device.RevokedAt = &revokedAt
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockRepo.EXPECT().Exist(ctx, user.Login).Return(true, nil)
+	mockRepo.EXPECT().Get(ctx, user.Login).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	mockDevices.EXPECT().Get(ctx, device.ID).Return(device, nil)
+	mockSessions.EXPECT().Insert(gomock.Any(), gomock.Any()).Times(0)
+	mockAuthEngine.EXPECT().GenerateToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	tokens, err := userService.Login(ctx, user.Login, "qwerty", proof, nil)
+
+	assert.ErrorIs(t, err, ErrDeviceRevoked)
+	assert.Nil(t, tokens)
+}
+
+func TestDeviceService_Revoke_ForeignDeviceShouldFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	userID := *guid.New()
+	ctx := auth.SetUser(context.Background(), userID)
+	deviceID := *guid.New()
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices).AnyTimes()
+	mockDevices.EXPECT().Revoke(ctx, deviceID, userID, gomock.Any()).Return(persistence.ErrResourceNotFound)
+	sut := NewDeviceService(mockUow, mocks.NewMockUserService(ctrl), nil)
+
+	err := sut.Revoke(ctx, deviceID)
+
+	assert.ErrorIs(t, err, ErrDeviceNotFound)
+}
+
+func TestDeviceService_Register_NextDeviceShouldWaitForApproval(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	mockUsers := mocks.NewMockUserService(ctrl)
+	user, device, _ := createDeviceProof(t, "dima")
+	creds := &domain.Credentials{Password: "qwerty"}
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices).AnyTimes()
+	mockUsers.EXPECT().Authenticate(ctx, user.Login, creds).Return(user, nil).Times(2)
+	gomock.InOrder(
+		mockDevices.EXPECT().GetAll(ctx, user.ID).Return(nil, nil),
+		mockDevices.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *domain.Device) error {
+			assert.NotNil(t, d.ApprovedAt)
+			return nil
+		}),
+		mockDevices.EXPECT().GetAll(ctx, user.ID).Return([]*domain.Device{device}, nil),
+		mockDevices.EXPECT().Insert(ctx, gomock.Any()).Return(nil),
+	)
+	sut := NewDeviceService(mockUow, mockUsers, nil)
+
+	first, err := sut.Register(ctx, user.Login, creds, "laptop", public)
+	assert.NoError(t, err)
+	next, err := sut.Register(ctx, user.Login, creds, "phone", public)
+	assert.NoError(t, err)
+
+	assert.False(t, first.Pending())
+	assert.True(t, next.Pending())
+}
+
+func TestDeviceService_Approve_ShouldBeMadeFromApprovedDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	user, approver, _ := createDeviceProof(t, "dima")
+	pending := &domain.Device{ID: *guid.New(), UserID: user.ID, Name: "phone"}
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices).AnyTimes()
+	mockDevices.EXPECT().Get(gomock.Any(), approver.ID).Return(approver, nil)
+	mockDevices.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, persistence.ErrResourceNotFound)
+	mockDevices.EXPECT().Approve(gomock.Any(), pending.ID, user.ID, gomock.Any()).Return(nil)
+	sut := NewDeviceService(mockUow, mocks.NewMockUserService(ctrl), nil)
+	ctx := auth.SetUser(context.Background(), user.ID)
+
+	assert.ErrorIs(t, sut.Approve(ctx, pending.ID), ErrApproverRequired)
+	assert.ErrorIs(t, sut.Approve(auth.SetDevice(ctx, pending.ID), pending.ID), ErrApproverRequired)
+	assert.ErrorIs(t, sut.Approve(auth.SetDevice(ctx, *guid.New()), pending.ID), ErrApproverRequired)
+	assert.NoError(t, sut.Approve(auth.SetDevice(ctx, approver.ID), pending.ID))
+}
+
+func createDeviceProof(t *testing.T, login string) (*domain.User, *domain.Device, *domain.DeviceProof) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{
+		ID:       *guid.New(),
+		Login:    login,
+		Password: []byte("qwerty"),
+		Salt:     []byte("salt"),
+	}
+	now := time.Now()
+	device := &domain.Device{
+		ID:         *guid.New(),
+		UserID:     user.ID,
+		Name:       "laptop",
+		PublicKey:  public,
+		ApprovedAt: &now,
+	}
+	return user, device, &domain.DeviceProof{
+		DeviceID:  device.ID,
+		Timestamp: now,
+		Signature: ed25519.Sign(private, common.DeviceChallenge(login, device.ID.String(), now.Unix())),
+	}
+}
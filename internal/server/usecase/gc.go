@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+)
+
+// GCConfig what garbage collector removes
+type GCConfig struct {
+	// Retention deleted secrets are kept for the duration, so devices offline for a while still learn about deletion.
+	// After retention they are kept until every device of the owner and of recipients pulled past them
+	Retention time.Duration
+	// Grace files changed within the duration are kept, they may belong to uploads in progress
+	Grace time.Duration
+	// DryRun count what would be removed, nothing is removed
+	DryRun bool
+}
+
+// GCResult what was removed, or would be removed on dry run
+type GCResult struct {
+	Tombstones int
+	Blobs      int
+	BlobBytes  int64
+	// Stored files left in blob storage, on dry run files that would be removed are counted too
+	Stored BlobUsage
+	DryRun bool
+}
+
+// BlobUsage files of secrets in blob storage
+type BlobUsage struct {
+	Blobs int64
+	Bytes int64
+}
+
+// GCMetrics totals of the collector since start
+type GCMetrics struct {
+	Runs       int64
+	Failures   int64
+	Tombstones int64
+	Blobs      int64
+	BlobBytes  int64
+	// LastRun start of the last run, zero before the first one
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    error
+	// Stored files in blob storage after the last successful run
+	Stored BlobUsage
+}
+
+// GarbageCollector remove deleted secrets after retention and files no secret refers to
+type GarbageCollector struct {
+	uow     domain.UnitOfWork
+	fp      domain.Filer
+	config  *GCConfig
+	mu      sync.Mutex
+	metrics GCMetrics
+}
+
+func NewGarbageCollector(uow domain.UnitOfWork, fp domain.Filer, config *GCConfig) *GarbageCollector {
+	return &GarbageCollector{uow: uow, fp: fp, config: config}
+}
+
+// Metrics totals of runs made so far, dry runs count nothing as removed
+func (gc *GarbageCollector) Metrics() GCMetrics {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	return gc.metrics
+}
+
+func (gc *GarbageCollector) Collect(ctx context.Context) (*GCResult, error) {
+	start := time.Now()
+	result, err := gc.collect(ctx, start.UTC())
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	gc.metrics.Runs++
+	gc.metrics.LastRun = start
+	gc.metrics.LastDuration = time.Since(start)
+	gc.metrics.LastError = err
+	if err != nil {
+		gc.metrics.Failures++
+		return nil, err
+	}
+	if !result.DryRun {
+		gc.metrics.Tombstones += int64(result.Tombstones)
+		gc.metrics.Blobs += int64(result.Blobs)
+		gc.metrics.BlobBytes += result.BlobBytes
+	}
+	gc.metrics.Stored = result.Stored
+	return result, nil
+}
+
+func (gc *GarbageCollector) collect(ctx context.Context, now time.Time) (*GCResult, error) {
+	result := &GCResult{DryRun: gc.config.DryRun}
+	before := now.Add(-gc.config.Retention)
+	if gc.config.DryRun {
+		tombstones, err := gc.uow.SecretRepository().GetPurgeable(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		result.Tombstones = len(tombstones)
+	} else if err := gc.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		purged, err := work.SecretRepository().PurgeDeleted(ctx, before)
+		if err != nil {
+			return err
+		}
+		result.Tombstones = len(purged)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	secrets, err := gc.uow.SecretRepository().GetBigData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans, usage, err := orphanBlobs(gc.fp, secrets, now.Add(-gc.config.Grace))
+	if err != nil {
+		return nil, err
+	}
+	result.Stored = usage
+	for _, blob := range orphans {
+		if !gc.config.DryRun {
+			if err = gc.fp.Remove(blob.Name, blob.Version); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			result.Stored.Blobs--
+			result.Stored.Bytes -= blob.Size
+		}
+		result.Blobs++
+		result.BlobBytes += blob.Size
+	}
+	return result, nil
+}
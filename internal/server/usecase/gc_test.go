@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGarbageCollector_CollectShouldPurgeTombstonesAndOrphanFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	live := &domain.Secret{ID: *guid.New(), BigData: true, Version: 4}
+	deleted := &domain.Secret{ID: *guid.New(), BigData: true, Version: 6, Deleted: true}
+	old := time.Now().Add(-2 * time.Hour)
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().PurgeDeleted(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) ([]*domain.Secret, error) {
+		assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), before, time.Minute)
+		return []*domain.Secret{{ID: *guid.New()}, {ID: *guid.New()}}, nil
+	})
+	secretRepository.EXPECT().GetBigData(ctx).Return([]*domain.Secret{live, deleted}, nil)
+	txUow.EXPECT().SecretRepository().Return(secretRepository).AnyTimes()
+	filer := mocks.NewMockFiler(ctrl)
+	filer.EXPECT().Walk(gomock.Any()).DoAndReturn(func(fn func(blob *domain.Blob) error) error {
+		for _, blob := range []*domain.Blob{
+			{Name: live.ID.String(), Version: 4, Size: 10, ModifiedAt: old},
+			{Name: live.ID.String(), Version: 3, Size: 100, ModifiedAt: old},
+			{Name: deleted.ID.String(), Version: 6, Size: 1000, ModifiedAt: old},
+			{Name: "keeper.log", Version: 1, Size: 5, ModifiedAt: old},
+		} {
+			if err := fn(blob); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	filer.EXPECT().Remove(live.ID.String(), int32(3)).Return(nil)
+	filer.EXPECT().Remove(deleted.ID.String(), int32(6)).Return(nil)
+	sut := NewGarbageCollector(newMockUow(txUow), filer, &GCConfig{Retention: 30 * 24 * time.Hour, Grace: time.Hour})
+
+	result, err := sut.Collect(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &GCResult{Tombstones: 2, Blobs: 2, BlobBytes: 1100, Stored: BlobUsage{Blobs: 1, Bytes: 10}}, result)
+	assert.Equal(t, BlobUsage{Blobs: 1, Bytes: 10}, sut.Metrics().Stored)
+}
+
+func TestGarbageCollector_DryRunShouldRemoveNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	deleted := &domain.Secret{ID: *guid.New(), BigData: true, Version: 6, Deleted: true}
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().GetPurgeable(ctx, gomock.Any()).Return([]*domain.Secret{deleted}, nil)
+	secretRepository.EXPECT().GetBigData(ctx).Return([]*domain.Secret{deleted}, nil)
+	uow.EXPECT().SecretRepository().Return(secretRepository).Times(2)
+	filer := mocks.NewMockFiler(ctrl)
+	filer.EXPECT().Walk(gomock.Any()).DoAndReturn(func(fn func(blob *domain.Blob) error) error {
+		return fn(&domain.Blob{Name: deleted.ID.String(), Version: 6, Size: 1000, ModifiedAt: time.Now().Add(-2 * time.Hour)})
+	})
+	sut := NewGarbageCollector(uow, filer, &GCConfig{Retention: time.Hour, Grace: time.Hour, DryRun: true})
+
+	result, err := sut.Collect(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &GCResult{Tombstones: 1, Blobs: 1, BlobBytes: 1000, Stored: BlobUsage{Blobs: 1, Bytes: 1000}, DryRun: true}, result)
+	metrics := sut.Metrics()
+	assert.Equal(t, int64(1), metrics.Runs)
+	assert.Zero(t, metrics.Tombstones)
+	assert.Zero(t, metrics.Blobs)
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/srp"
+)
+
+var (
+	ErrHandshakeNotFound   = errors.New("handshake not found or expired")
+	ErrLegacyLoginDisabled = errors.New("login with the password is disabled")
+)
+
+const (
+	// HandshakeTTL time the client has to finish the handshake
+	HandshakeTTL = 20 * time.Second
+	// maxHandshakes pending handshakes kept at once, the oldest one is evicted over it
+	maxHandshakes = 10000
+	// maxLoginHandshakes pending handshakes of one login, the oldest one of the login is evicted over it
+	maxLoginHandshakes = 4
+	// maxPeerHandshakes pending handshakes of one client address
+	maxPeerHandshakes = 16
+)
+
+type handshake struct {
+	id    string
+	login string
+	peer  string
+	// user nil for unknown login, such handshake can't be finished
+	user      *domain.User
+	server    *srp.Server
+	clientKey []byte
+	expiresAt time.Time
+}
+
+// HandshakeStore pending SRP handshakes, each one can be finished once. Store never refuses a new
+// handshake: the oldest one of the same login, of the same peer or of all is evicted instead
+type HandshakeStore struct {
+	mu sync.Mutex
+	// order handshakes oldest first, they expire in the same order
+	order *list.List
+	items map[string]*list.Element
+	// pending count of handshakes per login and per peer
+	pending map[string]int
+	now     func() time.Time
+}
+
+func NewHandshakeStore() *HandshakeStore {
+	return &HandshakeStore{
+		order:   list.New(),
+		items:   make(map[string]*list.Element),
+		pending: make(map[string]int),
+		now:     time.Now,
+	}
+}
+
+// put keep handshake and return its id
+func (s *HandshakeStore) put(h *handshake) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	h.id = hex.EncodeToString(buf)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for front := s.order.Front(); front != nil && now.After(front.Value.(*handshake).expiresAt); front = s.order.Front() {
+		s.remove(front)
+	}
+	s.evict(loginKey(h.login), maxLoginHandshakes, func(item *handshake) bool { return item.login == h.login })
+	if h.peer != "" {
+		s.evict(peerKey(h.peer), maxPeerHandshakes, func(item *handshake) bool { return item.peer == h.peer })
+	}
+	if s.order.Len() >= maxHandshakes {
+		s.remove(s.order.Front())
+	}
+	h.expiresAt = now.Add(HandshakeTTL)
+	s.items[h.id] = s.order.PushBack(h)
+	s.pending[loginKey(h.login)]++
+	if h.peer != "" {
+		s.pending[peerKey(h.peer)]++
+	}
+	return h.id, nil
+}
+
+// take remove handshake, false when there is no such one or it expired
+func (s *HandshakeStore) take(id string) (*handshake, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.items[id]
+	if !ok {
+		return nil, false
+	}
+	h := s.remove(element)
+	if s.now().After(h.expiresAt) {
+		return nil, false
+	}
+	return h, true
+}
+
+// evict remove the oldest handshake matching the key when the key has limit of them pending
+func (s *HandshakeStore) evict(key string, limit int, match func(item *handshake) bool) {
+	if s.pending[key] < limit {
+		return
+	}
+	for element := s.order.Front(); element != nil; element = element.Next() {
+		if match(element.Value.(*handshake)) {
+			s.remove(element)
+			return
+		}
+	}
+}
+
+func (s *HandshakeStore) remove(element *list.Element) *handshake {
+	h := s.order.Remove(element).(*handshake)
+	delete(s.items, h.id)
+	s.release(loginKey(h.login))
+	if h.peer != "" {
+		s.release(peerKey(h.peer))
+	}
+	return h
+}
+
+func (s *HandshakeStore) release(key string) {
+	if s.pending[key]--; s.pending[key] <= 0 {
+		delete(s.pending, key)
+	}
+}
+
+func loginKey(login string) string {
+	return "login:" + login
+}
+
+func peerKey(peer string) string {
+	return "peer:" + peer
+}
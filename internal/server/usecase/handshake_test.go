@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandshakeStore_ShouldEvictOldestHandshakeOfLogin(t *testing.T) {
+	store := NewHandshakeStore()
+	ids := make([]string, 0, maxLoginHandshakes+1)
+	for i := 0; i <= maxLoginHandshakes; i++ {
+		id, err := store.put(&handshake{login: "dima", peer: fmt.Sprintf("10.0.0.%d", i)})
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+	other, err := store.put(&handshake{login: "other"})
+	assert.NoError(t, err)
+
+	_, oldest := store.take(ids[0])
+	_, newest := store.take(ids[maxLoginHandshakes])
+	_, otherLogin := store.take(other)
+
+	assert.False(t, oldest)
+	assert.True(t, newest)
+	assert.True(t, otherLogin)
+}
+
+func TestHandshakeStore_ShouldEvictOldestHandshakeOfPeer(t *testing.T) {
+	store := NewHandshakeStore()
+	ids := make([]string, 0, maxPeerHandshakes+1)
+	for i := 0; i <= maxPeerHandshakes; i++ {
+		id, err := store.put(&handshake{login: fmt.Sprintf("user%d", i), peer: "10.0.0.1"})
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	_, oldest := store.take(ids[0])
+	_, newest := store.take(ids[maxPeerHandshakes])
+
+	assert.False(t, oldest)
+	assert.True(t, newest)
+	assert.Equal(t, maxPeerHandshakes-1, store.pending[peerKey("10.0.0.1")])
+}
+
+func TestHandshakeStore_ShouldNotRefuseWhenFull(t *testing.T) {
+	now := time.Now()
+	store := NewHandshakeStore()
+	store.now = func() time.Time { return now }
+	first, err := store.put(&handshake{login: "user0"})
+	assert.NoError(t, err)
+	for i := 1; i < maxHandshakes; i++ {
+		_, err = store.put(&handshake{login: fmt.Sprintf("user%d", i)})
+		assert.NoError(t, err)
+	}
+
+	id, err := store.put(&handshake{login: "dima"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, maxHandshakes, store.order.Len())
+	_, evicted := store.take(first)
+	assert.False(t, evicted)
+	_, kept := store.take(id)
+	assert.True(t, kept)
+}
+
+func TestHandshakeStore_ShouldExpireHandshake(t *testing.T) {
+	now := time.Now()
+	store := NewHandshakeStore()
+	store.now = func() time.Time { return now }
+	expired, err := store.put(&handshake{login: "dima"})
+	assert.NoError(t, err)
+
+	now = now.Add(HandshakeTTL + time.Second)
+	_, err = store.put(&handshake{login: "other"})
+	assert.NoError(t, err)
+
+	_, ok := store.take(expired)
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.order.Len())
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed login attempts")
+
+// limiterPruneSize entries count after which forgotten entries are removed
+const limiterPruneSize = 4096
+
+// LimiterConfig lockout settings, limiter is disabled when MaxAttempts is zero
+type LimiterConfig struct {
+	// MaxAttempts failures allowed before lockout
+	MaxAttempts int
+	// Lockout duration of the first lockout, doubled on every next failure
+	Lockout time.Duration
+	// MaxLockout upper bound of lockout, failures older than it are forgotten
+	MaxLockout time.Duration
+}
+
+type attempts struct {
+	failures    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// LoginLimiter count failed attempts per key and lock the key out exponentially
+type LoginLimiter struct {
+	mu      sync.Mutex
+	config  *LimiterConfig
+	entries map[string]*attempts
+	now     func() time.Time
+}
+
+func NewLoginLimiter(config *LimiterConfig) *LoginLimiter {
+	return &LoginLimiter{
+		config:  config,
+		entries: make(map[string]*attempts),
+		now:     time.Now,
+	}
+}
+
+// Check return time left until key is unlocked, zero when attempt is allowed
+func (l *LoginLimiter) Check(key string) time.Duration {
+	if l.config.MaxAttempts <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.entries[key]
+	if !ok {
+		return 0
+	}
+	if left := entry.lockedUntil.Sub(l.now()); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// Fail count failed attempt, key is locked out when attempts are exhausted
+func (l *LoginLimiter) Fail(key string) {
+	if l.config.MaxAttempts <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if len(l.entries) > limiterPruneSize {
+		l.prune(now)
+	}
+	entry, ok := l.entries[key]
+	if !ok || l.forgotten(entry, now) {
+		entry = &attempts{}
+		l.entries[key] = entry
+	}
+	entry.failures++
+	entry.last = now
+	if over := entry.failures - l.config.MaxAttempts; over >= 0 {
+		entry.lockedUntil = now.Add(l.lockout(over))
+	}
+}
+
+// Reset forget failures of key after successful attempt
+func (l *LoginLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, key)
+}
+
+// lockout Lockout doubled for every failure over the limit, capped with MaxLockout
+func (l *LoginLimiter) lockout(over int) time.Duration {
+	lockout := l.config.Lockout
+	for i := 0; i < over && lockout < l.config.MaxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, l.config.MaxLockout)
+}
+
+func (l *LoginLimiter) forgotten(entry *attempts, now time.Time) bool {
+	return now.After(entry.lockedUntil) && now.Sub(entry.last) > l.config.MaxLockout
+}
+
+func (l *LoginLimiter) prune(now time.Time) {
+	for key, entry := range l.entries {
+		if l.forgotten(entry, now) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLimiter_ShouldDoubleLockout(t *testing.T) {
+	now := time.Now()
+	limiter := NewLoginLimiter(&LimiterConfig{MaxAttempts: 2, Lockout: time.Second, MaxLockout: 3 * time.Second})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Fail("dima")
+	beforeLimit := limiter.Check("dima")
+	limiter.Fail("dima")
+	first := limiter.Check("dima")
+	limiter.Fail("dima")
+	second := limiter.Check("dima")
+	limiter.Fail("dima")
+	capped := limiter.Check("dima")
+
+	assert.Zero(t, beforeLimit)
+	assert.Equal(t, time.Second, first)
+	assert.Equal(t, 2*time.Second, second)
+	assert.Equal(t, 3*time.Second, capped)
+}
+
+func TestLoginLimiter_ShouldForgetOldFailures(t *testing.T) {
+	now := time.Now()
+	limiter := NewLoginLimiter(&LimiterConfig{MaxAttempts: 2, Lockout: time.Second, MaxLockout: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Fail("dima")
+	now = now.Add(2 * time.Minute)
+	limiter.Fail("dima")
+	afterPause := limiter.Check("dima")
+	limiter.Fail("dima")
+	limiter.Reset("dima")
+	afterReset := limiter.Check("dima")
+
+	assert.Zero(t, afterPause)
+	assert.Zero(t, afterReset)
+}
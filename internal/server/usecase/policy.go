@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/srp"
+)
+
+var ErrWeakPassword = errors.New("password doesn't match policy")
+
+// PasswordPolicy rules new passwords must follow. Password of SRP account isn't seen by the server,
+// only its verifier is checked by ValidateVerifier
+type PasswordPolicy struct {
+	minLength int
+	blocklist map[string]struct{}
+}
+
+// NewPasswordPolicy blocklist is compared case-insensitively
+func NewPasswordPolicy(minLength int, blocklist []string) *PasswordPolicy {
+	policy := &PasswordPolicy{
+		minLength: minLength,
+		blocklist: make(map[string]struct{}, len(blocklist)),
+	}
+	for _, password := range blocklist {
+		if password = strings.TrimSpace(password); password != "" {
+			policy.blocklist[strings.ToLower(password)] = struct{}{}
+		}
+	}
+	return policy
+}
+
+// Validate check password of the user
+func (p *PasswordPolicy) Validate(login, password string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return fmt.Errorf("%w: at least %d characters required", ErrWeakPassword, p.minLength)
+	}
+	if strings.EqualFold(login, password) {
+		return fmt.Errorf("%w: password equals login", ErrWeakPassword)
+	}
+	if _, ok := p.blocklist[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: password is too common", ErrWeakPassword)
+	}
+	return nil
+}
+
+// ValidateVerifier refuse verifier of the password equal to the login, other rules are checked by the client
+func (p *PasswordPolicy) ValidateVerifier(login string, verifier *domain.Verifier) error {
+	if bytes.Equal(srp.Verifier(login, verifier.Salt), verifier.Value) {
+		return fmt.Errorf("%w: password equals login", ErrWeakPassword)
+	}
+	return nil
+}
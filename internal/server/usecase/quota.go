@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/beevik/guid"
+)
+
+var (
+	ErrSecretsQuota    = errors.New("secrets quota exceeded")
+	ErrStorageQuota    = errors.New("storage quota exceeded")
+	ErrBlobTooLarge    = errors.New("file is too large")
+	ErrMessageTooLarge = errors.New("message is too large")
+)
+
+// pushQuota usage of the user counted along the push, limits are checked before anything is written.
+// Secrets are charged to the user who pushes them last
+type pushQuota struct {
+	config  *SyncConfig
+	userID  guid.Guid
+	secrets int64
+	bytes   int64
+	// blobs bytes of files received by the push
+	blobs map[guid.Guid]int64
+}
+
+// pushQuota read current usage of the user, nothing is read when storage isn't limited
+func (ss *SyncService) pushQuota(ctx context.Context, uow domain.UnitOfWork, userID guid.Guid) (*pushQuota, error) {
+	quota := &pushQuota{config: ss.config, userID: userID, blobs: make(map[guid.Guid]int64)}
+	if ss.config.MaxSecrets <= 0 && ss.config.MaxBlobBytes <= 0 {
+		return quota, nil
+	}
+	usage, err := uow.SecretRepository().Usage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	quota.secrets = usage.Secrets
+	quota.bytes = usage.BlobBytes
+	return quota, nil
+}
+
+// message check size of a single message
+func (pq *pushQuota) message(p *Push) error {
+	if pq.config.MaxMessageSize <= 0 {
+		return nil
+	}
+	size := int64(len(p.Buffer))
+	if p.Secret != nil {
+		size += int64(len(p.Secret.Dek) + len(p.Secret.Data))
+		for _, dek := range p.Secret.Shares {
+			size += int64(len(dek))
+		}
+	}
+	if size > pq.config.MaxMessageSize {
+		return fmt.Errorf("%w: %d bytes, %d allowed", ErrMessageTooLarge, size, pq.config.MaxMessageSize)
+	}
+	return nil
+}
+
+// add count new live secret
+func (pq *pushQuota) add() error {
+	if pq.config.MaxSecrets > 0 && pq.secrets+1 > pq.config.MaxSecrets {
+		return fmt.Errorf("%w: %d secrets allowed", ErrSecretsQuota, pq.config.MaxSecrets)
+	}
+	pq.secrets++
+	return nil
+}
+
+// charge count stored secret pushed again as deleted or not, file of the secret is released when
+// its new version takes its place. The secret counts to the user from now on, its owner stays
+func (pq *pushQuota) charge(secret *domain.Secret, deleted, replaced bool) error {
+	// secret charged to another member was counted to them
+	var secrets, bytes int64
+	if secret.ChargedTo == pq.userID && !secret.Deleted {
+		secrets, bytes = 1, secret.Size
+	}
+	secret.ChargedTo = pq.userID
+	if !deleted {
+		secrets--
+		if !replaced {
+			bytes -= secret.Size
+		}
+	}
+	if secrets < 0 && pq.config.MaxSecrets > 0 && pq.secrets-secrets > pq.config.MaxSecrets {
+		return fmt.Errorf("%w: %d secrets allowed", ErrSecretsQuota, pq.config.MaxSecrets)
+	}
+	if bytes < 0 && pq.config.MaxBlobBytes > 0 && pq.bytes-bytes > pq.config.MaxBlobBytes {
+		return fmt.Errorf("%w: %d bytes allowed", ErrStorageQuota, pq.config.MaxBlobBytes)
+	}
+	pq.secrets -= secrets
+	pq.bytes -= bytes
+	return nil
+}
+
+// write count chunk of the file before it is written
+func (pq *pushQuota) write(id guid.Guid, n int) error {
+	size := pq.blobs[id] + int64(n)
+	if pq.config.MaxBlobSize > 0 && size > pq.config.MaxBlobSize {
+		return fmt.Errorf("%w: %d bytes allowed", ErrBlobTooLarge, pq.config.MaxBlobSize)
+	}
+	if pq.config.MaxBlobBytes > 0 && pq.bytes+int64(n) > pq.config.MaxBlobBytes {
+		return fmt.Errorf("%w: %d bytes allowed", ErrStorageQuota, pq.config.MaxBlobBytes)
+	}
+	pq.blobs[id] = size
+	pq.bytes += int64(n)
+	return nil
+}
+
+// size bytes of the file received by the push
+func (pq *pushQuota) size(id guid.Guid) int64 {
+	return pq.blobs[id]
+}
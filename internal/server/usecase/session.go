@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/domain/auth"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused, session revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session revoked")
+	ErrSessionExpired      = errors.New("session expired")
+)
+
+// refreshTokenLength random bytes in refresh token
+const refreshTokenLength = 32
+
+type SessionConfig struct {
+	// AccessTokenExpiration lifetime of access token
+	AccessTokenExpiration time.Duration
+	// RefreshTokenExpiration session is closed when it is not refreshed for the duration
+	RefreshTokenExpiration time.Duration
+	// Audit journal of token refreshes, nothing is recorded when it isn't set
+	Audit domain.AuditLog
+}
+
+type SessionService struct {
+	uow    domain.UnitOfWork
+	engine auth.Engine
+	config *SessionConfig
+}
+
+func NewSessionService(uow domain.UnitOfWork, engine auth.Engine, config *SessionConfig) *SessionService {
+	return &SessionService{uow: uow, engine: engine, config: config}
+}
+
+// Start open a session of the user and issue its first tokens
+func (ss *SessionService) Start(ctx context.Context, work domain.UnitOfWork, userID guid.Guid, deviceID *guid.Guid) (*domain.Tokens, error) {
+	now := time.Now().UTC()
+	session := &domain.Session{
+		UserID:     userID,
+		DeviceID:   deviceID,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(ss.config.RefreshTokenExpiration),
+	}
+	repository := work.SessionRepository()
+	if err := repository.Insert(ctx, session); err != nil {
+		return nil, err
+	}
+	return ss.issue(ctx, repository, session, now)
+}
+
+// Refresh exchange refresh token for a new pair of tokens. The token can be used once,
+// second use means it was stolen, so the whole session is revoked
+func (ss *SessionService) Refresh(ctx context.Context, refreshToken string) (*domain.Tokens, error) {
+	var tokens *domain.Tokens
+	var session *domain.Session
+	var reused bool
+	if err := ss.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		repository := work.SessionRepository()
+		hash := hashRefreshToken(refreshToken)
+		token, err := repository.GetToken(ctx, hash)
+		if err != nil {
+			if errors.Is(err, persistence.ErrResourceNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if session, err = repository.Get(ctx, token.SessionID); err != nil {
+			return err
+		}
+		if session.Revoked() {
+			return ErrSessionRevoked
+		}
+		now := time.Now().UTC()
+		if token.UsedAt != nil {
+			reused = true
+			return repository.Revoke(ctx, session.ID, session.UserID, now)
+		}
+		if now.After(session.ExpiresAt) {
+			return ErrSessionExpired
+		}
+		if session.DeviceID != nil {
+			if _, err = getDevice(ctx, work.DeviceRepository(), session.UserID, *session.DeviceID); err != nil {
+				return err
+			}
+		}
+		if err = repository.UseToken(ctx, hash, now); err != nil {
+			if errors.Is(err, persistence.ErrResourceNotFound) {
+				// a concurrent request used the token
+				reused = true
+				return repository.Revoke(ctx, session.ID, session.UserID, now)
+			}
+			return err
+		}
+		session.LastUsedAt = now
+		session.ExpiresAt = now.Add(ss.config.RefreshTokenExpiration)
+		if err = repository.Extend(ctx, session.ID, session.LastUsedAt, session.ExpiresAt); err != nil {
+			return err
+		}
+		tokens, err = ss.issue(ctx, repository, session, now)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	recordAudit(ctx, ss.config.Audit, &domain.AuditEvent{
+		Type:     domain.AuditTokenRefreshed,
+		UserID:   &session.UserID,
+		DeviceID: session.DeviceID,
+	})
+	return tokens, nil
+}
+
+// List active sessions of the user
+func (ss *SessionService) List(ctx context.Context) ([]*domain.Session, error) {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ss.uow.SessionRepository().GetActive(ctx, userID, time.Now().UTC())
+}
+
+// Terminate revoke session of the user. Its tokens are rejected starting from the next request
+func (ss *SessionService) Terminate(ctx context.Context, id guid.Guid) error {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return err
+	}
+	if err = ss.uow.SessionRepository().Revoke(ctx, id, userID, time.Now().UTC()); err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Logout terminate session of the request
+func (ss *SessionService) Logout(ctx context.Context) error {
+	id, ok := sh.Session(ctx)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	return ss.Terminate(ctx, id)
+}
+
+// VerifySession check session belongs to the user and is not revoked
+func (ss *SessionService) VerifySession(ctx context.Context, userID, sessionID guid.Guid) error {
+	session, err := ss.uow.SessionRepository().Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if session.Revoked() {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+func (ss *SessionService) issue(
+	ctx context.Context,
+	repository domain.SessionRepository,
+	session *domain.Session,
+	now time.Time,
+) (*domain.Tokens, error) {
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	if err = repository.InsertToken(ctx, &domain.RefreshToken{
+		Hash:      hashRefreshToken(refreshToken),
+		SessionID: session.ID,
+	}); err != nil {
+		return nil, err
+	}
+	var deviceID string
+	if session.DeviceID != nil {
+		deviceID = session.DeviceID.String()
+	}
+	access, err := ss.engine.GenerateToken(session.UserID, deviceID, session.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Tokens{
+		Access:    access,
+		Refresh:   refreshToken,
+		ExpiresAt: now.Add(ss.config.AccessTokenExpiration),
+	}, nil
+}
+
+func newRefreshToken() (string, error) {
+	buf := make([]byte, refreshTokenLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRefreshToken token has enough entropy, so plain sha256 is sufficient
+func hashRefreshToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
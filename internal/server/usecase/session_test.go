@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/domain/auth"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionService_Refresh_ShouldRotateToken(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	sut := createSessionService(newMockUow(mockTx), mockAuthEngine)
+	session := createSession(time.Now().Add(time.Hour))
+	refreshToken := "refresh"
+	mockTx.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockSessions.EXPECT().GetToken(ctx, hashRefreshToken(refreshToken)).
+		Return(&domain.RefreshToken{Hash: hashRefreshToken(refreshToken), SessionID: session.ID}, nil)
+	mockSessions.EXPECT().Get(ctx, session.ID).Return(session, nil)
+	mockSessions.EXPECT().UseToken(ctx, hashRefreshToken(refreshToken), gomock.Any()).Return(nil)
+	mockSessions.EXPECT().Extend(ctx, session.ID, gomock.Any(), gomock.Any()).Return(nil)
+	mockSessions.EXPECT().InsertToken(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *domain.RefreshToken) error {
+		assert.Equal(t, session.ID, token.SessionID)
+		assert.NotEqual(t, hashRefreshToken(refreshToken), token.Hash)
+		return nil
+	})
+	mockAuthEngine.EXPECT().GenerateToken(session.UserID, "", session.ID.String()).Return("token", nil)
+
+	tokens, err := sut.Refresh(ctx, refreshToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "token", tokens.Access)
+	assert.NotEqual(t, refreshToken, tokens.Refresh)
+}
+
+func TestSessionService_Refresh_ReusedTokenShouldRevokeSession(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	sut := createSessionService(newMockUow(mockTx), mockAuthEngine)
+	session := createSession(time.Now().Add(time.Hour))
+	usedAt := time.Now().Add(-time.Minute)
+	mockTx.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockSessions.EXPECT().GetToken(ctx, gomock.Any()).
+		Return(&domain.RefreshToken{SessionID: session.ID, UsedAt: &usedAt}, nil)
+	mockSessions.EXPECT().Get(ctx, session.ID).Return(session, nil)
+	mockSessions.EXPECT().Revoke(ctx, session.ID, session.UserID, gomock.Any()).Return(nil)
+	mockSessions.EXPECT().InsertToken(gomock.Any(), gomock.Any()).Times(0)
+	mockAuthEngine.EXPECT().GenerateToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	tokens, err := sut.Refresh(ctx, "refresh")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+}
+
+func TestSessionService_Refresh_ExpiredSessionShouldFail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	sut := createSessionService(newMockUow(mockTx), mocks.NewMockEngine(ctrl))
+	session := createSession(time.Now().Add(-time.Minute))
+	mockTx.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockSessions.EXPECT().GetToken(ctx, gomock.Any()).Return(&domain.RefreshToken{SessionID: session.ID}, nil)
+	mockSessions.EXPECT().Get(ctx, session.ID).Return(session, nil)
+	mockSessions.EXPECT().UseToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := sut.Refresh(ctx, "refresh")
+
+	assert.ErrorIs(t, err, ErrSessionExpired)
+}
+
+func TestSessionService_VerifySession_RevokedShouldFail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	sut := createSessionService(mockUow, mocks.NewMockEngine(ctrl))
+	session := createSession(time.Now().Add(time.Hour))
+	revokedAt := time.Now()
+	session.RevokedAt = &revokedAt
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockSessions.EXPECT().Get(ctx, session.ID).Return(session, nil)
+
+	err := sut.VerifySession(ctx, session.UserID, session.ID)
+
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+}
+
+func TestSessionService_VerifySession_UnknownShouldFail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	sut := createSessionService(mockUow, mocks.NewMockEngine(ctrl))
+	sessionID := *guid.New()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockSessions.EXPECT().Get(ctx, sessionID).Return(nil, persistence.ErrResourceNotFound)
+
+	err := sut.VerifySession(ctx, *guid.New(), sessionID)
+
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func createSession(expiresAt time.Time) *domain.Session {
+	return &domain.Session{
+		ID:         *guid.New(),
+		UserID:     *guid.New(),
+		LastUsedAt: time.Now().Add(-time.Hour),
+		ExpiresAt:  expiresAt,
+	}
+}
+
+func createSessionService(work domain.UnitOfWork, engine auth.Engine) *SessionService {
+	return NewSessionService(work, engine, &SessionConfig{
+		AccessTokenExpiration:  time.Minute,
+		RefreshTokenExpiration: time.Hour,
+	})
+}
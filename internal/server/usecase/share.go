@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+)
+
+var (
+	ErrShareWithSelf     = errors.New("secret can't be shared with its owner")
+	ErrNoPublicKey       = errors.New("user has no public key yet")
+	ErrShareNotFound     = errors.New("share not found")
+	ErrInvalidShareKey   = errors.New("invalid public key")
+	ErrSealedDekRequired = errors.New("sealed dek is required")
+)
+
+// shareKeySize size of X25519 public key
+const shareKeySize = 32
+
+// ShareService sharing secrets between users. Server never sees the dek, owner seals it
+// to public key of the recipient on the client side
+type ShareService struct {
+	uow    domain.UnitOfWork
+	broker *ChangeBroker
+}
+
+func NewShareService(uow domain.UnitOfWork, broker *ChangeBroker) *ShareService {
+	return &ShareService{uow: uow, broker: broker}
+}
+
+// SetPublicKey publish key secrets are shared to with the user
+func (ss *ShareService) SetPublicKey(ctx context.Context, publicKey []byte) error {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return err
+	}
+	if len(publicKey) != shareKeySize {
+		return ErrInvalidShareKey
+	}
+	if err = ss.uow.UserRepository().UpdatePublicKey(ctx, userID, publicKey); err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetPublicKey public key of the user, the owner checks it before sharing
+func (ss *ShareService) GetPublicKey(ctx context.Context, login string) ([]byte, error) {
+	user, err := recipient(ctx, ss.uow, login)
+	if err != nil {
+		return nil, err
+	}
+	return user.PublicKey, nil
+}
+
+// Share give the user access to the secret. Recipient gets it with the next pull
+func (ss *ShareService) Share(ctx context.Context, secretID guid.Guid, login string, dek []byte) error {
+	if len(dek) == 0 {
+		return ErrSealedDekRequired
+	}
+	var state *domain.SyncState
+	if err := ss.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		secret, err := ownSecret(ctx, work, secretID)
+		if err != nil {
+			return err
+		}
+		user, err := recipient(ctx, work, login)
+		if err != nil {
+			return err
+		}
+		if user.ID == secret.UserID {
+			return ErrShareWithSelf
+		}
+		if len(user.PublicKey) == 0 {
+			return ErrNoPublicKey
+		}
+		if state, err = bumpState(ctx, work, user.ID, make(map[guid.Guid]*domain.SyncState)); err != nil {
+			return err
+		}
+		if err = work.ShareRepository().Save(ctx, &domain.Share{
+			SecretID:    secret.ID,
+			OwnerID:     secret.UserID,
+			RecipientID: user.ID,
+			Dek:         dek,
+			Version:     state.Value,
+		}); err != nil {
+			return err
+		}
+		return work.SyncStateRepository().Update(ctx, state)
+	}); err != nil {
+		return err
+	}
+	ss.broker.Publish(state.UserID, ChangeEvent{Version: state.Value})
+	return nil
+}
+
+// Revoke stop sharing the secret with the user, recipient removes it with the next pull.
+// Secret the recipient has already seen stays known, owner should change it
+func (ss *ShareService) Revoke(ctx context.Context, secretID guid.Guid, login string) error {
+	var state *domain.SyncState
+	if err := ss.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		if _, err := ownSecret(ctx, work, secretID); err != nil {
+			return err
+		}
+		user, err := recipient(ctx, work, login)
+		if err != nil {
+			return err
+		}
+		if state, err = bumpState(ctx, work, user.ID, make(map[guid.Guid]*domain.SyncState)); err != nil {
+			return err
+		}
+		if err = work.ShareRepository().Revoke(ctx, secretID, user.ID, state.Value, time.Now().UTC()); err != nil {
+			if errors.Is(err, persistence.ErrResourceNotFound) {
+				return ErrShareNotFound
+			}
+			return err
+		}
+		return work.SyncStateRepository().Update(ctx, state)
+	}); err != nil {
+		return err
+	}
+	ss.broker.Publish(state.UserID, ChangeEvent{Version: state.Value})
+	return nil
+}
+
+// List active shares of secrets of the user
+func (ss *ShareService) List(ctx context.Context) ([]*domain.Share, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ss.uow.ShareRepository().GetByOwner(ctx, userID)
+}
+
+// ownSecret secret of the current user, deleted one can't be shared
+func ownSecret(ctx context.Context, work domain.UnitOfWork, id guid.Guid) (*domain.Secret, error) {
+	secret, err := work.SecretRepository().Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, err
+	}
+	if err = owned(ctx, secret); err != nil {
+		return nil, err
+	}
+	if secret.Deleted {
+		return nil, ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+func recipient(ctx context.Context, work domain.UnitOfWork, login string) (*domain.User, error) {
+	repository := work.UserRepository()
+	exist, err := repository.Exist(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrUserNotFound
+	}
+	return repository.Get(ctx, login)
+}
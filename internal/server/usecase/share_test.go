@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareService_Share_ShouldBumpRecipientVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ownerID := *guid.New()
+	ctx := auth.SetUser(context.Background(), ownerID)
+	secret := &domain.Secret{ID: *guid.New(), UserID: ownerID, Version: 3}
+	recipient := &domain.User{ID: *guid.New(), Login: "olga", PublicKey: make([]byte, 32)}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	shares := mocks.NewMockShareRepository(ctrl)
+	txUow.EXPECT().SecretRepository().Return(secrets).AnyTimes()
+	txUow.EXPECT().UserRepository().Return(users).AnyTimes()
+	txUow.EXPECT().SyncStateRepository().Return(states).AnyTimes()
+	txUow.EXPECT().ShareRepository().Return(shares).AnyTimes()
+	secrets.EXPECT().Get(ctx, secret.ID).Return(secret, nil)
+	users.EXPECT().Exist(ctx, recipient.Login).Return(true, nil)
+	users.EXPECT().Get(ctx, recipient.Login).Return(recipient, nil)
+	states.EXPECT().Get(ctx, syncTypeName, recipient.ID).
+		Return(&domain.SyncState{ID: syncTypeName, UserID: recipient.ID, Value: 7}, nil)
+	shares.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, share *domain.Share) error {
+		assert.Equal(t, recipient.ID, share.RecipientID)
+		assert.Equal(t, ownerID, share.OwnerID)
+		assert.Equal(t, int32(8), share.Version)
+		assert.Equal(t, []byte("sealed"), share.Dek)
+		return nil
+	})
+	states.EXPECT().Update(ctx, &domain.SyncState{ID: syncTypeName, UserID: recipient.ID, Value: 8}).Return(nil)
+	broker := NewChangeBroker()
+	events, cancel := broker.Subscribe(recipient.ID, "")
+	defer cancel()
+	sut := NewShareService(newMockUow(txUow), broker)
+
+	err := sut.Share(ctx, secret.ID, recipient.Login, []byte("sealed"))
+
+	assert.NoError(t, err)
+	select {
+	case event := <-events:
+		assert.Equal(t, int32(8), event.Version)
+	case <-time.After(time.Second):
+		t.Fatal("recipient wasn't notified")
+	}
+}
+
+func TestShareService_Share_ForeignSecretShouldFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := auth.SetUser(context.Background(), *guid.New())
+	secret := &domain.Secret{ID: *guid.New(), UserID: *guid.New()}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	txUow.EXPECT().SecretRepository().Return(secrets)
+	secrets.EXPECT().Get(ctx, secret.ID).Return(secret, nil)
+	sut := NewShareService(newMockUow(txUow), NewChangeBroker())
+
+	err := sut.Share(ctx, secret.ID, "olga", []byte("sealed"))
+
+	assert.ErrorIs(t, err, ErrSecretNotOwned)
+}
+
+func TestSyncService_File_RecipientShouldGetCurrentVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	recipientID := *guid.New()
+	ctx := auth.SetUser(context.Background(), recipientID)
+	secret := &domain.Secret{ID: *guid.New(), UserID: *guid.New(), Version: 4, BigData: true}
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	shares := mocks.NewMockShareRepository(ctrl)
+	filer := mocks.NewMockFiler(ctrl)
+	uow.EXPECT().SecretRepository().Return(secrets)
+	uow.EXPECT().ShareRepository().Return(shares)
+	secrets.EXPECT().Get(ctx, secret.ID).Return(secret, nil)
+	shares.EXPECT().Get(ctx, secret.ID, recipientID).Return(&domain.Share{SecretID: secret.ID, RecipientID: recipientID, Version: 9}, nil)
+	filer.EXPECT().OpenRead(secret.ID.String(), secret.Version).Return(io.NopCloser(strings.NewReader("file")), nil)
+	sut := NewSyncService(uow, filer, NewChangeBroker(), &SyncConfig{})
+
+	reader, err := sut.File(ctx, secret.ID, 9)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, reader)
+}
+
+func TestSyncService_File_StrangerShouldNotGetFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	strangerID := *guid.New()
+	ctx := auth.SetUser(context.Background(), strangerID)
+	secret := &domain.Secret{ID: *guid.New(), UserID: *guid.New(), Version: 4, BigData: true}
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	shares := mocks.NewMockShareRepository(ctrl)
+	uow.EXPECT().SecretRepository().Return(secrets)
+	uow.EXPECT().ShareRepository().Return(shares)
+	secrets.EXPECT().Get(ctx, secret.ID).Return(secret, nil)
+	revokedAt := time.Now()
+	shares.EXPECT().Get(ctx, secret.ID, strangerID).Return(&domain.Share{RevokedAt: &revokedAt}, nil)
+	sut := NewSyncService(uow, mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+
+	reader, err := sut.File(ctx, secret.ID, 4)
+
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+	assert.Nil(t, reader)
+}
+
+func TestSyncService_Push_SharedSecretWithoutKeysShouldFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ownerID := *guid.New()
+	ctx := auth.SetUser(context.Background(), ownerID)
+	secret := &domain.Secret{ID: *guid.New(), UserID: ownerID, Version: 2}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	shares := mocks.NewMockShareRepository(ctrl)
+	txUow.EXPECT().SyncStateRepository().Return(states).AnyTimes()
+	txUow.EXPECT().SecretRepository().Return(secrets).AnyTimes()
+	txUow.EXPECT().ShareRepository().Return(shares).AnyTimes()
+	states.EXPECT().Get(ctx, syncTypeName, ownerID).Return(&domain.SyncState{ID: syncTypeName, UserID: ownerID, Value: 2}, nil)
+	secrets.EXPECT().Get(ctx, secret.ID).Return(secret, nil)
+	secrets.EXPECT().Update(ctx, secret).Return(nil)
+	shares.EXPECT().GetBySecret(ctx, secret.ID).
+		Return([]*domain.Share{{SecretID: secret.ID, RecipientID: *guid.New(), Recipient: "olga"}}, nil)
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+	stream := newMockStream([]*Push{{
+		Type:   DefaultOperation,
+		Secret: &Secret{ID: secret.ID, ModifiedAt: time.Now(), Dek: []byte("dek"), Data: []byte("data"), Version: 2},
+	}})
+
+	err := sut.Push(ctx, 2, stream.Next)
+
+	assert.ErrorIs(t, err, ErrShareKeyRequired)
+}
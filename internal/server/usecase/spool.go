@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"os"
+)
+
+// spool messages of push received before the transaction. They wait in a temp file, so the transaction
+// locking the sync state doesn't last as long as the client takes to send them
+type spool struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *gob.Encoder
+	decoder *gob.Decoder
+}
+
+func newSpool() (*spool, error) {
+	file, err := os.CreateTemp("", "keeper-push-*")
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	return &spool{file: file, writer: writer, encoder: gob.NewEncoder(writer)}, nil
+}
+
+func (s *spool) add(p *Push) error {
+	return s.encoder.Encode(p)
+}
+
+// rewind start reading messages from the first one
+func (s *spool) rewind() error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	s.decoder = gob.NewDecoder(bufio.NewReader(s.file))
+	return nil
+}
+
+// next message in order of arrival, io.EOF after the last one
+func (s *spool) next() (*Push, error) {
+	var p Push
+	if err := s.decoder.Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// close remove the temp file
+func (s *spool) close() {
+	_ = s.file.Close()
+	_ = os.Remove(s.file.Name())
+}
@@ -3,9 +3,11 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/DimKa163/keeper/internal/server/domain"
@@ -15,8 +17,17 @@ import (
 )
 
 var syncTypeName = reflect.TypeOf(domain.Secret{}).Name()
+
+const (
+	DefaultPageSize int32 = 100
+	MaxPageSize     int32 = 1000
+)
+
 var (
-	ErrVersionConflict = errors.New("conflict")
+	ErrVersionConflict  = errors.New("conflict")
+	ErrSecretNotFound   = errors.New("secret not found")
+	ErrSecretNotOwned   = errors.New("secret belongs to another user")
+	ErrShareKeyRequired = errors.New("secret is shared, dek sealed to every recipient is required")
 )
 
 type OperationType int
@@ -37,6 +48,8 @@ type (
 		Data       []byte
 		Version    int32
 		Deleted    bool
+		// Shares dek sealed to every recipient of the secret by login
+		Shares map[string][]byte
 	}
 	Push struct {
 		Secret *Secret
@@ -45,22 +58,65 @@ type (
 	}
 )
 
+// syncScope secrets the request syncs: own secrets of the user or secrets of the vault set to context
+type syncScope struct {
+	userID  guid.Guid
+	vaultID *guid.Guid
+}
+
+// state sync state that versions the secrets, the one of the vault or of the user
+func (sc *syncScope) state(ctx context.Context, repository domain.SyncStateRepository) (*domain.SyncState, error) {
+	if sc.vaultID != nil {
+		return repository.GetVault(ctx, *sc.vaultID)
+	}
+	return repository.Get(ctx, syncTypeName, sc.userID)
+}
+
+// owns own secret can be changed by its owner only, vault secret within its vault only
+func (sc *syncScope) owns(secret *domain.Secret) error {
+	if sc.vaultID == nil {
+		if secret.VaultID != nil || secret.UserID != sc.userID {
+			return ErrSecretNotOwned
+		}
+		return nil
+	}
+	if secret.VaultID == nil || *secret.VaultID != *sc.vaultID {
+		return ErrSecretNotOwned
+	}
+	return nil
+}
+
+// SyncConfig sync settings
+type SyncConfig struct {
+	// Audit journal of pushes, pulls and downloads, nothing is recorded when it isn't set
+	Audit domain.AuditLog
+	// MaxSecrets live secrets a user may have
+	MaxSecrets int64
+	// MaxBlobBytes total size of files of a user
+	MaxBlobBytes int64
+	// MaxBlobSize size of a single file
+	MaxBlobSize int64
+	// MaxMessageSize size of dek, payload and chunk of a single push message
+	MaxMessageSize int64
+}
+
 type SyncService struct {
-	uow domain.UnitOfWork
-	fp  domain.Filer
+	uow    domain.UnitOfWork
+	fp     domain.Filer
+	broker *ChangeBroker
+	config *SyncConfig
 }
 
-func NewSyncService(uow domain.UnitOfWork, fp domain.Filer) *SyncService {
-	return &SyncService{uow: uow, fp: fp}
+func NewSyncService(uow domain.UnitOfWork, fp domain.Filer, broker *ChangeBroker, config *SyncConfig) *SyncService {
+	return &SyncService{uow: uow, fp: fp, broker: broker, config: config}
 }
 
 func (ss *SyncService) ValidateVersion(ctx context.Context, version int32) error {
-	userID, err := auth.User(ctx)
+	sc, err := ss.scope(ctx, ss.uow, domain.WriterRole)
 	if err != nil {
 		return err
 	}
-	syncStateRepository := ss.uow.SyncStateRepository()
-	syncState, err := syncStateRepository.Get(ctx, syncTypeName, userID)
+	syncState, err := sc.state(ctx, ss.uow.SyncStateRepository())
 	if err != nil {
 		return err
 	}
@@ -70,106 +126,453 @@ func (ss *SyncService) ValidateVersion(ctx context.Context, version int32) error
 	return nil
 }
 
-func (ss *SyncService) File(id guid.Guid, version int32) (io.ReadCloser, error) {
-	return ss.fp.OpenRead(id.String(), version)
+// File open file of the secret. Recipient of the shared secret knows it under their own version,
+// so the current one is always served. File of vault secret is served to members of the vault
+func (ss *SyncService) File(ctx context.Context, id guid.Guid, version int32) (io.ReadCloser, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := ss.uow.SecretRepository().Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, err
+	}
+	if secret.VaultID != nil {
+		if _, err = vaultMember(ctx, ss.uow, *secret.VaultID, domain.ReaderRole); err != nil {
+			if errors.Is(err, ErrVaultNotFound) {
+				return nil, ErrSecretNotFound
+			}
+			return nil, err
+		}
+		return ss.download(ctx, secret, version)
+	}
+	if secret.UserID != userID {
+		share, err := ss.uow.ShareRepository().Get(ctx, id, userID)
+		if err != nil {
+			if errors.Is(err, persistence.ErrResourceNotFound) {
+				return nil, ErrSecretNotFound
+			}
+			return nil, err
+		}
+		if share.Revoked() {
+			return nil, ErrSecretNotFound
+		}
+		version = secret.Version
+	}
+	return ss.download(ctx, secret, version)
 }
 
-func (ss *SyncService) Push(ctx context.Context, fn func(ctx context.Context) (*Push, error)) error {
-	if err := ss.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
-		userID, err := auth.User(ctx)
-		if err != nil {
+// download open file of the secret, download is recorded with bytes read when the file is closed
+func (ss *SyncService) download(ctx context.Context, secret *domain.Secret, version int32) (io.ReadCloser, error) {
+	file, err := ss.fp.OpenRead(secret.ID.String(), version)
+	if err != nil || ss.config.Audit == nil {
+		return file, err
+	}
+	return &auditedReader{ReadCloser: file, done: func(n int64) {
+		recordAudit(ctx, ss.config.Audit, &domain.AuditEvent{
+			Type:     domain.AuditDownloaded,
+			SecretID: &secret.ID,
+			Version:  version,
+			Bytes:    n,
+			Reason:   vaultReason(secret.VaultID),
+		})
+	}}, nil
+}
+
+// Push apply changes of own secrets of the user or of the vault set to context, writer role is required for a vault.
+// ErrVersionConflict when the sync state moved past base the client bound the secrets to
+func (ss *SyncService) Push(ctx context.Context, base int32, fn func(ctx context.Context) (*Push, error)) error {
+	var version int32
+	var sc *syncScope
+	var members []*domain.VaultMember
+	// recipients sync states of recipients of changed shared secrets, each one is bumped once per push
+	recipients := make(map[guid.Guid]*domain.SyncState)
+	audit := newPushAudit()
+	received, err := ss.receive(ctx, fn, audit)
+	if err != nil {
+		return err
+	}
+	defer received.close()
+	files := newUploads(ss.fp)
+	// abort unfinished uploads
+	defer files.abort()
+	if err = ss.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		var err error
+		if sc, err = ss.scope(ctx, work, domain.WriterRole); err != nil {
 			return err
 		}
 		syncStateRepository := work.SyncStateRepository()
-		syncState, err := syncStateRepository.Get(ctx, syncTypeName, userID)
+		syncState, err := sc.state(ctx, syncStateRepository)
+		if err != nil {
+			return err
+		}
+		// the state is locked until commit, no other push moves the version
+		if syncState.Value != base {
+			return ErrVersionConflict
+		}
 		syncState.Value += 1
+		quota, err := ss.pushQuota(ctx, work, sc.userID)
 		if err != nil {
 			return err
 		}
+		if err = received.rewind(); err != nil {
+			return err
+		}
 		for {
-			req, err := fn(ctx)
+			req, err := received.next()
 			if err != nil {
 				if errors.Is(err, io.EOF) {
 					break
 				}
 				return err
 			}
+			var changed *domain.Secret
 			switch req.Type {
 			case DefaultOperation:
-				if err = ss.push(ctx, work, syncState, req); err != nil {
+				if changed, err = ss.push(ctx, work, sc, syncState, quota, req); err != nil {
 					return err
 				}
 			case BeginOperation:
-				if err = ss.startUploadFile(ctx, work, syncState, req); err != nil {
+				if changed, err = ss.startUploadFile(ctx, work, sc, syncState, quota, req); err != nil {
 					return err
 				}
 			case ChunkOperation:
-				if err = ss.writeChunk(ctx, work, syncState, req); err != nil {
+				if err = ss.writeChunk(ctx, work, sc, syncState, quota, files, req); err != nil {
 					return err
 				}
 			case EndOperation:
-				if err = ss.endFile(ctx, work, syncState, req); err != nil {
+				if changed, err = ss.endFile(ctx, work, sc, syncState, quota, files, req); err != nil {
 					return err
 				}
 			}
+			// vault secrets aren't shared one by one
+			if changed != nil && sc.vaultID == nil {
+				if err = ss.share(ctx, work, changed, req.Secret.Shares, recipients); err != nil {
+					return err
+				}
+			}
+		}
+		for _, state := range recipients {
+			if err = syncStateRepository.Update(ctx, state); err != nil {
+				return err
+			}
+		}
+		if sc.vaultID != nil {
+			if members, err = work.VaultRepository().GetMembers(ctx, *sc.vaultID); err != nil {
+				return err
+			}
 		}
+		version = syncState.Value
 		return syncStateRepository.Update(ctx, syncState)
 	}); err != nil {
 		return err
 	}
+	for _, pushed := range audit.events {
+		pushed.Version = version
+		pushed.Reason = reasons(pushed.Reason, vaultReason(sc.vaultID))
+		recordAudit(ctx, ss.config.Audit, pushed)
+	}
+	event := ChangeEvent{Version: version, ClientID: auth.Client(ctx), VaultID: sc.vaultID}
+	if sc.vaultID == nil {
+		ss.broker.Publish(sc.userID, event)
+	}
+	for _, member := range members {
+		ss.broker.Publish(member.UserID, event)
+	}
+	for recipientID, state := range recipients {
+		ss.broker.Publish(recipientID, ChangeEvent{Version: state.Value})
+	}
+	return nil
+}
+
+// receive read push stream to spool. Size of messages and files is checked on the way against limits
+// that don't depend on stored secrets, so the client can't make the server spool more than a push may store
+func (ss *SyncService) receive(ctx context.Context, fn func(ctx context.Context) (*Push, error), audit *pushAudit) (*spool, error) {
+	received, err := newSpool()
+	if err != nil {
+		return nil, err
+	}
+	limits := &pushQuota{config: ss.config, blobs: make(map[guid.Guid]int64)}
+	for {
+		req, err := fn(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return received, nil
+			}
+			received.close()
+			return nil, err
+		}
+		if err = limits.message(req); err == nil && req.Type == ChunkOperation {
+			err = limits.write(req.Secret.ID, len(req.Buffer))
+		}
+		if err == nil {
+			err = received.add(req)
+		}
+		if err != nil {
+			received.close()
+			return nil, err
+		}
+		audit.add(req)
+	}
+}
+
+// share pass changed secret to users it is shared with. Shared secret gets new version in sync state
+// of the recipient, dek must be sealed to the recipient anew unless the secret is deleted
+func (ss *SyncService) share(
+	ctx context.Context,
+	uow domain.UnitOfWork,
+	secret *domain.Secret,
+	keys map[string][]byte,
+	recipients map[guid.Guid]*domain.SyncState,
+) error {
+	shareRepository := uow.ShareRepository()
+	shares, err := shareRepository.GetBySecret(ctx, secret.ID)
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		if !secret.Deleted {
+			dek, ok := keys[share.Recipient]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrShareKeyRequired, share.Recipient)
+			}
+			share.Dek = dek
+		}
+		state, err := bumpState(ctx, uow, share.RecipientID, recipients)
+		if err != nil {
+			return err
+		}
+		share.Version = state.Value
+		if err = shareRepository.Update(ctx, share); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// bumpState take next version of the user sync state, the state is read and bumped once
+func bumpState(ctx context.Context, uow domain.UnitOfWork, userID guid.Guid, states map[guid.Guid]*domain.SyncState) (*domain.SyncState, error) {
+	if state, ok := states[userID]; ok {
+		return state, nil
+	}
+	state, err := uow.SyncStateRepository().Get(ctx, syncTypeName, userID)
+	if err != nil {
+		return nil, err
+	}
+	state.Value += 1
+	states[userID] = state
+	return state, nil
+}
+
+// owned secret can be changed by its owner only, secret of a vault is owned by none
+func owned(ctx context.Context, secret *domain.Secret) error {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return err
+	}
+	if secret.VaultID != nil || secret.UserID != userID {
+		return ErrSecretNotOwned
+	}
+	return nil
+}
+
+// scope secrets the request syncs. Vault set to context requires membership with at least the role
+func (ss *SyncService) scope(ctx context.Context, uow domain.UnitOfWork, role domain.VaultRole) (*syncScope, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vaultID, ok := auth.Vault(ctx)
+	if !ok {
+		return &syncScope{userID: userID}, nil
+	}
+	if _, err = vaultMember(ctx, uow, vaultID, role); err != nil {
+		return nil, err
+	}
+	return &syncScope{userID: userID, vaultID: &vaultID}, nil
+}
+
+// Subscribe call fn on every push made by other clients of the user or by members of vaults
+// the user is in until context is done. ErrSubscriberLagged when fn can't keep up with pushes
+func (ss *SyncService) Subscribe(ctx context.Context, fn func(ctx context.Context, event ChangeEvent) error) error {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return err
+	}
+	events, cancel := ss.broker.Subscribe(userID, auth.Client(ctx))
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return ErrSubscriberLagged
+			}
+			if err = fn(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Poll read secrets changed since version at once, any member can pull secrets of the vault set to context
 func (ss *SyncService) Poll(ctx context.Context, since int32) ([]*domain.Secret, int32, error) {
-	user, err := auth.User(ctx)
+	sc, err := ss.scope(ctx, ss.uow, domain.ReaderRole)
 	if err != nil {
 		return nil, -1, err
 	}
-	rep := ss.uow.SecretRepository()
-	data, err := rep.GetAll(ctx, user, since)
+	// read the version first, every change up to it is in the response
+	version, err := ss.version(ctx, sc)
 	if err != nil {
 		return nil, -1, err
 	}
-	userID, err := auth.User(ctx)
+	rep := ss.uow.SecretRepository()
+	var data []*domain.Secret
+	if sc.vaultID != nil {
+		data, err = rep.GetVaultAll(ctx, *sc.vaultID, since)
+	} else {
+		data, err = rep.GetAll(ctx, sc.userID, since)
+	}
 	if err != nil {
 		return nil, -1, err
 	}
-	syncRepository := ss.uow.SyncStateRepository()
-	state, err := syncRepository.Get(ctx, syncTypeName, userID)
+	pull := &pullAudit{since: since}
+	pull.add(data)
+	ss.recordPull(ctx, sc, version, pull)
+	if err = ss.saveDeviceVersion(ctx, sc, version); err != nil {
+		return nil, -1, err
+	}
+	return data, version, nil
+}
+
+// PollPages read secrets changed since version page by page, ordered by version and id.
+// Pushes made meanwhile come with the next pull
+func (ss *SyncService) PollPages(
+	ctx context.Context,
+	since int32,
+	after domain.SecretCursor,
+	size int32,
+	fn func(ctx context.Context, page []*domain.Secret, version int32, last bool) error,
+) error {
+	sc, err := ss.scope(ctx, ss.uow, domain.ReaderRole)
 	if err != nil {
-		if !errors.Is(err, persistence.ErrResourceNotFound) {
-			return nil, -1, err
+		return err
+	}
+	version, err := ss.version(ctx, sc)
+	if err != nil {
+		return err
+	}
+	if size <= 0 || size > MaxPageSize {
+		size = DefaultPageSize
+	}
+	if after.Version < since {
+		after = domain.SecretCursor{Version: since}
+	}
+	// pages sent before the error are audited too
+	pull := &pullAudit{since: since}
+	defer ss.recordPull(ctx, sc, version, pull)
+	rep := ss.uow.SecretRepository()
+	for {
+		var page []*domain.Secret
+		if sc.vaultID != nil {
+			page, err = rep.GetVaultPage(ctx, *sc.vaultID, after, version, size)
+		} else {
+			page, err = rep.GetPage(ctx, sc.userID, after, version, size)
+		}
+		if err != nil {
+			return err
 		}
-		state = &domain.SyncState{
-			ID:    syncTypeName,
-			Value: 0,
+		last := int32(len(page)) < size
+		if err = fn(ctx, page, version, last); err != nil {
+			return err
+		}
+		pull.add(page)
+		if last {
+			return ss.saveDeviceVersion(ctx, sc, version)
 		}
+		tail := page[len(page)-1]
+		after = domain.SecretCursor{Version: tail.Version, ID: tail.ID}
 	}
-	return data, state.Value, nil
 }
 
-func (ss *SyncService) push(ctx context.Context, uow domain.UnitOfWork, state *domain.SyncState, p *Push) error {
+// recordPull record pulled secrets as one event
+func (ss *SyncService) recordPull(ctx context.Context, sc *syncScope, version int32, pull *pullAudit) {
+	reason := reasons(fmt.Sprintf("since %d, %d secrets", pull.since, pull.count), vaultReason(sc.vaultID))
+	recordAudit(ctx, ss.config.Audit, &domain.AuditEvent{
+		Type:    domain.AuditPulled,
+		Version: version,
+		Bytes:   pull.bytes,
+		Reason:  reason,
+	})
+}
+
+// saveDeviceVersion remember version of the scope the device pulled up to, tombstones are kept till
+// every device has pulled them
+func (ss *SyncService) saveDeviceVersion(ctx context.Context, sc *syncScope, version int32) error {
+	deviceID, ok := auth.Device(ctx)
+	if !ok {
+		return nil
+	}
+	var err error
+	if sc.vaultID != nil {
+		err = ss.uow.DeviceRepository().UpdateVaultSyncVersion(ctx, deviceID, *sc.vaultID, version)
+	} else {
+		err = ss.uow.DeviceRepository().UpdateSyncVersion(ctx, deviceID, version)
+	}
+	if err != nil && !errors.Is(err, persistence.ErrResourceNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (ss *SyncService) version(ctx context.Context, sc *syncScope) (int32, error) {
+	state, err := sc.state(ctx, ss.uow.SyncStateRepository())
+	if err != nil {
+		if !errors.Is(err, persistence.ErrResourceNotFound) {
+			return -1, err
+		}
+		return 0, nil
+	}
+	return state.Value, nil
+}
+
+func (ss *SyncService) push(
+	ctx context.Context,
+	uow domain.UnitOfWork,
+	sc *syncScope,
+	state *domain.SyncState,
+	quota *pushQuota,
+	p *Push,
+) (*domain.Secret, error) {
 	secret := p.Secret
 	dataRepository := uow.SecretRepository()
 	data, err := dataRepository.Get(ctx, secret.ID)
 	if err != nil && !errors.Is(err, persistence.ErrResourceNotFound) {
-		return err
+		return nil, err
 	}
 	if data != nil {
+		if err = sc.owns(data); err != nil {
+			return nil, err
+		}
+		if err = quota.charge(data, secret.Deleted, false); err != nil {
+			return nil, err
+		}
 		data.ModifiedAt = secret.ModifiedAt
 		data.Dek = secret.Dek
 		data.Payload = secret.Data
 		data.Version = state.Value
 		data.Deleted = secret.Deleted
-		return dataRepository.Update(ctx, data)
-	}
-	userID, err := auth.User(ctx)
-	if err != nil {
-		return err
+		return data, dataRepository.Update(ctx, data)
 	}
 	data = &domain.Secret{
 		ID:         secret.ID,
 		ModifiedAt: secret.ModifiedAt,
-		UserID:     userID,
+		UserID:     sc.userID,
+		VaultID:    sc.vaultID,
 		Type:       secret.Type,
 		BigData:    false,
 		Dek:        secret.Dek,
@@ -177,80 +580,239 @@ func (ss *SyncService) push(ctx context.Context, uow domain.UnitOfWork, state *d
 		Deleted:    secret.Deleted,
 		Version:    state.Value,
 	}
-	return dataRepository.Insert(ctx, data)
+	if !secret.Deleted {
+		if err = quota.add(); err != nil {
+			return nil, err
+		}
+	}
+	// nobody has the new secret yet
+	return nil, dataRepository.Insert(ctx, data)
 }
 
-func (ss *SyncService) startUploadFile(ctx context.Context, uow domain.UnitOfWork, state *domain.SyncState, p *Push) error {
+func (ss *SyncService) startUploadFile(
+	ctx context.Context,
+	uow domain.UnitOfWork,
+	sc *syncScope,
+	state *domain.SyncState,
+	quota *pushQuota,
+	p *Push,
+) (*domain.Secret, error) {
 	secret := p.Secret
 	secretRep := uow.SecretRepository()
 	data, err := secretRep.Get(ctx, secret.ID)
 	if err != nil && !errors.Is(err, persistence.ErrResourceNotFound) {
-		return err
+		return nil, err
 	}
 	if data != nil {
+		if err = sc.owns(data); err != nil {
+			return nil, err
+		}
+		if err = quota.charge(data, secret.Deleted, true); err != nil {
+			return nil, err
+		}
 		data.Deleted = secret.Deleted
-		if data.Deleted {
-			if err = ss.fp.Remove(data.ID.String(), data.Version); err != nil {
-				return err
-			}
-			data.ModifiedAt = secret.ModifiedAt
-			data.Version = state.Value
+		if !data.Deleted {
+			// recipients get the new file version when the upload ends
+			return nil, secretRep.Update(ctx, data)
 		}
-		return secretRep.Update(ctx, data)
-	}
-	userID, err := auth.User(ctx)
-	if err != nil {
-		return err
+		if err = ss.fp.Remove(data.ID.String(), data.Version); err != nil {
+			return nil, err
+		}
+		data.ModifiedAt = secret.ModifiedAt
+		data.Version = state.Value
+		data.Size = 0
+		return data, secretRep.Update(ctx, data)
 	}
 	data = &domain.Secret{
 		ID:         secret.ID,
 		ModifiedAt: secret.ModifiedAt,
-		UserID:     userID,
+		UserID:     sc.userID,
+		VaultID:    sc.vaultID,
 		Type:       secret.Type,
 		BigData:    true,
 	}
-	return secretRep.Insert(ctx, data)
+	if err = quota.add(); err != nil {
+		return nil, err
+	}
+	return nil, secretRep.Insert(ctx, data)
 }
 
-func (ss *SyncService) writeChunk(ctx context.Context, uow domain.UnitOfWork, state *domain.SyncState, p *Push) error {
+// writeChunk append chunk to the file, the chunk exceeding a limit isn't written
+func (ss *SyncService) writeChunk(
+	ctx context.Context,
+	uow domain.UnitOfWork,
+	sc *syncScope,
+	state *domain.SyncState,
+	quota *pushQuota,
+	files *uploads,
+	p *Push,
+) error {
 	secret := p.Secret
 	dataRepository := uow.SecretRepository()
 	data, err := dataRepository.Get(ctx, secret.ID)
 	if err != nil {
 		return err
 	}
-	f, err := ss.fp.OpenWrite(data.ID.String(), state.Value)
-	if err != nil {
+	if err = sc.owns(data); err != nil {
 		return err
 	}
-	defer func(f io.WriteCloser) {
-		_ = f.Close()
-	}(f)
-	_, err = f.Write(p.Buffer)
-	if err != nil {
+	if err = quota.write(data.ID, len(p.Buffer)); err != nil {
 		return err
 	}
-	return nil
+	return files.write(data.ID, state.Value, p.Buffer)
 }
 
-func (ss *SyncService) endFile(ctx context.Context, uow domain.UnitOfWork, state *domain.SyncState, p *Push) error {
+func (ss *SyncService) endFile(
+	ctx context.Context,
+	uow domain.UnitOfWork,
+	sc *syncScope,
+	state *domain.SyncState,
+	quota *pushQuota,
+	files *uploads,
+	p *Push,
+) (*domain.Secret, error) {
 	secret := p.Secret
 	dataRepository := uow.SecretRepository()
 	data, err := dataRepository.Get(ctx, secret.ID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err = sc.owns(data); err != nil {
+		return nil, err
+	}
+	if err = files.commit(data.ID); err != nil {
+		return nil, err
 	}
 	oldVersion := data.Version
 	data.Dek = secret.Dek
 	data.Payload = secret.Data
 	data.Version = state.Value
 	data.ModifiedAt = secret.ModifiedAt
+	data.Size = quota.size(data.ID)
 	if err = dataRepository.Update(ctx, data); err != nil {
-		return err
+		return nil, err
 	}
 	// удаляем старую версию
 	if err = ss.fp.Remove(data.ID.String(), oldVersion); err != nil && !os.IsNotExist(err) {
-		return err
+		return nil, err
 	}
-	return nil
+	return data, nil
+}
+
+// uploads files written by the push, each one stays open from its first chunk to the end of upload
+type uploads struct {
+	fp      domain.Filer
+	writers map[guid.Guid]domain.BlobWriter
+}
+
+func newUploads(fp domain.Filer) *uploads {
+	return &uploads{fp: fp, writers: make(map[guid.Guid]domain.BlobWriter)}
+}
+
+func (u *uploads) write(id guid.Guid, version int32, chunk []byte) error {
+	w, ok := u.writers[id]
+	if !ok {
+		var err error
+		if w, err = u.fp.OpenWrite(id.String(), version); err != nil {
+			return err
+		}
+		u.writers[id] = w
+	}
+	_, err := w.Write(chunk)
+	return err
+}
+
+// commit store the file, empty file has nothing to store
+func (u *uploads) commit(id guid.Guid) error {
+	w, ok := u.writers[id]
+	if !ok {
+		return nil
+	}
+	delete(u.writers, id)
+	return w.Close()
+}
+
+// abort drop files not committed
+func (u *uploads) abort() {
+	for id, w := range u.writers {
+		_ = w.Abort()
+		delete(u.writers, id)
+	}
+}
+
+// pushAudit one event per secret of the push with bytes received for it, in order of arrival
+type pushAudit struct {
+	events []*domain.AuditEvent
+	byID   map[guid.Guid]*domain.AuditEvent
+}
+
+func newPushAudit() *pushAudit {
+	return &pushAudit{byID: make(map[guid.Guid]*domain.AuditEvent)}
+}
+
+func (pa *pushAudit) add(p *Push) {
+	id := p.Secret.ID
+	event, ok := pa.byID[id]
+	if !ok {
+		event = &domain.AuditEvent{Type: domain.AuditPushed, SecretID: &id}
+		pa.byID[id] = event
+		pa.events = append(pa.events, event)
+	}
+	event.Bytes += int64(len(p.Secret.Dek) + len(p.Secret.Data) + len(p.Buffer))
+	if p.Secret.Deleted {
+		event.Reason = "deleted"
+	}
+}
+
+// pullAudit secrets sent by a pull
+type pullAudit struct {
+	since int32
+	count int
+	bytes int64
+}
+
+func (pa *pullAudit) add(secrets []*domain.Secret) {
+	for _, secret := range secrets {
+		pa.count++
+		pa.bytes += int64(len(secret.Dek) + len(secret.Payload))
+	}
+}
+
+// auditedReader count bytes read from the file, done is called once on close
+type auditedReader struct {
+	io.ReadCloser
+	n    int64
+	done func(n int64)
+}
+
+func (ar *auditedReader) Read(p []byte) (int, error) {
+	n, err := ar.ReadCloser.Read(p)
+	ar.n += int64(n)
+	return n, err
+}
+
+func (ar *auditedReader) Close() error {
+	if ar.done != nil {
+		ar.done(ar.n)
+		ar.done = nil
+	}
+	return ar.ReadCloser.Close()
+}
+
+func vaultReason(vaultID *guid.Guid) string {
+	if vaultID == nil {
+		return ""
+	}
+	return "vault " + vaultID.String()
+}
+
+// reasons join non-empty parts of the reason
+func reasons(parts ...string) string {
+	filled := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			filled = append(filled, part)
+		}
+	}
+	return strings.Join(filled, ", ")
 }
@@ -59,11 +59,11 @@ func TestSyncService_Push_DefaultShouldBeSuccess(t *testing.T) {
 	txUow.EXPECT().SecretRepository().Return(secretRepository)
 	mockFiler := mocks.NewMockFiler(ctrl)
 
-	syncService := NewSyncService(uow, mockFiler)
+	syncService := NewSyncService(uow, mockFiler, NewChangeBroker(), &SyncConfig{})
 	arr := make([]*Push, 1)
 	arr[0] = message
 	str := newMockStream(arr)
-	err := syncService.Push(ctx, str.Next)
+	err := syncService.Push(ctx, 0, str.Next)
 
 	assert.NoError(t, err)
 	assert.Equal(t, int32(1), state.Value)
@@ -127,6 +127,7 @@ func TestSyncService_Push_FileShouldBeSuccess(t *testing.T) {
 	secretRepository.EXPECT().Get(ctx, id).Return(nil, persistence.ErrResourceNotFound).Times(1)
 	secretRepository.EXPECT().Get(ctx, id).Return(&domain.Secret{
 		ID:         id,
+		UserID:     userID,
 		CreatedAt:  time.Now(),
 		ModifiedAt: msgs[0].Secret.ModifiedAt,
 	}, nil).Times(len(msgs) - 1)
@@ -134,17 +135,197 @@ func TestSyncService_Push_FileShouldBeSuccess(t *testing.T) {
 	secretRepository.EXPECT().Update(ctx, gomock.Any()).Return(nil)
 	txUow.EXPECT().SyncStateRepository().Return(syncRepository)
 	txUow.EXPECT().SecretRepository().Return(secretRepository).Times(len(msgs))
+	shareRepository := mocks.NewMockShareRepository(ctrl)
+	shareRepository.EXPECT().GetBySecret(ctx, id).Return(nil, nil)
+	txUow.EXPECT().ShareRepository().Return(shareRepository)
 	mockFiler := mocks.NewMockFiler(ctrl)
 	mockFiler.EXPECT().OpenWrite(id.String(), state.Value+1).Return(&mockWriterCloser{}, nil).Times(len(msgs) - 2)
 	mockFiler.EXPECT().Remove(id.String(), int32(0)).Return(fs.ErrNotExist)
-	syncService := NewSyncService(uow, mockFiler)
+	syncService := NewSyncService(uow, mockFiler, NewChangeBroker(), &SyncConfig{})
 
 	str := newMockStream(msgs)
 
-	err := syncService.Push(ctx, str.Next)
+	err := syncService.Push(ctx, 0, str.Next)
 	assert.NoError(t, err)
 }
 
+func TestSyncService_Push_ShouldRejectSecretOverQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	userID := *guid.New()
+	ctx = auth.SetUser(ctx, userID)
+	message := &Push{
+		Type: DefaultOperation,
+		Secret: &Secret{
+			ID:         *guid.New(),
+			ModifiedAt: time.Now(),
+			Type:       domain.TextType,
+			Data:       []byte("data"),
+			Dek:        []byte("dek"),
+		},
+	}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	syncRepository := mocks.NewMockSyncStateRepository(ctrl)
+	syncRepository.EXPECT().Get(ctx, syncTypeName, userID).Return(&domain.SyncState{ID: syncTypeName}, nil)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().Usage(ctx, userID).Return(&domain.SecretUsage{Secrets: 10}, nil)
+	secretRepository.EXPECT().Get(ctx, message.Secret.ID).Return(nil, persistence.ErrResourceNotFound)
+	txUow.EXPECT().SyncStateRepository().Return(syncRepository)
+	txUow.EXPECT().SecretRepository().Return(secretRepository).Times(2)
+	syncService := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{MaxSecrets: 10})
+
+	err := syncService.Push(ctx, 0, newMockStream([]*Push{message}).Next)
+
+	assert.ErrorIs(t, err, ErrSecretsQuota)
+}
+
+func TestSyncService_Push_ShouldAbortFileOverStorageQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	userID := *guid.New()
+	ctx = auth.SetUser(ctx, userID)
+	id := *guid.New()
+	msgs := []*Push{
+		{Type: BeginOperation, Secret: &Secret{ID: id, ModifiedAt: time.Now()}},
+		{Type: ChunkOperation, Secret: &Secret{ID: id}, Buffer: make([]byte, datatool.KB)},
+		{Type: ChunkOperation, Secret: &Secret{ID: id}, Buffer: make([]byte, datatool.KB)},
+	}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	syncRepository := mocks.NewMockSyncStateRepository(ctrl)
+	syncRepository.EXPECT().Get(ctx, syncTypeName, userID).Return(&domain.SyncState{ID: syncTypeName, Value: 4}, nil)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	secretRepository.EXPECT().Usage(ctx, userID).Return(&domain.SecretUsage{Secrets: 1, BlobBytes: 9 * datatool.KB}, nil)
+	secretRepository.EXPECT().Get(ctx, id).Return(nil, persistence.ErrResourceNotFound)
+	secretRepository.EXPECT().Insert(ctx, gomock.Any()).Return(nil)
+	secretRepository.EXPECT().Get(ctx, id).Return(&domain.Secret{ID: id, UserID: userID, BigData: true}, nil).Times(2)
+	txUow.EXPECT().SyncStateRepository().Return(syncRepository)
+	txUow.EXPECT().SecretRepository().Return(secretRepository).Times(4)
+	mockFiler := mocks.NewMockFiler(ctrl)
+	writer := mocks.NewMockBlobWriter(ctrl)
+	writer.EXPECT().Write(msgs[1].Buffer).Return(len(msgs[1].Buffer), nil)
+	writer.EXPECT().Abort().Return(nil)
+	mockFiler.EXPECT().OpenWrite(id.String(), int32(5)).Return(writer, nil)
+	syncService := NewSyncService(newMockUow(txUow), mockFiler, NewChangeBroker(), &SyncConfig{
+		MaxSecrets:   10,
+		MaxBlobBytes: 10 * datatool.KB,
+	})
+
+	err := syncService.Push(ctx, 4, newMockStream(msgs).Next)
+
+	assert.ErrorIs(t, err, ErrStorageQuota)
+}
+
+func TestSyncService_Push_ShouldRejectFileOverSizeLimitBeforeTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	userID := *guid.New()
+	ctx = auth.SetUser(ctx, userID)
+	id := *guid.New()
+	msgs := []*Push{
+		{Type: BeginOperation, Secret: &Secret{ID: id, ModifiedAt: time.Now()}},
+		{Type: ChunkOperation, Secret: &Secret{ID: id}, Buffer: make([]byte, datatool.KB)},
+		{Type: ChunkOperation, Secret: &Secret{ID: id}, Buffer: make([]byte, datatool.KB)},
+	}
+	// rejected before the transaction starts
+	syncService := NewSyncService(newMockUow(mocks.NewMockUnitOfWork(ctrl)), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{
+		MaxBlobSize: datatool.KB + 1,
+	})
+
+	err := syncService.Push(ctx, 4, newMockStream(msgs).Next)
+
+	assert.ErrorIs(t, err, ErrBlobTooLarge)
+}
+
+func TestSyncService_Push_ShouldRejectLargeMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	userID := *guid.New()
+	ctx = auth.SetUser(ctx, userID)
+	message := &Push{
+		Type:   ChunkOperation,
+		Secret: &Secret{ID: *guid.New()},
+		Buffer: make([]byte, datatool.MB),
+	}
+	syncService := NewSyncService(newMockUow(mocks.NewMockUnitOfWork(ctrl)), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{MaxMessageSize: datatool.KB})
+
+	err := syncService.Push(ctx, 0, newMockStream([]*Push{message}).Next)
+
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+}
+
+func TestSyncService_Push_ShouldRejectWhenStateMovedSinceBase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	userID := *guid.New()
+	ctx = auth.SetUser(ctx, userID)
+	message := &Push{
+		Type:   DefaultOperation,
+		Secret: &Secret{ID: *guid.New(), ModifiedAt: time.Now(), Data: []byte("data"), Dek: []byte("dek"), Version: 4},
+	}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	syncRepository := mocks.NewMockSyncStateRepository(ctrl)
+	// another client pushed after the records were bound to version 4
+	syncRepository.EXPECT().Get(ctx, syncTypeName, userID).Return(&domain.SyncState{ID: syncTypeName, Value: 4}, nil)
+	txUow.EXPECT().SyncStateRepository().Return(syncRepository)
+	syncService := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+
+	err := syncService.Push(ctx, 3, newMockStream([]*Push{message}).Next)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestSyncService_PollPages_ShouldReadUntilLastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	userID := *guid.New()
+	ctx = auth.SetUser(ctx, userID)
+	state := &domain.SyncState{
+		ID:    syncTypeName,
+		Value: 5,
+	}
+	first := []*domain.Secret{
+		{ID: *guid.New(), Version: 3},
+		{ID: *guid.New(), Version: 4},
+	}
+	second := []*domain.Secret{
+		{ID: *guid.New(), Version: 5},
+	}
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	syncRepository := mocks.NewMockSyncStateRepository(ctrl)
+	syncRepository.EXPECT().Get(ctx, syncTypeName, userID).Return(state, nil)
+	secretRepository := mocks.NewMockSecretRepository(ctrl)
+	gomock.InOrder(
+		secretRepository.EXPECT().
+			GetPage(ctx, userID, domain.SecretCursor{Version: 2}, state.Value, int32(2)).
+			Return(first, nil),
+		secretRepository.EXPECT().
+			GetPage(ctx, userID, domain.SecretCursor{Version: 4, ID: first[1].ID}, state.Value, int32(2)).
+			Return(second, nil),
+	)
+	uow.EXPECT().SyncStateRepository().Return(syncRepository)
+	uow.EXPECT().SecretRepository().Return(secretRepository)
+	syncService := NewSyncService(uow, mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+
+	var received []*domain.Secret
+	var lastSeen bool
+	err := syncService.PollPages(ctx, 2, domain.SecretCursor{}, 2, func(ctx context.Context, page []*domain.Secret, version int32, last bool) error {
+		assert.Equal(t, state.Value, version)
+		received = append(received, page...)
+		lastSeen = last
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, lastSeen)
+	assert.Equal(t, append(first, second...), received)
+}
+
 type mockUow struct {
 	tx domain.UnitOfWork
 }
@@ -161,6 +342,26 @@ func (m *mockUow) SyncStateRepository() domain.SyncStateRepository {
 	return m.tx.SyncStateRepository()
 }
 
+func (m *mockUow) DeviceRepository() domain.DeviceRepository {
+	return m.tx.DeviceRepository()
+}
+
+func (m *mockUow) SessionRepository() domain.SessionRepository {
+	return m.tx.SessionRepository()
+}
+
+func (m *mockUow) ShareRepository() domain.ShareRepository {
+	return m.tx.ShareRepository()
+}
+
+func (m *mockUow) VaultRepository() domain.VaultRepository {
+	return m.tx.VaultRepository()
+}
+
+func (m *mockUow) AuditRepository() domain.AuditRepository {
+	return m.tx.AuditRepository()
+}
+
 func (m *mockUow) Tx(ctx context.Context, fn func(ctx context.Context, work domain.UnitOfWork) error) error {
 	return fn(ctx, m.tx)
 }
@@ -195,3 +396,6 @@ func (m *mockWriterCloser) Write(p []byte) (n int, err error) {
 func (m *mockWriterCloser) Close() error {
 	return nil
 }
+func (m *mockWriterCloser) Abort() error {
+	return nil
+}
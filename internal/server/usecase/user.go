@@ -3,51 +3,317 @@ package usecase
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/DimKa163/keeper/internal/server/domain"
 	"github.com/DimKa163/keeper/internal/server/domain/auth"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/DimKa163/keeper/internal/srp"
+	"github.com/beevik/guid"
 )
 
 var (
 	ErrLoginAlreadyExists = errors.New("user already exists")
 	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials = errors.New("invalid login or password")
+	ErrAccountDisabled    = errors.New("account disabled by administrator")
+	ErrNoFakeVerifierKey  = errors.New("fake verifier key isn't configured")
+	ErrRecoveryRequired   = errors.New("wrapped master key is required")
+	ErrRecoveryNotSet     = errors.New("recovery isn't set")
 )
 
+// UserConfig account security settings
+type UserConfig struct {
+	// Logins lockout of a login after failed attempts
+	Logins *LoginLimiter
+	// Peers lockout of a client address after failed attempts to any login
+	Peers  *LoginLimiter
+	Policy *PasswordPolicy
+	Audit  domain.AuditLog
+	// LegacyLogin allow login with the password while accounts are migrated to SRP, it's off
+	// when migration is over
+	LegacyLogin bool
+	Handshakes  *HandshakeStore
+	// FakeVerifierKey secret salt and verifier of unknown logins are derived from. It must be the same
+	// on every replica and after restart, otherwise a changing salt gives unknown logins away
+	FakeVerifierKey []byte
+}
+
 type UserService struct {
 	unitOfWork  domain.UnitOfWork
 	authService auth.AuthService
-	authEngine  auth.Engine
+	sessions    *SessionService
+	fp          domain.Filer
+	*UserConfig
+	dummyOnce sync.Once
+	dummy     []byte
+	dummyErr  error
 }
 
-func NewUserService(unitOfWork domain.UnitOfWork, authService auth.AuthService, engine auth.Engine) *UserService {
-	return &UserService{unitOfWork: unitOfWork, authService: authService, authEngine: engine}
+func NewUserService(
+	unitOfWork domain.UnitOfWork,
+	authService auth.AuthService,
+	sessions *SessionService,
+	fp domain.Filer,
+	config *UserConfig,
+) *UserService {
+	return &UserService{unitOfWork: unitOfWork, authService: authService, sessions: sessions, fp: fp, UserConfig: config}
 }
 
-func (us *UserService) Login(ctx context.Context, login, password string) (string, error) {
+func (us *UserService) Login(
+	ctx context.Context,
+	login, password string,
+	proof *domain.DeviceProof,
+	upgrade *domain.Verifier,
+) (*domain.Tokens, error) {
+	user, _, err := us.authenticate(ctx, login, &domain.Credentials{Password: password}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if upgrade != nil && user.Verifier == nil {
+		if err = srp.CheckVerifier(upgrade.Salt, upgrade.Value); err != nil {
+			return nil, err
+		}
+		// the password is known, drop the hash
+		if err = us.unitOfWork.UserRepository().UpdateVerifier(ctx, user.ID, upgrade.Salt, upgrade.Value); err != nil {
+			return nil, err
+		}
+	}
+	return us.startSession(ctx, user, proof)
+}
+
+func (us *UserService) Register(ctx context.Context, login, password string) (*domain.Tokens, error) {
+	if !us.LegacyLogin {
+		return nil, ErrLegacyLoginDisabled
+	}
+	if err := us.Policy.Validate(login, password); err != nil {
+		return nil, err
+	}
+	return us.create(ctx, login, func() (*domain.User, error) {
+		pwd, salt, err := us.authService.GenerateHash([]byte(password))
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewUser(login, pwd, salt), nil
+	})
+}
+
+// RegisterVerifier server never sees the password, so only the password equal to the login is rejected.
+// Length is checked by the client, the blocklist of the policy isn't enforced unlike Register
+func (us *UserService) RegisterVerifier(ctx context.Context, login string, verifier *domain.Verifier) (*domain.Tokens, error) {
+	if err := srp.CheckVerifier(verifier.Salt, verifier.Value); err != nil {
+		return nil, err
+	}
+	if err := us.Policy.ValidateVerifier(login, verifier); err != nil {
+		return nil, err
+	}
+	return us.create(ctx, login, func() (*domain.User, error) {
+		return &domain.User{Login: login, Salt: verifier.Salt, Verifier: verifier.Value}, nil
+	})
+}
+
+// BeginLogin unknown login and account without verifier get a handshake with stable fake salt
+// that can't be finished, so accounts can't be enumerated
+func (us *UserService) BeginLogin(ctx context.Context, login string, publicKey []byte) (*domain.Handshake, error) {
+	if err := us.checkLockout(ctx, login, sh.Peer(ctx)); err != nil {
+		return nil, err
+	}
 	repository := us.unitOfWork.UserRepository()
 	exist, err := repository.Exist(ctx, login)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if !exist {
-		return "", ErrUserNotFound
+	var user *domain.User
+	if exist {
+		if user, err = repository.Get(ctx, login); err != nil {
+			return nil, err
+		}
+		if user.Verifier == nil {
+			// an account without verifier looks unknown until a password login upgrades it
+			user = nil
+		}
+	}
+	salt, verifier, err := us.fakeVerifier(login)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		salt, verifier = user.Salt, user.Verifier
+	}
+	server, err := srp.NewServer(verifier)
+	if err != nil {
+		return nil, err
+	}
+	id, err := us.Handshakes.put(&handshake{login: login, peer: sh.Peer(ctx), user: user, server: server, clientKey: publicKey})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Handshake{ID: id, Salt: salt, PublicKey: server.PublicKey()}, nil
+}
+
+func (us *UserService) FinishLogin(
+	ctx context.Context,
+	login string,
+	creds *domain.Credentials,
+	proof *domain.DeviceProof,
+) (*domain.Tokens, []byte, error) {
+	if creds.HandshakeID == "" {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrHandshakeNotFound)
+	}
+	user, serverProof, err := us.authenticate(ctx, login, creds, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, err := us.startSession(ctx, user, proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, serverProof, nil
+}
+
+// Authenticate check credentials, failures count to the lockout
+func (us *UserService) Authenticate(ctx context.Context, login string, creds *domain.Credentials) (*domain.User, error) {
+	user, _, err := us.authenticate(ctx, login, creds, nil)
+	return user, err
+}
+
+// authenticate check handshake proof or legacy password, server proof is returned for the handshake.
+// Known user is given when the account is already loaded, credentials must belong to it
+func (us *UserService) authenticate(
+	ctx context.Context,
+	login string,
+	creds *domain.Credentials,
+	known *domain.User,
+) (*domain.User, []byte, error) {
+	peer := sh.Peer(ctx)
+	if err := us.checkLockout(ctx, login, peer); err != nil {
+		return nil, nil, err
+	}
+	var user *domain.User
+	var serverProof []byte
+	var err error
+	switch {
+	case creds.HandshakeID != "":
+		user, serverProof, err = us.finishHandshake(login, creds)
+		if err == nil && known != nil && user.ID != known.ID {
+			err = fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrHandshakeNotFound)
+		}
+	case !us.LegacyLogin:
+		err = ErrLegacyLoginDisabled
+	default:
+		user, err = us.checkPassword(ctx, login, creds.Password, known)
 	}
-	user, err := repository.Get(ctx, login)
 	if err != nil {
-		return "", err
+		if errors.Is(err, ErrInvalidCredentials) {
+			us.Logins.Fail(login)
+			if peer != "" {
+				us.Peers.Fail(peer)
+			}
+			us.record(ctx, &domain.AuditEvent{Type: domain.AuditLoginFailed, Login: login, Reason: err.Error()})
+		}
+		return nil, nil, err
+	}
+	us.Logins.Reset(login)
+	if user.DisabledAt != nil {
+		us.record(ctx, &domain.AuditEvent{Type: domain.AuditLoginFailed, UserID: &user.ID, Login: login, Reason: ErrAccountDisabled.Error()})
+		return nil, nil, ErrAccountDisabled
+	}
+	return user, serverProof, nil
+}
+
+// finishHandshake check client proof, handshake is removed whatever the result
+func (us *UserService) finishHandshake(login string, creds *domain.Credentials) (*domain.User, []byte, error) {
+	h, ok := us.Handshakes.take(creds.HandshakeID)
+	if !ok || h.login != login {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrHandshakeNotFound)
+	}
+	serverProof, err := h.server.Verify(h.clientKey, creds.Proof)
+	if err != nil {
+		if errors.Is(err, srp.ErrInvalidProof) || errors.Is(err, srp.ErrInvalidPublicKey) {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
+		return nil, nil, err
+	}
+	if h.user == nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrUserNotFound)
+	}
+	return h.user, serverProof, nil
+}
+
+// checkPassword check password of legacy user, user is looked up by login unless given. Unknown login
+// costs the same hash computation as a wrong password, so accounts can't be enumerated by response time
+func (us *UserService) checkPassword(ctx context.Context, login, password string, user *domain.User) (*domain.User, error) {
+	if user == nil {
+		repository := us.unitOfWork.UserRepository()
+		exist, err := repository.Exist(ctx, login)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			dummy, err := us.dummyHash()
+			if err != nil {
+				return nil, err
+			}
+			_ = us.authService.Authenticate([]byte(password), dummy, dummy)
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, ErrUserNotFound)
+		}
+		if user, err = repository.Get(ctx, login); err != nil {
+			return nil, err
+		}
+	}
+	if err := us.authService.Authenticate([]byte(password), user.Password, user.Salt); err != nil {
+		if errors.Is(err, auth.ErrInvalidPassword) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
+		return nil, err
 	}
-	if err = us.authService.Authenticate([]byte(password), user.Password, user.Salt); err != nil {
-		return "", err
+	return user, nil
+}
+
+// fakeVerifier salt and verifier of unknown login, derived from the configured key so they are the same
+// on every attempt
+func (us *UserService) fakeVerifier(login string) ([]byte, []byte, error) {
+	if len(us.FakeVerifierKey) == 0 {
+		return nil, nil, ErrNoFakeVerifierKey
 	}
-	token, err := us.authEngine.GenerateToken(user.ID)
+	mac := hmac.New(sha256.New, us.FakeVerifierKey)
+	mac.Write([]byte(login))
+	sum := mac.Sum(nil)
+	return sum[:srp.SaltSize], sum, nil
+}
+
+// startSession bind session to the device when proof is given. Proof is required once the user has
+// registered devices, so every session of such user is bound to a device and ends when the device is revoked
+func (us *UserService) startSession(ctx context.Context, user *domain.User, proof *domain.DeviceProof) (*domain.Tokens, error) {
+	var deviceID *guid.Guid
+	repository := us.unitOfWork.DeviceRepository()
+	if proof == nil {
+		if err := requireDeviceProof(ctx, repository, user.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := verifyDeviceProof(ctx, repository, user, proof, time.Now()); err != nil {
+			return nil, err
+		}
+		deviceID = &proof.DeviceID
+	}
+	tokens, err := us.sessions.Start(ctx, us.unitOfWork, user.ID, deviceID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token, nil
+	us.record(ctx, &domain.AuditEvent{Type: domain.AuditLogin, UserID: &user.ID, Login: user.Login, DeviceID: deviceID})
+	return tokens, nil
 }
 
-func (us *UserService) Register(ctx context.Context, login, password string) (string, error) {
+// create insert user made by newUser with initial sync state and start a session,
+// user is made only when the login is free
+func (us *UserService) create(ctx context.Context, login string, newUser func() (*domain.User, error)) (*domain.Tokens, error) {
 	if err := us.unitOfWork.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
 		repository := work.UserRepository()
 		exist, err := repository.Exist(ctx, login)
@@ -57,11 +323,10 @@ func (us *UserService) Register(ctx context.Context, login, password string) (st
 		if exist {
 			return ErrLoginAlreadyExists
 		}
-		pwd, salt, err := us.authService.GenerateHash([]byte(password))
+		user, err := newUser()
 		if err != nil {
 			return err
 		}
-		user := domain.NewUser(login, pwd, salt)
 		if err = repository.Insert(ctx, user); err != nil {
 			return err
 		}
@@ -78,16 +343,255 @@ func (us *UserService) Register(ctx context.Context, login, password string) (st
 		}
 		return nil
 	}); err != nil {
-		return "", err
+		return nil, err
 	}
 	repository := us.unitOfWork.UserRepository()
 	user, err := repository.Get(ctx, login)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	token, err := us.authEngine.GenerateToken(user.ID)
+	tokens, err := us.sessions.Start(ctx, us.unitOfWork, user.ID, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token, nil
+	us.record(ctx, &domain.AuditEvent{Type: domain.AuditRegistered, UserID: &user.ID, Login: user.Login})
+	return tokens, nil
+}
+
+// dummyHash random value unknown logins are checked against
+func (us *UserService) dummyHash() ([]byte, error) {
+	us.dummyOnce.Do(func() {
+		us.dummy, us.dummyErr = us.authService.GenerateSalt()
+	})
+	return us.dummy, us.dummyErr
+}
+
+// ChangePassword new password is set as SRP verifier, legacy account is upgraded by the change
+func (us *UserService) ChangePassword(ctx context.Context, creds *domain.Credentials, verifier *domain.Verifier) error {
+	if err := srp.CheckVerifier(verifier.Salt, verifier.Value); err != nil {
+		return err
+	}
+	user, err := us.currentUser(ctx, creds)
+	if err != nil {
+		return err
+	}
+	if err = us.Policy.ValidateVerifier(user.Login, verifier); err != nil {
+		return err
+	}
+	current, _ := sh.Session(ctx)
+	if err = us.unitOfWork.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		if err := work.UserRepository().UpdateVerifier(ctx, user.ID, verifier.Salt, verifier.Value); err != nil {
+			return err
+		}
+		// other sessions may belong to whoever knew the old password
+		now := time.Now()
+		sessions, err := work.SessionRepository().GetActive(ctx, user.ID, now)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if session.ID == current {
+				continue
+			}
+			if err = work.SessionRepository().Revoke(ctx, session.ID, user.ID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	us.record(ctx, &domain.AuditEvent{Type: domain.AuditPasswordChanged, UserID: &user.ID, Login: user.Login})
+	return nil
+}
+
+func (us *UserService) ChangeLogin(ctx context.Context, creds *domain.Credentials, newLogin string) error {
+	user, err := us.currentUser(ctx, creds)
+	if err != nil {
+		return err
+	}
+	if err = us.unitOfWork.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		repository := work.UserRepository()
+		exist, err := repository.Exist(ctx, newLogin)
+		if err != nil {
+			return err
+		}
+		if exist {
+			return ErrLoginAlreadyExists
+		}
+		return repository.UpdateLogin(ctx, user.ID, newLogin)
+	}); err != nil {
+		return err
+	}
+	us.Logins.Reset(user.Login)
+	us.record(ctx, &domain.AuditEvent{
+		Type:   domain.AuditLoginChanged,
+		UserID: &user.ID,
+		Login:  newLogin,
+		Reason: user.Login,
+	})
+	return nil
+}
+
+// DeleteAccount remove account of the current user, the deletion is confirmed with credentials
+func (us *UserService) DeleteAccount(ctx context.Context, creds *domain.Credentials) error {
+	user, err := us.currentUser(ctx, creds)
+	if err != nil {
+		return err
+	}
+	if err = deleteAccount(ctx, us.unitOfWork, us.fp, user.ID); err != nil {
+		return err
+	}
+	us.Logins.Reset(user.Login)
+	us.record(ctx, &domain.AuditEvent{Type: domain.AuditAccountDeleted, UserID: &user.ID, Login: user.Login})
+	return nil
+}
+
+func (us *UserService) SetRecovery(ctx context.Context, recovery []byte) error {
+	if len(recovery) == 0 {
+		return ErrRecoveryRequired
+	}
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return err
+	}
+	if err = us.unitOfWork.UserRepository().UpdateRecovery(ctx, userID, recovery); err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (us *UserService) GetRecovery(ctx context.Context) ([]byte, error) {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recovery, err := us.unitOfWork.UserRepository().GetRecovery(ctx, userID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if len(recovery) == 0 {
+		return nil, ErrRecoveryNotSet
+	}
+	return recovery, nil
+}
+
+// deleteAccount remove user with own secrets. Vaults the user owns pass to another member, files are removed
+// after commit and the ones that fail to be removed are left to the garbage collector
+func deleteAccount(ctx context.Context, uow domain.UnitOfWork, fp domain.Filer, userID guid.Guid) error {
+	var removed []*domain.Secret
+	if err := uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		vaultSecrets, err := releaseVaults(ctx, work, userID)
+		if err != nil {
+			return err
+		}
+		secrets, err := work.SecretRepository().DeleteAll(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err = work.SyncStateRepository().DeleteAll(ctx, userID); err != nil {
+			return err
+		}
+		if err = work.UserRepository().Delete(ctx, userID); err != nil {
+			return err
+		}
+		removed = append(secrets, vaultSecrets...)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, secret := range removed {
+		if secret.BigData {
+			_ = fp.Remove(secret.ID.String(), secret.Version)
+		}
+	}
+	return nil
+}
+
+// releaseVaults give vaults the user owns to the member with the highest role, the earliest one among equals.
+// Vault with no other member is removed, its secrets are returned. Other memberships go with the user
+func releaseVaults(ctx context.Context, work domain.UnitOfWork, userID guid.Guid) ([]*domain.Secret, error) {
+	repository := work.VaultRepository()
+	memberships, err := repository.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	removed := make([]*domain.Secret, 0)
+	for _, membership := range memberships {
+		if membership.Role != domain.OwnerRole {
+			continue
+		}
+		members, err := repository.GetMembers(ctx, membership.VaultID)
+		if err != nil {
+			return nil, err
+		}
+		var heir *domain.VaultMember
+		for _, member := range members {
+			if member.UserID == userID {
+				continue
+			}
+			if heir == nil || member.Role > heir.Role || member.Role == heir.Role && member.CreatedAt.Before(heir.CreatedAt) {
+				heir = member
+			}
+		}
+		if heir != nil {
+			heir.Role = domain.OwnerRole
+			if err = repository.SaveMember(ctx, heir); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		secrets, err := work.SecretRepository().GetVaultAll(ctx, membership.VaultID, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err = repository.Delete(ctx, membership.VaultID); err != nil {
+			return nil, err
+		}
+		removed = append(removed, secrets...)
+	}
+	return removed, nil
+}
+
+// currentUser user of the request, account change is confirmed with credentials of the user
+func (us *UserService) currentUser(ctx context.Context, creds *domain.Credentials) (*domain.User, error) {
+	userID, err := sh.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := us.unitOfWork.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if _, _, err = us.authenticate(ctx, user.Login, creds, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkLockout ErrTooManyAttempts while login or peer is locked out
+func (us *UserService) checkLockout(ctx context.Context, login, peer string) error {
+	left := us.Logins.Check(login)
+	if peer != "" {
+		left = max(left, us.Peers.Check(peer))
+	}
+	if left <= 0 {
+		return nil
+	}
+	err := fmt.Errorf("%w, retry in %s", ErrTooManyAttempts, left.Round(time.Second))
+	us.record(ctx, &domain.AuditEvent{Type: domain.AuditLoginLocked, Login: login, Reason: err.Error()})
+	return err
+}
+
+func (us *UserService) record(ctx context.Context, event *domain.AuditEvent) {
+	recordAudit(ctx, us.Audit, event)
 }
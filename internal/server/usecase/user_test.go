@@ -2,13 +2,17 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/beevik/guid"
 
 	"github.com/DimKa163/keeper/internal/mocks"
 	"github.com/DimKa163/keeper/internal/server/domain"
 	"github.com/DimKa163/keeper/internal/server/domain/auth"
+	sh "github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/DimKa163/keeper/internal/srp"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +25,7 @@ func TestUserService_Register_Successfully(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository(ctrl)
 	mockAuthEngine := mocks.NewMockEngine(ctrl)
 	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
 	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
 
 	login := "dima"
@@ -34,16 +39,19 @@ func TestUserService_Register_Successfully(t *testing.T) {
 	}
 	token := "token"
 	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
 	mockRepo.EXPECT().Exist(ctx, login).Return(false, nil)
 	mockAuthService.EXPECT().GenerateHash([]byte(password)).Return(user.Password, user.Salt, nil)
 	mockRepo.EXPECT().Insert(ctx, &domain.User{Login: login, Password: user.Password, Salt: user.Salt}).Return(nil)
 	mockRepo.EXPECT().Get(ctx, login).Return(user, nil)
-	mockAuthEngine.EXPECT().GenerateToken(user.ID).Return(token, nil)
-	token, err := userService.Register(ctx, login, password)
+	mockSessions.EXPECT().Insert(ctx, gomock.Any()).Return(nil)
+	mockSessions.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return(token, nil)
+	tokens, err := userService.Register(ctx, login, password)
 
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
-	assert.Equal(t, token, token)
+	assert.Equal(t, token, tokens.Access)
+	assert.NotEmpty(t, tokens.Refresh)
 }
 
 func TestUserService_Register_FailToCreateUser(t *testing.T) {
@@ -54,6 +62,7 @@ func TestUserService_Register_FailToCreateUser(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository(ctrl)
 	mockAuthEngine := mocks.NewMockEngine(ctrl)
 	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
 	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
 
 	login := "dima"
@@ -67,16 +76,18 @@ func TestUserService_Register_FailToCreateUser(t *testing.T) {
 	}
 	token := "token"
 	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
 	mockRepo.EXPECT().Exist(ctx, login).Return(true, nil)
 	mockAuthService.EXPECT().GenerateHash([]byte(password)).Return(user.Password, user.Salt, nil).Times(0)
 	mockRepo.EXPECT().Insert(ctx, &domain.User{Login: login, Password: user.Password, Salt: user.Salt}).Return(nil).Times(0)
 	mockRepo.EXPECT().Get(ctx, login).Return(user, nil).Times(0)
-	mockAuthEngine.EXPECT().GenerateToken(user.ID).Return(token, nil).Times(0)
-	token, err := userService.Register(ctx, login, password)
+	mockSessions.EXPECT().Insert(ctx, gomock.Any()).Times(0)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return(token, nil).Times(0)
+	tokens, err := userService.Register(ctx, login, password)
 
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, ErrLoginAlreadyExists)
-	assert.Empty(t, token)
+	assert.Nil(t, tokens)
 }
 
 func TestUserService_Login_ShouldBeSuccess(t *testing.T) {
@@ -87,6 +98,7 @@ func TestUserService_Login_ShouldBeSuccess(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository(ctrl)
 	mockAuthEngine := mocks.NewMockEngine(ctrl)
 	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
 	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
 
 	login := "dima"
@@ -100,15 +112,46 @@ func TestUserService_Login_ShouldBeSuccess(t *testing.T) {
 	}
 	token := "token"
 	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	mockDevices.EXPECT().GetAll(ctx, user.ID).Return([]*domain.Device{}, nil)
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices)
 	mockRepo.EXPECT().Exist(ctx, login).Return(true, nil)
 	mockRepo.EXPECT().Get(ctx, login).Return(user, nil).Times(1)
 	mockAuthService.EXPECT().Authenticate([]byte(password), user.Password, user.Salt).Return(nil)
-	mockAuthEngine.EXPECT().GenerateToken(user.ID).Return(token, nil).Times(1)
+	mockSessions.EXPECT().Insert(ctx, gomock.Any()).Return(nil)
+	mockSessions.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return(token, nil).Times(1)
 
-	tkn, err := userService.Login(ctx, login, password)
+	tkn, err := userService.Login(ctx, login, password, nil, nil)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
-	assert.Equal(t, token, tkn)
+	assert.NotEmpty(t, tkn.Refresh)
+	assert.Equal(t, token, tkn.Access)
+}
+
+func TestUserService_Login_ShouldRequireDeviceProofOnceDevicesAreRegistered(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	userService := createUserService(mockUow, mockAuthService, mocks.NewMockEngine(ctrl))
+
+	user := &domain.User{ID: *guid.New(), Login: "dima", Password: []byte("hash"), Salt: []byte("salt")}
+	revokedAt := time.Now()
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices)
+	mockRepo.EXPECT().Exist(ctx, user.Login).Return(true, nil)
+	mockRepo.EXPECT().Get(ctx, user.Login).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	// a revoked device can't log in by leaving the signature out
+	mockDevices.EXPECT().GetAll(ctx, user.ID).Return([]*domain.Device{{ID: *guid.New(), UserID: user.ID, RevokedAt: &revokedAt}}, nil)
+
+	_, err := userService.Login(ctx, user.Login, "qwerty", nil, nil)
+
+	assert.ErrorIs(t, err, ErrDeviceProofRequired)
 }
 
 func TestUserService_Login_FailToLoginWithWrongPassword(t *testing.T) {
@@ -119,6 +162,7 @@ func TestUserService_Login_FailToLoginWithWrongPassword(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository(ctrl)
 	mockAuthEngine := mocks.NewMockEngine(ctrl)
 	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
 	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
 
 	login := "dima"
@@ -133,16 +177,17 @@ func TestUserService_Login_FailToLoginWithWrongPassword(t *testing.T) {
 	}
 	token := "token"
 	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
 	mockRepo.EXPECT().Exist(ctx, login).Return(true, nil)
 	mockRepo.EXPECT().Get(ctx, login).Return(user, nil).Times(1)
 	mockAuthService.EXPECT().Authenticate([]byte(wrongPassword), user.Password, user.Salt).Return(auth.ErrInvalidPassword)
-	mockAuthEngine.EXPECT().GenerateToken(user.ID).Return(token, nil).Times(0)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return(token, nil).Times(0)
 
-	tkn, err := userService.Login(ctx, login, wrongPassword)
+	tkn, err := userService.Login(ctx, login, wrongPassword, nil, nil)
 
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, auth.ErrInvalidPassword)
-	assert.Empty(t, tkn)
+	assert.Nil(t, tkn)
 }
 
 func TestUserService_Login_FailToLoginWithWrongLogin(t *testing.T) {
@@ -153,6 +198,7 @@ func TestUserService_Login_FailToLoginWithWrongLogin(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository(ctrl)
 	mockAuthEngine := mocks.NewMockEngine(ctrl)
 	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
 	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
 
 	wrongLogin := "dima1"
@@ -167,19 +213,448 @@ func TestUserService_Login_FailToLoginWithWrongLogin(t *testing.T) {
 	}
 	token := "token"
 	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	dummy := []byte("dummy")
 	mockRepo.EXPECT().Exist(ctx, wrongLogin).Return(false, nil)
 	mockRepo.EXPECT().Get(ctx, wrongLogin).Return(user, nil).Times(0)
-	mockAuthService.EXPECT().Authenticate([]byte(password), user.Password, user.Salt).Return(nil).Times(0)
-	mockAuthEngine.EXPECT().GenerateToken(user.ID).Return(token, nil).Times(0)
+	mockAuthService.EXPECT().GenerateSalt().Return(dummy, nil)
+	mockAuthService.EXPECT().Authenticate([]byte(password), dummy, dummy).Return(auth.ErrInvalidPassword)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return(token, nil).Times(0)
 
-	tkn, err := userService.Login(ctx, wrongLogin, password)
+	tkn, err := userService.Login(ctx, wrongLogin, password, nil, nil)
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
 	assert.ErrorIs(t, err, ErrUserNotFound)
-	assert.Empty(t, tkn)
+	assert.Nil(t, tkn)
+}
+
+func TestUserService_Login_ShouldLockOutAfterFailedAttempts(t *testing.T) {
+	ctx := sh.SetPeer(context.Background(), "10.0.0.1")
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	audit := &recordingAuditLog{}
+	userService := NewUserService(mockUow, mockAuthService, createSessionService(mockUow, mockAuthEngine), nil, &UserConfig{
+		Logins:      NewLoginLimiter(&LimiterConfig{MaxAttempts: 2, Lockout: time.Minute, MaxLockout: time.Hour}),
+		Peers:       NewLoginLimiter(&LimiterConfig{}),
+		Policy:      NewPasswordPolicy(0, nil),
+		Audit:       audit,
+		LegacyLogin: true,
+		Handshakes:  NewHandshakeStore(),
+	})
+
+	login := "dima"
+	user := &domain.User{ID: *guid.New(), Login: login, Password: []byte("qwerty"), Salt: []byte("salt")}
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().Exist(ctx, login).Return(true, nil).Times(2)
+	mockRepo.EXPECT().Get(ctx, login).Return(user, nil).Times(2)
+	mockAuthService.EXPECT().Authenticate(gomock.Any(), user.Password, user.Salt).Return(auth.ErrInvalidPassword).Times(2)
+
+	_, first := userService.Login(ctx, login, "wrong", nil, nil)
+	_, second := userService.Login(ctx, login, "wrong", nil, nil)
+	_, locked := userService.Login(ctx, login, "qwerty", nil, nil)
+
+	assert.ErrorIs(t, first, ErrInvalidCredentials)
+	assert.ErrorIs(t, second, ErrInvalidCredentials)
+	assert.ErrorIs(t, locked, ErrTooManyAttempts)
+	assert.Equal(t, []string{domain.AuditLoginFailed, domain.AuditLoginFailed, domain.AuditLoginLocked}, audit.types())
+	assert.Equal(t, "10.0.0.1", audit.events[0].Peer)
+}
+
+func TestUserService_Register_ShouldRejectWeakPassword(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	userService := NewUserService(mockUow, mockAuthService, createSessionService(mockUow, mockAuthEngine), nil, &UserConfig{
+		Logins:      NewLoginLimiter(&LimiterConfig{}),
+		Peers:       NewLoginLimiter(&LimiterConfig{}),
+		Policy:      NewPasswordPolicy(8, []string{"password1"}),
+		Audit:       &recordingAuditLog{},
+		LegacyLogin: true,
+		Handshakes:  NewHandshakeStore(),
+	})
+
+	_, short := userService.Register(ctx, "dima", "qwerty")
+	_, common := userService.Register(ctx, "dima", "Password1")
+	_, asLogin := userService.Register(ctx, "dima1234", "DIMA1234")
+
+	assert.ErrorIs(t, short, ErrWeakPassword)
+	assert.ErrorIs(t, common, ErrWeakPassword)
+	assert.ErrorIs(t, asLogin, ErrWeakPassword)
+}
+
+func TestUserService_RegisterVerifier_ShouldRejectPasswordEqualToLogin(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userService := createUserService(mocks.NewMockUnitOfWork(ctrl), mocks.NewMockAuthService(ctrl), mocks.NewMockEngine(ctrl))
+	salt, err := srp.NewSalt()
+	assert.NoError(t, err)
+
+	_, asLogin := userService.RegisterVerifier(ctx, "Dima1234", &domain.Verifier{Salt: salt, Value: srp.Verifier("Dima1234", salt)})
+
+	assert.ErrorIs(t, asLogin, ErrWeakPassword)
+}
+
+func TestPasswordPolicy_ValidateVerifier_ShouldLeaveOtherChecksToClient(t *testing.T) {
+	policy := NewPasswordPolicy(8, []string{"password1"})
+	salt, err := srp.NewSalt()
+	assert.NoError(t, err)
+
+	// the server can't see the password: other letter case, short and common passwords pass
+	otherCase := policy.ValidateVerifier("Dima1234", &domain.Verifier{Salt: salt, Value: srp.Verifier("dima1234", salt)})
+	short := policy.ValidateVerifier("dima", &domain.Verifier{Salt: salt, Value: srp.Verifier("qwerty", salt)})
+	blocked := policy.ValidateVerifier("dima", &domain.Verifier{Salt: salt, Value: srp.Verifier("password1", salt)})
+
+	assert.NoError(t, otherCase)
+	assert.NoError(t, short)
+	assert.NoError(t, blocked)
+}
+
+func TestUserService_ChangePassword_ShouldTerminateOtherSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	userService := createUserService(newMockUow(mockTx), mockAuthService, mocks.NewMockEngine(ctrl))
+
+	user := &domain.User{ID: *guid.New(), Login: "dima", Password: []byte("hash"), Salt: []byte("salt")}
+	current := &domain.Session{ID: *guid.New(), UserID: user.ID}
+	other := &domain.Session{ID: *guid.New(), UserID: user.ID}
+	ctx := sh.SetSession(sh.SetUser(context.Background(), user.ID), current.ID)
+	mockTx.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockTx.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	mockRepo.EXPECT().UpdateVerifier(ctx, user.ID, []byte("new-salt"), []byte("new-verifier")).Return(nil)
+	mockSessions.EXPECT().GetActive(ctx, user.ID, gomock.Any()).Return([]*domain.Session{current, other}, nil)
+	mockSessions.EXPECT().Revoke(ctx, other.ID, user.ID, gomock.Any()).Return(nil)
+
+	err := userService.ChangePassword(ctx, &domain.Credentials{Password: "qwerty"}, &domain.Verifier{
+		Salt:  []byte("new-salt"),
+		Value: []byte("new-verifier"),
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_DeleteAccount_ShouldRemoveSecretsAndFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockSecrets := mocks.NewMockSecretRepository(ctrl)
+	mockStates := mocks.NewMockSyncStateRepository(ctrl)
+	mockVaults := mocks.NewMockVaultRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockFiler := mocks.NewMockFiler(ctrl)
+	userService := NewUserService(newMockUow(mockTx), mockAuthService, nil, mockFiler, &UserConfig{
+		Logins:      NewLoginLimiter(&LimiterConfig{}),
+		Peers:       NewLoginLimiter(&LimiterConfig{}),
+		Policy:      NewPasswordPolicy(0, nil),
+		Audit:       &recordingAuditLog{},
+		LegacyLogin: true,
+		Handshakes:  NewHandshakeStore(),
+	})
+
+	user := &domain.User{ID: *guid.New(), Login: "dima", Password: []byte("hash"), Salt: []byte("salt")}
+	ctx := sh.SetUser(context.Background(), user.ID)
+	blob := &domain.Secret{ID: *guid.New(), UserID: user.ID, BigData: true, Version: 4}
+	text := &domain.Secret{ID: *guid.New(), UserID: user.ID, Version: 2}
+	mockTx.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockTx.EXPECT().SecretRepository().Return(mockSecrets).AnyTimes()
+	mockTx.EXPECT().SyncStateRepository().Return(mockStates).AnyTimes()
+	mockTx.EXPECT().VaultRepository().Return(mockVaults).AnyTimes()
+	mockRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	mockVaults.EXPECT().GetByUser(ctx, user.ID).Return([]*domain.VaultMember{}, nil)
+	mockSecrets.EXPECT().DeleteAll(ctx, user.ID).Return([]*domain.Secret{blob, text}, nil)
+	mockStates.EXPECT().DeleteAll(ctx, user.ID).Return(nil)
+	mockRepo.EXPECT().Delete(ctx, user.ID).Return(nil)
+	mockFiler.EXPECT().Remove(blob.ID.String(), blob.Version).Return(nil)
+
+	err := userService.DeleteAccount(ctx, &domain.Credentials{Password: "qwerty"})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_DeleteAccount_ShouldReleaseOwnedVaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockSecrets := mocks.NewMockSecretRepository(ctrl)
+	mockStates := mocks.NewMockSyncStateRepository(ctrl)
+	mockVaults := mocks.NewMockVaultRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockFiler := mocks.NewMockFiler(ctrl)
+	userService := createUserService(newMockUow(mockTx), mockAuthService, nil)
+	userService.fp = mockFiler
+
+	user := &domain.User{ID: *guid.New(), Login: "dima", Password: []byte("hash"), Salt: []byte("salt")}
+	ctx := sh.SetUser(context.Background(), user.ID)
+	shared, alone, joined := *guid.New(), *guid.New(), *guid.New()
+	now := time.Now()
+	writer := &domain.VaultMember{VaultID: shared, UserID: *guid.New(), Role: domain.WriterRole, CreatedAt: now.Add(-2 * time.Hour)}
+	firstAdmin := &domain.VaultMember{VaultID: shared, UserID: *guid.New(), Role: domain.AdminRole, CreatedAt: now.Add(-time.Hour)}
+	secondAdmin := &domain.VaultMember{VaultID: shared, UserID: *guid.New(), Role: domain.AdminRole, CreatedAt: now}
+	blob := &domain.Secret{ID: *guid.New(), VaultID: &alone, BigData: true, Version: 3}
+	mockTx.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockTx.EXPECT().SecretRepository().Return(mockSecrets).AnyTimes()
+	mockTx.EXPECT().SyncStateRepository().Return(mockStates).AnyTimes()
+	mockTx.EXPECT().VaultRepository().Return(mockVaults).AnyTimes()
+	mockRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	mockVaults.EXPECT().GetByUser(ctx, user.ID).Return([]*domain.VaultMember{
+		{VaultID: shared, UserID: user.ID, Role: domain.OwnerRole},
+		{VaultID: alone, UserID: user.ID, Role: domain.OwnerRole},
+		{VaultID: joined, UserID: user.ID, Role: domain.WriterRole},
+	}, nil)
+	mockVaults.EXPECT().GetMembers(ctx, shared).Return([]*domain.VaultMember{
+		{VaultID: shared, UserID: user.ID, Role: domain.OwnerRole}, writer, secondAdmin, firstAdmin,
+	}, nil)
+	mockVaults.EXPECT().SaveMember(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, member *domain.VaultMember) error {
+		assert.Equal(t, firstAdmin.UserID, member.UserID)
+		assert.Equal(t, domain.OwnerRole, member.Role)
+		return nil
+	})
+	mockVaults.EXPECT().GetMembers(ctx, alone).Return([]*domain.VaultMember{
+		{VaultID: alone, UserID: user.ID, Role: domain.OwnerRole},
+	}, nil)
+	mockSecrets.EXPECT().GetVaultAll(ctx, alone, int32(0)).Return([]*domain.Secret{blob}, nil)
+	mockVaults.EXPECT().Delete(ctx, alone).Return(nil)
+	mockSecrets.EXPECT().DeleteAll(ctx, user.ID).Return([]*domain.Secret{}, nil)
+	mockStates.EXPECT().DeleteAll(ctx, user.ID).Return(nil)
+	mockRepo.EXPECT().Delete(ctx, user.ID).Return(nil)
+	// the account is gone already, the file is left to the garbage collector
+	mockFiler.EXPECT().Remove(blob.ID.String(), blob.Version).Return(errors.New("storage is unavailable"))
+
+	err := userService.DeleteAccount(ctx, &domain.Credentials{Password: "qwerty"})
+
+	assert.NoError(t, err)
+}
+
+func TestUserService_DeleteAccount_ShouldRequirePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	userService := createUserService(newMockUow(mockTx), mockAuthService, mocks.NewMockEngine(ctrl))
+
+	user := &domain.User{ID: *guid.New(), Login: "dima", Password: []byte("hash"), Salt: []byte("salt")}
+	ctx := sh.SetUser(context.Background(), user.ID)
+	mockTx.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+	mockAuthService.EXPECT().Authenticate([]byte("wrong"), user.Password, user.Salt).Return(auth.ErrInvalidPassword)
+	mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+	err := userService.DeleteAccount(ctx, &domain.Credentials{Password: "wrong"})
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestUserService_Recovery_ShouldBeKeptForTheUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockTx := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	userService := createUserService(mockTx, mocks.NewMockAuthService(ctrl), mocks.NewMockEngine(ctrl))
+
+	userID := *guid.New()
+	ctx := sh.SetUser(context.Background(), userID)
+	mockTx.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	gomock.InOrder(
+		mockRepo.EXPECT().GetRecovery(ctx, userID).Return(nil, nil),
+		mockRepo.EXPECT().UpdateRecovery(ctx, userID, []byte("wrapped")).Return(nil),
+		mockRepo.EXPECT().GetRecovery(ctx, userID).Return([]byte("wrapped"), nil),
+	)
+
+	_, err := userService.GetRecovery(ctx)
+	assert.ErrorIs(t, err, ErrRecoveryNotSet)
+	assert.ErrorIs(t, userService.SetRecovery(ctx, nil), ErrRecoveryRequired)
+	assert.NoError(t, userService.SetRecovery(ctx, []byte("wrapped")))
+	recovery, err := userService.GetRecovery(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("wrapped"), recovery)
+}
+
+func TestUserService_FinishLogin_ShouldProveBothSides(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
+
+	salt := []byte("0123456789abcdef")
+	user := &domain.User{ID: *guid.New(), Login: "dima", Salt: salt, Verifier: srp.Verifier("qwerty", salt)}
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	mockDevices.EXPECT().GetAll(ctx, user.ID).Return([]*domain.Device{}, nil)
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices)
+	mockRepo.EXPECT().Exist(ctx, user.Login).Return(true, nil)
+	mockRepo.EXPECT().Get(ctx, user.Login).Return(user, nil)
+	mockSessions.EXPECT().Insert(ctx, gomock.Any()).Return(nil)
+	mockSessions.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return("token", nil)
+	client, err := srp.NewClient("qwerty")
+	assert.NoError(t, err)
+
+	handshake, err := userService.BeginLogin(ctx, user.Login, client.PublicKey())
+	assert.NoError(t, err)
+	proof, err := client.Proof(handshake.Salt, handshake.PublicKey)
+	assert.NoError(t, err)
+	creds := &domain.Credentials{HandshakeID: handshake.ID, Proof: proof}
+	tokens, serverProof, err := userService.FinishLogin(ctx, user.Login, creds, nil)
+	assert.NoError(t, err)
+	_, _, replayed := userService.FinishLogin(ctx, user.Login, creds, nil)
+
+	assert.Equal(t, "token", tokens.Access)
+	assert.NoError(t, client.VerifyServer(serverProof))
+	assert.ErrorIs(t, replayed, ErrInvalidCredentials)
+}
+
+func TestUserService_BeginLogin_UnknownLoginShouldLookLikeKnown(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	userService := createUserService(mockUow, mockAuthService, mocks.NewMockEngine(ctrl))
+
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().Exist(ctx, "ghost").Return(false, nil).Times(2)
+	client, err := srp.NewClient("qwerty")
+	assert.NoError(t, err)
+
+	first, err := userService.BeginLogin(ctx, "ghost", client.PublicKey())
+	assert.NoError(t, err)
+	second, err := userService.BeginLogin(ctx, "ghost", client.PublicKey())
+	assert.NoError(t, err)
+	proof, err := client.Proof(second.Salt, second.PublicKey)
+	assert.NoError(t, err)
+	_, _, err = userService.FinishLogin(ctx, "ghost", &domain.Credentials{HandshakeID: second.ID, Proof: proof}, nil)
+
+	assert.Equal(t, first.Salt, second.Salt)
+	assert.Len(t, first.Salt, srp.SaltSize)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestUserService_BeginLogin_FakeSaltShouldSurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().Exist(ctx, "ghost").Return(false, nil).Times(2)
+
+	// a restarted server or another replica is configured with the same key
+	first, err := createUserService(mockUow, nil, nil).BeginLogin(ctx, "ghost", []byte("A"))
+	assert.NoError(t, err)
+	second, err := createUserService(mockUow, nil, nil).BeginLogin(ctx, "ghost", []byte("A"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first.Salt, second.Salt)
+}
+
+func TestUserService_Login_ShouldUpgradeLegacyAccount(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthEngine := mocks.NewMockEngine(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockSessions := mocks.NewMockSessionRepository(ctrl)
+	userService := createUserService(mockUow, mockAuthService, mockAuthEngine)
+
+	user := &domain.User{ID: *guid.New(), Login: "dima", Password: []byte("hash"), Salt: []byte("salt")}
+	upgrade := &domain.Verifier{Salt: []byte("new-salt"), Value: []byte("verifier")}
+	mockUow.EXPECT().UserRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().SessionRepository().Return(mockSessions).AnyTimes()
+	mockDevices := mocks.NewMockDeviceRepository(ctrl)
+	mockDevices.EXPECT().GetAll(ctx, user.ID).Return([]*domain.Device{}, nil)
+	mockUow.EXPECT().DeviceRepository().Return(mockDevices)
+	mockRepo.EXPECT().Exist(ctx, user.Login).Return(true, nil).Times(2)
+	mockRepo.EXPECT().Get(ctx, user.Login).Return(user, nil).Times(2)
+	mockAuthService.EXPECT().Authenticate([]byte("qwerty"), user.Password, user.Salt).Return(nil)
+	mockRepo.EXPECT().UpdateVerifier(ctx, user.ID, upgrade.Salt, upgrade.Value).Return(nil)
+	mockSessions.EXPECT().Insert(ctx, gomock.Any()).Return(nil)
+	mockSessions.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+	mockAuthEngine.EXPECT().GenerateToken(user.ID, "", gomock.Any()).Return("token", nil)
+
+	handshake, beginErr := userService.BeginLogin(ctx, user.Login, []byte("A"))
+	fakeSalt, _, _ := userService.fakeVerifier(user.Login)
+	tokens, err := userService.Login(ctx, user.Login, "qwerty", nil, upgrade)
+
+	// an account without verifier must look like an unknown login
+	assert.NoError(t, beginErr)
+	assert.Equal(t, fakeSalt, handshake.Salt)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", tokens.Access)
+}
+
+func TestUserService_Login_ShouldBeDisabledWithoutLegacyLogin(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	userService := NewUserService(mockUow, mocks.NewMockAuthService(ctrl), nil, nil, &UserConfig{
+		Logins:     NewLoginLimiter(&LimiterConfig{}),
+		Peers:      NewLoginLimiter(&LimiterConfig{}),
+		Policy:     NewPasswordPolicy(0, nil),
+		Audit:      &recordingAuditLog{},
+		Handshakes: NewHandshakeStore(),
+	})
+
+	_, login := userService.Login(ctx, "dima", "qwerty", nil, nil)
+	_, register := userService.Register(ctx, "dima", "qwerty")
+
+	assert.ErrorIs(t, login, ErrLegacyLoginDisabled)
+	assert.ErrorIs(t, register, ErrLegacyLoginDisabled)
 }
 
 func createUserService(work domain.UnitOfWork, authService auth.AuthService, engine auth.Engine) *UserService {
-	userService := NewUserService(work, authService, engine)
+	userService := NewUserService(work, authService, createSessionService(work, engine), nil, &UserConfig{
+		Logins:          NewLoginLimiter(&LimiterConfig{}),
+		Peers:           NewLoginLimiter(&LimiterConfig{}),
+		Policy:          NewPasswordPolicy(0, nil),
+		Audit:           &recordingAuditLog{},
+		LegacyLogin:     true,
+		Handshakes:      NewHandshakeStore(),
+		FakeVerifierKey: []byte("fake-key"),
+	})
 	return userService
 }
+
+type recordingAuditLog struct {
+	events []*domain.AuditEvent
+}
+
+func (l *recordingAuditLog) Record(_ context.Context, event *domain.AuditEvent) {
+	l.events = append(l.events, event)
+}
+
+func (l *recordingAuditLog) types() []string {
+	types := make([]string, len(l.events))
+	for i, event := range l.events {
+		types[i] = event.Type
+	}
+	return types
+}
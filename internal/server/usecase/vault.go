@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+)
+
+var (
+	ErrVaultNotFound      = errors.New("vault not found")
+	ErrVaultRoleTooLow    = errors.New("vault role doesn't allow it")
+	ErrVaultOwnerRole     = errors.New("vault has the only owner, its role can't be given or changed")
+	ErrVaultOwnerLeave    = errors.New("owner can't leave the vault")
+	ErrVaultNameRequired  = errors.New("vault name is required")
+	ErrVaultKeyRequired   = errors.New("vault key sealed to the member is required")
+	ErrAlreadyVaultMember = errors.New("user is a member of the vault already")
+)
+
+// VaultService vaults shared by a team. Server never sees the vault key, members seal it
+// to public key of each other on the client side
+type VaultService struct {
+	uow domain.UnitOfWork
+}
+
+func NewVaultService(uow domain.UnitOfWork) *VaultService {
+	return &VaultService{uow: uow}
+}
+
+// Create create vault with own sync state, the user becomes its owner
+func (vs *VaultService) Create(ctx context.Context, id guid.Guid, name string, vaultKey []byte) (*domain.VaultMember, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrVaultNameRequired
+	}
+	if len(vaultKey) == 0 {
+		return nil, ErrVaultKeyRequired
+	}
+	var member *domain.VaultMember
+	if err = vs.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		repository := work.VaultRepository()
+		if err := repository.Insert(ctx, &domain.Vault{ID: id, Name: name, CreatedBy: userID}); err != nil {
+			return err
+		}
+		if err := repository.SaveMember(ctx, &domain.VaultMember{
+			VaultID:  id,
+			UserID:   userID,
+			Role:     domain.OwnerRole,
+			VaultKey: vaultKey,
+		}); err != nil {
+			return err
+		}
+		if err := work.SyncStateRepository().Insert(ctx, &domain.SyncState{VaultID: &id}); err != nil {
+			return err
+		}
+		member, err = repository.GetMember(ctx, id, userID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// Invite add the user to the vault or change role of a member. Admin can't give a role above their own
+// nor change role of another admin, the owner role is never given
+func (vs *VaultService) Invite(
+	ctx context.Context,
+	vaultID guid.Guid,
+	login string,
+	role domain.VaultRole,
+	vaultKey []byte,
+) (*domain.VaultMember, error) {
+	if role == domain.OwnerRole {
+		return nil, ErrVaultOwnerRole
+	}
+	if role < domain.ReaderRole || role > domain.OwnerRole {
+		return nil, domain.ErrUnknownVaultRole
+	}
+	if len(vaultKey) == 0 {
+		return nil, ErrVaultKeyRequired
+	}
+	var member *domain.VaultMember
+	if err := vs.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		inviter, err := vaultMember(ctx, work, vaultID, domain.AdminRole)
+		if err != nil {
+			return err
+		}
+		if role > inviter.Role {
+			return ErrVaultRoleTooLow
+		}
+		user, err := recipient(ctx, work, login)
+		if err != nil {
+			return err
+		}
+		if user.ID == inviter.UserID {
+			return ErrAlreadyVaultMember
+		}
+		if len(user.PublicKey) == 0 {
+			return ErrNoPublicKey
+		}
+		repository := work.VaultRepository()
+		existing, err := repository.GetMember(ctx, vaultID, user.ID)
+		if err != nil && !errors.Is(err, persistence.ErrResourceNotFound) {
+			return err
+		}
+		if existing != nil {
+			if existing.Role == domain.OwnerRole {
+				return ErrVaultOwnerRole
+			}
+			if existing.Role >= inviter.Role && inviter.Role != domain.OwnerRole {
+				return ErrVaultRoleTooLow
+			}
+		}
+		if err = repository.SaveMember(ctx, &domain.VaultMember{
+			VaultID:  vaultID,
+			UserID:   user.ID,
+			Role:     role,
+			VaultKey: vaultKey,
+		}); err != nil {
+			return err
+		}
+		member, err = repository.GetMember(ctx, vaultID, user.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// Members members of the vault, any member can see them
+func (vs *VaultService) Members(ctx context.Context, vaultID guid.Guid) ([]*domain.VaultMember, error) {
+	if _, err := vaultMember(ctx, vs.uow, vaultID, domain.ReaderRole); err != nil {
+		return nil, err
+	}
+	return vs.uow.VaultRepository().GetMembers(ctx, vaultID)
+}
+
+// Leave stop being a member of the vault. Whatever the member has seen stays known to them,
+// secrets of the vault should be changed after
+func (vs *VaultService) Leave(ctx context.Context, vaultID guid.Guid) error {
+	return vs.uow.Tx(ctx, func(ctx context.Context, work domain.UnitOfWork) error {
+		member, err := vaultMember(ctx, work, vaultID, domain.ReaderRole)
+		if err != nil {
+			return err
+		}
+		if member.Role == domain.OwnerRole {
+			return ErrVaultOwnerLeave
+		}
+		return work.VaultRepository().DeleteMember(ctx, vaultID, member.UserID)
+	})
+}
+
+// List vaults the user is a member of, with vault key sealed to the user
+func (vs *VaultService) List(ctx context.Context) ([]*domain.VaultMember, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vs.uow.VaultRepository().GetByUser(ctx, userID)
+}
+
+// vaultMember membership of the current user with at least the role. Vault the user isn't a member of
+// is not found, so its existence isn't revealed
+func vaultMember(ctx context.Context, work domain.UnitOfWork, vaultID guid.Guid, role domain.VaultRole) (*domain.VaultMember, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	member, err := work.VaultRepository().GetMember(ctx, vaultID, userID)
+	if err != nil {
+		if errors.Is(err, persistence.ErrResourceNotFound) {
+			return nil, ErrVaultNotFound
+		}
+		return nil, err
+	}
+	if member.Role < role {
+		return nil, ErrVaultRoleTooLow
+	}
+	return member, nil
+}
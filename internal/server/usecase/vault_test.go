@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DimKa163/keeper/internal/mocks"
+	"github.com/DimKa163/keeper/internal/server/domain"
+	"github.com/DimKa163/keeper/internal/server/infrastructure/persistence"
+	"github.com/DimKa163/keeper/internal/server/shared/auth"
+	"github.com/beevik/guid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncService_Push_WriterShouldPushToVaultState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	writerID := *guid.New()
+	otherID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetVault(auth.SetClient(auth.SetUser(context.Background(), writerID), "laptop"), vaultID)
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	txUow.EXPECT().SecretRepository().Return(secrets).AnyTimes()
+	txUow.EXPECT().SyncStateRepository().Return(states).AnyTimes()
+	txUow.EXPECT().VaultRepository().Return(vaults).AnyTimes()
+	vaults.EXPECT().GetMember(ctx, vaultID, writerID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: writerID, Role: domain.WriterRole}, nil)
+	states.EXPECT().GetVault(ctx, vaultID).Return(&domain.SyncState{VaultID: &vaultID, Value: 4}, nil)
+	id := *guid.New()
+	secrets.EXPECT().Get(ctx, id).Return(nil, nil)
+	secrets.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, secret *domain.Secret) error {
+		assert.Equal(t, writerID, secret.UserID)
+		assert.Equal(t, &vaultID, secret.VaultID)
+		assert.Equal(t, int32(5), secret.Version)
+		return nil
+	})
+	vaults.EXPECT().GetMembers(ctx, vaultID).Return([]*domain.VaultMember{
+		{VaultID: vaultID, UserID: writerID, Role: domain.WriterRole},
+		{VaultID: vaultID, UserID: otherID, Role: domain.ReaderRole},
+	}, nil)
+	states.EXPECT().Update(ctx, &domain.SyncState{VaultID: &vaultID, Value: 5}).Return(nil)
+	broker := NewChangeBroker()
+	events, cancel := broker.Subscribe(otherID, "phone")
+	defer cancel()
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), broker, &SyncConfig{})
+	stream := newMockStream([]*Push{{Type: DefaultOperation, Secret: &Secret{ID: id, Data: []byte("data")}}})
+
+	err := sut.Push(ctx, 4, stream.Next)
+
+	assert.NoError(t, err)
+	select {
+	case event := <-events:
+		assert.Equal(t, int32(5), event.Version)
+		assert.Equal(t, &vaultID, event.VaultID)
+	case <-time.After(time.Second):
+		t.Fatal("member wasn't notified")
+	}
+}
+
+func TestSyncService_Push_WriterShouldBeChargedForSecretOfAnotherMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	writerID := *guid.New()
+	otherID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetVault(auth.SetUser(context.Background(), writerID), vaultID)
+	stored := &domain.Secret{ID: *guid.New(), UserID: otherID, ChargedTo: otherID, VaultID: &vaultID, Version: 2}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	txUow.EXPECT().SecretRepository().Return(secrets).AnyTimes()
+	txUow.EXPECT().SyncStateRepository().Return(states)
+	txUow.EXPECT().VaultRepository().Return(vaults)
+	vaults.EXPECT().GetMember(ctx, vaultID, writerID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: writerID, Role: domain.WriterRole}, nil)
+	states.EXPECT().GetVault(ctx, vaultID).Return(&domain.SyncState{VaultID: &vaultID, Value: 2}, nil)
+	// the writer is at the limit already
+	secrets.EXPECT().Usage(ctx, writerID).Return(&domain.SecretUsage{Secrets: 1}, nil)
+	secrets.EXPECT().Get(ctx, stored.ID).Return(stored, nil)
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{MaxSecrets: 1})
+	stream := newMockStream([]*Push{{Type: DefaultOperation, Secret: &Secret{ID: stored.ID, Data: []byte("data")}}})
+
+	err := sut.Push(ctx, 2, stream.Next)
+
+	assert.ErrorIs(t, err, ErrSecretsQuota)
+}
+
+func TestSyncService_Push_SecretOfAnotherMemberShouldKeepOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	writerID := *guid.New()
+	otherID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetVault(auth.SetUser(context.Background(), writerID), vaultID)
+	stored := &domain.Secret{ID: *guid.New(), UserID: otherID, ChargedTo: otherID, VaultID: &vaultID, Version: 2}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	txUow.EXPECT().SecretRepository().Return(secrets).AnyTimes()
+	txUow.EXPECT().SyncStateRepository().Return(states).AnyTimes()
+	txUow.EXPECT().VaultRepository().Return(vaults).AnyTimes()
+	vaults.EXPECT().GetMember(ctx, vaultID, writerID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: writerID, Role: domain.WriterRole}, nil)
+	states.EXPECT().GetVault(ctx, vaultID).Return(&domain.SyncState{VaultID: &vaultID, Value: 2}, nil)
+	secrets.EXPECT().Usage(ctx, writerID).Return(&domain.SecretUsage{}, nil)
+	secrets.EXPECT().Get(ctx, stored.ID).Return(stored, nil)
+	secrets.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, secret *domain.Secret) error {
+		assert.Equal(t, otherID, secret.UserID)
+		assert.Equal(t, writerID, secret.ChargedTo)
+		return nil
+	})
+	vaults.EXPECT().GetMembers(ctx, vaultID).Return([]*domain.VaultMember{}, nil)
+	states.EXPECT().Update(ctx, &domain.SyncState{VaultID: &vaultID, Value: 3}).Return(nil)
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{MaxSecrets: 1})
+	stream := newMockStream([]*Push{{Type: DefaultOperation, Secret: &Secret{ID: stored.ID, Data: []byte("data")}}})
+
+	err := sut.Push(ctx, 2, stream.Next)
+
+	assert.NoError(t, err)
+}
+
+func TestSyncService_Push_ReaderShouldNotPushToVault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	readerID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetVault(auth.SetUser(context.Background(), readerID), vaultID)
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	txUow.EXPECT().VaultRepository().Return(vaults)
+	vaults.EXPECT().GetMember(ctx, vaultID, readerID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: readerID, Role: domain.ReaderRole}, nil)
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+
+	err := sut.Push(ctx, 0, newMockStream(nil).Next)
+
+	assert.ErrorIs(t, err, ErrVaultRoleTooLow)
+}
+
+func TestSyncService_Push_OwnSecretShouldNotGoToVault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	userID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetVault(auth.SetUser(context.Background(), userID), vaultID)
+	own := &domain.Secret{ID: *guid.New(), UserID: userID, Version: 2}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	txUow.EXPECT().SecretRepository().Return(secrets)
+	txUow.EXPECT().SyncStateRepository().Return(states)
+	txUow.EXPECT().VaultRepository().Return(vaults)
+	vaults.EXPECT().GetMember(ctx, vaultID, userID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: userID, Role: domain.OwnerRole}, nil)
+	states.EXPECT().GetVault(ctx, vaultID).Return(&domain.SyncState{VaultID: &vaultID, Value: 1}, nil)
+	secrets.EXPECT().Get(ctx, own.ID).Return(own, nil)
+	sut := NewSyncService(newMockUow(txUow), mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+	stream := newMockStream([]*Push{{Type: DefaultOperation, Secret: &Secret{ID: own.ID}}})
+
+	err := sut.Push(ctx, 1, stream.Next)
+
+	assert.ErrorIs(t, err, ErrSecretNotOwned)
+}
+
+func TestSyncService_PollPages_StrangerShouldNotPullVault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	strangerID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetVault(auth.SetUser(context.Background(), strangerID), vaultID)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	uow.EXPECT().VaultRepository().Return(vaults)
+	vaults.EXPECT().GetMember(ctx, vaultID, strangerID).Return(nil, persistence.ErrResourceNotFound)
+	sut := NewSyncService(uow, mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+
+	err := sut.PollPages(ctx, 0, domain.SecretCursor{}, 10, func(context.Context, []*domain.Secret, int32, bool) error {
+		t.Fatal("stranger got a page")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrVaultNotFound)
+}
+
+func TestSyncService_PollPages_ShouldRememberVaultVersionOfDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	readerID := *guid.New()
+	vaultID := *guid.New()
+	deviceID := *guid.New()
+	ctx := auth.SetDevice(auth.SetVault(auth.SetUser(context.Background(), readerID), vaultID), deviceID)
+	uow := mocks.NewMockUnitOfWork(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	states := mocks.NewMockSyncStateRepository(ctrl)
+	secrets := mocks.NewMockSecretRepository(ctrl)
+	devices := mocks.NewMockDeviceRepository(ctrl)
+	uow.EXPECT().VaultRepository().Return(vaults)
+	uow.EXPECT().SyncStateRepository().Return(states)
+	uow.EXPECT().SecretRepository().Return(secrets)
+	uow.EXPECT().DeviceRepository().Return(devices)
+	vaults.EXPECT().GetMember(ctx, vaultID, readerID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: readerID, Role: domain.ReaderRole}, nil)
+	states.EXPECT().GetVault(ctx, vaultID).Return(&domain.SyncState{VaultID: &vaultID, Value: 6}, nil)
+	secrets.EXPECT().GetVaultPage(ctx, vaultID, domain.SecretCursor{}, int32(6), int32(10)).Return(nil, nil)
+	// the vault version doesn't touch the device's own version
+	devices.EXPECT().UpdateVaultSyncVersion(ctx, deviceID, vaultID, int32(6)).Return(nil)
+	sut := NewSyncService(uow, mocks.NewMockFiler(ctrl), NewChangeBroker(), &SyncConfig{})
+
+	err := sut.PollPages(ctx, 0, domain.SecretCursor{}, 10, func(context.Context, []*domain.Secret, int32, bool) error {
+		return nil
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestVaultService_Invite_AdminShouldNotChangeAnotherAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	adminID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetUser(context.Background(), adminID)
+	other := &domain.User{ID: *guid.New(), Login: "olga", PublicKey: make([]byte, 32)}
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	txUow.EXPECT().VaultRepository().Return(vaults).AnyTimes()
+	txUow.EXPECT().UserRepository().Return(users).AnyTimes()
+	vaults.EXPECT().GetMember(ctx, vaultID, adminID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: adminID, Role: domain.AdminRole}, nil)
+	users.EXPECT().Exist(ctx, other.Login).Return(true, nil)
+	users.EXPECT().Get(ctx, other.Login).Return(other, nil)
+	vaults.EXPECT().GetMember(ctx, vaultID, other.ID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: other.ID, Role: domain.AdminRole}, nil)
+	sut := NewVaultService(newMockUow(txUow))
+
+	_, err := sut.Invite(ctx, vaultID, other.Login, domain.ReaderRole, []byte("sealed"))
+
+	assert.ErrorIs(t, err, ErrVaultRoleTooLow)
+}
+
+func TestVaultService_Leave_OwnerShouldNotLeave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ownerID := *guid.New()
+	vaultID := *guid.New()
+	ctx := auth.SetUser(context.Background(), ownerID)
+	txUow := mocks.NewMockUnitOfWork(ctrl)
+	vaults := mocks.NewMockVaultRepository(ctrl)
+	txUow.EXPECT().VaultRepository().Return(vaults)
+	vaults.EXPECT().GetMember(ctx, vaultID, ownerID).
+		Return(&domain.VaultMember{VaultID: vaultID, UserID: ownerID, Role: domain.OwnerRole}, nil)
+	sut := NewVaultService(newMockUow(txUow))
+
+	err := sut.Leave(ctx, vaultID)
+
+	assert.ErrorIs(t, err, ErrVaultOwnerLeave)
+}
@@ -0,0 +1,200 @@
+// Package srp SRP-6a password authenticated key exchange. Server keeps only a verifier
+// of the password and never sees the password itself, even on registration
+package srp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid SRP public key")
+	ErrInvalidProof     = errors.New("invalid SRP proof")
+	ErrInvalidVerifier  = errors.New("invalid SRP verifier")
+)
+
+// 2048-bit group of RFC 5054
+const groupN = "AC6BDB41324A9A9BF166DE5E1389582FAF72B6651987EE07FC3192943DB56050A37329CBB4A099ED" +
+	"8193E0757767A13DD52312AB4B03310DCD7F48A9DA04FD50E8083969EDB767B0CF6095179A163AB3661A05FBD5FAAAE8" +
+	"2918A9962F0B93B855F97993EC975EEAA80D740ADBF4FF747359D041D5C33EA71D281E446B14773BCA97B43A23FB8016" +
+	"76BD207A436C6481F1D2B9078717461A5B9D32E688F87748544523B524B0D57D5EA77A2775D2ECFA032CFBDBF52FB378" +
+	"6160279004E57AE6AF874E7303CE53299CCC041C7BC308D82A5698F3A8D0C38271AE35F8E9DBFBB694B5C803D89F7AE4" +
+	"35DE236D525F54759B65E372FCD68EF20FA7111F9E4AFF73"
+
+const (
+	// SaltSize size of the salt generated for a new verifier
+	SaltSize = 16
+	// ephemeralSize size of secret ephemeral values a and b
+	ephemeralSize = 32
+
+	// password is stretched with argon2id before it goes to x, so stolen verifier is expensive to brute force
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	argonKeyLen  = 32
+)
+
+var (
+	n, _ = new(big.Int).SetString(groupN, 16)
+	g    = big.NewInt(2)
+	k    = hashInt(pad(n), pad(g))
+)
+
+// NewSalt random salt of a new verifier
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Verifier v = g^x, login isn't part of x so login can be changed without a new verifier
+func Verifier(password string, salt []byte) []byte {
+	return pad(new(big.Int).Exp(g, privateKey(password, salt), n))
+}
+
+// CheckVerifier verifier made by the client must be an element of the group
+func CheckVerifier(salt, verifier []byte) error {
+	if len(salt) == 0 || !valid(new(big.Int).SetBytes(verifier)) {
+		return ErrInvalidVerifier
+	}
+	return nil
+}
+
+// Client side of the exchange, used once
+type Client struct {
+	password string
+	a        *big.Int
+	A        *big.Int
+	m1       []byte
+	key      []byte
+}
+
+func NewClient(password string) (*Client, error) {
+	a, err := randomInt()
+	if err != nil {
+		return nil, err
+	}
+	return &Client{password: password, a: a, A: new(big.Int).Exp(g, a, n)}, nil
+}
+
+// PublicKey A sent to the server
+func (c *Client) PublicKey() []byte {
+	return pad(c.A)
+}
+
+// Proof M1 of password knowledge for salt and server public key B
+func (c *Client) Proof(salt, serverKey []byte) ([]byte, error) {
+	B := new(big.Int).SetBytes(serverKey)
+	if !valid(B) {
+		return nil, ErrInvalidPublicKey
+	}
+	u := hashInt(pad(c.A), pad(B))
+	if u.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	x := privateKey(c.password, salt)
+	// S = (B - k*g^x) ^ (a + u*x)
+	base := new(big.Int).Sub(B, new(big.Int).Mul(k, new(big.Int).Exp(g, x, n)))
+	base.Mod(base, n)
+	exp := new(big.Int).Add(c.a, new(big.Int).Mul(u, x))
+	S := new(big.Int).Exp(base, exp, n)
+	c.key = hash(pad(S))
+	c.m1 = hash(pad(c.A), pad(B), c.key)
+	return c.m1, nil
+}
+
+// VerifyServer check server proof M2, server that doesn't know the verifier can't make it
+func (c *Client) VerifyServer(m2 []byte) error {
+	if c.m1 == nil || subtle.ConstantTimeCompare(m2, hash(pad(c.A), c.m1, c.key)) != 1 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Server side of the exchange, used once
+type Server struct {
+	v *big.Int
+	b *big.Int
+	B *big.Int
+}
+
+func NewServer(verifier []byte) (*Server, error) {
+	b, err := randomInt()
+	if err != nil {
+		return nil, err
+	}
+	v := new(big.Int).SetBytes(verifier)
+	// B = k*v + g^b
+	B := new(big.Int).Add(new(big.Int).Mul(k, v), new(big.Int).Exp(g, b, n))
+	B.Mod(B, n)
+	return &Server{v: v, b: b, B: B}, nil
+}
+
+// PublicKey B sent to the client
+func (s *Server) PublicKey() []byte {
+	return pad(s.B)
+}
+
+// Verify check client proof M1 for client public key A, return server proof M2
+func (s *Server) Verify(clientKey, m1 []byte) ([]byte, error) {
+	A := new(big.Int).SetBytes(clientKey)
+	if !valid(A) {
+		return nil, ErrInvalidPublicKey
+	}
+	u := hashInt(pad(A), pad(s.B))
+	if u.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	// S = (A * v^u) ^ b
+	base := new(big.Int).Mul(A, new(big.Int).Exp(s.v, u, n))
+	base.Mod(base, n)
+	S := new(big.Int).Exp(base, s.b, n)
+	key := hash(pad(S))
+	expected := hash(pad(A), pad(s.B), key)
+	if subtle.ConstantTimeCompare(m1, expected) != 1 {
+		return nil, ErrInvalidProof
+	}
+	return hash(pad(A), expected, key), nil
+}
+
+// valid public key of the other side is reduced modulo N and not zero, zero breaks the exchange
+func valid(key *big.Int) bool {
+	return key.Sign() > 0 && key.Cmp(n) < 0
+}
+
+func privateKey(password string, salt []byte) *big.Int {
+	stretched := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return hashInt(salt, stretched)
+}
+
+func randomInt() (*big.Int, error) {
+	buf := make([]byte, ephemeralSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// pad value to the length of N
+func pad(value *big.Int) []byte {
+	return value.FillBytes(make([]byte, (n.BitLen()+7)/8))
+}
+
+func hash(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+func hashInt(parts ...[]byte) *big.Int {
+	return new(big.Int).SetBytes(hash(parts...))
+}
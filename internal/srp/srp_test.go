@@ -0,0 +1,76 @@
+package srp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup_ShouldBeSafePrime(t *testing.T) {
+	q := new(big.Int).Rsh(n, 1)
+
+	assert.Equal(t, 2048, n.BitLen())
+	assert.True(t, n.ProbablyPrime(20))
+	assert.True(t, q.ProbablyPrime(20))
+}
+
+func TestExchange_ShouldAgreeWithRightPassword(t *testing.T) {
+	salt, err := NewSalt()
+	assert.NoError(t, err)
+	server, client := exchange(t, Verifier("qwerty", salt), "qwerty")
+
+	m1, err := client.Proof(salt, server.PublicKey())
+	assert.NoError(t, err)
+	m2, err := server.Verify(client.PublicKey(), m1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, client.VerifyServer(m2))
+}
+
+func TestExchange_ShouldRejectWrongPassword(t *testing.T) {
+	salt, err := NewSalt()
+	assert.NoError(t, err)
+	server, client := exchange(t, Verifier("qwerty", salt), "qwerty1")
+
+	m1, err := client.Proof(salt, server.PublicKey())
+	assert.NoError(t, err)
+	_, err = server.Verify(client.PublicKey(), m1)
+
+	assert.ErrorIs(t, err, ErrInvalidProof)
+}
+
+func TestExchange_ShouldRejectZeroPublicKey(t *testing.T) {
+	salt, err := NewSalt()
+	assert.NoError(t, err)
+	server, client := exchange(t, Verifier("qwerty", salt), "qwerty")
+
+	_, clientErr := client.Proof(salt, pad(n))
+	_, serverErr := server.Verify(new(big.Int).Mul(n, big.NewInt(2)).Bytes(), []byte("proof"))
+
+	assert.ErrorIs(t, clientErr, ErrInvalidPublicKey)
+	assert.ErrorIs(t, serverErr, ErrInvalidPublicKey)
+}
+
+func TestClient_ShouldRejectServerWithoutVerifier(t *testing.T) {
+	salt, err := NewSalt()
+	assert.NoError(t, err)
+	server, client := exchange(t, Verifier("other", salt), "qwerty")
+
+	_, err = client.Proof(salt, server.PublicKey())
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, client.VerifyServer([]byte("forged")), ErrInvalidProof)
+}
+
+func exchange(t *testing.T, verifier []byte, password string) (*Server, *Client) {
+	server, err := NewServer(verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewClient(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, client
+}